@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+)
+
+// standaloneRestore restores a backup of the shard into a mysqld which is not
+// managed by a tablet, as requested by --restore-to-dir or --restore-to-mycnf-file,
+// instead of taking a backup. The mysqld listens on --mysql-port if mysqlPortSet,
+// and on a free port otherwise.
+func standaloneRestore(ctx context.Context, mysqlPortSet bool) error {
+	if restoreToDir != "" && restoreToMycnfFile != "" {
+		return errors.New("--restore-to-dir and --restore-to-mycnf-file are mutually exclusive")
+	}
+	if restoreToPos != "" && restoreToTimestamp != "" {
+		return errors.New("--restore-to-pos and --restore-to-timestamp are mutually exclusive")
+	}
+	dbName := initDbNameOverride
+	if dbName == "" {
+		dbName = "vt_" + initKeyspace
+	}
+	params := mysqlctl.StandaloneRestoreParams{
+		Logger:               logutil.NewConsoleLogger(),
+		Keyspace:             initKeyspace,
+		Shard:                initShard,
+		BackupName:           restoreBackupName,
+		DbName:               dbName,
+		Concurrency:          concurrency,
+		Dir:                  restoreToDir,
+		DBConfigs:            &dbconfigs.GlobalDBConfigs,
+		CollationEnv:         collationEnv,
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		Stats:                backupstats.RestoreStats(),
+	}
+	if mysqlPortSet {
+		params.MysqlPort = mysqlPort
+	}
+	if restoreToPos != "" {
+		pos, _, err := replication.DecodePositionMySQL56(restoreToPos)
+		if err != nil {
+			return fmt.Errorf("invalid --restore-to-pos %q: %w", restoreToPos, err)
+		}
+		params.RestoreToPos = pos
+	}
+	if restoreToTimestamp != "" {
+		ts, err := mysqlctl.ParseRFC3339(restoreToTimestamp)
+		if err != nil {
+			return fmt.Errorf("invalid --restore-to-timestamp %q: %w", restoreToTimestamp, err)
+		}
+		params.RestoreToTimestamp = ts
+	}
+	if restoreToMycnfFile != "" {
+		cnf, err := mysqlctl.ReadMycnf(&mysqlctl.Mycnf{Path: restoreToMycnfFile}, 0)
+		if err != nil {
+			return fmt.Errorf("failed to read %v: %w", restoreToMycnfFile, err)
+		}
+		params.Cnf = cnf
+	}
+
+	manifest, cnf, err := mysqlctl.StandaloneRestore(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to restore a backup of %v/%v: %w", initKeyspace, initShard, err)
+	}
+	log.Info(fmt.Sprintf("Restored backup %v at position %v into the mysqld configured by %v", manifest.BackupName, replication.EncodePosition(manifest.Position), cnf.Path))
+	return nil
+}
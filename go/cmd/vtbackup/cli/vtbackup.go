@@ -43,6 +43,7 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tieredbackupstorage"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -106,6 +107,13 @@ var (
 	keepAliveTimeout     time.Duration
 	disableRedoLog       bool
 
+	// standalone restore flags
+	restoreToDir       string
+	restoreToMycnfFile string
+	restoreBackupName  string
+	restoreToPos       string
+	restoreToTimestamp string
+
 	collationEnv *collations.Environment
 
 	// Deprecated, use "Phase" instead.
@@ -195,7 +203,14 @@ to the handling of the query path.
 The command-line parameters to vtbackup specify a policy for when a new backup
 is needed, and when old backups should be removed. If the existing backups
 already satisfy the policy, then vtbackup will do nothing and return success
-immediately.`,
+immediately.
+
+With --restore-to-dir or --restore-to-mycnf-file, vtbackup instead restores a
+backup of the shard into a standalone mysqld, optionally up to a point in time
+with --restore-to-pos or --restore-to-timestamp, and exits leaving that mysqld
+running with replication disabled. This mode needs no topology server and no
+tablet, which makes it suitable to recover data deleted by mistake without
+affecting the shard.`,
 		Version: servenv.AppVersion.String(),
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
@@ -240,6 +255,13 @@ func init() {
 	Main.Flags().DurationVar(&keepAliveTimeout, "keep-alive-timeout", keepAliveTimeout, "Wait until timeout elapses after a successful backup before shutting down.")
 	Main.Flags().BoolVar(&disableRedoLog, "disable-redo-log", disableRedoLog, "Disable InnoDB redo log during replication-from-primary phase of backup.")
 
+	// standalone restore flags
+	Main.Flags().StringVar(&restoreToDir, "restore-to-dir", restoreToDir, "Instead of taking a backup, restore a backup of the shard into a new mysqld whose files all live in this directory, which must be empty or not exist. The mysqld is left running, with replication disabled, and listens on --mysql-port if it is set, or on a free port otherwise. No topology server is used.")
+	Main.Flags().StringVar(&restoreToMycnfFile, "restore-to-mycnf-file", restoreToMycnfFile, "Instead of taking a backup, restore a backup of the shard into the existing mysqld configured by this my.cnf file, replacing its files. The mysqld is left running, with replication disabled. No topology server is used.")
+	Main.Flags().StringVar(&restoreBackupName, "restore-backup-name", restoreBackupName, "(standalone restore parameter) name of the full backup to restore. When empty, the most recent suitable full backup is restored.")
+	Main.Flags().StringVar(&restoreToPos, "restore-to-pos", restoreToPos, "(standalone restore parameter) run a point in time recovery that ends with the given position. This will attempt to use one full backup followed by zero or more incremental backups")
+	Main.Flags().StringVar(&restoreToTimestamp, "restore-to-timestamp", restoreToTimestamp, "(standalone restore parameter) run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
+
 	acl.RegisterFlags(Main.Flags())
 
 	collationEnv = collations.NewEnvironment(servenv.MySQLServerVersion())
@@ -272,6 +294,10 @@ func run(cc *cobra.Command, args []string) error {
 
 	defer logutil.Flush()
 
+	if restoreToDir != "" || restoreToMycnfFile != "" {
+		return standaloneRestore(ctx, cc.Flags().Changed("mysql-port"))
+	}
+
 	if minRetentionCount < 1 {
 		log.Error("min_retention_count must be at least 1 to allow restores to succeed")
 		exit.Return(1)
@@ -283,6 +309,9 @@ func run(cc *cobra.Command, args []string) error {
 		return fmt.Errorf("Can't get backup storage: %w", err)
 	}
 	defer backupStorage.Close()
+	// Wait for the tiered backup storage, if it is used, to replicate the backup
+	// before exiting.
+	defer tieredbackupstorage.WaitForReplications()
 	// Open connection to topology server.
 	topoServer := topo.Open()
 	defer topoServer.Close()
@@ -484,6 +513,8 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 			Shard:                initShard,
 			Stats:                backupstats.RestoreStats(),
 			MysqlShutdownTimeout: mysqlShutdownTimeout,
+			TopoServer:           topoServer,
+			TabletAlias:          topoproto.TabletAliasString(tabletAlias),
 		}
 		backupManifest, err := mysqlctl.Restore(ctx, params)
 		switch err {
@@ -819,6 +850,14 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 			break
 		}
 	}
+
+	// Remove the chunks which were only referenced by the pruned backups, if they were chunked.
+	removed, err := mysqlctl.RemoveUnreferencedBackupChunks(ctx, backupStorage, backupDir, false)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Failed to remove the unreferenced backup chunks of %v: %v", backupDir, err))
+	} else if len(removed) > 0 {
+		log.Info(fmt.Sprintf("Removed %d unreferenced backup chunks of %v.", len(removed), backupDir))
+	}
 	return nil
 }
 
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/utils"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var backupRetentionCheckInterval time.Duration
+
+func init() {
+	utils.SetFlagDurationVar(Main.Flags(), &backupRetentionCheckInterval, "backup-retention-check-interval", backupRetentionCheckInterval, "How often the backups of the keyspaces with a backup retention policy are pruned. Pruning is disabled if zero; it should only be enabled on a single vtctld.")
+}
+
+func initBackupRetention(ctx context.Context) {
+	if backupRetentionCheckInterval <= 0 {
+		return
+	}
+
+	server := grpcvtctldserver.NewVtctldServer(env, ts)
+	timer := timer.NewTimer(backupRetentionCheckInterval)
+	timer.Start(func() {
+		keyspaces, err := ts.GetKeyspaces(ctx)
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to get the keyspaces to prune backups of: %v", err))
+			return
+		}
+		for _, keyspace := range keyspaces {
+			ki, err := ts.GetKeyspace(ctx, keyspace)
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to get keyspace %v to prune its backups: %v", keyspace, err))
+				continue
+			}
+			if mysqlctl.BackupRetentionPolicyIsEmpty(ki.BackupRetentionPolicy) {
+				continue
+			}
+			resp, err := server.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{Keyspace: keyspace})
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to prune the backups of keyspace %v: %v", keyspace, err))
+				continue
+			}
+			if len(resp.PrunedBackups) > 0 {
+				log.Info(fmt.Sprintf("Pruned %d backups of keyspace %v", len(resp.PrunedBackups), keyspace))
+			}
+		}
+	})
+	servenv.OnClose(func() { timer.Stop() })
+}
@@ -81,6 +81,9 @@ func run(cmd *cobra.Command, args []string) error {
 	// Start schema manager service.
 	initSchema(cmd.Context())
 
+	// Start pruning the backups of keyspaces with a backup retention policy.
+	initBackupRetention(cmd.Context())
+
 	// And run the server.
 	servenv.RunDefault()
 
@@ -55,6 +55,20 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandBackupShard,
 	}
+	// CopyBackup makes a CopyBackup gRPC call to a vtctld.
+	CopyBackup = &cobra.Command{
+		Use:   "CopyBackup --from <storage> --to <storage> [--remove-source] <keyspace/shard> <backup name>",
+		Short: "Copies the given backup between two backup storage implementations registered in vtctld.",
+		Long: `Copies the given backup between two backup storage implementations registered in vtctld.
+
+The files of the backup are copied from the --from backup storage to the --to backup storage, checked
+against the hashes recorded in the backup MANIFEST, and read back from the --to backup storage to verify
+them. With --remove-source, the backup is removed from the --from backup storage once it has been copied,
+which moves it. The copied files are printed as JSON.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandCopyBackup,
+	}
 	// GetBackups makes a GetBackups gRPC call to a vtctld.
 	GetBackups = &cobra.Command{
 		Use:                   "GetBackups [--limit <limit>] [--json] <keyspace/shard>",
@@ -63,6 +77,22 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetBackups,
 	}
+	// PruneBackups makes a PruneBackups gRPC call to a vtctld.
+	PruneBackups = &cobra.Command{
+		Use:   "PruneBackups [--dry-run] [--shards <shard>,...] <keyspace>",
+		Short: "Removes the backups of the given keyspace which are not kept by its backup retention policy.",
+		Long: `Removes the backups of the given keyspace which are not kept by its backup retention policy.
+
+The retention policy of the keyspace is set with SetKeyspaceBackupRetentionPolicy. Incremental backups
+which are kept also keep the full and incremental backups they depend on. The removed backups are printed
+as JSON; with --dry-run, the backups which would be removed are printed and nothing is removed.
+
+With the tiered backup storage, the complete backups which are missing from a secondary backup storage,
+for instance because the process which took them exited before replicating them, are replicated again.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandPruneBackups,
+	}
 	// RemoveBackup makes a RemoveBackup gRPC call to a vtctld.
 	RemoveBackup = &cobra.Command{
 		Use:                   "RemoveBackup <keyspace/shard> <backup name>",
@@ -79,6 +109,21 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// VerifyBackup makes a VerifyBackup gRPC call to a vtctld.
+	VerifyBackup = &cobra.Command{
+		Use:   "VerifyBackup [--concurrency <concurrency>] <tablet_alias> <backup name>",
+		Short: "Restores the given backup into a scratch mysqld on the specified tablet and checks every restored table.",
+		Long: `Restores the given backup into a scratch mysqld on the specified tablet and checks every restored table.
+
+The backup is restored into a temporary data directory, every table is checked with CHECK TABLE
+and its rows are counted, and the scratch mysqld is then removed. The scratch mysqld competes with
+the mysqld of the tablet for disk, memory and CPU, so the tablet must be of type SPARE or BACKUP.
+The result is recorded in the VERIFICATION file of the backup. The command fails if the backup
+could not be restored or if any table failed its checks.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandVerifyBackup,
+	}
 )
 
 var backupOptions = struct {
@@ -205,6 +250,43 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var copyBackupOptions = struct {
+	FromStorage  string
+	ToStorage    string
+	RemoveSource bool
+}{}
+
+func commandCopyBackup(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	name := cmd.Flags().Arg(1)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.CopyBackup(commandCtx, &vtctldatapb.CopyBackupRequest{
+		Keyspace:     keyspace,
+		Shard:        shard,
+		Name:         name,
+		FromStorage:  copyBackupOptions.FromStorage,
+		ToStorage:    copyBackupOptions.ToStorage,
+		RemoveSource: copyBackupOptions.RemoveSource,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var getBackupsOptions = struct {
 	Limit      uint32
 	OutputJSON bool
@@ -247,6 +329,33 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var pruneBackupsOptions = struct {
+	DryRun bool
+	Shards []string
+}{}
+
+func commandPruneBackups(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PruneBackups(commandCtx, &vtctldatapb.PruneBackupsRequest{
+		Keyspace: keyspace,
+		Shards:   pruneBackupsOptions.Shards,
+		DryRun:   pruneBackupsOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func commandRemoveBackup(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
@@ -328,6 +437,42 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var verifyBackupOptions = struct {
+	Concurrency int32
+}{}
+
+func commandVerifyBackup(cmd *cobra.Command, args []string) error {
+	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	name := cmd.Flags().Arg(1)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.VerifyBackup(commandCtx, &vtctldatapb.VerifyBackupRequest{
+		TabletAlias: alias,
+		BackupName:  name,
+		Concurrency: verifyBackupOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	if !resp.Result.Ok {
+		return fmt.Errorf("backup %s failed verification", name)
+	}
+	return nil
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -347,10 +492,21 @@ func init() {
 	addInitSQLFlags(BackupShard)
 	Root.AddCommand(BackupShard)
 
+	CopyBackup.Flags().StringVar(&copyBackupOptions.FromStorage, "from", "", "Name of the backup storage implementation to copy the backup from, such as file or s3.")
+	CopyBackup.Flags().StringVar(&copyBackupOptions.ToStorage, "to", "", "Name of the backup storage implementation to copy the backup to, such as file or s3.")
+	CopyBackup.Flags().BoolVar(&copyBackupOptions.RemoveSource, "remove-source", false, "Remove the backup from the --from backup storage once it has been copied and verified.")
+	CopyBackup.MarkFlagRequired("from")
+	CopyBackup.MarkFlagRequired("to")
+	Root.AddCommand(CopyBackup)
+
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
+	PruneBackups.Flags().BoolVar(&pruneBackupsOptions.DryRun, "dry-run", false, "Only print the backups which would be removed, without removing them.")
+	PruneBackups.Flags().StringSliceVar(&pruneBackupsOptions.Shards, "shards", nil, "Only prune the backups of these shards. Defaults to every shard in the keyspace.")
+	Root.AddCommand(PruneBackups)
+
 	Root.AddCommand(RemoveBackup)
 
 	RestoreFromBackup.Flags().StringVarP(&restoreFromBackupOptions.BackupTimestamp, "backup-timestamp", "t", "", "Use the backup taken at, or closest before, this timestamp. Omit to use the latest backup. Timestamp format is \"YYYY-mm-DD.HHMMSS\".")
@@ -359,6 +515,9 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	VerifyBackup.Flags().Int32Var(&verifyBackupOptions.Concurrency, "concurrency", 0, "Specifies the number of files to restore simultaneously. Defaults to the restore concurrency of the tablet.")
+	Root.AddCommand(VerifyBackup)
 }
 
 func addInitSQLFlags(cmd *cobra.Command) {
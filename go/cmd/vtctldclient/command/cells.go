@@ -18,6 +18,7 @@ package command
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -97,6 +98,19 @@ the same group (alias).`,
 		Args:                  cobra.NoArgs,
 		RunE:                  commandGetCellsAliases,
 	}
+	// SetCellBackupBandwidthLimit makes a SetCellBackupBandwidthLimit gRPC call to a vtctld.
+	SetCellBackupBandwidthLimit = &cobra.Command{
+		Use:   "SetCellBackupBandwidthLimit <cell> <bytes-per-second>",
+		Short: "Limits the bandwidth used by the builtin backups and restores of all the tablets of a cell.",
+		Long: `Limits the bandwidth used by the builtin backups and restores of all the tablets of a cell.
+
+The limit is stored with the token bucket which the tablets of the cell share in its topology server.
+The tablets which are transferring files use the new limit at once, the other ones within 30 seconds.
+A limit of 0 removes the limit of the cell.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandSetCellBackupBandwidthLimit,
+	}
 	// UpdateCellInfo makes an UpdateCellInfo gRPC call to a vtctld.
 	UpdateCellInfo = &cobra.Command{
 		Use:   "UpdateCellInfo [--root <root>] [--server-address <addr>] <cell>",
@@ -241,6 +255,32 @@ func commandGetCellsAliases(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandSetCellBackupBandwidthLimit(cmd *cobra.Command, args []string) error {
+	cell := cmd.Flags().Arg(0)
+	limit, err := strconv.ParseInt(cmd.Flags().Arg(1), 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse bytes-per-second as int64: %w", err)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetCellBackupBandwidthLimit(commandCtx, &vtctldatapb.SetCellBackupBandwidthLimitRequest{
+		Cell:  cell,
+		Limit: limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Bucket)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated the backup bandwidth limit of cell %s. New BackupBandwidthBucket:\n%s\n", cell, data)
+	return nil
+}
+
 var updateCellInfoOptions topodatapb.CellInfo
 
 func commandUpdateCellInfo(cmd *cobra.Command, args []string) error {
@@ -304,6 +344,8 @@ func init() {
 	Root.AddCommand(GetCellInfo)
 	Root.AddCommand(GetCellsAliases)
 
+	Root.AddCommand(SetCellBackupBandwidthLimit)
+
 	UpdateCellInfo.Flags().StringVarP(&updateCellInfoOptions.ServerAddress, "server-address", "a", "", "The address the topology server will connect to for this cell.")
 	UpdateCellInfo.Flags().StringVarP(&updateCellInfoOptions.Root, "root", "r", "", "The root path the topology server will use for this cell.")
 	Root.AddCommand(UpdateCellInfo)
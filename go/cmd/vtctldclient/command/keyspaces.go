@@ -95,6 +95,22 @@ Otherwise, the keyspace must be empty (have no shards), or returns an error.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandRemoveKeyspaceCell,
 	}
+	// SetKeyspaceBackupRetentionPolicy makes a SetKeyspaceBackupRetentionPolicy gRPC call to a vtctld.
+	SetKeyspaceBackupRetentionPolicy = &cobra.Command{
+		Use:   "SetKeyspaceBackupRetentionPolicy [--keep-full-backups <count>] [--keep-newer-than <duration>] [--point-in-time-recovery-window <duration>] <keyspace name>",
+		Short: "Sets the policy deciding which backups of the specified keyspace are kept when backups are pruned.",
+		Long: `Sets the policy deciding which backups of the specified keyspace are kept when backups are pruned.
+A backup is kept if any of the rules keeps it. The most recent complete full backup of each shard, and the
+incremental backups taken after it, are always kept, as are the backups which kept incremental backups depend on.
+Calling the command without any rule removes the policy, which disables pruning for the keyspace.
+
+To keep the last 7 full backups of the customer keyspace, and every backup needed to restore it to any point
+in time within the last 3 days, you would use the following command:
+SetKeyspaceBackupRetentionPolicy --keep-full-backups 7 --point-in-time-recovery-window 72h customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetKeyspaceBackupRetentionPolicy,
+	}
 	// SetKeyspaceDurabilityPolicy makes a SetKeyspaceDurabilityPolicy gRPC call to a vtcltd.
 	SetKeyspaceDurabilityPolicy = &cobra.Command{
 		Use:   "SetKeyspaceDurabilityPolicy [--durability-policy=policy_name] <keyspace name>",
@@ -313,6 +329,43 @@ func commandRemoveKeyspaceCell(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var setKeyspaceBackupRetentionPolicyOptions = struct {
+	KeepFullBackups           uint32
+	KeepNewerThan             time.Duration
+	PointInTimeRecoveryWindow time.Duration
+}{}
+
+func commandSetKeyspaceBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	policy := &topodatapb.BackupRetentionPolicy{
+		KeepFullBackups: setKeyspaceBackupRetentionPolicyOptions.KeepFullBackups,
+	}
+	if cmd.Flags().Changed("keep-newer-than") {
+		policy.KeepNewerThan = protoutil.DurationToProto(setKeyspaceBackupRetentionPolicyOptions.KeepNewerThan)
+	}
+	if cmd.Flags().Changed("point-in-time-recovery-window") {
+		policy.PointInTimeRecoveryWindow = protoutil.DurationToProto(setKeyspaceBackupRetentionPolicyOptions.PointInTimeRecoveryWindow)
+	}
+
+	resp, err := client.SetKeyspaceBackupRetentionPolicy(commandCtx, &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+		Keyspace:              keyspace,
+		BackupRetentionPolicy: policy,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var setKeyspaceDurabilityPolicyOptions = struct {
 	DurabilityPolicy string
 }{}
@@ -380,6 +433,11 @@ func init() {
 	RemoveKeyspaceCell.Flags().BoolVarP(&removeKeyspaceCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified keyspace.")
 	Root.AddCommand(RemoveKeyspaceCell)
 
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepFullBackups, "keep-full-backups", 0, "Number of most recent complete full backups to keep for each shard.")
+	SetKeyspaceBackupRetentionPolicy.Flags().DurationVar(&setKeyspaceBackupRetentionPolicyOptions.KeepNewerThan, "keep-newer-than", 0, "Keep every backup taken within this duration.")
+	SetKeyspaceBackupRetentionPolicy.Flags().DurationVar(&setKeyspaceBackupRetentionPolicyOptions.PointInTimeRecoveryWindow, "point-in-time-recovery-window", 0, "Keep the full and incremental backups needed to restore each shard to any point in time within this duration.")
+	Root.AddCommand(SetKeyspaceBackupRetentionPolicy)
+
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", policy.DurabilityNone, "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
 	Root.AddCommand(SetKeyspaceDurabilityPolicy)
 
@@ -18,6 +18,7 @@ package command
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -57,6 +58,30 @@ NOTE: This command automatically updates the serving graph.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandChangeTabletType,
 	}
+	// CloneTablet makes a CloneTablet gRPC call to a vtctld.
+	CloneTablet = &cobra.Command{
+		Use:   "CloneTablet [--from <alias>] [--cells <cells>] [--tablet-types <types>] [--tablet-types-in-preference-order] [--wait-for-donor-timeout <duration>] --to <alias>",
+		Short: "Replaces the data of a tablet with a MySQL CLONE of another tablet in its shard.",
+		Long: `Replaces the data of a tablet with a MySQL CLONE of another tablet in its shard.
+
+All the data of the tablet given with --to is deleted and replaced with a clone of
+the mysqld of the donor tablet. The tablet is not serving while the clone runs.
+Once it is done, replication is started from the position of the clone and the
+tablet goes back to its previous type.
+
+If --from is not given, a healthy donor of one of --tablet-types is picked from
+--cells. As the clone talks to the mysqld of the donor directly, donors whose
+query service is not serving are candidates too. By default, only SPARE, BACKUP
+and DRAINED tablets are picked, so that the clone does not slow down the queries
+of the shard: serving tablets are only picked when their types are given with
+--tablet-types.
+
+Both tablets must run MySQL 8.0.17+ with the clone plugin, and the tablets must
+be started with --mysql-clone-enabled and a clone user (--db-clone-user).`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandCloneTablet,
+	}
 	// DeleteTablets makes a DeleteTablets gRPC call to a vtctld.
 	DeleteTablets = &cobra.Command{
 		Use:                   "DeleteTablets <alias> [ <alias> ... ]",
@@ -288,6 +313,59 @@ func commandChangeTabletType(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var cloneTabletOptions = struct {
+	From                         string
+	To                           string
+	Cells                        []string
+	TabletTypes                  []topodatapb.TabletType
+	TabletTypesInPreferenceOrder bool
+	WaitForDonorTimeout          time.Duration
+}{}
+
+func commandCloneTablet(cmd *cobra.Command, args []string) error {
+	to, err := topoproto.ParseTabletAlias(cloneTabletOptions.To)
+	if err != nil {
+		return err
+	}
+
+	req := &vtctldatapb.CloneTabletRequest{
+		ToAlias:     to,
+		Cells:       cloneTabletOptions.Cells,
+		TabletTypes: cloneTabletOptions.TabletTypes,
+	}
+	if cloneTabletOptions.WaitForDonorTimeout > 0 {
+		req.WaitForDonorTimeout = protoutil.DurationToProto(cloneTabletOptions.WaitForDonorTimeout)
+	}
+	if cloneTabletOptions.From != "" {
+		req.FromAlias, err = topoproto.ParseTabletAlias(cloneTabletOptions.From)
+		if err != nil {
+			return err
+		}
+	}
+	if cloneTabletOptions.TabletTypesInPreferenceOrder {
+		req.TabletSelectionPreference = tabletmanagerdatapb.TabletSelectionPreference_INORDER
+	}
+
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.CloneTablet(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			fmt.Printf("%s/%s (%s from %s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), topoproto.TabletAliasString(resp.DonorAlias), resp.Event)
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 var deleteTabletsOptions = struct {
 	AllowPrimary bool
 }{}
@@ -648,6 +726,15 @@ func init() {
 	ChangeTabletType.Flags().BoolVarP(&changeTabletTypeOptions.DryRun, "dry-run", "d", false, "Shows the proposed change without actually executing it.")
 	Root.AddCommand(ChangeTabletType)
 
+	CloneTablet.Flags().StringVar(&cloneTabletOptions.From, "from", "", "Alias of the donor tablet. If not given, a healthy donor is picked from the shard of the tablet.")
+	CloneTablet.Flags().StringVar(&cloneTabletOptions.To, "to", "", "Alias of the tablet whose data is replaced by the clone.")
+	CloneTablet.MarkFlagRequired("to")
+	CloneTablet.Flags().StringSliceVarP(&cloneTabletOptions.Cells, "cells", "c", nil, "Cells to pick a donor from. Defaults to the cell of the tablet.")
+	CloneTablet.Flags().Var((*topoproto.TabletTypeListFlag)(&cloneTabletOptions.TabletTypes), "tablet-types", "Tablet types to pick a donor from. Defaults to SPARE, BACKUP and DRAINED.")
+	CloneTablet.Flags().BoolVar(&cloneTabletOptions.TabletTypesInPreferenceOrder, "tablet-types-in-preference-order", true, "When picking a donor, look for candidates in the type order as they are listed in the tablet-types flag.")
+	CloneTablet.Flags().DurationVar(&cloneTabletOptions.WaitForDonorTimeout, "wait-for-donor-timeout", 0, "How long to wait for a healthy donor to be picked. Defaults to 1m.")
+	Root.AddCommand(CloneTablet)
+
 	DeleteTablets.Flags().BoolVarP(&deleteTabletsOptions.AllowPrimary, "allow-primary", "p", false, "Allow the primary tablet of a shard to be deleted. Use with caution.")
 	Root.AddCommand(DeleteTablets)
 
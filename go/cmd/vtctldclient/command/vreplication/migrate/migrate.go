@@ -37,13 +37,15 @@ var migrate = &cobra.Command{
 }
 
 var createOptions = struct {
-	MountName      string
-	SourceKeyspace string
-	AllTables      bool
-	IncludeTables  []string
-	ExcludeTables  []string
-	SourceTimeZone string
-	NoRoutingRules bool
+	MountName        string
+	SourceKeyspace   string
+	AllTables        bool
+	IncludeTables    []string
+	ExcludeTables    []string
+	SourceTimeZone   string
+	NoRoutingRules   bool
+	Bidirectional    bool
+	ReverseMountName string
 }{}
 
 var createCommand = &cobra.Command{
@@ -59,6 +61,9 @@ var createCommand = &cobra.Command{
 		if !cmd.Flags().Lookup("tables").Changed && !cmd.Flags().Lookup("all-tables").Changed {
 			return errors.New("tables or all-tables are required to specify which tables to move")
 		}
+		if createOptions.Bidirectional && createOptions.ReverseMountName == "" {
+			return errors.New("reverse-mount-name is required for a bidirectional workflow")
+		}
 		if err := common.ParseAndValidateCreateOptions(cmd); err != nil {
 			return err
 		}
@@ -88,6 +93,8 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		AutoStart:                 common.CreateOptions.AutoStart,
 		StopAfterCopy:             common.CreateOptions.StopAfterCopy,
 		NoRoutingRules:            createOptions.NoRoutingRules,
+		Bidirectional:             createOptions.Bidirectional,
+		ReverseMountName:          createOptions.ReverseMountName,
 	}
 
 	_, err := common.GetClient().MigrateCreate(common.GetCommandCtx(), req)
@@ -109,6 +116,8 @@ func addCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSliceVar(&createOptions.IncludeTables, "tables", nil, "Source tables to copy.")
 	cmd.Flags().StringSliceVar(&createOptions.ExcludeTables, "exclude-tables", nil, "Source tables to exclude from copying.")
 	cmd.Flags().BoolVar(&createOptions.NoRoutingRules, "no-routing-rules", false, "(Advanced) Do not create routing rules while creating the workflow. See the reference documentation for limitations if you use this flag.")
+	cmd.Flags().BoolVar(&createOptions.Bidirectional, "bidirectional", false, "Also replicate the changes made on the target back to the source, so that writes can be served by either cluster during the cutover. Requires binlog_rows_query_log_events on both clusters.")
+	cmd.Flags().StringVar(&createOptions.ReverseMountName, "reverse-mount-name", "", "Name this cluster is mounted as in the external cluster. Required with --bidirectional.")
 }
 
 func registerCommands(root *cobra.Command) {
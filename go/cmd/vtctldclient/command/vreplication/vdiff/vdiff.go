@@ -23,12 +23,14 @@ import (
 	"io"
 	"math"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/bndr/gotabulate"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
@@ -76,6 +78,12 @@ var (
 		Arg string
 	}{}
 
+	repairOptions = struct {
+		UUID         uuid.UUID
+		TargetShards []string
+		BatchSize    int64
+	}{}
+
 	resumeOptions = struct {
 		UUID         uuid.UUID
 		TargetShards []string
@@ -179,6 +187,31 @@ vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --targe
 		RunE: commandDelete,
 	}
 
+	// repair makes a VDiffRepair gRPC call to a vtctld.
+	repair = &cobra.Command{
+		Use:   "repair",
+		Short: "Repair the differences found by a completed VDiff.",
+		Long: `Repair the differences found by a completed VDiff by re-reading the rows in its report from the source and writing them to the target, deleting any target rows that no longer exist on the source.
+If the report only has a sample of the differing rows, the table is diffed again to find all of them. Rows may change while they are repaired, so you should create a new VDiff afterwards to confirm that the tables now match.`,
+		Example:               `vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer repair a037a9e2-5628-11ee-8c99-0242ac120002`,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Repair"},
+		Args:                  cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			uuid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid UUID provided: %v", err)
+			}
+			repairOptions.UUID = uuid
+			if repairOptions.BatchSize < 1 {
+				return fmt.Errorf("invalid batch size provided (%d), it must be greater than 0", repairOptions.BatchSize)
+			}
+
+			return common.ValidateShards(repairOptions.TargetShards)
+		},
+		RunE: commandRepair,
+	}
+
 	// resume makes a VDiffResume gRPC call to a vtctld.
 	resume = &cobra.Command{
 		Use:                   "resume",
@@ -371,6 +404,82 @@ func commandDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// repairSummary is what was repaired in one table on one target shard.
+type repairSummary struct {
+	Shard        string
+	TableName    string
+	RowsUpserted int64
+	RowsDeleted  int64
+}
+
+func commandRepair(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().VDiffRepair(common.GetCommandCtx(), &vtctldatapb.VDiffRepairRequest{
+		Workflow:       common.BaseOptions.Workflow,
+		TargetKeyspace: common.BaseOptions.TargetKeyspace,
+		Uuid:           repairOptions.UUID.String(),
+		TargetShards:   repairOptions.TargetShards,
+		BatchSize:      repairOptions.BatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	return displayRepairResponse(cmd.OutOrStdout(), format, resp)
+}
+
+func displayRepairResponse(out io.Writer, format string, resp *vtctldatapb.VDiffRepairResponse) error {
+	var summaries []*repairSummary
+	for _, shard := range maps.Keys(resp.TabletResponses) {
+		tabletResp := resp.TabletResponses[shard]
+		if tabletResp == nil || tabletResp.Output == nil {
+			continue
+		}
+		qr := sqltypes.Proto3ToResult(tabletResp.Output)
+		for _, row := range qr.Named().Rows {
+			summaries = append(summaries, &repairSummary{
+				Shard:        shard,
+				TableName:    row.AsString("table_name", ""),
+				RowsUpserted: row.AsInt64("rows_upserted", 0),
+				RowsDeleted:  row.AsInt64("rows_deleted", 0),
+			})
+		}
+	}
+	slices.SortFunc(summaries, func(a, b *repairSummary) int {
+		if c := strings.Compare(a.TableName, b.TableName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Shard, b.Shard)
+	})
+
+	if format == "json" {
+		if summaries == nil {
+			summaries = []*repairSummary{}
+		}
+		jsonText, err := cli.MarshalJSONPretty(summaries)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(jsonText))
+		return nil
+	}
+	if len(summaries) == 0 {
+		fmt.Fprintf(out, "VDiff %s completed: no differences to repair\n", vdiff.RepairAction)
+		return nil
+	}
+	fmt.Fprintf(out, "VDiff %s completed\n", vdiff.RepairAction)
+	for _, summary := range summaries {
+		fmt.Fprintf(out, "Table %s on shard %s: %d row(s) upserted, %d row(s) deleted\n",
+			summary.TableName, summary.Shard, summary.RowsUpserted, summary.RowsDeleted)
+	}
+	return nil
+}
+
 func commandResume(cmd *cobra.Command, args []string) error {
 	format, err := common.GetOutputFormat(cmd)
 	if err != nil {
@@ -705,6 +814,10 @@ func registerCommands(root *cobra.Command) {
 
 	base.AddCommand(delete)
 
+	repair.Flags().StringSliceVar(&repairOptions.TargetShards, "target-shards", nil, "The target shards to repair the vdiff differences on; default is all shards.")
+	repair.Flags().Int64Var(&repairOptions.BatchSize, "batch-size", vdiff.DefaultRepairBatchSize, "The number of rows to re-read from the source and write to the target in each transaction.")
+	base.AddCommand(repair)
+
 	resume.Flags().StringSliceVar(&resumeOptions.TargetShards, "target-shards", nil, "The target shards to resume the vdiff on; default is all shards.")
 	base.AddCommand(resume)
 
@@ -19,6 +19,7 @@ package vdiff
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -814,3 +815,25 @@ func TestGetStructNames(t *testing.T) {
 	want := []string{"A", "B"}
 	require.EqualValues(t, want, got)
 }
+
+func TestDisplayRepairResponse(t *testing.T) {
+	repairFields := sqltypes.MakeTestFields("table_name|rows_upserted|rows_deleted", "varchar|int64|int64")
+	resp := &vtctldatapb.VDiffRepairResponse{
+		TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+			"80-": {Output: sqltypes.ResultToProto3(sqltypes.MakeTestResult(repairFields, "t1|2|0"))},
+			"-80": {Output: sqltypes.ResultToProto3(sqltypes.MakeTestResult(repairFields, "t2|0|1", "t1|3|1"))},
+		},
+	}
+
+	out := &strings.Builder{}
+	require.NoError(t, displayRepairResponse(out, "text", resp))
+	require.Equal(t, `VDiff repair completed
+Table t1 on shard -80: 3 row(s) upserted, 1 row(s) deleted
+Table t1 on shard 80-: 2 row(s) upserted, 0 row(s) deleted
+Table t2 on shard -80: 0 row(s) upserted, 1 row(s) deleted
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, displayRepairResponse(out, "json", &vtctldatapb.VDiffRepairResponse{}))
+	require.Equal(t, "[]\n", out.String())
+}
@@ -79,6 +79,18 @@ func (c fallbackClient) VStream(ctx context.Context, tabletType topodatapb.Table
 	return c.fallback.VStream(ctx, tabletType, vgtid, filter, flags, send)
 }
 
+func (c fallbackClient) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	return c.fallback.VStreamAck(ctx, subscriptionName, vgtid)
+}
+
+func (c fallbackClient) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	return c.fallback.VStreamSubscriptions(ctx)
+}
+
+func (c fallbackClient) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	return c.fallback.DeleteVStreamSubscription(ctx, subscriptionName)
+}
+
 func (c fallbackClient) HandlePanic(err *error) {
 	c.fallback.HandlePanic(err)
 }
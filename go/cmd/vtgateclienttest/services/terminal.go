@@ -93,6 +93,18 @@ func (c *terminalClient) VStream(ctx context.Context, tabletType topodatapb.Tabl
 	return errTerminal
 }
 
+func (c *terminalClient) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	return errTerminal
+}
+
+func (c *terminalClient) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	return nil, errTerminal
+}
+
+func (c *terminalClient) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	return errTerminal
+}
+
 func (c *terminalClient) HandlePanic(err *error) {
 	if x := recover(); x != nil {
 		log.Error(fmt.Sprintf("Uncaught panic:\n%v\n%s", x, tb.Stack(4)))
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"fmt"
+	"time"
+
+	vaultapi "github.com/aquarapid/vaultlib"
+)
+
+// ReadSecretField reads a field of a KV secret from Vault, authenticating with the
+// token read from tokenFilePath. As with the Vault auth server, the address, the CA
+// certificate and the token can be overridden by the environment.
+func ReadSecretField(addr string, timeout time.Duration, caCertPath, tokenFilePath, path, field string) (string, error) {
+	token, err := readFromFile(tokenFilePath)
+	if err != nil {
+		return "", fmt.Errorf("cannot read the Vault token from %s: %v", tokenFilePath, err)
+	}
+
+	config := vaultapi.NewConfig()
+	if config.Address == "" {
+		config.Address = addr
+	}
+	if config.Timeout == (0 * time.Second) {
+		config.Timeout = timeout
+	}
+	if config.CACert == "" {
+		config.CACert = caCertPath
+	}
+	if config.Token == "" {
+		config.Token = token
+	}
+	if config.CACert != "" {
+		config.InsecureSSL = false
+	}
+
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		return "", fmt.Errorf("error in Vault client initialization: %v", err)
+	}
+	secret, err := client.GetSecret(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read the Vault secret %s: %v", path, err)
+	}
+	value, ok := secret.KV[field]
+	if !ok || value == "" {
+		return "", fmt.Errorf("the Vault secret %s has no %s field", path, field)
+	}
+	return value, nil
+}
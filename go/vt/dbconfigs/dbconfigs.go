@@ -326,6 +326,26 @@ func (dbcfgs *DBConfigs) Clone() *DBConfigs {
 	return &result
 }
 
+// CloneWithSocket returns a copy of the DBConfigs whose connection parameters,
+// except for the external replication ones, use the given socket file instead.
+// The credentials are kept, which makes it possible to connect to a second mysqld
+// restored from a backup of the first one.
+func (dbcfgs *DBConfigs) CloneWithSocket(socketFile string, collationEnv *collations.Environment) *DBConfigs {
+	result := dbcfgs.Clone()
+	result.Socket = ""
+	result.Host = ""
+	result.Port = 0
+	for _, userKey := range All {
+		if userKey == ExternalRepl {
+			continue
+		}
+		_, cp := result.getParams(userKey)
+		*cp = mysql.ConnParams{}
+	}
+	result.InitWithSocket(socketFile, collationEnv)
+	return result
+}
+
 // InitWithSocket will initialize all the necessary connection parameters.
 // Precedence is as follows: if UserConfig settings are set,
 // they supersede all other settings.
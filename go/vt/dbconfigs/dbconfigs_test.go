@@ -222,6 +222,39 @@ func TestUseTCP(t *testing.T) {
 	assert.Equal(t, want, dbConfigs.dbaParams)
 }
 
+func TestCloneWithSocket(t *testing.T) {
+	dbConfigs := DBConfigs{
+		Host:   "a",
+		Port:   1,
+		Socket: "b",
+		App: UserConfig{
+			User:   "app",
+			UseTCP: true,
+		},
+		Dba: UserConfig{
+			User:     "dba",
+			Password: "secret",
+		},
+		Charset: "utf8",
+	}
+	dbConfigs.InitWithSocket("default", collations.MySQL8())
+
+	clone := dbConfigs.CloneWithSocket("scratch.sock", collations.MySQL8())
+	want := mysql.ConnParams{
+		Uname:      "dba",
+		Pass:       "secret",
+		UnixSocket: "scratch.sock",
+		Charset:    collations.CollationUtf8mb3ID,
+	}
+	assert.Equal(t, want, clone.dbaParams)
+	assert.Equal(t, "scratch.sock", clone.appParams.UnixSocket)
+	assert.Empty(t, clone.appParams.Host)
+
+	// The original DBConfigs is left untouched.
+	assert.Equal(t, "b", dbConfigs.dbaParams.UnixSocket)
+	assert.Equal(t, "a", dbConfigs.appParams.Host)
+}
+
 func TestAccessors(t *testing.T) {
 	dbc := &DBConfigs{
 		appParams:      mysql.ConnParams{},
@@ -212,6 +212,7 @@ type AZBlobBackupHandle struct {
 	dir       string
 	name      string
 	readOnly  bool
+	ended     bool
 	waitGroup sync.WaitGroup
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -269,7 +270,11 @@ func (bh *AZBlobBackupHandle) EndBackup(ctx context.Context) error {
 		return errors.New("EndBackup cannot be called on read-only backup")
 	}
 	bh.waitGroup.Wait()
-	return bh.Error()
+	if err := bh.Error(); err != nil {
+		return err
+	}
+	bh.ended = true
+	return nil
 }
 
 // AbortBackup implements BackupHandle.
@@ -286,8 +291,8 @@ func (bh *AZBlobBackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile implements BackupHandle.
 func (bh *AZBlobBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
-	if !bh.readOnly {
-		return nil, errors.New("ReadFile cannot be called on read-write backup")
+	if !bh.readOnly && !bh.ended {
+		return nil, errors.New("ReadFile cannot be called on read-write backup before EndBackup")
 	}
 
 	obj := objName(bh.dir, filename)
@@ -388,6 +393,12 @@ func (bs *AZBlobBackupStorage) StartBackup(ctx context.Context, dir, name string
 	}, nil
 }
 
+// ReopenBackup implements BackupStorage. Blobs are added to an existing
+// backup as to a new one.
+func (bs *AZBlobBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *AZBlobBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	log.Info(fmt.Sprintf("ListBackups: [azblob] container: %s, directory: %s", containerName, objName(dir, "")))
@@ -22,8 +22,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -150,12 +152,6 @@ func Backup(ctx context.Context, params BackupParams) error {
 		Stats:  bsStats,
 	})
 
-	bh, err := bs.StartBackup(ctx, backupDir, name)
-	if err != nil {
-		return vterrors.Wrap(err, "StartBackup failed")
-	}
-	params.Logger.Infof("Starting backup %v", bh.Name())
-
 	// Scope stats to selected backup engine.
 	beParams := params.Copy()
 	beParams.Stats = params.Stats.Scope(
@@ -174,6 +170,25 @@ func Backup(ctx context.Context, params BackupParams) error {
 		}
 	}
 
+	// Resume the interrupted backup of the tablet, if any.
+	var resumed string
+	if be.Name() == builtinBackupEngineName && builtinBackupResume {
+		if resumed, err = findBackupToResume(ctx, bs, backupDir, params); err != nil {
+			return vterrors.Wrap(err, "failed to find a backup to resume")
+		}
+	}
+
+	var bh backupstorage.BackupHandle
+	if resumed != "" {
+		params.Logger.Infof("Resuming interrupted backup %v", resumed)
+		if bh, err = bs.ReopenBackup(ctx, backupDir, resumed); err != nil {
+			return vterrors.Wrap(err, "ReopenBackup failed")
+		}
+	} else if bh, err = bs.StartBackup(ctx, backupDir, name); err != nil {
+		return vterrors.Wrap(err, "StartBackup failed")
+	}
+	params.Logger.Infof("Starting backup %v", bh.Name())
+
 	params.Logger.Infof("Using backup engine %q", be.Name())
 
 	// Take the backup, and either AbortBackup or EndBackup.
@@ -267,8 +282,9 @@ func checkNoDB(ctx context.Context, mysqld MysqlDaemon, dbName string) (bool, er
 // removeExistingFiles will delete existing files in the data dir to prevent
 // conflicts with the restored archive. In particular, binlogs can be created
 // even during initial bootstrap, and these can interfere with configuring
-// replication if kept around after the restore.
-func removeExistingFiles(cnf *Mycnf) error {
+// replication if kept around after the restore. The files in keep are not
+// deleted, when resuming an interrupted restore.
+func removeExistingFiles(cnf *Mycnf, keep map[string]bool) error {
 	paths := map[string]string{
 		"BinLogPath.*":          cnf.BinLogPath,
 		"DataDir":               cnf.DataDir,
@@ -293,6 +309,9 @@ func removeExistingFiles(cnf *Mycnf) error {
 				return vterrors.Wrapf(err, "can't expand path glob %q", path)
 			}
 			for _, match := range matches {
+				if keep[match] {
+					continue
+				}
 				if err := os.Remove(match); err != nil {
 					return vterrors.Wrapf(err, "can't remove existing file from %v (%v)", name, match)
 				}
@@ -306,6 +325,12 @@ func removeExistingFiles(cnf *Mycnf) error {
 			continue
 		}
 		log.Info(fmt.Sprintf("Restore: removing files in %v (%v)", name, path))
+		if len(keep) > 0 {
+			if err := removeFilesExcept(path, keep); err != nil {
+				return vterrors.Wrapf(err, "can't remove existing files in %v (%v)", name, path)
+			}
+			continue
+		}
 		if err := os.RemoveAll(path); err != nil {
 			return vterrors.Wrapf(err, "can't remove existing files in %v (%v)", name, path)
 		}
@@ -313,6 +338,23 @@ func removeExistingFiles(cnf *Mycnf) error {
 	return nil
 }
 
+// removeFilesExcept deletes the files in a directory, recursively, except the
+// ones in keep.
+func removeFilesExcept(dir string, keep map[string]bool) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || keep[p] {
+			return nil
+		}
+		return os.Remove(p)
+	})
+}
+
 // ShouldRestore checks whether a database with tables already exists
 // and returns whether a restore action should be performed
 func ShouldRestore(ctx context.Context, logger logutil.Logger, cnf *Mycnf, mysqld MysqlDaemon,
@@ -403,6 +445,13 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	if err != nil {
 		return nil, vterrors.Wrap(err, "ListBackups failed")
 	}
+	// The other backups are kept, since a point in time recovery from the named
+	// backup uses the incremental backups taken after it.
+	if params.BackupName != "" && !slices.ContainsFunc(bhs, func(bh backupstorage.BackupHandle) bool {
+		return bh.Name() == params.BackupName
+	}) {
+		return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "could not find backup %q in %s", params.BackupName, backupDir)
+	}
 
 	if len(bhs) == 0 {
 		// There are no backups (not even broken/incomplete ones).
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// retainedBackup is a backup considered by a backup retention policy.
+type retainedBackup struct {
+	name string
+	// time is the time at which the backup was taken, from its MANIFEST or else
+	// from its name, or nil if it cannot be parsed from either.
+	time *time.Time
+	// manifest is nil if the backup is incomplete.
+	manifest *BackupManifest
+}
+
+func (b *retainedBackup) isFull() bool {
+	return b.manifest != nil && !b.manifest.Incremental
+}
+
+func (b *retainedBackup) isIncremental() bool {
+	return b.manifest != nil && b.manifest.Incremental
+}
+
+// BackupRetentionPolicyIsEmpty returns true if the policy does not retain
+// any backups on its own, in which case backups must not be pruned.
+func BackupRetentionPolicyIsEmpty(policy *topodatapb.BackupRetentionPolicy) bool {
+	return policy.GetKeepFullBackups() == 0 && policy.GetKeepNewerThan() == nil && policy.GetPointInTimeRecoveryWindow() == nil
+}
+
+// FindBackupsToPrune returns the backups, as returned by ListBackups for a
+// single shard, which are not kept by the backup retention policy at the
+// given time. Incremental backups which are kept also keep the backups they
+// depend on, and the most recent complete full backup is always kept along
+// with the incremental backups taken after it.
+func FindBackupsToPrune(ctx context.Context, dir string, bhs []backupstorage.BackupHandle, policy *topodatapb.BackupRetentionPolicy, now time.Time) ([]backupstorage.BackupHandle, error) {
+	if BackupRetentionPolicyIsEmpty(policy) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "backup retention policy for %v is empty", dir)
+	}
+
+	backups := make([]*retainedBackup, 0, len(bhs))
+	for _, bh := range bhs {
+		backupTime, _, err := ParseBackupName(dir, bh.Name())
+		if err != nil {
+			backupTime = nil
+		}
+		manifest, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Info(fmt.Sprintf("Considering backup %v/%v as incomplete: %v", dir, bh.Name(), err))
+			manifest = nil
+		} else if manifestTime, err := ParseRFC3339(manifest.BackupTime); err == nil {
+			// A resumed backup keeps the name of the interrupted backup, and its
+			// MANIFEST has the time at which it was resumed.
+			backupTime = &manifestTime
+		}
+		backups = append(backups, &retainedBackup{name: bh.Name(), time: backupTime, manifest: manifest})
+	}
+
+	prune, err := selectBackupsToPrune(backups, policy, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []backupstorage.BackupHandle
+	for i, bh := range bhs {
+		if prune[i] {
+			pruned = append(pruned, bh)
+		}
+	}
+	return pruned, nil
+}
+
+// selectBackupsToPrune returns, for each backup, whether it is not kept by
+// the policy at the given time.
+func selectBackupsToPrune(backups []*retainedBackup, policy *topodatapb.BackupRetentionPolicy, now time.Time) ([]bool, error) {
+	keepNewerThan, _, err := protoutil.DurationFromProto(policy.GetKeepNewerThan())
+	if err != nil {
+		return nil, vterrors.Wrap(err, "invalid keep_newer_than")
+	}
+	pitrWindow, _, err := protoutil.DurationFromProto(policy.GetPointInTimeRecoveryWindow())
+	if err != nil {
+		return nil, vterrors.Wrap(err, "invalid point_in_time_recovery_window")
+	}
+
+	// Backups whose time is unknown cannot be ordered, so they are always kept
+	// and otherwise ignored.
+	var ordered []int
+	keep := make([]bool, len(backups))
+	for i, b := range backups {
+		if b.time == nil {
+			keep[i] = true
+			continue
+		}
+		ordered = append(ordered, i)
+	}
+	slices.SortStableFunc(ordered, func(a, b int) int {
+		return backups[a].time.Compare(*backups[b].time)
+	})
+
+	var fulls []int
+	for _, i := range ordered {
+		if backups[i].isFull() {
+			fulls = append(fulls, i)
+		}
+	}
+
+	// The most recent complete full backups, and the incremental backups taken
+	// after the most recent one.
+	keepFulls := max(int(policy.GetKeepFullBackups()), 1)
+	for _, i := range fulls[max(len(fulls)-keepFulls, 0):] {
+		keep[i] = true
+	}
+	for _, i := range ordered {
+		if backups[i].isIncremental() && (len(fulls) == 0 || backups[i].time.After(*backups[fulls[len(fulls)-1]].time)) {
+			keep[i] = true
+		}
+	}
+
+	// Everything newer than the keep_newer_than duration, including backups
+	// which may still be in progress.
+	if keepNewerThan > 0 {
+		cutoff := now.Add(-keepNewerThan)
+		for _, i := range ordered {
+			if backups[i].time.After(cutoff) {
+				keep[i] = true
+			}
+		}
+	}
+
+	// The latest full backup taken before the start of the point in time
+	// recovery window, and every incremental backup after it.
+	if pitrWindow > 0 && len(fulls) > 0 {
+		cutoff := now.Add(-pitrWindow)
+		base := fulls[0]
+		for _, i := range fulls {
+			if backups[i].time.After(cutoff) {
+				break
+			}
+			base = i
+		}
+		keep[base] = true
+		for _, i := range ordered {
+			if backups[i].isIncremental() && backups[i].time.After(*backups[base].time) {
+				keep[i] = true
+			}
+		}
+	}
+
+	// Kept incremental backups keep the backups they depend on. Since the
+	// restore path is computed from positions, the latest full backup taken
+	// before an incremental backup and every incremental backup in between are
+	// kept, as well as the backup it was explicitly taken from.
+	byName := make(map[string]int, len(backups))
+	for _, i := range ordered {
+		byName[backups[i].name] = i
+	}
+	for pos := len(ordered) - 1; pos >= 0; pos-- {
+		i := ordered[pos]
+		if !keep[i] || !backups[i].isIncremental() {
+			continue
+		}
+		if from, ok := byName[backups[i].manifest.FromBackup]; ok {
+			keep[from] = true
+		}
+		for prev := pos - 1; prev >= 0; prev-- {
+			j := ordered[prev]
+			if backups[j].isFull() {
+				keep[j] = true
+				break
+			}
+			if backups[j].isIncremental() {
+				keep[j] = true
+			}
+		}
+	}
+
+	// Incomplete backups are only pruned once a newer complete backup exists,
+	// so that backups which are still in progress are not removed.
+	newestComplete := -1
+	for pos, i := range ordered {
+		if backups[i].manifest != nil {
+			newestComplete = pos
+		}
+	}
+	prune := make([]bool, len(backups))
+	for pos, i := range ordered {
+		if keep[i] {
+			continue
+		}
+		if backups[i].manifest == nil && pos > newestComplete {
+			continue
+		}
+		prune[i] = true
+	}
+	return prune, nil
+}
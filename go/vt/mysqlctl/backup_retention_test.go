@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestSelectBackupsToPrune(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	backup := func(name string, age time.Duration, manifest *BackupManifest) *retainedBackup {
+		backupTime := now.Add(-age)
+		return &retainedBackup{name: name, time: &backupTime, manifest: manifest}
+	}
+	full := &BackupManifest{}
+	incremental := func(from string) *BackupManifest {
+		return &BackupManifest{Incremental: true, FromBackup: from}
+	}
+	backups := []*retainedBackup{
+		backup("full-10", 10*day, full),
+		backup("inc-9", 9*day, incremental("full-10")),
+		backup("failed-8", 8*day, nil),
+		backup("full-7", 7*day, full),
+		backup("inc-6", 6*day, incremental("")),
+		backup("inc-5", 5*day, incremental("")),
+		backup("full-4", 4*day, full),
+		backup("inc-3", 3*day, incremental("full-4")),
+		backup("full-2", 2*day, full),
+		backup("inc-1", day, incremental("")),
+		{name: "unparseable", manifest: full},
+		backup("in-progress", time.Hour, nil),
+	}
+
+	tcs := []struct {
+		name   string
+		policy *topodatapb.BackupRetentionPolicy
+		pruned []string
+	}{
+		{
+			name:   "latest full backup",
+			policy: &topodatapb.BackupRetentionPolicy{KeepFullBackups: 1},
+			pruned: []string{"full-10", "inc-9", "failed-8", "full-7", "inc-6", "inc-5", "full-4", "inc-3"},
+		},
+		{
+			name:   "full backups",
+			policy: &topodatapb.BackupRetentionPolicy{KeepFullBackups: 3},
+			pruned: []string{"full-10", "inc-9", "failed-8", "inc-6", "inc-5", "inc-3"},
+		},
+		{
+			name:   "newer than",
+			policy: &topodatapb.BackupRetentionPolicy{KeepNewerThan: protoutil.DurationToProto(90 * time.Hour)},
+			pruned: []string{"full-10", "inc-9", "failed-8", "full-7", "inc-6", "inc-5"},
+		},
+		{
+			name:   "newer than keeps the base of incremental backups",
+			policy: &topodatapb.BackupRetentionPolicy{KeepNewerThan: protoutil.DurationToProto(5*day + time.Hour)},
+			pruned: []string{"full-10", "inc-9", "failed-8"},
+		},
+		{
+			name:   "point in time recovery window",
+			policy: &topodatapb.BackupRetentionPolicy{PointInTimeRecoveryWindow: protoutil.DurationToProto(5 * day)},
+			pruned: []string{"full-10", "inc-9", "failed-8"},
+		},
+		{
+			name:   "point in time recovery window older than every backup",
+			policy: &topodatapb.BackupRetentionPolicy{PointInTimeRecoveryWindow: protoutil.DurationToProto(30 * day)},
+			pruned: []string{"failed-8"},
+		},
+		{
+			name: "combined",
+			policy: &topodatapb.BackupRetentionPolicy{
+				KeepFullBackups:           4,
+				PointInTimeRecoveryWindow: protoutil.DurationToProto(3 * day),
+			},
+			pruned: []string{"inc-9", "failed-8", "inc-6", "inc-5"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			prune, err := selectBackupsToPrune(backups, tc.policy, now)
+			require.NoError(t, err)
+			var pruned []string
+			for i, b := range backups {
+				if prune[i] {
+					pruned = append(pruned, b.name)
+				}
+			}
+			assert.Equal(t, tc.pruned, pruned)
+		})
+	}
+}
+
+func TestFindBackupsToPrune(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+	now := time.Now().UTC()
+	name := func(age time.Duration) string {
+		return now.Add(-age).Format(BackupTimestampFormat) + ".zone1-0000000100"
+	}
+	day := 24 * time.Hour
+
+	writeChunkedBackup(t, bs, "ks/-80", name(3*day), &builtinBackupManifest{})
+	writeChunkedBackup(t, bs, "ks/-80", name(2*day), nil)
+	writeChunkedBackup(t, bs, "ks/-80", name(day), &builtinBackupManifest{})
+	bhs, err := bs.ListBackups(ctx, "ks/-80")
+	require.NoError(t, err)
+
+	_, err = FindBackupsToPrune(ctx, "ks/-80", bhs, &topodatapb.BackupRetentionPolicy{}, now)
+	assert.ErrorContains(t, err, "backup retention policy for ks/-80 is empty")
+
+	pruned, err := FindBackupsToPrune(ctx, "ks/-80", bhs, &topodatapb.BackupRetentionPolicy{KeepFullBackups: 1}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []backupstorage.BackupHandle{bhs[0], bhs[1]}, pruned)
+
+	// A backup resumed after the most recent one is ordered by the time in its
+	// MANIFEST, rather than by the time in its name.
+	writeChunkedBackup(t, bs, "ks/-80", name(4*day), &builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupTime: FormatRFC3339(now.Add(-time.Hour))},
+	})
+	bhs, err = bs.ListBackups(ctx, "ks/-80")
+	require.NoError(t, err)
+	pruned, err = FindBackupsToPrune(ctx, "ks/-80", bhs, &topodatapb.BackupRetentionPolicy{KeepFullBackups: 1}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []backupstorage.BackupHandle{bhs[1], bhs[2], bhs[3]}, pruned)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// The bandwidth used by builtin backups and restores to transfer files to and from
+// the backup storage can be limited for each tablet, with
+// --builtinbackup-bandwidth-limit, and for all the tablets of a cell, with
+// the SetCellBackupBandwidthLimit vtctld command.
+//
+// The limit of a cell is stored along with a token bucket in the topology server
+// of the cell, and the bucket holds at most cellBandwidthBurst of transfers.
+// Tablets take grants of cellBandwidthGrant of transfers from the bucket, so that
+// they share the bandwidth of the cell while updating the bucket about twice per
+// second in total, whatever their number. A tablet whose cell is not limited only
+// reads the bucket every cellBandwidthLimitRefresh.
+
+var (
+	// builtinBackupBandwidthLimit is the maximum number of bytes per second transferred
+	// by the backups and restores of a tablet.
+	builtinBackupBandwidthLimit int64
+)
+
+const (
+	// cellBandwidthGrant is the duration of the transfers covered by each grant of
+	// tokens taken from the bucket of a cell.
+	cellBandwidthGrant = 500 * time.Millisecond
+
+	// cellBandwidthBurst is the duration of the transfers held by a full bucket.
+	cellBandwidthBurst = 2 * time.Second
+
+	// cellBandwidthMinWait is the minimum time to wait for the bucket of a cell to
+	// be refilled.
+	cellBandwidthMinWait = 10 * time.Millisecond
+
+	// cellBandwidthLimitRefresh is how often a tablet reads the limit of its cell,
+	// while the cell is not limited.
+	cellBandwidthLimitRefresh = 30 * time.Second
+)
+
+var (
+	bandwidthLimitersMu sync.Mutex
+	// bandwidthLimiters are the bandwidth limiters of the tablets, by alias, so that
+	// concurrent backups and restores of a tablet share its limit.
+	bandwidthLimiters = make(map[string]*bandwidthLimiter)
+)
+
+// bandwidthLimiter limits the bandwidth used by the backups and restores of a tablet.
+type bandwidthLimiter struct {
+	tablet *rate.Limiter
+	cell   *cellBandwidth
+}
+
+// getBandwidthLimiter returns the bandwidth limiter of a tablet, or nil if the
+// bandwidth of its backups and restores cannot be limited. The limit of the cell
+// is only enforced if the alias of the tablet and the topology server are known.
+func getBandwidthLimiter(ts *topo.Server, tabletAlias string) *bandwidthLimiter {
+	bandwidthLimitersMu.Lock()
+	defer bandwidthLimitersMu.Unlock()
+	if bl, ok := bandwidthLimiters[tabletAlias]; ok {
+		return bl
+	}
+
+	bl := &bandwidthLimiter{}
+	if builtinBackupBandwidthLimit > 0 {
+		bl.tablet = rate.NewLimiter(rate.Limit(builtinBackupBandwidthLimit), int(builtinBackupBandwidthLimit))
+	}
+	if alias, err := topoproto.ParseTabletAlias(tabletAlias); err == nil && ts != nil {
+		bl.cell = &cellBandwidth{ts: ts, cell: alias.Cell}
+	}
+	if bl.tablet == nil && bl.cell == nil {
+		bl = nil
+	}
+	bandwidthLimiters[tabletAlias] = bl
+	return bl
+}
+
+// wait blocks until n bytes can be transferred.
+func (bl *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	if bl.tablet != nil {
+		// WaitN fails for more bytes than the burst of the limiter.
+		for remaining := n; remaining > 0; {
+			m := min(remaining, bl.tablet.Burst())
+			if err := bl.tablet.WaitN(ctx, m); err != nil {
+				return err
+			}
+			remaining -= m
+		}
+	}
+	if bl.cell != nil {
+		return bl.cell.wait(ctx, int64(n))
+	}
+	return nil
+}
+
+// reader returns a reader whose reads are limited by the bandwidth limiter.
+func (bl *bandwidthLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if bl == nil {
+		return r
+	}
+	return &bandwidthLimitedReader{ctx: ctx, r: r, bl: bl}
+}
+
+// writer returns a writer whose writes are limited by the bandwidth limiter.
+func (bl *bandwidthLimiter) writer(ctx context.Context, w io.WriteCloser) io.WriteCloser {
+	if bl == nil {
+		return w
+	}
+	return &bandwidthLimitedWriter{ctx: ctx, WriteCloser: w, bl: bl}
+}
+
+type bandwidthLimitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	bl  *bandwidthLimiter
+}
+
+func (r *bandwidthLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.bl.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type bandwidthLimitedWriter struct {
+	io.WriteCloser
+	ctx context.Context
+	bl  *bandwidthLimiter
+}
+
+func (w *bandwidthLimitedWriter) Write(p []byte) (int, error) {
+	if err := w.bl.wait(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.WriteCloser.Write(p)
+}
+
+// cellBandwidth takes tokens from the bucket of a cell.
+type cellBandwidth struct {
+	ts   *topo.Server
+	cell string
+
+	mu sync.Mutex
+	// limit is the limit of the cell, as of checkedAt.
+	limit     int64
+	checkedAt time.Time
+	// credit is the number of tokens taken from the bucket and not used yet.
+	credit int64
+}
+
+// wait blocks until n bytes can be transferred. If the bucket cannot be read or
+// updated, the limit of the cell is enforced for the tablet only, rather than failing
+// the transfer.
+func (cb *cellBandwidth) wait(ctx context.Context, n int64) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.limit <= 0 && time.Since(cb.checkedAt) < cellBandwidthLimitRefresh {
+		return nil
+	}
+	for cb.credit < n {
+		grant := max(int64(float64(cb.limit)*cellBandwidthGrant.Seconds()), 1)
+		want := max(n-cb.credit, grant)
+		taken, wait, err := cb.take(ctx, want)
+		switch {
+		case err == nil:
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case topo.IsErrType(err, topo.BadVersion), topo.IsErrType(err, topo.NodeExists):
+			// The other tablets of the cell are updating the bucket, and hold the
+			// tokens for now.
+			wait = cellBandwidthGrant
+		case cb.limit > 0:
+			log.Warn(fmt.Sprintf("Failed to take tokens from the backup bandwidth bucket of cell %v: %v", cb.cell, err))
+			taken, wait = want, time.Duration(float64(want)/float64(cb.limit)*float64(time.Second))
+		default:
+			log.Warn(fmt.Sprintf("Failed to read the backup bandwidth limit of cell %v: %v", cb.cell, err))
+		}
+		if cb.limit <= 0 {
+			cb.credit = 0
+			return nil
+		}
+		cb.credit += taken
+		if wait <= 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(max(wait, cellBandwidthMinWait)):
+		}
+	}
+	cb.credit -= n
+	return nil
+}
+
+// take takes up to want tokens from the bucket, and updates the limit of the cell.
+// If the bucket is empty, it returns how long to wait for it to be refilled with
+// want tokens.
+func (cb *cellBandwidth) take(ctx context.Context, want int64) (taken int64, wait time.Duration, err error) {
+	cb.checkedAt = time.Now()
+	err = cb.ts.UpdateBackupBandwidthBucket(ctx, cb.cell, func(bucket *topodatapb.BackupBandwidthBucket) error {
+		now := time.Now()
+		cb.limit = bucket.Limit
+		if bucket.Limit <= 0 {
+			taken, wait = 0, 0
+			return topo.NewError(topo.NoUpdateNeeded, cb.cell)
+		}
+		// A new bucket is full.
+		capacity := int64(cellBandwidthBurst.Seconds() * float64(bucket.Limit))
+		tokens := capacity
+		if bucket.RefilledAt != nil {
+			elapsed := max(now.Sub(protoutil.TimeFromProto(bucket.RefilledAt)), 0)
+			tokens = min(bucket.Tokens+int64(elapsed.Seconds()*float64(bucket.Limit)), capacity)
+		}
+		if tokens <= 0 {
+			taken = 0
+			wait = time.Duration(float64(min(want, capacity)-tokens) / float64(bucket.Limit) * float64(time.Second))
+			return topo.NewError(topo.NoUpdateNeeded, cb.cell)
+		}
+		taken, wait = min(want, tokens), 0
+		bucket.Tokens = tokens - taken
+		bucket.RefilledAt = protoutil.TimeToProto(now)
+		return nil
+	})
+	return taken, wait, err
+}
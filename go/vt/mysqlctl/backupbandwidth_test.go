@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const testBandwidthLimit = 1024 * 1024
+
+// setBandwidthLimit sets the bandwidth limit of the tablets for the duration of the test.
+func setBandwidthLimit(t *testing.T, limit int64) {
+	oldLimit := builtinBackupBandwidthLimit
+	clearLimiters := func() {
+		bandwidthLimitersMu.Lock()
+		defer bandwidthLimitersMu.Unlock()
+		clear(bandwidthLimiters)
+	}
+	t.Cleanup(func() {
+		builtinBackupBandwidthLimit = oldLimit
+		clearLimiters()
+	})
+	builtinBackupBandwidthLimit = limit
+	clearLimiters()
+}
+
+// setCellBandwidthLimit sets the bandwidth limit of a cell, and empties its bucket.
+func setCellBandwidthLimit(t *testing.T, ts *topo.Server, cell string, limit int64) {
+	require.NoError(t, ts.UpdateBackupBandwidthBucket(context.Background(), cell, func(bucket *topodatapb.BackupBandwidthBucket) error {
+		bucket.Limit = limit
+		bucket.Tokens = 0
+		bucket.RefilledAt = protoutil.TimeToProto(time.Now())
+		return nil
+	}))
+}
+
+func TestTabletBandwidthLimit(t *testing.T) {
+	ctx := context.Background()
+	setBandwidthLimit(t, 0)
+	assert.Nil(t, getBandwidthLimiter(nil, "zone1-0000000100"))
+
+	setBandwidthLimit(t, testBandwidthLimit)
+	bl := getBandwidthLimiter(nil, "zone1-0000000100")
+	require.NotNil(t, bl)
+	assert.Nil(t, bl.cell)
+	assert.Same(t, bl, getBandwidthLimiter(nil, "zone1-0000000100"))
+	assert.NotSame(t, bl, getBandwidthLimiter(nil, "zone1-0000000101"))
+
+	// The first second of transfers is allowed at once, and the next half second is limited.
+	data := make([]byte, testBandwidthLimit*3/2)
+	start := time.Now()
+	n, err := io.Copy(io.Discard, bl.reader(ctx, bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.EqualValues(t, len(data), n)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	// A write waits for the bandwidth to be available, and fails if the context is canceled.
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = bl.writer(ctx, nopWriteCloser{io.Discard}).Write(data)
+	assert.Error(t, err)
+}
+
+func TestCellBandwidthLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+
+	setBandwidthLimit(t, 0)
+	assert.Nil(t, getBandwidthLimiter(nil, "zone1-0000000100"))
+	setCellBandwidthLimit(t, ts, "zone1", testBandwidthLimit)
+
+	// Two tablets of the cell share its bandwidth, while the tablet of another cell
+	// is not limited.
+	data := make([]byte, testBandwidthLimit/2)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, alias := range []string{"zone1-0000000101", "zone1-0000000102"} {
+		bl := getBandwidthLimiter(ts, alias)
+		require.NotNil(t, bl.cell)
+		assert.Equal(t, "zone1", bl.cell.cell)
+		wg.Go(func() {
+			_, err := io.Copy(io.Discard, bl.reader(ctx, bytes.NewReader(data)))
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 800*time.Millisecond)
+
+	start = time.Now()
+	_, err := io.Copy(io.Discard, getBandwidthLimiter(ts, "zone2-0000000100").reader(ctx, bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	_, err = ts.GetBackupBandwidthBucket(ctx, "zone2")
+	assert.True(t, topo.IsErrType(err, topo.NoNode))
+
+	// The limit of the cell is removed as soon as a tablet takes tokens from the
+	// bucket, and is only read again after a while.
+	bl := getBandwidthLimiter(ts, "zone1-0000000101")
+	setCellBandwidthLimit(t, ts, "zone1", 0)
+	start = time.Now()
+	_, err = io.Copy(io.Discard, bl.reader(ctx, bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Zero(t, bl.cell.limit)
+	setCellBandwidthLimit(t, ts, "zone1", testBandwidthLimit)
+	_, err = io.Copy(io.Discard, bl.reader(ctx, bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Zero(t, bl.cell.limit)
+	bl.cell.checkedAt = time.Time{}
+	start = time.Now()
+	_, err = io.Copy(io.Discard, bl.reader(ctx, bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.EqualValues(t, testBandwidthLimit, bl.cell.limit)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -156,6 +156,14 @@ type RestoreParams struct {
 	MysqlShutdownTimeout time.Duration
 	// AllowedBackupEngines if present will filter out any backups taken with engines not included in the list
 	AllowedBackupEngines []string
+	// BackupName, if set, is the name of the only full backup considered for the
+	// restore. A point in time recovery then applies the incremental backups taken
+	// after it.
+	BackupName string
+	// TopoServer and TabletAlias are used to limit the bandwidth of the restore
+	// across the tablets of the cell.
+	TopoServer  *topo.Server
+	TabletAlias string
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -175,6 +183,9 @@ func (p *RestoreParams) Copy() RestoreParams {
 		DryRun:               p.DryRun,
 		Stats:                p.Stats,
 		MysqlShutdownTimeout: p.MysqlShutdownTimeout,
+		BackupName:           p.BackupName,
+		TopoServer:           p.TopoServer,
+		TabletAlias:          p.TabletAlias,
 	}
 }
 
@@ -320,7 +331,9 @@ type BackupManifest struct {
 	// Incremental indicates whether this is an incremental backup
 	Incremental bool
 
-	// BackupTime is when the backup was taken in UTC time (RFC 3339 format)
+	// BackupTime is when the backup was taken in UTC time (RFC 3339 format).
+	// A resumed backup keeps the name of the interrupted backup, but its
+	// BackupTime is the time at which it was resumed.
 	BackupTime string
 
 	// FinishedTime is the time (in RFC 3339 format, UTC) at which the backup finished, if known.
@@ -528,12 +541,21 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 
 	// Let's first populate the manifests
 	for i, bh := range bhs {
+		pinned := params.BackupName == "" || bh.Name() == params.BackupName
+		if !pinned && !params.IsIncrementalRecovery() {
+			continue
+		}
 		// Check that the backup MANIFEST exists and can be successfully decoded.
 		bm, err := GetBackupManifest(ctx, bh)
 		if err != nil {
 			params.Logger.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v)", bh.Name(), backupDir, err)
 			continue
 		}
+		if !pinned && !bm.Incremental {
+			// Only the named full backup is restored, along with the incremental
+			// backups of a point in time recovery.
+			continue
+		}
 
 		// if allowed backup engine is not empty, we only try to restore from backups taken with the specified backup engines
 		if len(params.AllowedBackupEngines) > 0 && !slices.Contains(params.AllowedBackupEngines, bm.BackupMethod) {
@@ -545,6 +567,10 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 		manifests[i] = bm // manifests's order is insignificant, it will be sorted later on
 		manifestHandleMap.Map(bm, bh)
 	}
+	// The backups are listed in the order of the time in their names, which a resumed
+	// backup does not update, so the full backups are searched in the order of the
+	// time in their MANIFEST.
+	sortManifestsByBackupTime(manifests)
 	restorePath = &RestorePath{
 		manifestHandleMap: manifestHandleMap,
 	}
@@ -619,6 +645,24 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 	return restorePath, nil
 }
 
+// sortManifestsByBackupTime sorts the manifests by their BackupTime. Missing manifests,
+// and the ones whose BackupTime cannot be parsed, come first.
+func sortManifestsByBackupTime(manifests []*BackupManifest) {
+	backupTime := func(bm *BackupManifest) time.Time {
+		if bm == nil {
+			return time.Time{}
+		}
+		t, err := ParseRFC3339(bm.BackupTime)
+		if err != nil {
+			return time.Time{}
+		}
+		return t
+	}
+	slices.SortStableFunc(manifests, func(a, b *BackupManifest) int {
+		return backupTime(a).Compare(backupTime(b))
+	})
+}
+
 // See https://github.com/mysql/mysql-server/commit/9a940abe085fc75e1ffe7b72286927fdc9f11207 for the
 // importance of this specific version and why downgrades within patches are allowed since that version.
 var (
@@ -707,15 +751,26 @@ func validateMySQLVersionUpgradeCompatible(to string, from string, upgradeSafe b
 	return fmt.Errorf("running MySQL version %q is newer than backup MySQL version %q which is not safe to upgrade", to, from)
 }
 
-func prepareToRestore(ctx context.Context, cnf *Mycnf, mysqld MysqlDaemon, logger logutil.Logger, mysqlShutdownTimeout time.Duration) error {
+// prepareToRestore shuts down mysqld and deletes the existing files. When resuming
+// an interrupted restore, keepFiles is called once mysqld is shut down, and returns
+// the files which are not deleted.
+func prepareToRestore(ctx context.Context, cnf *Mycnf, mysqld MysqlDaemon, logger logutil.Logger, mysqlShutdownTimeout time.Duration, keepFiles func() (map[string]bool, error)) error {
 	// shutdown mysqld if it is running
 	logger.Infof("Restore: shutdown mysqld")
 	if err := mysqld.Shutdown(ctx, cnf, true, mysqlShutdownTimeout); err != nil {
 		return err
 	}
 
+	var keep map[string]bool
+	if keepFiles != nil {
+		var err error
+		if keep, err = keepFiles(); err != nil {
+			return err
+		}
+	}
+
 	logger.Infof("Restore: deleting existing files")
-	if err := removeExistingFiles(cnf); err != nil {
+	if err := removeExistingFiles(cnf, keep); err != nil {
 		return err
 	}
 
@@ -750,6 +805,9 @@ func removeStateFile(cnf *Mycnf) error {
 	if err := os.Remove(fname); err != nil {
 		return fmt.Errorf("unable to delete file: %v", err)
 	}
+	if err := removeRestoreProgress(cnf); err != nil {
+		return fmt.Errorf("unable to delete restore progress: %v", err)
+	}
 	return nil
 }
 
@@ -1,9 +1,17 @@
 package mysqlctl
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 func TestValidateMySQLVersionUpgradeCompatible(t *testing.T) {
@@ -212,3 +220,70 @@ func TestValidateMySQLVersionUpgradeCompatible(t *testing.T) {
 		})
 	}
 }
+
+func TestSortManifestsByBackupTime(t *testing.T) {
+	resumed := &BackupManifest{BackupName: "2026-10-17.000000.zone1-0000000100", BackupTime: "2026-10-19T00:00:00Z"}
+	previous := &BackupManifest{BackupName: "2026-10-18.000000.zone1-0000000100", BackupTime: "2026-10-18T00:00:00Z"}
+	invalid := &BackupManifest{BackupName: "2026-10-18.120000.zone1-0000000100"}
+	manifests := []*BackupManifest{resumed, nil, previous, invalid}
+
+	sortManifestsByBackupTime(manifests)
+	assert.Equal(t, []*BackupManifest{nil, invalid, previous, resumed}, manifests)
+}
+
+func TestRestoreBackupName(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+	position := func(gtids string) replication.Position {
+		return replication.MustParsePosition(replication.Mysql56FlavorID, "16b1039f-22b6-11ed-b765-0a43f95f28a3:"+gtids)
+	}
+	writeBackup := func(name, pos, fromPos string) {
+		bm := &builtinBackupManifest{BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName, Position: position(pos)}}
+		if fromPos != "" {
+			bm.Incremental = true
+			bm.FromPosition = position(fromPos)
+		}
+		writeChunkedBackup(t, bs, "ks/-80", name, bm)
+	}
+	writeBackup("2026-10-15.000000.zone1-0000000100", "1-50", "")
+	writeBackup("2026-10-16.000000.zone1-0000000100", "1-70", "1-50")
+	writeBackup("2026-10-17.000000.zone1-0000000100", "1-80", "")
+	writeBackup("2026-10-18.000000.zone1-0000000100", "1-90", "1-70")
+
+	restore := func(backupName, restoreToPos string) (string, error) {
+		logger := logutil.NewMemoryLogger()
+		params := RestoreParams{
+			Mysqld:     NewFakeMysqlDaemon(nil),
+			Logger:     logger,
+			Keyspace:   "ks",
+			Shard:      "-80",
+			BackupName: backupName,
+			DryRun:     true,
+		}
+		if restoreToPos != "" {
+			params.RestoreToPos = position(restoreToPos)
+		}
+		_, err := Restore(ctx, params)
+		return logger.String(), err
+	}
+
+	logs, err := restore("", "1-85")
+	require.NoError(t, err)
+	assert.Contains(t, logs, "RestorePath: [full:2026-10-17.000000.zone1-0000000100, incremental:2026-10-18.000000.zone1-0000000100]")
+
+	// The named full backup is restored along with the incremental backups taken after it.
+	logs, err = restore("2026-10-15.000000.zone1-0000000100", "1-85")
+	require.NoError(t, err)
+	assert.Contains(t, logs, "RestorePath: [full:2026-10-15.000000.zone1-0000000100, incremental:2026-10-16.000000.zone1-0000000100, incremental:2026-10-18.000000.zone1-0000000100]")
+
+	logs, err = restore("2026-10-15.000000.zone1-0000000100", "")
+	require.NoError(t, err)
+	assert.Contains(t, logs, "RestorePath: [full:2026-10-15.000000.zone1-0000000100]")
+
+	// An incremental backup cannot be named.
+	_, err = restore("2026-10-16.000000.zone1-0000000100", "1-85")
+	assert.ErrorContains(t, err, "no full backup found before GTID")
+
+	_, err = restore("2026-10-14.000000.zone1-0000000100", "1-85")
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+}
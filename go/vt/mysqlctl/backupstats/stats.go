@@ -66,6 +66,7 @@ var (
 
 	registerBackupStats  sync.Once
 	registerRestoreStats sync.Once
+	registerVerifyStats  sync.Once
 
 	backupBytes       *stats.CountersWithMultiLabels
 	backupCount       *stats.CountersWithMultiLabels
@@ -73,6 +74,9 @@ var (
 	restoreBytes      *stats.CountersWithMultiLabels
 	restoreCount      *stats.CountersWithMultiLabels
 	restoreDurationNs *stats.CountersWithMultiLabels
+	verifyBytes       *stats.CountersWithMultiLabels
+	verifyCount       *stats.CountersWithMultiLabels
+	verifyDurationNs  *stats.CountersWithMultiLabels
 )
 
 // BackupStats creates a new Stats for backup operations.
@@ -137,6 +141,38 @@ func RestoreStats() Stats {
 	return newScopedStats(restoreBytes, restoreCount, restoreDurationNs, nil)
 }
 
+// VerifyStats creates a new Stats for the restores of backup verifications,
+// which are kept apart from the restores of the tablet.
+//
+// It registers the following metrics with the Vitess stats package.
+//
+//   - VerifyBackupBytes: number of bytes processed by an an operation for given
+//     component and implementation.
+//   - VerifyBackupCount: number of times an operation has happened for given
+//     component and implementation.
+//   - VerifyBackupDurationNanoseconds: time spent on an operation for a given
+//     component and implementation.
+func VerifyStats() Stats {
+	registerVerifyStats.Do(func() {
+		verifyBytes = stats.NewCountersWithMultiLabels(
+			"VerifyBackupBytes",
+			"How many bytes processed while verifying backups.",
+			labels,
+		)
+		verifyCount = stats.NewCountersWithMultiLabels(
+			"VerifyBackupCount",
+			"How many backup verification operations have happened.",
+			labels,
+		)
+		verifyDurationNs = stats.NewCountersWithMultiLabels(
+			"VerifyBackupDurationNanoseconds",
+			"How much time has been spent on backup verification operations (in nanoseconds).",
+			labels,
+		)
+	})
+	return newScopedStats(verifyBytes, verifyCount, verifyDurationNs, nil)
+}
+
 // NoStats returns a no-op Stats suitable for tests and for backwards
 // compoatibility.
 func NoStats() Stats {
@@ -46,6 +46,23 @@ func TestRestoreStats(t *testing.T) {
 	require.NotNil(t, restoreDurationNs)
 }
 
+func TestVerifyStats(t *testing.T) {
+	require.Nil(t, restoreBytes)
+	require.Nil(t, restoreCount)
+	require.Nil(t, restoreDurationNs)
+	require.Nil(t, verifyBytes)
+
+	VerifyStats()
+	defer resetStats()
+
+	require.Nil(t, restoreBytes)
+	require.Nil(t, restoreCount)
+	require.Nil(t, restoreDurationNs)
+	require.NotNil(t, verifyBytes)
+	require.NotNil(t, verifyCount)
+	require.NotNil(t, verifyDurationNs)
+}
+
 func TestScope(t *testing.T) {
 	bytes := stats.NewCountersWithMultiLabels("TestScopeBytes", "", labels)
 	count := stats.NewCountersWithMultiLabels("TestScopeCount", "", labels)
@@ -178,4 +195,7 @@ func resetStats() {
 	restoreBytes = nil
 	restoreCount = nil
 	restoreDurationNs = nil
+	verifyBytes = nil
+	verifyCount = nil
+	verifyDurationNs = nil
 }
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// BackupFile is a file of a backup.
+type BackupFile struct {
+	Name string
+	// Hash is the hex encoded CRC-32 (IEEE) of the contents of the file. When it
+	// is set before a copy, the file read from the source must match it.
+	Hash string
+	// Size is the size of the contents of the file.
+	Size int64
+}
+
+// FindBackup returns the read-only handle of the backup with the given name in
+// dir, or a NOT_FOUND error if there is none.
+func FindBackup(ctx context.Context, bs BackupStorage, dir, name string) (BackupHandle, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot list the backups in %v", dir)
+	}
+	for _, bh := range bhs {
+		if bh.Name() == name {
+			return bh, nil
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "could not find backup %q in %v", name, dir)
+}
+
+// CopyBackup copies the files of the read-only backup src into a backup of the
+// same directory and name in dst, then reads them back through the handle of the
+// copy and checks that they have the same hash as in src. The files are copied in
+// order, so the file which marks the backup as complete should be the last one.
+// The copy is removed from dst if it cannot be completed or verified.
+//
+// It returns the files which were copied, with their size and hash.
+func CopyBackup(ctx context.Context, src BackupHandle, dst BackupStorage, files []BackupFile) ([]BackupFile, error) {
+	dir, name := src.Directory(), src.Name()
+	bh, err := dst.StartBackup(ctx, dir, name)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot start backup %v/%v", dir, name)
+	}
+
+	copied, err := copyFiles(ctx, src, bh, files)
+	if err != nil {
+		if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+			err = errors.Join(err, vterrors.Wrapf(abortErr, "cannot abort backup %v/%v", dir, name))
+		}
+		return nil, err
+	}
+	err = bh.EndBackup(ctx)
+	if err != nil {
+		err = vterrors.Wrapf(err, "cannot end backup %v/%v", dir, name)
+	} else {
+		err = verifyCopiedFiles(ctx, bh, copied)
+	}
+	if err != nil {
+		if removeErr := dst.RemoveBackup(ctx, dir, name); removeErr != nil {
+			err = errors.Join(err, vterrors.Wrapf(removeErr, "cannot remove backup %v/%v", dir, name))
+		}
+		return nil, err
+	}
+	return copied, nil
+}
+
+// AddBackupFiles copies files of the read-only backup src into dst, a backup of
+// the same directory and name which was reopened, such as a copy of src made by
+// CopyBackup. It ends dst, then reads the files back through it and checks that
+// they have the same hash as in src. The backup is left in dst if the files
+// cannot be copied.
+//
+// It returns the files which were copied, with their size and hash.
+func AddBackupFiles(ctx context.Context, src, dst BackupHandle, files []BackupFile) ([]BackupFile, error) {
+	copied, err := copyFiles(ctx, src, dst, files)
+	if endErr := dst.EndBackup(ctx); endErr != nil {
+		err = errors.Join(err, vterrors.Wrapf(endErr, "cannot end backup %v/%v", dst.Directory(), dst.Name()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyCopiedFiles(ctx, dst, copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// copyFiles copies files, in order, from the read-only backup src into the
+// read-write backup dst.
+func copyFiles(ctx context.Context, src, dst BackupHandle, files []BackupFile) ([]BackupFile, error) {
+	copied := make([]BackupFile, 0, len(files))
+	for _, file := range files {
+		size, hash, err := copyFile(ctx, src, dst, file.Name)
+		if err == nil && file.Hash != "" && hash != file.Hash {
+			err = vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "hash mismatch for %v, got %v expected %v", file.Name, hash, file.Hash)
+		}
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot copy %v of backup %v/%v", file.Name, src.Directory(), src.Name())
+		}
+		copied = append(copied, BackupFile{Name: file.Name, Hash: hash, Size: size})
+	}
+	return copied, nil
+}
+
+// copyFile copies a file from the read-only backup src into the read-write
+// backup dst, and returns the size and hash of its contents.
+func copyFile(ctx context.Context, src, dst BackupHandle, name string) (size int64, hash string, finalErr error) {
+	reader, err := src.ReadFile(ctx, name)
+	if err != nil {
+		return 0, "", err
+	}
+	defer reader.Close()
+
+	writer, err := dst.AddFile(ctx, name, FileSizeUnknown)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() {
+		if err := writer.Close(); err != nil && finalErr == nil {
+			finalErr = err
+		}
+	}()
+
+	h := crc32.NewIEEE()
+	size, err = io.Copy(io.MultiWriter(writer, h), reader)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyCopiedFiles checks that the files of an ended backup have the expected
+// size and hash. Reading them through the handle which wrote them saves listing
+// the backups of the destination, which can be many, such as chunks.
+func verifyCopiedFiles(ctx context.Context, bh BackupHandle, files []BackupFile) error {
+	dir, name := bh.Directory(), bh.Name()
+	for _, file := range files {
+		size, hash, err := hashFile(ctx, bh, file.Name)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot read %v of backup %v/%v back", file.Name, dir, name)
+		}
+		if size != file.Size || hash != file.Hash {
+			return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "%v of backup %v/%v was corrupted by the copy: got %v bytes with hash %v, expected %v bytes with hash %v",
+				file.Name, dir, name, size, hash, file.Size, file.Hash)
+		}
+	}
+	return nil
+}
+
+// hashFile returns the size and hash of a file of a backup.
+func hashFile(ctx context.Context, bh BackupHandle, name string) (int64, string, error) {
+	reader, err := bh.ReadFile(ctx, name)
+	if err != nil {
+		return 0, "", err
+	}
+	defer reader.Close()
+
+	h := crc32.NewIEEE()
+	size, err := io.Copy(h, reader)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
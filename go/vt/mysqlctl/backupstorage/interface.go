@@ -85,7 +85,9 @@ type BackupHandle interface {
 	AbortBackup(ctx context.Context) error
 
 	// ReadFile starts reading a file from a backup.
-	// Only works for read-only backups (created by ListBackups).
+	// Only works for read-only backups (created by ListBackups), and
+	// for read-write backups once EndBackup succeeded, so that the
+	// files of a backup can be read back after it is written.
 	// The context is valid for the duration of the reads, until the
 	// ReadCloser is closed.
 	ReadFile(ctx context.Context, filename string) (io.ReadCloser, error)
@@ -107,11 +109,18 @@ type BackupStorage interface {
 	// StartBackup creates a new backup with the given name.  If a
 	// backup with the same name already exists, it's an error.
 	// The returned backup is read-write
-	// (AddFile/EndBackup/AbortBackup can all be called, not
-	// ReadFile). The provided context is only valid for that
+	// (AddFile/EndBackup/AbortBackup can all be called, and
+	// ReadFile only once EndBackup succeeded). The provided context is only valid for that
 	// function, and should not be stored by the implementation.
 	StartBackup(ctx context.Context, dir, name string) (BackupHandle, error)
 
+	// ReopenBackup returns a read-write handle on an existing backup, so that
+	// files can be added to it, such as the ones of an interrupted backup
+	// which is resumed. The backup keeps its name. Implementations backed by
+	// object stores, where a backup is a set of objects, may not check that
+	// the backup exists.
+	ReopenBackup(ctx context.Context, dir, name string) (BackupHandle, error)
+
 	// RemoveBackup removes all the data associated with a backup.
 	// It will not appear in ListBackups after RemoveBackup succeeds.
 	RemoveBackup(ctx context.Context, dir, name string) error
@@ -129,6 +138,16 @@ type BackupStorage interface {
 	WithParams(Params) BackupStorage
 }
 
+// Reconciler is implemented by the BackupStorages which copy the backups to
+// other backup storages in the background, such as the tiered backup storage.
+// A copy which was lost, for instance because the process exited before it
+// was done, is made again by Reconcile.
+type Reconciler interface {
+	// Reconcile starts copying, in the background, the complete backups in
+	// dir which are missing from some of the backup storages.
+	Reconcile(ctx context.Context, dir string) error
+}
+
 // BackupStorageMap contains the registered implementations for BackupStorage
 var BackupStorageMap = make(map[string]BackupStorage)
 
@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package memorybackupstorage implements a BackupStorage which keeps its
+// backups in memory, for tests.
+package memorybackupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"sync"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	mysqlctlerrors "vitess.io/vitess/go/vt/mysqlctl/errors"
+)
+
+// MemoryBackupStorage is a BackupStorage which keeps its backups in memory,
+// for tests which need working backups in more than one BackupStorage.
+type MemoryBackupStorage struct {
+	mu sync.Mutex
+	// backups maps the path of a backup to its files.
+	backups map[string]map[string][]byte
+}
+
+// NewMemoryBackupStorage returns an empty MemoryBackupStorage.
+func NewMemoryBackupStorage() *MemoryBackupStorage {
+	return &MemoryBackupStorage{backups: make(map[string]map[string][]byte)}
+}
+
+// ListBackups is part of the BackupStorage interface.
+func (mbs *MemoryBackupStorage) ListBackups(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+
+	var bhs []backupstorage.BackupHandle
+	for _, p := range slices.Sorted(maps.Keys(mbs.backups)) {
+		if path.Dir(p) == dir {
+			bhs = append(bhs, &memoryBackupHandle{mbs: mbs, dir: dir, name: path.Base(p), readOnly: true})
+		}
+	}
+	return bhs, nil
+}
+
+// StartBackup is part of the BackupStorage interface.
+func (mbs *MemoryBackupStorage) StartBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+
+	p := path.Join(dir, name)
+	if _, ok := mbs.backups[p]; ok {
+		return nil, fmt.Errorf("backup %v already exists", p)
+	}
+	mbs.backups[p] = make(map[string][]byte)
+	return &memoryBackupHandle{mbs: mbs, dir: dir, name: name}, nil
+}
+
+// ReopenBackup is part of the BackupStorage interface.
+func (mbs *MemoryBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+
+	if _, ok := mbs.backups[path.Join(dir, name)]; !ok {
+		return nil, fmt.Errorf("backup %v/%v does not exist", dir, name)
+	}
+	return &memoryBackupHandle{mbs: mbs, dir: dir, name: name}, nil
+}
+
+// RemoveBackup is part of the BackupStorage interface.
+func (mbs *MemoryBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+	delete(mbs.backups, path.Join(dir, name))
+	return nil
+}
+
+// Close is part of the BackupStorage interface.
+func (mbs *MemoryBackupStorage) Close() error {
+	return nil
+}
+
+// WithParams is part of the BackupStorage interface. The backups are shared
+// with the returned BackupStorage.
+func (mbs *MemoryBackupStorage) WithParams(backupstorage.Params) backupstorage.BackupStorage {
+	return mbs
+}
+
+// ReadBackupFile returns the contents of a file of a backup.
+func (mbs *MemoryBackupStorage) ReadBackupFile(dir, name, filename string) ([]byte, bool) {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+	data, ok := mbs.backups[path.Join(dir, name)][filename]
+	return data, ok
+}
+
+// WriteBackupFile sets the contents of a file of a backup, creating the backup
+// if it does not exist.
+func (mbs *MemoryBackupStorage) WriteBackupFile(dir, name, filename string, data []byte) {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+	p := path.Join(dir, name)
+	if _, ok := mbs.backups[p]; !ok {
+		mbs.backups[p] = make(map[string][]byte)
+	}
+	mbs.backups[p][filename] = slices.Clone(data)
+}
+
+// memoryBackupHandle implements BackupHandle for MemoryBackupStorage.
+type memoryBackupHandle struct {
+	mbs      *MemoryBackupStorage
+	dir      string
+	name     string
+	readOnly bool
+	ended    bool
+	mysqlctlerrors.PerFileErrorRecorder
+}
+
+// Directory is part of the BackupHandle interface.
+func (mbh *memoryBackupHandle) Directory() string {
+	return mbh.dir
+}
+
+// Name is part of the BackupHandle interface.
+func (mbh *memoryBackupHandle) Name() string {
+	return mbh.name
+}
+
+// AddFile is part of the BackupHandle interface. The file is stored when it
+// is closed.
+func (mbh *memoryBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	if mbh.readOnly {
+		return nil, errors.New("AddFile cannot be called on read-only backup")
+	}
+	return &memoryBackupFile{mbh: mbh, filename: filename}, nil
+}
+
+// EndBackup is part of the BackupHandle interface.
+func (mbh *memoryBackupHandle) EndBackup(ctx context.Context) error {
+	if mbh.readOnly {
+		return errors.New("EndBackup cannot be called on read-only backup")
+	}
+	mbh.ended = true
+	return nil
+}
+
+// AbortBackup is part of the BackupHandle interface.
+func (mbh *memoryBackupHandle) AbortBackup(ctx context.Context) error {
+	if mbh.readOnly {
+		return errors.New("AbortBackup cannot be called on read-only backup")
+	}
+	return mbh.mbs.RemoveBackup(ctx, mbh.dir, mbh.name)
+}
+
+// ReadFile is part of the BackupHandle interface.
+func (mbh *memoryBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	if !mbh.readOnly && !mbh.ended {
+		return nil, errors.New("ReadFile cannot be called on read-write backup before EndBackup")
+	}
+	data, ok := mbh.mbs.ReadBackupFile(mbh.dir, mbh.name, filename)
+	if !ok {
+		return nil, fmt.Errorf("file %v of backup %v/%v: %w", filename, mbh.dir, mbh.name, os.ErrNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// memoryBackupFile is a file being added to a memoryBackupHandle.
+type memoryBackupFile struct {
+	bytes.Buffer
+	mbh      *memoryBackupHandle
+	filename string
+}
+
+// Close stores the file in its backup.
+func (f *memoryBackupFile) Close() error {
+	f.mbh.mbs.WriteBackupFile(f.mbh.dir, f.mbh.name, f.filename, f.Bytes())
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blackbox
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/s3backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/s3backupstorage/fakes3"
+)
+
+// These tests run the builtin backup engine against an in-process S3
+// emulator, injecting faults to exercise the retry and cleanup logic. Each
+// request is attempted once by the S3 client, so that every injected fault
+// reaches the backup engine.
+
+// setupFakeS3 starts an S3 emulator and points the S3 backup storage at it.
+func setupFakeS3(t *testing.T) *fakes3.Server {
+	s := fakes3.NewServer(fakes3.Options{})
+	t.Cleanup(s.Close)
+	s.CreateBucket("backups")
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "fake")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "fake")
+	t.Setenv("AWS_CONFIG_FILE", path.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_CA_BUNDLE", "")
+	s3backupstorage.InitFlag(s3backupstorage.FakeConfig{
+		Region:    "us-east-1",
+		Endpoint:  s.URL(),
+		Bucket:    "backups",
+		ForcePath: true,
+		Retries:   1,
+	})
+	return s
+}
+
+// executeS3Backup takes a builtin backup of a fake cluster into the S3
+// emulator. It returns the backup handle, so that it can be aborted, and the
+// root of the fake cluster.
+func executeS3Backup(ctx context.Context, t *testing.T, dir, name string) (*s3backupstorage.FakeS3BackupHandle, string, mysqlctl.BackupResult, error) {
+	backupRoot, keyspace, shard, ts := SetupCluster(ctx, t, 2, 2)
+	logger := logutil.NewMemoryLogger()
+	fakeStats := backupstats.NewFakeStats()
+	bh, err := s3backupstorage.NewFakeS3BackupHandle(ctx, dir, name, logger, fakeStats)
+	require.NoError(t, err)
+
+	// Spin up a fake daemon to be used in backups. It needs to be allowed to receive:
+	// "STOP REPLICA", "START REPLICA", in that order.
+	fakedb := fakesqldb.New(t)
+	defer fakedb.Close()
+	mysqld := mysqlctl.NewFakeMysqlDaemon(fakedb)
+	defer mysqld.Close()
+	mysqld.ExpectedExecuteSuperQueryList = []string{"STOP REPLICA", "START REPLICA"}
+
+	be := &mysqlctl.BuiltinBackupEngine{}
+	result, err := be.ExecuteBackup(ctx, mysqlctl.BackupParams{
+		Logger: logger,
+		Mysqld: mysqld,
+		Cnf: &mysqlctl.Mycnf{
+			InnodbDataHomeDir:     path.Join(backupRoot, "innodb"),
+			InnodbLogGroupHomeDir: path.Join(backupRoot, "log"),
+			DataDir:               path.Join(backupRoot, "datadir"),
+		},
+		Concurrency:          1,
+		HookExtraEnv:         map[string]string{},
+		TopoServer:           ts,
+		Keyspace:             keyspace,
+		Shard:                shard,
+		Stats:                fakeStats,
+		MysqlShutdownTimeout: MysqlShutdownTimeout,
+	}, bh)
+	return bh, backupRoot, result, err
+}
+
+func TestExecuteBackupS3RetriesFailedUploads(t *testing.T) {
+	ctx := context.Background()
+	s := setupFakeS3(t)
+	dir, name := t.Name(), time.Now().Format(mysqlctl.BackupTimestampFormat)
+
+	// The upload of the first file fails, and the upload of the second one is
+	// lost in the middle of the request.
+	s.InjectFault(fakes3.Fault{Operation: fakes3.PutObject, KeyPrefix: path.Join(dir, name, "0"), Times: 1, Type: fakes3.InternalError})
+	s.InjectFault(fakes3.Fault{Operation: fakes3.PutObject, KeyPrefix: path.Join(dir, name, "1"), Times: 1, Type: fakes3.Disconnect})
+
+	bh, backupRoot, result, err := executeS3Backup(ctx, t, dir, name)
+	require.NoError(t, err)
+	require.Equal(t, mysqlctl.BackupUsable, result)
+	t.Cleanup(func() { require.NoError(t, bh.AbortBackup(ctx)) })
+	assert.Equal(t, 2, s.FaultsInjected())
+	// Four files, the two failed uploads and the manifest.
+	assert.Equal(t, 7, s.Requests(fakes3.PutObject))
+
+	// The download of the first file is lost in the middle of the response,
+	// and the restore reads it again.
+	s.InjectFault(fakes3.Fault{Operation: fakes3.GetObject, KeyPrefix: path.Join(dir, name, "0"), Times: 1, Type: fakes3.TruncateResponse})
+
+	fakeStats := backupstats.NewFakeStats()
+	restoreBh, err := s3backupstorage.NewFakeS3RestoreHandle(ctx, dir, logutil.NewMemoryLogger(), fakeStats)
+	require.NoError(t, err)
+
+	fakedb := fakesqldb.New(t)
+	defer fakedb.Close()
+	mysqld := mysqlctl.NewFakeMysqlDaemon(fakedb)
+	defer mysqld.Close()
+	mysqld.ExpectedExecuteSuperQueryList = []string{"STOP REPLICA", "START REPLICA"}
+
+	be := &mysqlctl.BuiltinBackupEngine{}
+	bm, err := be.ExecuteRestore(ctx, mysqlctl.RestoreParams{
+		Cnf: &mysqlctl.Mycnf{
+			InnodbDataHomeDir:     path.Join(backupRoot, "innodb"),
+			InnodbLogGroupHomeDir: path.Join(backupRoot, "log"),
+			DataDir:               path.Join(backupRoot, "datadir"),
+			BinLogPath:            path.Join(backupRoot, "binlog"),
+			RelayLogPath:          path.Join(backupRoot, "relaylog"),
+			RelayLogIndexPath:     path.Join(backupRoot, "relaylogindex"),
+			RelayLogInfoPath:      path.Join(backupRoot, "relayloginfo"),
+		},
+		Logger:               logutil.NewMemoryLogger(),
+		Mysqld:               mysqld,
+		Concurrency:          1,
+		HookExtraEnv:         map[string]string{},
+		DbName:               "test",
+		Keyspace:             "test",
+		Shard:                "-",
+		StartTime:            time.Now(),
+		RestoreToPos:         replication.Position{},
+		Stats:                fakeStats,
+		MysqlShutdownTimeout: MysqlShutdownTimeout,
+	}, restoreBh)
+	require.NoError(t, err)
+	assert.NotNil(t, bm)
+	assert.Equal(t, 3, s.FaultsInjected())
+	// The manifest, four files and the truncated download.
+	assert.Equal(t, 6, s.Requests(fakes3.GetObject))
+}
+
+func TestExecuteBackupS3CleansUpFailedBackups(t *testing.T) {
+	ctx := context.Background()
+	s := setupFakeS3(t)
+	dir, name := t.Name(), time.Now().Format(mysqlctl.BackupTimestampFormat)
+
+	// Every upload of the second file fails, so the backup is unusable once
+	// the engine has retried it.
+	s.InjectFault(fakes3.Fault{Operation: fakes3.PutObject, KeyPrefix: path.Join(dir, name, "1"), Type: fakes3.InternalError})
+
+	bh, _, result, err := executeS3Backup(ctx, t, dir, name)
+	require.Error(t, err)
+	require.Equal(t, mysqlctl.BackupUnusable, result)
+	assert.Equal(t, 2, s.FaultsInjected())
+	_, ok := s.Object("backups", path.Join(dir, name, "MANIFEST"))
+	assert.False(t, ok)
+
+	require.NoError(t, bh.AbortBackup(ctx))
+	assert.Empty(t, s.Keys("backups"))
+	assert.Equal(t, 0, s.MultipartUploads())
+}
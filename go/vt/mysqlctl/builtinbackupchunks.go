@@ -0,0 +1,615 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/ioutil"
+	"vitess.io/vitess/go/vt/logutil"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Full builtin backups can be taken in chunked mode, which deduplicates the data
+// of the backups of a shard at the block level. Every file is split into chunks of
+// --builtinbackup-chunk-size bytes, which are stored in the chunk store of the shard
+// under the SHA-256 of their contents. A chunk is only uploaded if the chunk store
+// doesn't have it yet, and the MANIFEST of the backup lists the chunks of each file.
+// Since InnoDB modifies its files page by page, most of the chunks of a backup are
+// usually already stored by the previous backups of the shard.
+//
+// Every chunk is stored as its own entry of the chunk store, so that the chunks which
+// are not referenced by any backup anymore can be removed one by one, see
+// RemoveUnreferencedBackupChunks.
+//
+// The chunks are shared by the backups of the shard and named after their contents,
+// so they cannot be encrypted with the data key of a single backup: chunked backups
+// cannot be encrypted, and fail when --builtinbackup-encryption-key-file or
+// --builtinbackup-encryption-vault-addr is set.
+
+const (
+	// builtinBackupChunkStoreDir is the directory of the BackupStorage under which the
+	// chunk stores of the shards are kept. It is outside of the backup directories of
+	// the shards, so that the chunks are not listed as backups.
+	builtinBackupChunkStoreDir = "_vt_chunks"
+
+	// builtinBackupChunkFileName is the name of the file holding the data of a chunk.
+	builtinBackupChunkFileName = "chunk"
+
+	// builtinBackupChunkStoreFileName is the name of the file which a chunked backup
+	// writes before uploading any chunk, and which holds the directory of its chunk
+	// store. Since the MANIFEST is written last, it is what makes a chunked backup in
+	// progress visible to RemoveUnreferencedBackupChunks.
+	builtinBackupChunkStoreFileName = "CHUNK_STORE"
+
+	// builtinBackupChunkUncompressed is the compression engine recorded in the name of
+	// the chunks which are not compressed.
+	builtinBackupChunkUncompressed = "none"
+)
+
+var (
+	// builtinBackupChunkSize is the size of the chunks of chunked backups, which are
+	// disabled when it is zero.
+	builtinBackupChunkSize uint
+
+	// builtinBackupChunkGCGracePeriod is how long a backup without a MANIFEST is
+	// considered to be in progress, which prevents the removal of unreferenced chunks.
+	builtinBackupChunkGCGracePeriod = 24 * time.Hour
+
+	// discardLogger is given to the compressors of the chunks, which would otherwise
+	// log a line per chunk.
+	discardLogger = logutil.NewCallbackLogger(func(*logutilpb.Event) {})
+)
+
+// backupChunkStoreDir returns the directory of the chunk store of a backup directory.
+func backupChunkStoreDir(backupDir string) string {
+	return path.Join(builtinBackupChunkStoreDir, backupDir)
+}
+
+// backupChunkStore gives access to the chunks of the chunk store of a shard.
+type backupChunkStore struct {
+	bs  backupstorage.BackupStorage
+	dir string
+
+	// chunkSize is the size of the chunks the files are split into.
+	chunkSize int64
+	// engine is the compression engine of the chunks which are uploaded.
+	engine string
+
+	mu sync.Mutex
+	// chunks holds the chunks which are stored, or being uploaded, by name.
+	chunks map[string]backupstorage.BackupHandle
+
+	uploadedChunks atomic.Int64
+	uploadedBytes  atomic.Int64
+	reusedChunks   atomic.Int64
+}
+
+// openBackupChunkStore lists the chunks of the chunk store in dir.
+func openBackupChunkStore(ctx context.Context, bs backupstorage.BackupStorage, dir string, chunkSize int64) (*backupChunkStore, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot list the chunks in %v", dir)
+	}
+	cs := &backupChunkStore{
+		bs:        bs,
+		dir:       dir,
+		chunkSize: chunkSize,
+		chunks:    make(map[string]backupstorage.BackupHandle, len(bhs)),
+	}
+	for _, bh := range bhs {
+		cs.chunks[bh.Name()] = bh
+	}
+	return cs, nil
+}
+
+// newBackupChunkStore returns the chunk store which a chunked backup of backupDir
+// uploads its chunks to, or nil if backups are not chunked.
+func newBackupChunkStore(ctx context.Context, bs backupstorage.BackupStorage, backupDir string, bc *backupCipher) (*backupChunkStore, error) {
+	if builtinBackupChunkSize == 0 {
+		return nil, nil
+	}
+	if bc != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "chunked backups (--builtinbackup-chunk-size) cannot be encrypted")
+	}
+	engine := builtinBackupChunkUncompressed
+	if backupStorageCompress {
+		if ExternalCompressorCmd != "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "chunked backups (--builtinbackup-chunk-size) cannot be compressed with an external compressor")
+		}
+		engine = CompressionEngineName
+	}
+	cs, err := openBackupChunkStore(ctx, bs, backupChunkStoreDir(backupDir), int64(builtinBackupChunkSize))
+	if err != nil {
+		return nil, err
+	}
+	cs.engine = engine
+	return cs, nil
+}
+
+// markBackup records in the backup bh that it is a chunked backup of the chunk store.
+func (cs *backupChunkStore) markBackup(ctx context.Context, bh backupstorage.BackupHandle) error {
+	wc, err := bh.AddFile(ctx, builtinBackupChunkStoreFileName, int64(len(cs.dir)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v to backup", builtinBackupChunkStoreFileName)
+	}
+	if _, err := io.WriteString(wc, cs.dir); err != nil {
+		wc.Close()
+		return vterrors.Wrapf(err, "cannot write %v", builtinBackupChunkStoreFileName)
+	}
+	if err := wc.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close %v", builtinBackupChunkStoreFileName)
+	}
+	return nil
+}
+
+// readBackupChunkStore returns the directory of the chunk store recorded in the backup
+// bh, or an error if bh is not a chunked backup.
+func readBackupChunkStore(ctx context.Context, bh backupstorage.BackupHandle) (string, error) {
+	rc, err := bh.ReadFile(ctx, builtinBackupChunkStoreFileName)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	dir, err := io.ReadAll(rc)
+	if err != nil {
+		return "", err
+	}
+	return string(dir), nil
+}
+
+// chunkName returns the name of a chunk holding data, compressed with engine.
+func chunkName(data []byte, engine string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "." + engine
+}
+
+// parseChunkName returns the SHA-256 of the data of a chunk, and the compression
+// engine of the chunk.
+func parseChunkName(name string) (sum string, engine string, err error) {
+	sum, engine, ok := strings.Cut(name, ".")
+	if !ok || len(sum) != 2*sha256.Size || engine == "" {
+		return "", "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid chunk name %q", name)
+	}
+	return sum, engine, nil
+}
+
+// claim returns true if the chunk is neither stored nor being uploaded, in which
+// case the caller must upload it.
+func (cs *backupChunkStore) claim(name string) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, ok := cs.chunks[name]; ok {
+		return false
+	}
+	cs.chunks[name] = nil
+	return true
+}
+
+// handle returns the BackupHandle of a stored chunk.
+func (cs *backupChunkStore) handle(name string) (backupstorage.BackupHandle, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	bh := cs.chunks[name]
+	if bh == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "chunk %v is missing from %v", name, cs.dir)
+	}
+	return bh, nil
+}
+
+// backupChunkedFiles splits the files into chunks, and uploads the chunks which are
+// missing from the chunk store. It sets the Chunks of the file entries.
+func (be *BuiltinBackupEngine) backupChunkedFiles(ctx context.Context, params BackupParams, cs *backupChunkStore, fes []FileEntry) error {
+	params.Logger.Infof("Backing up the files in chunks of %v bytes to %v, which holds %v chunks", cs.chunkSize, cs.dir, len(cs.chunks))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(params.Concurrency)
+	for i := range fes {
+		fe := &fes[i]
+		name, size, err := chunkedFileSize(params.Cnf, fe)
+		if err != nil {
+			return errors.Join(err, g.Wait())
+		}
+		fe.Chunks = make([]string, (size+cs.chunkSize-1)/cs.chunkSize)
+		params.Logger.Infof("Backing up file %v in %v chunks", fe.Name, len(fe.Chunks))
+		for c := range fe.Chunks {
+			g.Go(func() error {
+				data, err := cs.readChunk(gctx, params, name, c, size)
+				if err != nil {
+					return err
+				}
+				chunk := chunkName(data, cs.engine)
+				fe.Chunks[c] = chunk
+				if !cs.claim(chunk) {
+					cs.reusedChunks.Add(1)
+					return nil
+				}
+				return cs.uploadWithRetry(gctx, params, fe, c, chunk, data)
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// The chunks which are not referenced by any backup may have been removed from the chunk
+	// store in the meantime, including the ones this backup reuses or has just uploaded.
+	if err := cs.reuploadRemovedChunks(ctx, params, fes); err != nil {
+		return err
+	}
+
+	params.Logger.Infof("Uploaded %v chunks (%v bytes) to %v, and reused %v chunks", cs.uploadedChunks.Load(), cs.uploadedBytes.Load(), cs.dir, cs.reusedChunks.Load())
+	return nil
+}
+
+// chunkedFileSize returns the full path and the size of a file to back up in chunks.
+func chunkedFileSize(cnf *Mycnf, fe *FileEntry) (string, int64, error) {
+	name, err := fe.fullPath(cnf)
+	if err != nil {
+		return "", 0, vterrors.Wrapf(err, "cannot evaluate full name for %v", fe.Name)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		return "", 0, vterrors.Wrapf(err, "cannot stat source file %v", name)
+	}
+	return name, fi.Size(), nil
+}
+
+// readChunk reads the chunk c of a file.
+func (cs *backupChunkStore) readChunk(ctx context.Context, params BackupParams, name string, c int, size int64) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	offset := int64(c) * cs.chunkSize
+	data := make([]byte, min(cs.chunkSize, size-offset))
+
+	readAt := time.Now()
+	source, err := os.Open(name)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot open source file %v", name)
+	}
+	defer source.Close()
+	if _, err := source.ReadAt(data, offset); err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read chunk %v of %v", c, name)
+	}
+	params.Stats.Scope(stats.Operation("Source:Read")).TimedIncrementBytes(len(data), time.Since(readAt))
+	return data, nil
+}
+
+// reuploadRemovedChunks uploads the chunks of the files which are not in the chunk
+// store anymore.
+func (cs *backupChunkStore) reuploadRemovedChunks(ctx context.Context, params BackupParams, fes []FileEntry) error {
+	bhs, err := cs.bs.ListBackups(ctx, cs.dir)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot list the chunks in %v", cs.dir)
+	}
+	stored := make(map[string]bool, len(bhs))
+	for _, bh := range bhs {
+		stored[bh.Name()] = true
+	}
+	for i := range fes {
+		fe := &fes[i]
+		for c, chunk := range fe.Chunks {
+			if stored[chunk] {
+				continue
+			}
+			params.Logger.Warningf("Chunk %v of %v was removed from %v during the backup, uploading it again", c, fe.Name, cs.dir)
+			name, size, err := chunkedFileSize(params.Cnf, fe)
+			if err != nil {
+				return err
+			}
+			data, err := cs.readChunk(ctx, params, name, c, size)
+			if err != nil {
+				return err
+			}
+			if chunkName(data, cs.engine) != chunk {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "chunk %v of %v changed during the backup", c, fe.Name)
+			}
+			if err := cs.uploadWithRetry(ctx, params, fe, c, chunk, data); err != nil {
+				return err
+			}
+			stored[chunk] = true
+		}
+	}
+	return nil
+}
+
+// uploadWithRetry uploads the chunk c of a file, and retries once if that fails.
+func (cs *backupChunkStore) uploadWithRetry(ctx context.Context, params BackupParams, fe *FileEntry, c int, chunk string, data []byte) error {
+	var err error
+	for retry := 0; retry <= maxRetriesPerFile; retry++ {
+		if err = cs.upload(ctx, params, chunk, data); err == nil || ctx.Err() != nil {
+			break
+		}
+		params.Logger.Infof("Failed uploading chunk %v of %v %s: %v", c, fe.Name, retryToString(retry), err)
+	}
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot upload chunk %v of %v", c, fe.Name)
+	}
+	return nil
+}
+
+// upload stores a chunk in the chunk store.
+func (cs *backupChunkStore) upload(ctx context.Context, params BackupParams, chunk string, data []byte) (finalErr error) {
+	bh, err := cs.bs.StartBackup(ctx, cs.dir, chunk)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if finalErr != nil {
+			if err := bh.AbortBackup(ctx); err != nil {
+				params.Logger.Errorf("Failed to abort the upload of chunk %v: %v", chunk, err)
+			}
+		}
+	}()
+
+	wc, err := bh.AddFile(ctx, builtinBackupChunkFileName, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	var written int64
+	destStats := params.Stats.Scope(stats.Operation("Destination:Write"))
+	dest := getBandwidthLimiter(params.TopoServer, params.TabletAlias).writer(ctx, ioutil.NewMeteredWriteCloser(wc, destStats.TimedIncrementBytes, func(n int, _ time.Duration) {
+		written += int64(n)
+	}))
+
+	write := func() error {
+		if cs.engine == builtinBackupChunkUncompressed {
+			_, err := dest.Write(data)
+			return err
+		}
+		compressor, err := newBuiltinCompressor(cs.engine, dest, discardLogger)
+		if err != nil {
+			return err
+		}
+		if _, err := compressor.Write(data); err != nil {
+			return errors.Join(err, compressor.Close())
+		}
+		return compressor.Close()
+	}
+	if err := write(); err != nil {
+		return errors.Join(err, dest.Close())
+	}
+	if err := closeWithRetry(ctx, params.Logger, dest, chunk); err != nil {
+		return err
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		return err
+	}
+	if err := bh.Error(); err != nil {
+		return err
+	}
+
+	cs.uploadedChunks.Add(1)
+	cs.uploadedBytes.Add(written)
+	return nil
+}
+
+// restoreChunkedFiles restores the files of a chunked backup from its chunk store.
+func (be *BuiltinBackupEngine) restoreChunkedFiles(ctx context.Context, params RestoreParams, bm builtinBackupManifest) error {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return vterrors.Wrap(err, "unable to get backup storage")
+	}
+	defer bs.Close()
+
+	cs, err := openBackupChunkStore(ctx, bs, bm.ChunkStore, bm.ChunkSize)
+	if err != nil {
+		return err
+	}
+	sources, err := openRestoreChunkSources(ctx, params, cs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := sources.close(); cerr != nil {
+			params.Logger.Warningf("Restore: cannot close the sources: %v", cerr)
+		}
+	}()
+	var chunks int
+	for _, fe := range bm.FileEntries {
+		for _, chunk := range fe.Chunks {
+			if !slices.ContainsFunc(sources.sources, holdsChunk(chunk)) {
+				return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "cannot restore %v: chunk %v is missing from %v", fe.Name, chunk, cs.dir)
+			}
+		}
+		chunks += len(fe.Chunks)
+	}
+	params.Logger.Infof("Restoring %v files from %v chunks of %v", len(bm.FileEntries), chunks, cs.dir)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(params.Concurrency)
+	for i := range bm.FileEntries {
+		fe := &bm.FileEntries[i]
+		// Create the file first, for it to exist even if it is empty.
+		dest, err := fe.open(params.Cnf, false)
+		if err != nil {
+			return errors.Join(err, g.Wait())
+		}
+		name := dest.Name()
+		if err := dest.Close(); err != nil {
+			return errors.Join(vterrors.Wrapf(err, "cannot close destination file %v", name), g.Wait())
+		}
+		params.Logger.Infof("Restoring file %v from %v chunks", fe.Name, len(fe.Chunks))
+		for c, chunk := range fe.Chunks {
+			g.Go(func() error {
+				return sources.restoreChunk(gctx, params, fe, name, c, chunk)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// restoreChunk writes the chunk c of a file, and retries once, from another source
+// if there's one, if reading the chunk fails.
+func (rs *restoreSources) restoreChunk(ctx context.Context, params RestoreParams, fe *FileEntry, name string, c int, chunk string) error {
+	var data []byte
+	var err error
+	for retry := 0; retry <= maxRetriesPerFile; retry++ {
+		var source *restoreSource
+		if source, err = rs.acquire(chunk, holdsChunk(chunk)); err != nil {
+			break
+		}
+		data, err = source.cs.read(ctx, params, chunk)
+		rs.release(source, chunk, err)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		params.Logger.Infof("Failed restoring chunk %v of %v from %v %s: %v", c, fe.Name, source.name, retryToString(retry), err)
+	}
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot restore chunk %v of %v", c, fe.Name)
+	}
+
+	writeAt := time.Now()
+	dest, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot open destination file %v", name)
+	}
+	if _, err := dest.WriteAt(data, int64(c)*rs.sources[0].cs.chunkSize); err != nil {
+		return errors.Join(vterrors.Wrapf(err, "cannot write chunk %v of %v", c, fe.Name), dest.Close())
+	}
+	if err := dest.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close destination file %v", name)
+	}
+	params.Stats.Scope(stats.Operation("Destination:Write")).TimedIncrementBytes(len(data), time.Since(writeAt))
+	return nil
+}
+
+// read returns the data of a chunk, after checking it against the name of the chunk.
+func (cs *backupChunkStore) read(ctx context.Context, params RestoreParams, chunk string) (data []byte, finalErr error) {
+	sum, engine, err := parseChunkName(chunk)
+	if err != nil {
+		return nil, err
+	}
+	bh, err := cs.handle(chunk)
+	if err != nil {
+		return nil, err
+	}
+	source, err := bh.ReadFile(ctx, builtinBackupChunkFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		finalErr = errors.Join(finalErr, source.Close())
+	}()
+
+	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
+	var reader io.Reader = ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes)
+	reader = getBandwidthLimiter(params.TopoServer, params.TabletAlias).reader(ctx, reader)
+	if engine != builtinBackupChunkUncompressed {
+		decompressor, err := newBuiltinDecompressor(engine, reader, discardLogger)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "can't create decompressor")
+		}
+		defer func() {
+			finalErr = errors.Join(finalErr, decompressor.Close())
+		}()
+		reader = decompressor
+	}
+
+	if data, err = io.ReadAll(io.LimitReader(reader, cs.chunkSize+1)); err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > cs.chunkSize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "chunk %v is larger than %v bytes", chunk, cs.chunkSize)
+	}
+	if got := sha256.Sum256(data); hex.EncodeToString(got[:]) != sum {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "hash mismatch for chunk %v, got %v", chunk, hex.EncodeToString(got[:]))
+	}
+	return data, nil
+}
+
+// RemoveUnreferencedBackupChunks removes the chunks of the chunk store of backupDir
+// which are not referenced by any of the backups in backupDir, and returns their names.
+// If dryRun is true, the chunks are only returned.
+//
+// Nothing is removed while a chunked backup may still be in progress in backupDir, that
+// is if a backup with a CHUNK_STORE file but without a readable MANIFEST was started
+// less than --builtinbackup-chunk-gc-grace-period ago. The chunks which such a backup
+// reuses may otherwise be removed before its MANIFEST references them.
+func RemoveUnreferencedBackupChunks(ctx context.Context, bs backupstorage.BackupStorage, backupDir string, dryRun bool) ([]string, error) {
+	dir := backupChunkStoreDir(backupDir)
+	chunks, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot list the chunks in %v", dir)
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	backups, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot list the backups in %v", backupDir)
+	}
+	referenced := make(map[string]bool)
+	for _, bh := range backups {
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			if chunkStore, cerr := readBackupChunkStore(ctx, bh); cerr != nil || chunkStore != dir {
+				// This backup does not use the chunk store.
+				continue
+			}
+			backupTime, _, perr := ParseBackupName(backupDir, bh.Name())
+			if perr == nil && backupTime != nil && time.Since(*backupTime) < builtinBackupChunkGCGracePeriod {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "chunked backup %v in %v may still be in progress, not removing any chunk: %v", bh.Name(), backupDir, err)
+			}
+			// This backup is incomplete, and its chunks are not needed.
+			continue
+		}
+		if bm.ChunkStore != dir {
+			continue
+		}
+		for _, fe := range bm.FileEntries {
+			for _, chunk := range fe.Chunks {
+				referenced[chunk] = true
+			}
+		}
+	}
+
+	var removed []string
+	for _, chunk := range chunks {
+		if referenced[chunk.Name()] {
+			continue
+		}
+		if !dryRun {
+			if err := bs.RemoveBackup(ctx, dir, chunk.Name()); err != nil {
+				return removed, vterrors.Wrapf(err, "cannot remove chunk %v from %v", chunk.Name(), dir)
+			}
+		}
+		removed = append(removed, chunk.Name())
+	}
+	return removed, nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const testChunkSize = 1024
+
+// setupChunkedBackups enables chunked backups to a file backup storage for the duration of the test.
+func setupChunkedBackups(t *testing.T) backupstorage.BackupStorage {
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	oldImplementation := backupstorage.BackupStorageImplementation
+	oldChunkSize := builtinBackupChunkSize
+	oldCompress := backupStorageCompress
+	oldEngine := CompressionEngineName
+	t.Cleanup(func() {
+		filebackupstorage.FileBackupStorageRoot = oldRoot
+		backupstorage.BackupStorageImplementation = oldImplementation
+		builtinBackupChunkSize = oldChunkSize
+		backupStorageCompress = oldCompress
+		CompressionEngineName = oldEngine
+	})
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	backupstorage.BackupStorageImplementation = "file"
+	builtinBackupChunkSize = testChunkSize
+	backupStorageCompress = true
+	CompressionEngineName = ZstdCompressor
+
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	return bs
+}
+
+func writeChunkedBackup(t *testing.T, bs backupstorage.BackupStorage, dir, name string, bm *builtinBackupManifest) {
+	ctx := context.Background()
+	bh, err := bs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	if bm != nil {
+		wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(wc).Encode(bm))
+		require.NoError(t, wc.Close())
+	}
+	require.NoError(t, bh.EndBackup(ctx))
+}
+
+func TestChunkedBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+
+	// The first two chunks of the file are identical, and the last one is shorter.
+	chunk := make([]byte, testChunkSize)
+	_, err := rand.Read(chunk)
+	require.NoError(t, err)
+	tail := make([]byte, testChunkSize/2)
+	_, err = rand.Read(tail)
+	require.NoError(t, err)
+	content := bytes.Join([][]byte{chunk, chunk, tail}, nil)
+
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, "t1.ibd"), content, 0o644))
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, "empty.ibd"), nil, 0o644))
+	backupParams := BackupParams{
+		Cnf:         cnf,
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}
+	be := &BuiltinBackupEngine{}
+
+	cs, err := newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	require.NoError(t, err)
+	fes := []FileEntry{{Base: backupData, Name: "t1.ibd"}, {Base: backupData, Name: "empty.ibd"}}
+	require.NoError(t, be.backupChunkedFiles(ctx, backupParams, cs, fes))
+	require.Len(t, fes[0].Chunks, 3)
+	assert.Equal(t, fes[0].Chunks[0], fes[0].Chunks[1])
+	assert.Empty(t, fes[1].Chunks)
+	assert.EqualValues(t, 2, cs.uploadedChunks.Load())
+	assert.EqualValues(t, 1, cs.reusedChunks.Load())
+	firstTail := fes[0].Chunks[2]
+
+	// Only the modified chunk is uploaded by the next backup.
+	_, err = rand.Read(content[2*testChunkSize:])
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, "t1.ibd"), content, 0o644))
+	cs, err = newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	require.NoError(t, err)
+	fes = []FileEntry{{Base: backupData, Name: "t1.ibd"}, {Base: backupData, Name: "empty.ibd"}}
+	require.NoError(t, be.backupChunkedFiles(ctx, backupParams, cs, fes))
+	assert.EqualValues(t, 1, cs.uploadedChunks.Load())
+	assert.EqualValues(t, 2, cs.reusedChunks.Load())
+	assert.NotEqual(t, firstTail, fes[0].Chunks[2])
+
+	bm := builtinBackupManifest{FileEntries: fes, ChunkStore: cs.dir, ChunkSize: cs.chunkSize}
+	restoreParams := RestoreParams{
+		Cnf:         &Mycnf{DataDir: t.TempDir()},
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}
+	require.NoError(t, be.restoreChunkedFiles(ctx, restoreParams, bm))
+	restored, err := os.ReadFile(path.Join(restoreParams.Cnf.DataDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.Equal(t, content, restored)
+	restored, err = os.ReadFile(path.Join(restoreParams.Cnf.DataDir, "empty.ibd"))
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+
+	// A corrupted chunk is detected.
+	chunkFile := path.Join(filebackupstorage.FileBackupStorageRoot, cs.dir, fes[0].Chunks[2], builtinBackupChunkFileName)
+	require.NoError(t, os.WriteFile(chunkFile, []byte("corrupted"), 0o644))
+	err = be.restoreChunkedFiles(ctx, restoreParams, bm)
+	assert.ErrorContains(t, err, "cannot restore chunk 2 of t1.ibd")
+
+	// A missing chunk is detected before anything is restored.
+	require.NoError(t, bs.RemoveBackup(ctx, cs.dir, fes[0].Chunks[2]))
+	err = be.restoreChunkedFiles(ctx, restoreParams, bm)
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+
+	// A chunk which was removed while the files were being backed up is uploaded again.
+	require.NoError(t, cs.reuploadRemovedChunks(ctx, backupParams, fes))
+	require.NoError(t, be.restoreChunkedFiles(ctx, restoreParams, bm))
+}
+
+func TestNewBackupChunkStore(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+
+	_, err := newBackupChunkStore(ctx, bs, "ks/-80", &backupCipher{})
+	assert.ErrorContains(t, err, "chunked backups (--builtinbackup-chunk-size) cannot be encrypted")
+
+	oldCmd := ExternalCompressorCmd
+	ExternalCompressorCmd = "gzip"
+	_, err = newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	ExternalCompressorCmd = oldCmd
+	assert.ErrorContains(t, err, "cannot be compressed with an external compressor")
+
+	backupStorageCompress = false
+	cs, err := newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	require.NoError(t, err)
+	assert.Equal(t, builtinBackupChunkUncompressed, cs.engine)
+	assert.Equal(t, "_vt_chunks/ks/-80", cs.dir)
+
+	builtinBackupChunkSize = 0
+	cs, err = newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	require.NoError(t, err)
+	assert.Nil(t, cs)
+}
+
+func TestRemoveUnreferencedBackupChunks(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+
+	chunks := make([]string, 3)
+	for i := range chunks {
+		chunks[i] = chunkName([]byte{byte(i)}, builtinBackupChunkUncompressed)
+		writeChunkedBackup(t, bs, backupChunkStoreDir("ks/-80"), chunks[i], nil)
+	}
+	writeChunkedBackup(t, bs, "ks/-80", "2026-10-17.000000.zone1-0000000100", &builtinBackupManifest{
+		FileEntries: []FileEntry{{Base: backupData, Name: "t1.ibd", Chunks: []string{chunks[0], chunks[0]}}},
+		ChunkStore:  backupChunkStoreDir("ks/-80"),
+		ChunkSize:   testChunkSize,
+	})
+	writeChunkedBackup(t, bs, "ks/-80", "2026-10-18.000000.zone1-0000000100", &builtinBackupManifest{
+		FileEntries: []FileEntry{{Base: backupData, Name: "t1.ibd", Chunks: []string{chunks[0], chunks[1]}}},
+		ChunkStore:  backupChunkStoreDir("ks/-80"),
+		ChunkSize:   testChunkSize,
+	})
+	// A failed backup does not prevent the removal of the chunks.
+	writeChunkedBackup(t, bs, "ks/-80", "2026-10-01.000000.zone1-0000000100", nil)
+
+	removed, err := RemoveUnreferencedBackupChunks(ctx, bs, "ks/-80", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{chunks[2]}, removed)
+	stored, err := bs.ListBackups(ctx, backupChunkStoreDir("ks/-80"))
+	require.NoError(t, err)
+	assert.Len(t, stored, 3)
+
+	// A backup which is not chunked does not prevent the removal of the chunks, even
+	// while it is in progress.
+	inProgress := time.Now().UTC().Format(BackupTimestampFormat) + ".zone1-0000000101"
+	writeChunkedBackup(t, bs, "ks/-80", inProgress, nil)
+	removed, err = RemoveUnreferencedBackupChunks(ctx, bs, "ks/-80", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{chunks[2]}, removed)
+	require.NoError(t, bs.RemoveBackup(ctx, "ks/-80", inProgress))
+
+	// A chunked backup which may still be in progress prevents the removal of the chunks.
+	cs, err := newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	require.NoError(t, err)
+	bh, err := bs.StartBackup(ctx, "ks/-80", inProgress)
+	require.NoError(t, err)
+	require.NoError(t, cs.markBackup(ctx, bh))
+	_, err = RemoveUnreferencedBackupChunks(ctx, bs, "ks/-80", false)
+	assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	require.NoError(t, bh.AbortBackup(ctx))
+
+	removed, err = RemoveUnreferencedBackupChunks(ctx, bs, "ks/-80", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{chunks[2]}, removed)
+	stored, err = bs.ListBackups(ctx, backupChunkStoreDir("ks/-80"))
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	// There is nothing to remove for shards without chunked backups.
+	removed, err = RemoveUnreferencedBackupChunks(ctx, bs, "ks/80-", false)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
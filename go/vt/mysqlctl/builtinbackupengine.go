@@ -136,6 +136,20 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// Encryption describes how the files were encrypted, after they were compressed.
+	// It is nil if the files are not encrypted.
+	Encryption *BackupEncryption `json:",omitempty"`
+
+	// ChunkStore is the directory of the BackupStorage which holds the chunks of the
+	// files of a chunked backup. It is empty if the files are stored in the backup.
+	ChunkStore string `json:",omitempty"`
+
+	// ChunkSize is the size of the chunks the files of a chunked backup are split into.
+	ChunkSize int64 `json:",omitempty"`
+
+	// cipher decrypts the files of the backup during a restore.
+	cipher *backupCipher
 }
 
 // FileEntry is one file to backup
@@ -158,12 +172,21 @@ type FileEntry struct {
 	// for writing files in a temporary directory
 	ParentPath string
 
+	// Chunks lists the chunks of the file in the chunk store, in order, for chunked backups.
+	Chunks []string `json:",omitempty"`
+
 	// RetryCount specifies how many times we retried restoring/backing up this FileEntry.
 	// If we fail to restore/backup this FileEntry, we will retry up to maxRetriesPerFile times.
 	// Every time the builtin backup engine retries this file, we increment this field by 1.
 	// We don't care about adding this information to the MANIFEST and also to not cause any compatibility issue
 	// we are adding the - json tag to let Go know it can ignore the field.
 	RetryCount int `json:"-"`
+
+	// dataHash and dataSize are the hash and size of the data of the file, as read when
+	// backing it up or written when restoring it. They record the progress of resumable
+	// backups and restores.
+	dataHash string
+	dataSize int64
 }
 
 func init() {
@@ -177,6 +200,11 @@ func registerBuiltinBackupEngineFlags(fs *pflag.FlagSet) {
 	utils.SetFlagDurationVar(fs, &builtinBackupProgress, "builtinbackup-progress", builtinBackupProgress, "how often to send progress updates when backing up large files.")
 	fs.UintVar(&builtinBackupFileReadBufferSize, "builtinbackup-file-read-buffer-size", builtinBackupFileReadBufferSize, "read files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.UintVar(&builtinBackupFileWriteBufferSize, "builtinbackup-file-write-buffer-size", builtinBackupFileWriteBufferSize, "write files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
+	fs.UintVar(&builtinBackupChunkSize, "builtinbackup-chunk-size", builtinBackupChunkSize, "when set, full builtin backups split the files into chunks of this many bytes, which are stored in a chunk store shared by the backups of the shard. Only the chunks missing from the chunk store are uploaded. Chunked backups cannot be encrypted nor use an external compressor.")
+	utils.SetFlagDurationVar(fs, &builtinBackupChunkGCGracePeriod, "builtinbackup-chunk-gc-grace-period", builtinBackupChunkGCGracePeriod, "how long a backup without a MANIFEST is considered to be in progress, during which the chunks which are not referenced by any backup are not removed.")
+	fs.Int64Var(&builtinBackupBandwidthLimit, "builtinbackup-bandwidth-limit", builtinBackupBandwidthLimit, "when set, the maximum number of bytes per second transferred to and from the backup storage by the builtin backups and restores of the tablet.")
+	fs.StringSliceVar(&builtinRestoreSources, "builtinbackup-restore-sources", builtinRestoreSources, "the backup storage implementations which hold copies of the backups, e.g. the tiers of the tiered backup storage. The builtin restores read the files of the backup from these backup storages and from --backup-storage-implementation in parallel, and retry the files which fail from another one.")
+	fs.BoolVar(&builtinBackupResume, "builtinbackup-resume", builtinBackupResume, "when set, builtin backups and restores record their progress, and the ones which were interrupted are resumed instead of starting over: the next backup of the tablet skips the files which are unchanged since they were backed up, and the next restore of the backup skips the files which are unchanged since they were restored.")
 	fs.StringVar(&builtinIncrementalRestorePath, "builtinbackup-incremental-restore-path", builtinIncrementalRestorePath, "the directory where incremental restore files, namely binlog files, are extracted to. In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods. The path should exist. When empty, the default OS temp dir is assumed.")
 }
 
@@ -617,8 +645,51 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
-	// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
-	_ = be.backupFileEntries(ctx, fes, bh, params)
+	bc, err := newBackupCipher()
+	if err != nil {
+		return vterrors.Wrap(err, "can't set up the encryption of the backup")
+	}
+	if bc != nil {
+		params.Logger.Infof("encrypting the backup files with cipher %s and key %s", bc.encryption.Cipher, bc.encryption.KeyID)
+	}
+
+	var cs *backupChunkStore
+	var bp *backupProgress
+	if builtinBackupChunkSize > 0 && !isIncrementalBackup(params) {
+		bs, err := backupstorage.GetBackupStorage()
+		if err != nil {
+			return vterrors.Wrap(err, "unable to get backup storage")
+		}
+		defer bs.Close()
+		if cs, err = newBackupChunkStore(ctx, bs, GetBackupDir(params.Keyspace, params.Shard), bc); err != nil {
+			return vterrors.Wrap(err, "can't set up the chunk store of the backup")
+		}
+		if err := cs.markBackup(ctx, bh); err != nil {
+			return vterrors.Wrap(err, "can't mark the backup as chunked")
+		}
+	} else if builtinBackupResume {
+		// Chunked backups need not record their progress, since they do not upload
+		// the chunks which are already in the chunk store.
+		bs, err := backupstorage.GetBackupStorage()
+		if err != nil {
+			return vterrors.Wrap(err, "unable to get backup storage")
+		}
+		defer bs.Close()
+		if bp, bc, err = openBackupProgress(ctx, params, bs, bh, bc); err != nil {
+			return vterrors.Wrap(err, "can't set up the progress of the backup")
+		}
+		// Record the progress of a backup which fails too, so that it's resumed.
+		defer bp.close(ctx)
+	}
+
+	if cs != nil {
+		if err := be.backupChunkedFiles(ctx, params, cs, fes); err != nil {
+			return err
+		}
+	} else {
+		// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
+		_ = be.backupFileEntries(ctx, fes, bh, params, bc, bp)
+	}
 
 	// BackupHandle supports the BackupErrorRecorder interface for tracking errors
 	// across any goroutines that fan out to take the backup. This means that we
@@ -649,7 +720,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.backupFileEntries(ctx, newFEs, bh, params)
+		err = be.backupFileEntries(ctx, newFEs, bh, params, bc, bp)
 		if err != nil {
 			return err
 		}
@@ -662,10 +733,13 @@ func (be *BuiltinBackupEngine) backupFiles(
 		}
 	}
 
+	// The progress is not recorded anymore once the backup is complete.
+	bp.close(ctx)
+
 	// Backup the MANIFEST file and apply retry logic.
 	var manifestErr error
 	for currentRetry := 0; currentRetry <= maxRetriesPerFile; currentRetry++ {
-		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, currentRetry)
+		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, bc, cs, currentRetry)
 		if manifestErr == nil || vterrors.Code(manifestErr) == vtrpcpb.Code_FAILED_PRECONDITION {
 			break
 		}
@@ -681,7 +755,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 // This function will ignore empty FileEntry, allowing the retry mechanism to send a partially empty slice, to not
 // mess up the index of retriable FileEntry.
 // This function does not leave any background operation behind itself, all calls to bh.AddFile will be finished or canceled.
-func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams) error {
+func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams, bc *backupCipher, bp *backupProgress) error {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer func() {
 		// If we reached this defer in all cases we can cancel the context.
@@ -713,15 +787,22 @@ func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []File
 			default:
 			}
 
+			// Skip the file if it was backed up by the interrupted backup this one resumes.
+			if bp.resume(ctxCancel, params, fe, name) {
+				return nil
+			}
+
 			// Backup the individual file.
 			var errBackupFile error
-			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name); errBackupFile != nil {
+			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name, bc); errBackupFile != nil {
 				bh.RecordError(name, vterrors.Wrapf(errBackupFile, "failed to backup file '%s'", name))
 				if fe.RetryCount >= maxRetriesPerFile || vterrors.Code(errBackupFile) == vtrpcpb.Code_FAILED_PRECONDITION {
 					// this is the last attempt, and we have an error, we can cancel everything and fail fast.
 					cancel()
 				}
+				return nil
 			}
+			bp.fileBackedUp(ctxCancel, fe, name)
 			return nil
 		})
 	}
@@ -844,7 +925,7 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 }
 
 // backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, bc *backupCipher) (finalErr error) {
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -904,7 +985,7 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	}(name, fe.Name)
 
 	destStats := params.Stats.Scope(stats.Operation("Destination:Write"))
-	timedDest := ioutil.NewMeteredWriteCloser(dest, destStats.TimedIncrementBytes)
+	timedDest := getBandwidthLimiter(params.TopoServer, params.TabletAlias).writer(cancelableCtx, ioutil.NewMeteredWriteCloser(dest, destStats.TimedIncrementBytes))
 
 	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
 
@@ -925,6 +1006,22 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 				createAndCopyErr = errors.Join(createAndCopyErr, vterrors.Wrap(err, "failed to close the source reader"))
 			}
 		}()
+		// Create the encryption pipe, if necessary. It must be closed after the compressor.
+		if bc != nil {
+			encryptor, err := bc.newWriter(writer)
+			if err != nil {
+				return vterrors.Wrap(err, "can't create encryptor")
+			}
+			writer = encryptor
+			defer func() {
+				if cerr := encryptor.Close(); cerr != nil {
+					cerr = vterrors.Wrapf(cerr, "failed to close encryptor %v", fe.Name)
+					params.Logger.Error(cerr)
+					createAndCopyErr = errors.Join(createAndCopyErr, cerr)
+				}
+			}()
+		}
+
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -974,6 +1071,7 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 
 	// Save the hash.
 	fe.Hash = bw.HashString()
+	fe.dataHash, fe.dataSize = br.HashString(), atomic.LoadInt64(&br.nn)
 	return nil
 }
 
@@ -989,6 +1087,8 @@ func (be *BuiltinBackupEngine) backupManifest(
 	mysqlVersion string,
 	incrDetails *IncrementalBackupDetails,
 	fes []FileEntry,
+	bc *backupCipher,
+	cs *backupChunkStore,
 	currentAttempt int,
 ) (finalErr error) {
 	retryStr := retryToString(currentAttempt)
@@ -1050,6 +1150,13 @@ func (be *BuiltinBackupEngine) backupManifest(
 			CompressionEngine:    CompressionEngineName,
 			ExternalDecompressor: ManifestExternalDecompressorCmd,
 		}
+		if bc != nil {
+			bm.Encryption = bc.encryption
+		}
+		if cs != nil {
+			bm.ChunkStore = cs.dir
+			bm.ChunkSize = cs.chunkSize
+		}
 		data, err := json.MarshalIndent(bm, "", "  ")
 		if err != nil {
 			return vterrors.Wrapf(err, "cannot JSON encode %v %s", backupManifestFileName, retryStr)
@@ -1073,14 +1180,27 @@ func (be *BuiltinBackupEngine) backupManifest(
 }
 
 // executeRestoreFullBackup restores the files from a full backup. The underlying mysql database service is expected to be stopped.
-func (be *BuiltinBackupEngine) executeRestoreFullBackup(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) error {
-	if err := prepareToRestore(ctx, params.Cnf, params.Mysqld, params.Logger, params.MysqlShutdownTimeout); err != nil {
+// When the previous restore was interrupted, the files it restored which are unchanged are kept.
+func (be *BuiltinBackupEngine) executeRestoreFullBackup(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest, interrupted bool) error {
+	var rp *restoreProgress
+	var keepFiles func() (map[string]bool, error)
+	if builtinBackupResume {
+		var err error
+		if rp, err = openRestoreProgress(params, bm, interrupted); err != nil {
+			return err
+		}
+		defer rp.close()
+		keepFiles = func() (map[string]bool, error) {
+			return rp.verify(ctx, params, bm.FileEntries)
+		}
+	}
+	if err := prepareToRestore(ctx, params.Cnf, params.Mysqld, params.Logger, params.MysqlShutdownTimeout, keepFiles); err != nil {
 		return err
 	}
 
 	params.Logger.Infof("Restore: copying %v files", len(bm.FileEntries))
 
-	if _, err := be.restoreFiles(ctx, params, bh, bm); err != nil {
+	if _, err := be.restoreFiles(ctx, params, bh, bm, rp); err != nil {
 		// don't delete the file here because that is how we detect an interrupted restore
 		return vterrors.Wrap(err, "failed to restore files")
 	}
@@ -1093,7 +1213,7 @@ func (be *BuiltinBackupEngine) executeRestoreFullBackup(ctx context.Context, par
 // The underlying mysql database is expected to be up and running.
 func (be *BuiltinBackupEngine) executeRestoreIncrementalBackup(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) error {
 	params.Logger.Infof("Restoring incremental backup to position: %v", bm.Position)
-	createdDir, err := be.restoreFiles(ctx, params, bh, bm)
+	createdDir, err := be.restoreFiles(ctx, params, bh, bm, nil)
 	defer os.RemoveAll(createdDir)
 	mysqld, ok := params.Mysqld.(*Mysqld)
 	if !ok {
@@ -1137,6 +1257,7 @@ func (be *BuiltinBackupEngine) ExecuteRestore(ctx context.Context, params Restor
 	}
 
 	// mark restore as in progress
+	interrupted := RestoreWasInterrupted(params.Cnf)
 	if err := createStateFile(params.Cnf); err != nil {
 		return nil, err
 	}
@@ -1144,7 +1265,7 @@ func (be *BuiltinBackupEngine) ExecuteRestore(ctx context.Context, params Restor
 	if bm.Incremental {
 		err = be.executeRestoreIncrementalBackup(ctx, params, bh, bm)
 	} else {
-		err = be.executeRestoreFullBackup(ctx, params, bh, bm)
+		err = be.executeRestoreFullBackup(ctx, params, bh, bm, interrupted)
 	}
 	if err != nil {
 		return nil, err
@@ -1174,8 +1295,8 @@ func (be *BuiltinBackupEngine) restoreManifest(ctx context.Context, params Resto
 }
 
 // restoreFiles will copy all the files from the BackupStorage to the
-// right place.
-func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) (createdDir string, err error) {
+// right place. The files restored by the interrupted restore rp resumes, if any, are skipped.
+func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest, rp *restoreProgress) (createdDir string, err error) {
 	// For optimization, we are replacing pargzip with pgzip, so newBuiltinDecompressor doesn't have to compare and print warning for every file
 	// since newBuiltinDecompressor is helper method and does not hold any state, it was hard to do it in that method itself.
 	if bm.CompressionEngine == PargzipCompressor {
@@ -1186,14 +1307,32 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}()
 	}
 
+	if bm.cipher, err = openBackupCipher(bm.Encryption); err != nil {
+		return "", vterrors.Wrapf(err, "can't set up the decryption of backup %s", bm.BackupName)
+	}
+
+	if bm.ChunkStore != "" {
+		return "", be.restoreChunkedFiles(ctx, params, bm)
+	}
+
+	sources, err := openRestoreSources(ctx, params, bh)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := sources.close(); cerr != nil {
+			params.Logger.Warningf("Restore: cannot close the sources: %v", cerr)
+		}
+	}()
+
 	if bm.Incremental {
 		createdDir, err = os.MkdirTemp(builtinIncrementalRestorePath, "restore-incremental-*")
 		if err != nil {
 			return "", err
 		}
 	}
-	fes := bm.FileEntries
-	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir)
+	fes := rp.remaining(bm.FileEntries)
+	_ = be.restoreFileEntries(ctx, fes, bh, sources, bm, params, createdDir, rp)
 	if files := bh.GetFailedFiles(); len(files) > 0 {
 		newFEs := make([]FileEntry, len(fes))
 		for _, file := range files {
@@ -1211,7 +1350,7 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.restoreFileEntries(ctx, newFEs, bh, bm, params, createdDir)
+		err = be.restoreFileEntries(ctx, newFEs, bh, sources, bm, params, createdDir, rp)
 		if err != nil {
 			return "", err
 		}
@@ -1219,7 +1358,8 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 	return createdDir, nil
 }
 
-func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, bm builtinBackupManifest, params RestoreParams, createdDir string) error {
+// restoreFileEntries restores the files from the sources, and records their errors in bh.
+func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, sources *restoreSources, bm builtinBackupManifest, params RestoreParams, createdDir string, rp *restoreProgress) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(params.Concurrency)
 
@@ -1245,15 +1385,24 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 			fe.ParentPath = createdDir
 
 			// And restore the file.
-			params.Logger.Infof("Copying file %v: %v %s", name, fe.Name, retryToString(fe.RetryCount))
-			if errRestore := be.restoreFile(ctx, params, bh, fe, bm, name); errRestore != nil {
+			source, err := sources.acquire(name, nil)
+			if err != nil {
+				bh.RecordError(name, err)
+				return err
+			}
+			params.Logger.Infof("Copying file %v: %v from %v %s", name, fe.Name, source.name, retryToString(fe.RetryCount))
+			errRestore := be.restoreFile(ctx, params, source.bh, fe, bm, name)
+			sources.release(source, name, errRestore)
+			if errRestore != nil {
 				bh.RecordError(name, vterrors.Wrapf(errRestore, "failed to restore file %v to %v", name, fe.Name))
 				if fe.RetryCount >= maxRetriesPerFile || vterrors.Code(errRestore) == vtrpcpb.Code_FAILED_PRECONDITION {
 					// this is the last attempt, and we have an error, we can return an error, which will let errgroup
 					// know it can cancel the context
 					return errRestore
 				}
+				return nil
 			}
+			rp.fileRestored(fe, name)
 			return nil
 		})
 	}
@@ -1288,7 +1437,7 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	// Create the backup/source reader and start reporting progress
 	retryStr := retryToString(fe.RetryCount)
-	br := newBackupReader(fe.Name, 0, timedSource)
+	br := newBackupReader(fe.Name, 0, getBandwidthLimiter(params.TopoServer, params.TabletAlias).reader(ctx, timedSource))
 	go br.ReportProgress(ctx, builtinBackupProgress, params.Logger, true, retryStr)
 	defer func() {
 		if err := br.Close(finalErr == nil); err != nil {
@@ -1320,6 +1469,13 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
 
+	// Create the decryptor if needed.
+	if bm.cipher != nil {
+		if reader, err = bm.cipher.newReader(reader); err != nil {
+			return vterrors.Wrap(err, "can't create decryptor")
+		}
+	}
+
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
 		var decompressor io.ReadCloser
@@ -1364,8 +1520,11 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 		}()
 	}
 
-	// Copy the data. Will also write to the hasher.
-	if _, err := io.Copy(bufferedDest, reader); err != nil {
+	// Copy the data. Will also write to the hasher, and to the hasher of the
+	// restored data.
+	dataHasher := crc32.NewIEEE()
+	dataSize, err := io.Copy(io.MultiWriter(bufferedDest, dataHasher), reader)
+	if err != nil {
 		return vterrors.Wrap(err, "failed to copy file contents")
 	}
 
@@ -1380,6 +1539,7 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 		return vterrors.Wrap(err, "failed to flush destination buffer")
 	}
 
+	fe.dataHash, fe.dataSize = hex.EncodeToString(dataHasher.Sum(nil)), dataSize
 	return nil
 }
 
@@ -0,0 +1,547 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/os2"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// Builtin backups and restores which are interrupted, for instance because the
+// process dies, can be resumed when --builtinbackup-resume is set.
+//
+// A backup records the files it has backed up so far in a PARTIAL_MANIFEST, which
+// is written to the backup along with the files, at most every
+// --builtinbackup-progress. The next backup of the same tablet then resumes the
+// interrupted backup, under the same name, and skips the files which are unchanged
+// at their source and still in the backup.
+//
+// A restore records the files it has restored so far in a file next to the restore
+// state file. When a restore of the same backup is interrupted, the next restore
+// keeps the restored files which are unchanged, and only restores the other ones.
+
+const (
+	// backupPartialManifestFileName is the name of the file within a backup which
+	// records the progress of the backup.
+	backupPartialManifestFileName = "PARTIAL_MANIFEST"
+
+	// restoreProgressFileName is the name of the file, in the tablet directory,
+	// which records the progress of a restore.
+	restoreProgressFileName = "restore_progress"
+)
+
+// builtinBackupResume is set when interrupted builtin backups and restores are resumed.
+var builtinBackupResume bool
+
+// builtinBackupPartialManifest records the progress of a builtin backup. A backup is
+// only resumed with the same settings as the interrupted backup.
+type builtinBackupPartialManifest struct {
+	Incremental        bool
+	SkipCompress       bool
+	CompressionEngine  string
+	ExternalCompressor string
+	Encryption         *BackupEncryption `json:",omitempty"`
+
+	// Files are the files which were backed up, by name in the backup.
+	Files map[string]builtinBackupPartialFile
+}
+
+// builtinBackupPartialFile is a file which was backed up by a builtin backup.
+type builtinBackupPartialFile struct {
+	FileEntry
+
+	// DataHash and DataSize are the hash and size of the source file.
+	DataHash string
+	DataSize int64
+}
+
+// sameSettings returns true if the files of a backup with the given settings can be
+// reused by another backup.
+func (pm *builtinBackupPartialManifest) sameSettings(other *builtinBackupPartialManifest) bool {
+	if pm.Incremental != other.Incremental ||
+		pm.SkipCompress != other.SkipCompress ||
+		pm.CompressionEngine != other.CompressionEngine ||
+		pm.ExternalCompressor != other.ExternalCompressor {
+		return false
+	}
+	if pm.Encryption == nil || other.Encryption == nil {
+		return pm.Encryption == nil && other.Encryption == nil
+	}
+	return pm.Encryption.KeyID == other.Encryption.KeyID
+}
+
+// hashFile returns the hash and the size of a file, computed in the same way as the
+// hash of the data of a FileEntry.
+func hashFile(name string) (string, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := crc32.NewIEEE()
+	n, err := io.Copy(h, bufio.NewReaderSize(f, 1024*1024))
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// readBackupPartialManifest reads the PARTIAL_MANIFEST of a backup.
+func readBackupPartialManifest(ctx context.Context, bh backupstorage.BackupHandle) (*builtinBackupPartialManifest, error) {
+	file, err := bh.ReadFile(ctx, backupPartialManifestFileName)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't read %v", backupPartialManifestFileName)
+	}
+	defer file.Close()
+
+	pm := &builtinBackupPartialManifest{}
+	if err := json.NewDecoder(file).Decode(pm); err != nil {
+		return nil, vterrors.Wrapf(err, "can't decode %v", backupPartialManifestFileName)
+	}
+	return pm, nil
+}
+
+// findBackupToResume returns the name of the interrupted backup of the tablet which a
+// new backup resumes, or an empty string if there is none. Only the most recent backup
+// of the tablet is resumed, if it is incomplete and recorded its progress. The resumed
+// backup keeps its name, and the BackupTime of its MANIFEST is the time at which it was
+// resumed, by which Restore and the backup retention policies order it.
+func findBackupToResume(ctx context.Context, bs backupstorage.BackupStorage, dir string, params BackupParams) (string, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return "", vterrors.Wrap(err, "ListBackups failed")
+	}
+	for i := len(bhs) - 1; i >= 0; i-- {
+		bh := bhs[i]
+		_, alias, err := ParseBackupName(dir, bh.Name())
+		if err != nil || alias == nil || topoproto.TabletAliasString(alias) != params.TabletAlias {
+			continue
+		}
+		if _, err := GetBackupManifest(ctx, bh); err == nil {
+			return "", nil
+		}
+		pm, err := readBackupPartialManifest(ctx, bh)
+		if err != nil || pm.Incremental != isIncrementalBackup(params) {
+			return "", nil
+		}
+		return bh.Name(), nil
+	}
+	return "", nil
+}
+
+// backupProgress records the progress of a builtin backup in its
+// PARTIAL_MANIFEST, and skips the files which were backed up by the interrupted
+// backup it resumes.
+type backupProgress struct {
+	// bh is a handle on the backup used to write the PARTIAL_MANIFEST, so that failing
+	// to write it does not fail the backup. It's ended once the backup is done.
+	bh     backupstorage.BackupHandle
+	logger logutil.Logger
+
+	// rbh is a read-only handle on the interrupted backup, and resumed are the files
+	// it backed up.
+	rbh     backupstorage.BackupHandle
+	resumed map[string]builtinBackupPartialFile
+
+	mu        sync.Mutex
+	manifest  *builtinBackupPartialManifest
+	lastWrite time.Time
+	// writing is true while the PARTIAL_MANIFEST is written. The files which are
+	// backed up meanwhile are recorded by the next write.
+	writing bool
+	closed  bool
+}
+
+// openBackupProgress starts recording the progress of a backup. If the backup resumes
+// an interrupted backup with the same settings, the returned cipher is the one of the
+// interrupted backup.
+func openBackupProgress(ctx context.Context, params BackupParams, bs backupstorage.BackupStorage, bh backupstorage.BackupHandle, bc *backupCipher) (*backupProgress, *backupCipher, error) {
+	pm := &builtinBackupPartialManifest{
+		Incremental:        isIncrementalBackup(params),
+		SkipCompress:       !backupStorageCompress,
+		CompressionEngine:  CompressionEngineName,
+		ExternalCompressor: ExternalCompressorCmd,
+		Files:              make(map[string]builtinBackupPartialFile),
+	}
+	if bc != nil {
+		pm.Encryption = bc.encryption
+	}
+	bp := &backupProgress{
+		logger:    params.Logger,
+		manifest:  pm,
+		lastWrite: time.Now(),
+	}
+
+	bhs, err := bs.ListBackups(ctx, bh.Directory())
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	for _, rbh := range bhs {
+		if rbh.Name() != bh.Name() {
+			continue
+		}
+		interrupted, err := readBackupPartialManifest(ctx, rbh)
+		if err != nil {
+			break
+		}
+		if !interrupted.sameSettings(pm) {
+			params.Logger.Infof("Not resuming backup %v, which was taken with different settings", bh.Name())
+			break
+		}
+		if interrupted.Encryption != nil {
+			if bc, err = openBackupCipher(interrupted.Encryption); err != nil {
+				return nil, nil, vterrors.Wrapf(err, "can't set up the encryption of the interrupted backup %v", bh.Name())
+			}
+			pm.Encryption = interrupted.Encryption
+		}
+		params.Logger.Infof("Resuming backup %v, which backed up %v files", bh.Name(), len(interrupted.Files))
+		bp.rbh = rbh
+		bp.resumed = interrupted.Files
+	}
+
+	if bp.bh, err = bs.ReopenBackup(ctx, bh.Directory(), bh.Name()); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "can't record the progress of backup %v", bh.Name())
+	}
+	return bp, bc, nil
+}
+
+// resume returns true if the file was backed up by the interrupted backup, its source
+// is unchanged and it's still in the backup, in which case the FileEntry is updated
+// from the interrupted backup.
+//
+// The hash recorded by the interrupted backup is trusted, and the file in the backup
+// is not read back. A file is recorded once its writer is closed, but some backup
+// storages, e.g. S3, finish uploading it in the background, so the interrupted backup
+// may have recorded a file which was not uploaded yet. Such storages only make a file
+// visible once it's completely uploaded, so checking that it exists is enough.
+func (bp *backupProgress) resume(ctx context.Context, params BackupParams, fe *FileEntry, name string) bool {
+	if bp == nil {
+		return false
+	}
+	pf, ok := bp.resumed[name]
+	if !ok || pf.Base != fe.Base || pf.Name != fe.Name {
+		return false
+	}
+	fullPath, err := fe.fullPath(params.Cnf)
+	if err != nil {
+		return false
+	}
+	hash, size, err := hashFile(fullPath)
+	if err != nil || hash != pf.DataHash || size != pf.DataSize {
+		return false
+	}
+	if !bp.backupFileExists(ctx, name) {
+		return false
+	}
+	params.Logger.Infof("Skipping file %v: %v, which was backed up by the interrupted backup", name, fe.Name)
+	fe.Hash = pf.Hash
+	fe.dataHash, fe.dataSize = pf.DataHash, pf.DataSize
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.manifest.Files[name] = pf
+	return true
+}
+
+// backupFileExists returns true if the file is in the interrupted backup. The file is
+// opened, but not read.
+func (bp *backupProgress) backupFileExists(ctx context.Context, name string) bool {
+	r, err := bp.rbh.ReadFile(ctx, name)
+	if err != nil {
+		return false
+	}
+	_ = r.Close()
+	return true
+}
+
+// fileBackedUp records that a file was backed up, and writes the PARTIAL_MANIFEST if it
+// was not written during the last --builtinbackup-progress, and is not being written.
+func (bp *backupProgress) fileBackedUp(ctx context.Context, fe *FileEntry, name string) {
+	if bp == nil {
+		return
+	}
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.manifest.Files[name] = builtinBackupPartialFile{
+		FileEntry: FileEntry{
+			Base:       fe.Base,
+			Name:       fe.Name,
+			Hash:       fe.Hash,
+			ParentPath: fe.ParentPath,
+		},
+		DataHash: fe.dataHash,
+		DataSize: fe.dataSize,
+	}
+	if bp.writing || time.Since(bp.lastWrite) < builtinBackupProgress {
+		return
+	}
+	data, err := json.MarshalIndent(bp.manifest, "", "  ")
+	if err != nil {
+		bp.logger.Warningf("Failed to record the progress of the backup in %v: %v", backupPartialManifestFileName, err)
+		return
+	}
+	bp.lastWrite = time.Now()
+	bp.writing = true
+	// The other files are backed up while the PARTIAL_MANIFEST is written.
+	bp.mu.Unlock()
+	err = bp.write(ctx, data)
+	bp.mu.Lock()
+	bp.writing = false
+	if err != nil {
+		bp.logger.Warningf("Failed to record the progress of the backup in %v: %v", backupPartialManifestFileName, err)
+	}
+}
+
+// write writes the PARTIAL_MANIFEST. The backup storage may finish writing it in the
+// background, so a write can overwrite a more recent one. The PARTIAL_MANIFEST then
+// records fewer files than were backed up, which are backed up again if the backup
+// is resumed.
+func (bp *backupProgress) write(ctx context.Context, data []byte) error {
+	defer bp.bh.ResetErrorForFile(backupPartialManifestFileName)
+	wc, err := bp.bh.AddFile(ctx, backupPartialManifestFileName, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	_, err = wc.Write(data)
+	return errors.Join(err, wc.Close())
+}
+
+// close records the files which were backed up since the last write of the
+// PARTIAL_MANIFEST, and waits for the PARTIAL_MANIFEST to be written. It must be
+// called once no more files are backed up, before the MANIFEST is written.
+// Calling it again does nothing.
+func (bp *backupProgress) close(ctx context.Context) {
+	if bp == nil {
+		return
+	}
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	if bp.closed {
+		return
+	}
+	bp.closed = true
+	data, err := json.MarshalIndent(bp.manifest, "", "  ")
+	if err == nil {
+		err = bp.write(ctx, data)
+	}
+	if err := errors.Join(err, bp.bh.EndBackup(ctx)); err != nil {
+		bp.logger.Warningf("Failed to record the progress of the backup in %v: %v", backupPartialManifestFileName, err)
+	}
+}
+
+// restoredFile is a file which was restored, as recorded in the progress of a
+// restore.
+type restoredFile struct {
+	// Backup is the directory and name of the backup the file was restored from.
+	Backup string
+	// File is the name of the file in the backup.
+	File string
+
+	Base     string
+	Name     string
+	DataHash string
+	DataSize int64
+}
+
+// restoreProgress records the progress of a builtin restore, one restored file
+// per line, and skips the files which were restored by the interrupted restore it
+// resumes.
+type restoreProgress struct {
+	backup string
+	logger logutil.Logger
+
+	// restored are the files restored by the interrupted restore, by name in the
+	// backup, and verified are the ones which are unchanged since.
+	restored map[string]restoredFile
+	verified map[string]bool
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openRestoreProgress starts recording the progress of a restore. If the previous
+// restore was interrupted, the files it restored from the same backup are loaded.
+func openRestoreProgress(params RestoreParams, bm builtinBackupManifest, interrupted bool) (*restoreProgress, error) {
+	rp := &restoreProgress{
+		backup:   path.Join(GetBackupDir(bm.Keyspace, bm.Shard), bm.BackupName),
+		logger:   params.Logger,
+		restored: make(map[string]restoredFile),
+		verified: make(map[string]bool),
+	}
+	name := filepath.Join(params.Cnf.TabletDir(), restoreProgressFileName)
+	if interrupted {
+		if err := rp.load(name); err != nil {
+			params.Logger.Infof("Not resuming the interrupted restore: %v", err)
+			clear(rp.restored)
+		}
+	}
+
+	var err error
+	if len(rp.restored) > 0 {
+		rp.file, err = os.OpenFile(name, os.O_WRONLY|os.O_APPEND, os2.PermFile)
+	} else {
+		rp.file, err = os2.Create(name)
+	}
+	if err != nil {
+		return nil, vterrors.Wrap(err, "can't record the progress of the restore")
+	}
+	return rp, nil
+}
+
+// load reads the files restored by the interrupted restore. A truncated last line,
+// written when the restore was interrupted, is ignored.
+func (rp *restoreProgress) load(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rf restoredFile
+		if err := json.Unmarshal(scanner.Bytes(), &rf); err != nil {
+			break
+		}
+		if rf.Backup == rp.backup {
+			rp.restored[rf.File] = rf
+		}
+	}
+	return scanner.Err()
+}
+
+// verify checks which files restored by the interrupted restore are unchanged, and
+// returns their paths.
+func (rp *restoreProgress) verify(ctx context.Context, params RestoreParams, fes []FileEntry) (map[string]bool, error) {
+	if rp == nil || len(rp.restored) == 0 {
+		return nil, nil
+	}
+	var mu sync.Mutex
+	keep := make(map[string]bool)
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(params.Concurrency, 1))
+	for i := range fes {
+		fe := &fes[i]
+		name := strconv.Itoa(i)
+		rf, ok := rp.restored[name]
+		if !ok || rf.Base != fe.Base || rf.Name != fe.Name {
+			continue
+		}
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fullPath, err := fe.fullPath(params.Cnf)
+			if err != nil {
+				return nil
+			}
+			hash, size, err := hashFile(fullPath)
+			if err != nil || hash != rf.DataHash || size != rf.DataSize {
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			keep[path.Clean(fullPath)] = true
+			rp.verified[name] = true
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	params.Logger.Infof("Resuming the interrupted restore of %v, which restored %v unchanged files", rp.backup, len(rp.verified))
+	return keep, nil
+}
+
+// remaining returns the files to restore, with an empty entry for the files which
+// were restored by the interrupted restore, so that the index of the other ones is
+// unchanged.
+func (rp *restoreProgress) remaining(fes []FileEntry) []FileEntry {
+	if rp == nil || len(rp.verified) == 0 {
+		return fes
+	}
+	remaining := make([]FileEntry, len(fes))
+	for i, fe := range fes {
+		if !rp.verified[strconv.Itoa(i)] {
+			remaining[i] = fe
+		}
+	}
+	return remaining
+}
+
+// fileRestored records that a file was restored.
+func (rp *restoreProgress) fileRestored(fe *FileEntry, name string) {
+	if rp == nil {
+		return
+	}
+	data, err := json.Marshal(restoredFile{
+		Backup:   rp.backup,
+		File:     name,
+		Base:     fe.Base,
+		Name:     fe.Name,
+		DataHash: fe.dataHash,
+		DataSize: fe.dataSize,
+	})
+	if err != nil {
+		return
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if _, err := rp.file.Write(append(data, '\n')); err != nil {
+		rp.logger.Warningf("Failed to record the progress of the restore: %v", err)
+	}
+}
+
+// close stops recording the progress of the restore.
+func (rp *restoreProgress) close() {
+	if rp == nil {
+		return
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	_ = rp.file.Close()
+}
+
+// removeRestoreProgress removes the progress of a restore, once it is complete.
+func removeRestoreProgress(cnf *Mycnf) error {
+	name := filepath.Join(cnf.TabletDir(), restoreProgressFileName)
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
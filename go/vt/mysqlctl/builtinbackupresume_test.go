@@ -0,0 +1,296 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+const testResumedBackup = "2026-10-19.120000.zone1-0000000100"
+
+// setupResumableBackups enables resumable backups to a file backup storage for the duration of the test.
+func setupResumableBackups(t *testing.T) backupstorage.BackupStorage {
+	oldRoot := filebackupstorage.FileBackupStorageRoot
+	oldImplementation := backupstorage.BackupStorageImplementation
+	oldResume := builtinBackupResume
+	oldProgress := builtinBackupProgress
+	oldCompress := backupStorageCompress
+	oldEngine := CompressionEngineName
+	t.Cleanup(func() {
+		filebackupstorage.FileBackupStorageRoot = oldRoot
+		backupstorage.BackupStorageImplementation = oldImplementation
+		builtinBackupResume = oldResume
+		builtinBackupProgress = oldProgress
+		backupStorageCompress = oldCompress
+		CompressionEngineName = oldEngine
+	})
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	backupstorage.BackupStorageImplementation = "file"
+	builtinBackupResume = true
+	// The progress is recorded after every file.
+	builtinBackupProgress = time.Nanosecond
+	backupStorageCompress = true
+	CompressionEngineName = ZstdCompressor
+
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	return bs
+}
+
+func writeResumableFiles(t *testing.T, dir string, contents map[string]string) []FileEntry {
+	var fes []FileEntry
+	for _, name := range []string{"t1.ibd", "t2.ibd", "t3.ibd"} {
+		require.NoError(t, os.WriteFile(path.Join(dir, name), []byte(contents[name]), 0o644))
+		fes = append(fes, FileEntry{Base: backupData, Name: name})
+	}
+	return fes
+}
+
+func TestResumeBackup(t *testing.T) {
+	ctx := context.Background()
+	bs := setupResumableBackups(t)
+	be := &BuiltinBackupEngine{}
+	contents := map[string]string{"t1.ibd": "one", "t2.ibd": "two", "t3.ibd": "three"}
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	params := BackupParams{
+		Cnf:         cnf,
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+		TabletAlias: "zone1-0000000100",
+	}
+
+	// The backup is interrupted once its files are backed up, before its MANIFEST
+	// is written.
+	bh, err := bs.StartBackup(ctx, "ks/-80", testResumedBackup)
+	require.NoError(t, err)
+	bp, bc, err := openBackupProgress(ctx, params, bs, bh, nil)
+	require.NoError(t, err)
+	assert.Nil(t, bc)
+	assert.Nil(t, bp.resumed)
+	fes := writeResumableFiles(t, cnf.DataDir, contents)
+	require.NoError(t, be.backupFileEntries(ctx, fes, bh, params, nil, bp))
+	bp.close(ctx)
+
+	bhs, err := bs.ListBackups(ctx, "ks/-80")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	pm, err := readBackupPartialManifest(ctx, bhs[0])
+	require.NoError(t, err)
+	assert.Len(t, pm.Files, 3)
+
+	name, err := findBackupToResume(ctx, bs, "ks/-80", params)
+	require.NoError(t, err)
+	assert.Equal(t, testResumedBackup, name)
+	other := params
+	other.TabletAlias = "zone1-0000000101"
+	name, err = findBackupToResume(ctx, bs, "ks/-80", other)
+	require.NoError(t, err)
+	assert.Empty(t, name)
+
+	// The second file is modified at its source, and the third one is missing from
+	// the backup.
+	contents["t2.ibd"] = "modified"
+	fes = writeResumableFiles(t, cnf.DataDir, contents)
+	backupFile := path.Join(filebackupstorage.FileBackupStorageRoot, "ks/-80", testResumedBackup, "2")
+	require.NoError(t, os.Remove(backupFile))
+
+	logger := logutil.NewMemoryLogger()
+	params.Logger = logger
+	bh, err = bs.ReopenBackup(ctx, "ks/-80", testResumedBackup)
+	require.NoError(t, err)
+	bp, _, err = openBackupProgress(ctx, params, bs, bh, nil)
+	require.NoError(t, err)
+	require.Len(t, bp.resumed, 3)
+	require.NoError(t, be.backupFileEntries(ctx, fes, bh, params, nil, bp))
+	bp.close(ctx)
+	assert.Contains(t, logger.String(), "Skipping file 0: t1.ibd")
+	assert.NotContains(t, logger.String(), "Skipping file 1")
+	assert.NotContains(t, logger.String(), "Skipping file 2")
+
+	// The resumed backup restores every file.
+	bm := builtinBackupManifest{
+		BackupManifest:    BackupManifest{BackupName: testResumedBackup, Keyspace: "ks", Shard: "-80"},
+		FileEntries:       fes,
+		CompressionEngine: ZstdCompressor,
+	}
+	restoreParams := RestoreParams{
+		Cnf:         &Mycnf{DataDir: t.TempDir()},
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}
+	bhs, err = bs.ListBackups(ctx, "ks/-80")
+	require.NoError(t, err)
+	_, err = be.restoreFiles(ctx, restoreParams, bhs[0], bm, nil)
+	require.NoError(t, err)
+	for name, content := range contents {
+		restored, err := os.ReadFile(path.Join(restoreParams.Cnf.DataDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(restored))
+	}
+
+	// A complete backup is not resumed.
+	bh, err = bs.ReopenBackup(ctx, "ks/-80", testResumedBackup)
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(wc).Encode(bm))
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+	name, err = findBackupToResume(ctx, bs, "ks/-80", params)
+	require.NoError(t, err)
+	assert.Empty(t, name)
+}
+
+func TestResumeRestore(t *testing.T) {
+	ctx := context.Background()
+	bs := setupResumableBackups(t)
+	be := &BuiltinBackupEngine{}
+	contents := map[string]string{"t1.ibd": "one", "t2.ibd": "two", "t3.ibd": "three"}
+	backupCnf := &Mycnf{DataDir: t.TempDir()}
+	fes := writeResumableFiles(t, backupCnf.DataDir, contents)
+	bh, err := bs.StartBackup(ctx, "ks/-80", testResumedBackup)
+	require.NoError(t, err)
+	require.NoError(t, be.backupFileEntries(ctx, fes, bh, BackupParams{
+		Cnf:         backupCnf,
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}, nil, nil))
+	bm := builtinBackupManifest{
+		BackupManifest:    BackupManifest{BackupName: testResumedBackup, Keyspace: "ks", Shard: "-80"},
+		FileEntries:       fes,
+		CompressionEngine: ZstdCompressor,
+	}
+	bhs, err := bs.ListBackups(ctx, "ks/-80")
+	require.NoError(t, err)
+	rbh := bhs[0]
+
+	root := t.TempDir()
+	cnf := &Mycnf{
+		DataDir:               path.Join(root, "data"),
+		InnodbDataHomeDir:     path.Join(root, "innodb"),
+		InnodbLogGroupHomeDir: path.Join(root, "log"),
+		BinLogPath:            path.Join(root, "binlog"),
+		RelayLogPath:          path.Join(root, "relaylog"),
+		RelayLogIndexPath:     path.Join(root, "relaylog.index"),
+		RelayLogInfoPath:      path.Join(root, "relaylog.info"),
+	}
+	params := RestoreParams{
+		Cnf:         cnf,
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}
+	rp, err := openRestoreProgress(params, bm, false)
+	require.NoError(t, err)
+	_, err = be.restoreFiles(ctx, params, rbh, bm, rp)
+	require.NoError(t, err)
+	rp.close()
+
+	// The restore is interrupted while the second file is restored, and a
+	// truncated line is recorded.
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, "t2.ibd"), []byte("tw"), 0o644))
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, "stray"), nil, 0o644))
+	progress, err := os.OpenFile(path.Join(cnf.TabletDir(), restoreProgressFileName), os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = progress.WriteString(`{"Backup":"ks/-80/` + testResumedBackup)
+	require.NoError(t, err)
+	require.NoError(t, progress.Close())
+
+	logger := logutil.NewMemoryLogger()
+	params.Logger = logger
+	rp, err = openRestoreProgress(params, bm, true)
+	require.NoError(t, err)
+	defer rp.close()
+	require.Len(t, rp.restored, 3)
+	keep, err := rp.verify(ctx, params, bm.FileEntries)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		path.Join(cnf.DataDir, "t1.ibd"): true,
+		path.Join(cnf.DataDir, "t3.ibd"): true,
+	}, keep)
+	remaining := rp.remaining(bm.FileEntries)
+	assert.Empty(t, remaining[0].Name)
+	assert.Equal(t, "t2.ibd", remaining[1].Name)
+	assert.Empty(t, remaining[2].Name)
+
+	require.NoError(t, removeExistingFiles(cnf, keep))
+	assert.NoFileExists(t, path.Join(cnf.DataDir, "stray"))
+	assert.NoFileExists(t, path.Join(cnf.DataDir, "t2.ibd"))
+	assert.FileExists(t, path.Join(cnf.DataDir, "t1.ibd"))
+
+	_, err = be.restoreFiles(ctx, params, rbh, bm, rp)
+	require.NoError(t, err)
+	assert.NotContains(t, logger.String(), "Copying file 0")
+	assert.Contains(t, logger.String(), "Copying file 1")
+	assert.NotContains(t, logger.String(), "Copying file 2")
+	for name, content := range contents {
+		restored, err := os.ReadFile(path.Join(cnf.DataDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(restored))
+	}
+
+	// The progress of a restore from another backup is ignored.
+	bm.BackupName = "2026-10-19.130000.zone1-0000000100"
+	other, err := openRestoreProgress(params, bm, true)
+	require.NoError(t, err)
+	defer other.close()
+	assert.Empty(t, other.restored)
+}
+
+func TestBackupProgressWrites(t *testing.T) {
+	ctx := context.Background()
+	setupResumableBackups(t)
+	bh := &FakeBackupHandle{AddFileReturn: FakeBackupHandleAddFileReturn{WriteCloser: &verificationWriter{}}}
+	bp := &backupProgress{
+		bh:       bh,
+		logger:   logutil.NewMemoryLogger(),
+		manifest: &builtinBackupPartialManifest{Files: make(map[string]builtinBackupPartialFile)},
+	}
+
+	// The files backed up while the PARTIAL_MANIFEST is written are recorded by
+	// the next write.
+	bp.fileBackedUp(ctx, &FileEntry{Base: backupData, Name: "t1.ibd"}, "0")
+	bp.writing = true
+	bp.fileBackedUp(ctx, &FileEntry{Base: backupData, Name: "t2.ibd"}, "1")
+	bp.writing = false
+	assert.Len(t, bh.AddFileCalls, 1)
+	assert.Len(t, bp.manifest.Files, 2)
+
+	// The handle is only ended once the backup is done.
+	assert.Empty(t, bh.EndBackupCalls)
+	bp.close(ctx)
+	assert.Len(t, bh.AddFileCalls, 2)
+	assert.Len(t, bh.EndBackupCalls, 1)
+	bp.close(ctx)
+	assert.Len(t, bh.EndBackupCalls, 1)
+}
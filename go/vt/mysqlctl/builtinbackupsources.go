@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// A builtin restore can read the files of a backup from several backup storages
+// which hold copies of it, e.g. the tiers of the tiered backup storage, or
+// buckets replicated in other regions. The storages are listed with
+// --builtinbackup-restore-sources, in addition to the backup storage the backup
+// was found in.
+//
+// Each file, or each chunk of a chunked backup, is read from the source which
+// has the fewest reads in progress, so that the reads of a restore spread over
+// all the sources. When a read fails, e.g. because the copy of a file is
+// corrupted, the retry is made from another source. The bandwidth limits apply
+// to the reads from all the sources.
+
+var (
+	// builtinRestoreSources are the backup storage implementations which the
+	// builtin restores read the files from, along with the backup storage.
+	builtinRestoreSources []string
+)
+
+// restoreSource is a backup storage which holds a copy of the backup which is
+// restored.
+type restoreSource struct {
+	name string
+	// bs is the backup storage of the source, which is closed with the sources.
+	// It's nil for the backup storage the backup was found in.
+	bs backupstorage.BackupStorage
+	// bh is the handle of the backup in the source, for non-chunked backups.
+	bh backupstorage.BackupHandle
+	// cs is the chunk store of the backup in the source, for chunked backups.
+	cs *backupChunkStore
+
+	// reads is the number of reads in progress.
+	reads int
+}
+
+// restoreSources are the sources which the files of a restore are read from.
+type restoreSources struct {
+	mu      sync.Mutex
+	sources []*restoreSource
+	// failed are the sources which failed to read a file or a chunk, by name.
+	failed map[string][]*restoreSource
+}
+
+func newRestoreSources(primary *restoreSource) *restoreSources {
+	return &restoreSources{
+		sources: []*restoreSource{primary},
+		failed:  make(map[string][]*restoreSource),
+	}
+}
+
+// openRestoreSources returns the sources of the backup bh, which was found in
+// the backup storage. The sources which don't hold the backup are skipped.
+func openRestoreSources(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle) (*restoreSources, error) {
+	rs := newRestoreSources(&restoreSource{name: backupstorage.BackupStorageImplementation, bh: bh})
+	err := rs.open(params, func(src *restoreSource) (bool, error) {
+		bhs, err := src.bs.ListBackups(ctx, bh.Directory())
+		if err != nil {
+			return false, err
+		}
+		for _, sbh := range bhs {
+			if sbh.Name() == bh.Name() {
+				src.bh = sbh
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// openRestoreChunkSources returns the sources of the chunks of a chunked
+// backup, whose chunk store cs was opened in the backup storage.
+func openRestoreChunkSources(ctx context.Context, params RestoreParams, cs *backupChunkStore) (*restoreSources, error) {
+	rs := newRestoreSources(&restoreSource{name: backupstorage.BackupStorageImplementation, cs: cs})
+	err := rs.open(params, func(src *restoreSource) (bool, error) {
+		scs, err := openBackupChunkStore(ctx, src.bs, cs.dir, cs.chunkSize)
+		if err != nil {
+			return false, err
+		}
+		src.cs = scs
+		return len(scs.chunks) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// open adds the sources of --builtinbackup-restore-sources for which find
+// returns true.
+func (rs *restoreSources) open(params RestoreParams, find func(src *restoreSource) (bool, error)) error {
+	for _, name := range builtinRestoreSources {
+		if slices.ContainsFunc(rs.sources, func(src *restoreSource) bool { return src.name == name }) {
+			continue
+		}
+		bs, ok := backupstorage.BackupStorageMap[name]
+		if !ok {
+			return errors.Join(vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no registered implementation of BackupStorage named %q in --builtinbackup-restore-sources", name), rs.close())
+		}
+		src := &restoreSource{
+			name: name,
+			bs:   bs.WithParams(backupstorage.Params{Logger: params.Logger, Stats: params.Stats}),
+		}
+		found, err := find(src)
+		if err != nil || !found {
+			// The restore can still read the files from the other sources.
+			if err != nil {
+				params.Logger.Warningf("Restore: cannot read the backup from source %v: %v", name, err)
+			} else {
+				params.Logger.Warningf("Restore: the backup is missing from source %v", name)
+			}
+			if err := src.bs.Close(); err != nil {
+				params.Logger.Warningf("Restore: cannot close source %v: %v", name, err)
+			}
+			continue
+		}
+		rs.sources = append(rs.sources, src)
+	}
+	if len(rs.sources) > 1 {
+		names := make([]string, 0, len(rs.sources))
+		for _, src := range rs.sources {
+			names = append(names, src.name)
+		}
+		params.Logger.Infof("Restore: reading the files from %v sources: %v", len(names), names)
+	}
+	return nil
+}
+
+// acquire returns the source to read a file or a chunk from: among the sources
+// which hold it, the one with the fewest reads in progress, preferring the ones
+// which did not fail to read it yet. release must be called once it's read.
+func (rs *restoreSources) acquire(name string, holds func(src *restoreSource) bool) (*restoreSource, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	var best *restoreSource
+	bestFailed := false
+	for _, src := range rs.sources {
+		if holds != nil && !holds(src) {
+			continue
+		}
+		failed := slices.Contains(rs.failed[name], src)
+		if best == nil || (bestFailed && !failed) || (failed == bestFailed && src.reads < best.reads) {
+			best, bestFailed = src, failed
+		}
+	}
+	if best == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "%v is missing from all the sources of the restore", name)
+	}
+	best.reads++
+	return best, nil
+}
+
+// release records the end of a read from the source, and whether it failed.
+func (rs *restoreSources) release(src *restoreSource, name string, err error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	src.reads--
+	if err != nil && !slices.Contains(rs.failed[name], src) {
+		rs.failed[name] = append(rs.failed[name], src)
+	}
+}
+
+// holdsChunk returns a function which tells if a source holds a chunk.
+func holdsChunk(chunk string) func(src *restoreSource) bool {
+	return func(src *restoreSource) bool {
+		_, err := src.cs.handle(chunk)
+		return err == nil
+	}
+}
+
+// close closes the backup storages of the sources.
+func (rs *restoreSources) close() error {
+	var err error
+	for _, src := range rs.sources {
+		if src.bs != nil {
+			err = errors.Join(err, src.bs.Close())
+		}
+	}
+	return err
+}
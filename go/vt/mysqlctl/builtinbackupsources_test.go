@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage/memorybackupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+// setRestoreSources registers the backup storages and reads the restores from
+// them, in order, for the duration of the test.
+func setRestoreSources(t *testing.T, names []string, storages []backupstorage.BackupStorage) {
+	oldSources := builtinRestoreSources
+	t.Cleanup(func() {
+		builtinRestoreSources = oldSources
+		for _, name := range names {
+			delete(backupstorage.BackupStorageMap, name)
+		}
+	})
+	for i, name := range names {
+		backupstorage.BackupStorageMap[name] = storages[i]
+	}
+	builtinRestoreSources = names
+}
+
+func TestRestoreSourcesAcquire(t *testing.T) {
+	primary := &restoreSource{name: "primary"}
+	mirror := &restoreSource{name: "mirror"}
+	rs := newRestoreSources(primary)
+	rs.sources = append(rs.sources, mirror)
+
+	// The reads spread over the sources.
+	src0, err := rs.acquire("0", nil)
+	require.NoError(t, err)
+	assert.Equal(t, primary, src0)
+	src1, err := rs.acquire("1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, mirror, src1)
+	rs.release(src0, "0", nil)
+	src2, err := rs.acquire("2", nil)
+	require.NoError(t, err)
+	assert.Equal(t, primary, src2)
+
+	// A read which failed is retried from another source, even if it's busier.
+	rs.release(src1, "1", errors.New("corrupted"))
+	src1, err = rs.acquire("1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, primary, src1)
+	rs.release(src1, "1", errors.New("corrupted"))
+	// Once it failed from all the sources, the least busy one is used again.
+	src1, err = rs.acquire("1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, mirror, src1)
+	rs.release(src1, "1", nil)
+	rs.release(src2, "2", nil)
+
+	// Only the sources which hold a chunk are used.
+	src, err := rs.acquire("chunk", func(src *restoreSource) bool { return src == mirror })
+	require.NoError(t, err)
+	assert.Equal(t, mirror, src)
+	rs.release(src, "chunk", nil)
+	_, err = rs.acquire("chunk", func(src *restoreSource) bool { return false })
+	assert.ErrorContains(t, err, "chunk is missing from all the sources of the restore")
+}
+
+func TestRestoreFromSources(t *testing.T) {
+	ctx := context.Background()
+	oldCompress := backupStorageCompress
+	t.Cleanup(func() {
+		backupStorageCompress = oldCompress
+	})
+	backupStorageCompress = true
+
+	const dir, name = "ks/0", "backup1"
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	var contents [][]byte
+	var fes []FileEntry
+	for i := range 4 {
+		content := make([]byte, 4096)
+		_, err := rand.Read(content)
+		require.NoError(t, err)
+		contents = append(contents, content)
+		fe := FileEntry{Base: backupData, Name: fmt.Sprintf("t%d.ibd", i)}
+		require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, fe.Name), content, 0o644))
+		fes = append(fes, fe)
+	}
+
+	be := &BuiltinBackupEngine{}
+	primary := memorybackupstorage.NewMemoryBackupStorage()
+	bh, err := primary.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	backupParams := BackupParams{
+		Cnf:         cnf,
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 1,
+	}
+	for i := range fes {
+		require.NoError(t, be.backupFile(ctx, backupParams, bh, &fes[i], strconv.Itoa(i), nil))
+	}
+	require.NoError(t, bh.EndBackup(ctx))
+
+	// The mirror holds a copy of the backup, and the other source does not.
+	mirror := memorybackupstorage.NewMemoryBackupStorage()
+	for i := range fes {
+		data, ok := primary.ReadBackupFile(dir, name, strconv.Itoa(i))
+		require.True(t, ok)
+		mirror.WriteBackupFile(dir, name, strconv.Itoa(i), data)
+	}
+	setRestoreSources(t, []string{"mirror", "empty"}, []backupstorage.BackupStorage{mirror, memorybackupstorage.NewMemoryBackupStorage()})
+
+	// A file is corrupted in the backup storage, and another one in the mirror.
+	primary.WriteBackupFile(dir, name, "1", []byte("corrupted"))
+	mirror.WriteBackupFile(dir, name, "2", []byte("corrupted"))
+
+	bhs, err := primary.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	bm := builtinBackupManifest{FileEntries: fes, CompressionEngine: CompressionEngineName}
+	for _, concurrency := range []int{1, 4} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			logger := logutil.NewMemoryLogger()
+			params := RestoreParams{
+				Cnf:         &Mycnf{DataDir: t.TempDir()},
+				Logger:      logger,
+				Stats:       backupstats.NoStats(),
+				Concurrency: concurrency,
+			}
+			_, err := be.restoreFiles(ctx, params, bhs[0], bm, nil)
+			require.NoError(t, err)
+			for i, fe := range fes {
+				restored, err := os.ReadFile(path.Join(params.Cnf.DataDir, fe.Name))
+				require.NoError(t, err)
+				assert.Equal(t, contents[i], restored, fe.Name)
+			}
+			assert.Contains(t, logger.String(), "the backup is missing from source empty")
+			if concurrency == 1 {
+				// The files are read from the backup storage, and the corrupted
+				// one is read again from the mirror.
+				assert.Contains(t, logger.String(), "Copying file 1: t1.ibd from mirror (attempt 2/2)")
+				assert.NotContains(t, logger.String(), "t2.ibd from mirror")
+			}
+		})
+	}
+
+	// A source which is not registered is an error.
+	setRestoreSources(t, []string{"missing"}, []backupstorage.BackupStorage{nil})
+	delete(backupstorage.BackupStorageMap, "missing")
+	_, err = be.restoreFiles(ctx, RestoreParams{Logger: logutil.NewMemoryLogger()}, bhs[0], bm, nil)
+	assert.ErrorContains(t, err, `no registered implementation of BackupStorage named "missing"`)
+}
+
+func TestChunkedRestoreFromSources(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+
+	content := make([]byte, 3*testChunkSize)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, "t1.ibd"), content, 0o644))
+	be := &BuiltinBackupEngine{}
+	cs, err := newBackupChunkStore(ctx, bs, "ks/-80", nil)
+	require.NoError(t, err)
+	fes := []FileEntry{{Base: backupData, Name: "t1.ibd"}}
+	require.NoError(t, be.backupChunkedFiles(ctx, BackupParams{
+		Cnf:         cnf,
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}, cs, fes))
+	require.Len(t, fes[0].Chunks, 3)
+
+	// The mirror holds a copy of the chunk store.
+	mirror := memorybackupstorage.NewMemoryBackupStorage()
+	for _, chunk := range fes[0].Chunks {
+		data, err := os.ReadFile(path.Join(filebackupstorage.FileBackupStorageRoot, cs.dir, chunk, builtinBackupChunkFileName))
+		require.NoError(t, err)
+		mirror.WriteBackupFile(cs.dir, chunk, builtinBackupChunkFileName, data)
+	}
+	setRestoreSources(t, []string{"mirror"}, []backupstorage.BackupStorage{mirror})
+
+	// A chunk is corrupted, and another one is missing from the backup storage.
+	chunkFile := path.Join(filebackupstorage.FileBackupStorageRoot, cs.dir, fes[0].Chunks[0], builtinBackupChunkFileName)
+	require.NoError(t, os.WriteFile(chunkFile, []byte("corrupted"), 0o644))
+	require.NoError(t, bs.RemoveBackup(ctx, cs.dir, fes[0].Chunks[2]))
+
+	bm := builtinBackupManifest{FileEntries: fes, ChunkStore: cs.dir, ChunkSize: cs.chunkSize}
+	params := RestoreParams{
+		Cnf:         &Mycnf{DataDir: t.TempDir()},
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 2,
+	}
+	require.NoError(t, be.restoreChunkedFiles(ctx, params, bm))
+	restored, err := os.ReadFile(path.Join(params.Cnf.DataDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.Equal(t, content, restored)
+}
@@ -69,6 +69,7 @@ type CephBackupHandle struct {
 	dir       string
 	name      string
 	readOnly  bool
+	ended     bool
 	waitGroup sync.WaitGroup
 	errorsbackup.PerFileErrorRecorder
 }
@@ -116,7 +117,11 @@ func (bh *CephBackupHandle) EndBackup(ctx context.Context) error {
 	}
 	bh.waitGroup.Wait()
 	// Return the saved PutObject() errors, if any.
-	return bh.Error()
+	if err := bh.Error(); err != nil {
+		return err
+	}
+	bh.ended = true
+	return nil
 }
 
 // AbortBackup implements BackupHandle.
@@ -129,8 +134,8 @@ func (bh *CephBackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile implements BackupHandle.
 func (bh *CephBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
-	if !bh.readOnly {
-		return nil, errors.New("ReadFile cannot be called on read-write backup")
+	if !bh.readOnly && !bh.ended {
+		return nil, errors.New("ReadFile cannot be called on read-write backup before EndBackup")
 	}
 	// ceph bucket name
 	bucket := alterBucketName(bh.dir)
@@ -222,6 +227,12 @@ func (bs *CephBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	}, nil
 }
 
+// ReopenBackup implements BackupStorage. Objects are added to an existing
+// backup as to a new one.
+func (bs *CephBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *CephBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client()
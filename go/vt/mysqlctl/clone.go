@@ -21,8 +21,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/pflag"
 
 	"vitess.io/vitess/go/mysql"
@@ -32,6 +34,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/servenv"
@@ -45,12 +48,16 @@ import (
 const (
 	clonePluginStatusQuery = "SELECT PLUGIN_STATUS FROM information_schema.PLUGINS WHERE PLUGIN_NAME = 'clone'"
 	cloneStatusQuery       = "SELECT STATE, ERROR_NO, ERROR_MESSAGE FROM performance_schema.clone_status ORDER BY ID DESC LIMIT 1"
+	cloneProgressQuery     = "SELECT STAGE, STATE, ESTIMATE, DATA FROM performance_schema.clone_progress ORDER BY ID"
 )
 
 var (
 	cloneFromPrimary        = false
 	cloneFromTablet         = ""
 	cloneRestartWaitTimeout = 5 * time.Minute
+
+	// cloneProgressInterval is how often the progress of a clone is reported.
+	cloneProgressInterval = 10 * time.Second
 )
 
 func init() {
@@ -103,6 +110,13 @@ func CloneFromDonor(ctx context.Context, topoServer *topo.Server, mysqld MysqlDa
 		return replication.Position{}, fmt.Errorf("failed to get tablet %s from topology: %v", topoproto.TabletAliasString(donorAlias), err)
 	}
 
+	return CloneFromTablet(ctx, mysqld, donorTablet.Tablet, nil)
+}
+
+// CloneFromTablet clones data from the given donor tablet using MySQL CLONE REMOTE.
+// If logger is not nil, the progress of the clone is reported to it while the
+// clone runs. It returns the GTID position of the cloned data.
+func CloneFromTablet(ctx context.Context, mysqld MysqlDaemon, donorTablet *topodatapb.Tablet, logger logutil.Logger) (replication.Position, error) {
 	// Get clone credentials.
 	cloneConfig := dbconfigs.GlobalDBConfigs.CloneUser
 	if cloneConfig.User == "" {
@@ -116,6 +130,7 @@ func CloneFromDonor(ctx context.Context, topoServer *topo.Server, mysqld MysqlDa
 		DonorUser:     cloneConfig.User,
 		DonorPassword: cloneConfig.Password,
 		UseSSL:        cloneConfig.UseSSL,
+		Logger:        logger,
 	}
 
 	log.Info(fmt.Sprintf("Clone executor configured for donor %s:%d", executor.DonorHost, executor.DonorPort))
@@ -150,6 +165,9 @@ type CloneExecutor struct {
 	DonorPassword string
 	// UseSSL indicates whether to use SSL for the clone connection.
 	UseSSL bool
+	// Logger, if set, receives the progress of the clone read from
+	// performance_schema.clone_progress while the clone runs.
+	Logger logutil.Logger
 }
 
 // validateRecipient checks that the recipient MySQL instance meets all prerequisites for cloning.
@@ -304,6 +322,18 @@ func (c *CloneExecutor) ExecuteClone(ctx context.Context, mysqld MysqlDaemon, wa
 
 	log.Info(fmt.Sprintf("Executing CLONE INSTANCE FROM %s:%d (this may take a while)", c.DonorHost, c.DonorPort))
 
+	if c.Logger != nil {
+		progressCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			c.reportCloneProgress(progressCtx, mysqld, cloneProgressInterval)
+		})
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+	}
+
 	// Execute the clone command. When clone completes, MySQL restarts automatically
 	// which will cause the connection to drop. We ignore this error and verify
 	// success by checking clone_status after MySQL comes back up.
@@ -440,3 +470,64 @@ func (c *CloneExecutor) waitForCloneComplete(ctx context.Context, mysqld MysqlDa
 		}
 	}
 }
+
+// reportCloneProgress polls performance_schema.clone_progress and reports the
+// progress of the clone to the logger of the executor until ctx is done. Query
+// errors are ignored, since mysqld restarts at the end of a clone.
+func (c *CloneExecutor) reportCloneProgress(ctx context.Context, mysqld MysqlDaemon, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		result, err := mysqld.FetchSuperQuery(ctx, cloneProgressQuery)
+		if err != nil {
+			continue
+		}
+		progress := formatCloneProgress(result)
+		if progress == "" || progress == last {
+			continue
+		}
+		last = progress
+		c.Logger.Infof("Clone progress from %s:%d: %s", c.DonorHost, c.DonorPort, progress)
+	}
+}
+
+// formatCloneProgress summarizes the rows of performance_schema.clone_progress,
+// which has one row per stage of the clone: it names the stage in progress, or
+// the last completed one, along with the bytes copied so far across all stages.
+func formatCloneProgress(result *sqltypes.Result) string {
+	var (
+		stage, state   string
+		estimate, data uint64
+	)
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		rowState := row[1].ToString()
+		if strings.EqualFold(rowState, "Not Started") {
+			continue
+		}
+		if !strings.EqualFold(state, "In Progress") {
+			stage, state = row[0].ToString(), rowState
+		}
+		rowEstimate, _ := row[2].ToCastUint64()
+		rowData, _ := row[3].ToCastUint64()
+		estimate += rowEstimate
+		data += rowData
+	}
+	if stage == "" {
+		return ""
+	}
+	if estimate == 0 {
+		return fmt.Sprintf("stage %s %s, %s copied", stage, state, humanize.IBytes(data))
+	}
+	return fmt.Sprintf("stage %s %s, %s of %s copied (%d%%)", stage, state,
+		humanize.IBytes(data), humanize.IBytes(estimate), min(data*100/estimate, 100))
+}
@@ -728,3 +728,81 @@ func TestCloneFromDonor(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCloneProgress(t *testing.T) {
+	fields := sqltypes.MakeTestFields("STAGE|STATE|ESTIMATE|DATA", "varchar|varchar|uint64|uint64")
+	tests := []struct {
+		name     string
+		result   *sqltypes.Result
+		expected string
+	}{
+		{
+			name:     "no rows",
+			result:   sqltypes.MakeTestResult(fields),
+			expected: "",
+		},
+		{
+			name: "not started",
+			result: sqltypes.MakeTestResult(fields,
+				"DROP DATA|Not Started|0|0",
+				"FILE COPY|Not Started|0|0",
+			),
+			expected: "",
+		},
+		{
+			name: "file copy in progress",
+			result: sqltypes.MakeTestResult(fields,
+				"DROP DATA|Completed|0|0",
+				"FILE COPY|In Progress|4294967296|1073741824",
+				"PAGE COPY|Not Started|0|0",
+			),
+			expected: "stage FILE COPY In Progress, 1.0 GiB of 4.0 GiB copied (25%)",
+		},
+		{
+			name: "all stages completed",
+			result: sqltypes.MakeTestResult(fields,
+				"DROP DATA|Completed|0|0",
+				"FILE COPY|Completed|1048576|1048576",
+				"PAGE COPY|Completed|1024|1024",
+				"RECOVERY|Completed|0|0",
+			),
+			expected: "stage RECOVERY Completed, 1.0 MiB of 1.0 MiB copied (100%)",
+		},
+		{
+			name: "no estimate",
+			result: sqltypes.MakeTestResult(fields,
+				"DROP DATA|In Progress|0|0",
+			),
+			expected: "stage DROP DATA In Progress, 0 B copied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatCloneProgress(tt.result))
+		})
+	}
+}
+
+func TestReportCloneProgress(t *testing.T) {
+	fmd := NewFakeMysqlDaemon(nil)
+	defer fmd.Close()
+
+	fmd.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		cloneProgressQuery: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("STAGE|STATE|ESTIMATE|DATA", "varchar|varchar|uint64|uint64"),
+			"FILE COPY|In Progress|200|50",
+		),
+	}
+
+	logger := logutil.NewMemoryLogger()
+	executor := &CloneExecutor{DonorHost: "donor", DonorPort: 3306, Logger: logger}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	executor.reportCloneProgress(ctx, fmd, 10*time.Millisecond)
+
+	// The progress does not change, so it is only reported once.
+	require.Len(t, logger.Events, 1)
+	assert.Equal(t, "Clone progress from donor:3306: stage FILE COPY In Progress, 50 B of 200 B copied (25%)", logger.Events[0].Value)
+}
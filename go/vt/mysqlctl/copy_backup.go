@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"strconv"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// CopyBackupParams holds the parameters of CopyBackup.
+type CopyBackupParams struct {
+	Logger logutil.Logger
+	// Keyspace and Shard are used to infer the directory where backups are stored.
+	Keyspace string
+	Shard    string
+	// BackupName is the name of the backup to copy.
+	BackupName string
+	// From is the BackupStorage the backup is copied from.
+	From backupstorage.BackupStorage
+	// To is the BackupStorage the backup is copied to.
+	To backupstorage.BackupStorage
+}
+
+// CopyBackup copies a complete backup between two BackupStorage implementations.
+// The files of the backup are listed by its MANIFEST, which is copied last so
+// that the copy only becomes usable once all of its files are in place. The
+// files are checked against the hashes recorded in the MANIFEST, when the backup
+// engine records them, and every copied file is read back from the destination
+// and checked against the source.
+//
+// The chunks of chunked backups which are missing from the chunk store of the
+// destination are copied along. The data of mysqlshell backups lives outside of
+// the BackupStorage, so only their MANIFEST is copied.
+//
+// It returns the files of the backup which were copied.
+func CopyBackup(ctx context.Context, params CopyBackupParams) ([]backupstorage.BackupFile, error) {
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	src, err := backupstorage.FindBackup(ctx, params.From, backupDir, params.BackupName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := backupstorage.FindBackup(ctx, params.To, backupDir, params.BackupName); err == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "backup %q already exists in %v of the destination", params.BackupName, backupDir)
+	} else if vterrors.Code(err) != vtrpcpb.Code_NOT_FOUND {
+		return nil, err
+	}
+
+	files, chunks, err := BackupFiles(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > 0 {
+		copied, err := CopyBackupChunks(ctx, params.From, params.To, backupDir, chunks)
+		if err != nil {
+			return nil, err
+		}
+		params.Logger.Infof("CopyBackup: copied %v of the %v chunks of backup %v/%v", copied, len(chunks), backupDir, params.BackupName)
+	}
+
+	params.Logger.Infof("CopyBackup: copying %v files of backup %v/%v", len(files), backupDir, params.BackupName)
+	copied, err := backupstorage.CopyBackup(ctx, src, params.To, files)
+	if err != nil {
+		return nil, err
+	}
+	params.Logger.Infof("CopyBackup: copied and verified backup %v/%v", backupDir, params.BackupName)
+	return copied, nil
+}
+
+// BackupFiles returns the files of a complete backup, as listed by its MANIFEST,
+// with their hash when the backup engine records it and the MANIFEST last. It
+// also returns the chunks of the chunk store referenced by chunked backups.
+func BackupFiles(ctx context.Context, bh backupstorage.BackupHandle) ([]backupstorage.BackupFile, []string, error) {
+	manifest, err := GetBackupManifest(ctx, bh)
+	if err != nil {
+		return nil, nil, vterrors.Wrapf(err, "backup %v/%v is incomplete", bh.Directory(), bh.Name())
+	}
+
+	var (
+		files  []backupstorage.BackupFile
+		chunks []string
+	)
+	switch manifest.BackupMethod {
+	case builtinBackupEngineName, "":
+		// The builtin engine is the only one that ever left BackupMethod unset.
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			return nil, nil, err
+		}
+		seen := make(map[string]bool)
+		for i, fe := range bm.FileEntries {
+			if len(fe.Chunks) == 0 {
+				files = append(files, backupstorage.BackupFile{Name: strconv.Itoa(i), Hash: fe.Hash})
+				continue
+			}
+			for _, chunk := range fe.Chunks {
+				if !seen[chunk] {
+					seen[chunk] = true
+					chunks = append(chunks, chunk)
+				}
+			}
+		}
+	case xtrabackupEngineName:
+		var xm xtraBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &xm); err != nil {
+			return nil, nil, err
+		}
+		if xm.NumStripes <= 1 {
+			files = append(files, backupstorage.BackupFile{Name: xm.FileName})
+		} else {
+			for i := range int(xm.NumStripes) {
+				files = append(files, backupstorage.BackupFile{Name: stripeFileName(xm.FileName, i)})
+			}
+		}
+	case mysqlShellBackupEngineName:
+		// The data of the backup is stored in its BackupLocation.
+	default:
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "cannot list the files of backup %v/%v created with %q engine", bh.Directory(), bh.Name(), manifest.BackupMethod)
+	}
+	return append(files, backupstorage.BackupFile{Name: backupManifestFileName}), chunks, nil
+}
+
+// CopyBackupChunks copies the given chunks of the chunk store of the backups in
+// backupDir from a BackupStorage to another, skipping the ones the destination
+// already has. It returns the number of chunks which were copied.
+func CopyBackupChunks(ctx context.Context, fromBs, toBs backupstorage.BackupStorage, backupDir string, chunks []string) (int, error) {
+	dir := backupChunkStoreDir(backupDir)
+	from, err := openBackupChunkStore(ctx, fromBs, dir, 0)
+	if err != nil {
+		return 0, err
+	}
+	to, err := openBackupChunkStore(ctx, toBs, dir, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	for _, chunk := range chunks {
+		if !to.claim(chunk) {
+			continue
+		}
+		bh, err := from.handle(chunk)
+		if err != nil {
+			return copied, err
+		}
+		if _, err := backupstorage.CopyBackup(ctx, bh, toBs, []backupstorage.BackupFile{{Name: builtinBackupChunkFileName}}); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
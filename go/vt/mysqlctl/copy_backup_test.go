@@ -0,0 +1,227 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage/memorybackupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// crc32Hex returns the hash of data, as recorded in the FileEntries of a MANIFEST.
+func crc32Hex(data []byte) string {
+	h := crc32.NewIEEE()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeTestBackup writes a backup with the given files and MANIFEST to bs.
+func writeTestBackup(t *testing.T, bs *memorybackupstorage.MemoryBackupStorage, dir, name string, manifest any, files map[string][]byte) []byte {
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	for file, contents := range files {
+		bs.WriteBackupFile(dir, name, file, contents)
+	}
+	bs.WriteBackupFile(dir, name, backupManifestFileName, data)
+	return data
+}
+
+func TestCopyBackup(t *testing.T) {
+	ctx := context.Background()
+	from, to := memorybackupstorage.NewMemoryBackupStorage(), memorybackupstorage.NewMemoryBackupStorage()
+	manifest := writeTestBackup(t, from, "ks/0", "backup1", builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries: []FileEntry{
+			{Base: backupData, Name: "a", Hash: crc32Hex([]byte("aaa"))},
+			{Base: backupData, Name: "b", Hash: crc32Hex([]byte("bb"))},
+		},
+	}, map[string][]byte{"0": []byte("aaa"), "1": []byte("bb")})
+
+	params := CopyBackupParams{
+		Logger:     logutil.NewMemoryLogger(),
+		Keyspace:   "ks",
+		Shard:      "0",
+		BackupName: "backup1",
+		From:       from,
+		To:         to,
+	}
+	files, err := CopyBackup(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []backupstorage.BackupFile{
+		{Name: "0", Hash: crc32Hex([]byte("aaa")), Size: 3},
+		{Name: "1", Hash: crc32Hex([]byte("bb")), Size: 2},
+		{Name: backupManifestFileName, Hash: crc32Hex(manifest), Size: int64(len(manifest))},
+	}, files)
+	for file, want := range map[string][]byte{"0": []byte("aaa"), "1": []byte("bb"), backupManifestFileName: manifest} {
+		data, ok := to.ReadBackupFile("ks/0", "backup1", file)
+		require.True(t, ok, file)
+		assert.Equal(t, want, data, file)
+	}
+
+	// The backup cannot be copied again.
+	_, err = CopyBackup(ctx, params)
+	assert.Equal(t, vtrpcpb.Code_ALREADY_EXISTS, vterrors.Code(err), err)
+
+	// A missing backup cannot be copied.
+	params.BackupName = "backup2"
+	_, err = CopyBackup(ctx, params)
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err), err)
+}
+
+func TestCopyBackupHashMismatch(t *testing.T) {
+	ctx := context.Background()
+	from, to := memorybackupstorage.NewMemoryBackupStorage(), memorybackupstorage.NewMemoryBackupStorage()
+	writeTestBackup(t, from, "ks/0", "backup1", builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries:    []FileEntry{{Base: backupData, Name: "a", Hash: crc32Hex([]byte("aaa"))}},
+	}, map[string][]byte{"0": []byte("corrupted")})
+
+	_, err := CopyBackup(ctx, CopyBackupParams{
+		Logger:     logutil.NewMemoryLogger(),
+		Keyspace:   "ks",
+		Shard:      "0",
+		BackupName: "backup1",
+		From:       from,
+		To:         to,
+	})
+	require.ErrorContains(t, err, "hash mismatch for 0")
+
+	// The partial copy is removed.
+	bhs, err := to.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	assert.Empty(t, bhs)
+}
+
+func TestCopyBackupChunks(t *testing.T) {
+	ctx := context.Background()
+	from, to := memorybackupstorage.NewMemoryBackupStorage(), memorybackupstorage.NewMemoryBackupStorage()
+	chunkDir := backupChunkStoreDir("ks/0")
+	from.WriteBackupFile(chunkDir, "chunk1", builtinBackupChunkFileName, []byte("c1"))
+	from.WriteBackupFile(chunkDir, "chunk2", builtinBackupChunkFileName, []byte("c2"))
+	// The destination already has the first chunk.
+	to.WriteBackupFile(chunkDir, "chunk1", builtinBackupChunkFileName, []byte("c1"))
+	writeTestBackup(t, from, "ks/0", "backup1", builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries: []FileEntry{
+			{Base: backupData, Name: "a", Chunks: []string{"chunk1", "chunk2"}},
+			{Base: backupData, Name: "b", Chunks: []string{"chunk2"}},
+		},
+	}, nil)
+
+	logger := logutil.NewMemoryLogger()
+	files, err := CopyBackup(ctx, CopyBackupParams{
+		Logger:     logger,
+		Keyspace:   "ks",
+		Shard:      "0",
+		BackupName: "backup1",
+		From:       from,
+		To:         to,
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, backupManifestFileName, files[0].Name)
+	assert.Contains(t, logger.String(), "copied 1 of the 2 chunks of backup ks/0/backup1")
+
+	data, ok := to.ReadBackupFile(chunkDir, "chunk2", builtinBackupChunkFileName)
+	require.True(t, ok)
+	assert.Equal(t, []byte("c2"), data)
+}
+
+func TestBackupFiles(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		manifest  any
+		wantFiles []backupstorage.BackupFile
+		wantErr   string
+	}{
+		{
+			name: "builtin",
+			manifest: builtinBackupManifest{
+				FileEntries: []FileEntry{{Name: "a", Hash: "1234"}},
+			},
+			wantFiles: []backupstorage.BackupFile{{Name: "0", Hash: "1234"}, {Name: backupManifestFileName}},
+		},
+		{
+			name: "xtrabackup",
+			manifest: xtraBackupManifest{
+				BackupManifest: BackupManifest{BackupMethod: xtrabackupEngineName},
+				FileName:       "backup.xbstream.gz",
+			},
+			wantFiles: []backupstorage.BackupFile{{Name: "backup.xbstream.gz"}, {Name: backupManifestFileName}},
+		},
+		{
+			name: "striped xtrabackup",
+			manifest: xtraBackupManifest{
+				BackupManifest: BackupManifest{BackupMethod: xtrabackupEngineName},
+				FileName:       "backup.xbstream.gz",
+				NumStripes:     2,
+			},
+			wantFiles: []backupstorage.BackupFile{{Name: "backup.xbstream.gz-000"}, {Name: "backup.xbstream.gz-001"}, {Name: backupManifestFileName}},
+		},
+		{
+			name: "mysqlshell",
+			manifest: MySQLShellBackupManifest{
+				BackupManifest: BackupManifest{BackupMethod: mysqlShellBackupEngineName},
+				BackupLocation: "s3://bucket/backup",
+			},
+			wantFiles: []backupstorage.BackupFile{{Name: backupManifestFileName}},
+		},
+		{
+			name:     "unknown engine",
+			manifest: BackupManifest{BackupMethod: "unknown"},
+			wantErr:  `cannot list the files of backup ks/0/backup1 created with "unknown" engine`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := memorybackupstorage.NewMemoryBackupStorage()
+			writeTestBackup(t, bs, "ks/0", "backup1", tt.manifest, nil)
+			bh, err := backupstorage.FindBackup(ctx, bs, "ks/0", "backup1")
+			require.NoError(t, err)
+
+			files, _, err := BackupFiles(ctx, bh)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFiles, files)
+		})
+	}
+
+	// Backups without a MANIFEST are incomplete.
+	bs := memorybackupstorage.NewMemoryBackupStorage()
+	bs.WriteBackupFile("ks/0", "backup1", "0", []byte("data"))
+	bh, err := backupstorage.FindBackup(ctx, bs, "ks/0", "backup1")
+	require.NoError(t, err)
+	_, _, err = BackupFiles(ctx, bh)
+	assert.ErrorContains(t, err, "backup ks/0/backup1 is incomplete")
+}
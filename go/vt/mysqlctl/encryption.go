@@ -0,0 +1,413 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql/vault"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The builtin backup engine can encrypt the files of a backup, after they are
+// compressed, with a data key which is generated for each backup. The data key is
+// wrapped by a key-encryption key (KEK), which is read from a file or from Vault,
+// and recorded in the MANIFEST along with the identifier of the KEK.
+//
+// Each file is encrypted with AES-256-GCM as a stream of segments, which are sealed
+// individually so that files can be encrypted and decrypted without buffering them.
+// The file starts with a random base nonce, from which the nonce of each segment is
+// derived with its index. The last segment is authenticated as such, so that a
+// truncated file cannot be decrypted.
+
+const (
+	// BackupEncryptionCipher is the cipher which encrypts the files of a builtin backup.
+	BackupEncryptionCipher = "aes-256-gcm-stream"
+
+	encryptionKeySize         = 32
+	encryptionSegmentSize     = 64 * 1024
+	encryptionWrappedKeyLabel = "vitess-backup-data-key"
+)
+
+var (
+	builtinBackupEncryptionKeyFile       string
+	builtinBackupEncryptionVaultAddr     string
+	builtinBackupEncryptionVaultTimeout  = 10 * time.Second
+	builtinBackupEncryptionVaultCACert   string
+	builtinBackupEncryptionVaultTokFile  string
+	builtinBackupEncryptionVaultPath     string
+	builtinBackupEncryptionVaultKeyField = "key"
+
+	errNoBackupEncryptionKey = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the backup is encrypted but no key-encryption key is configured, see --builtinbackup-encryption-key-file and --builtinbackup-encryption-vault-addr")
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupEncryptionFlags)
+	}
+}
+
+func registerBackupEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&builtinBackupEncryptionKeyFile, "builtinbackup-encryption-key-file", builtinBackupEncryptionKeyFile, "file containing the hex or base64 encoded 256-bit key-encryption key of builtin backups. When set, the files of builtin backups are encrypted with a data key which is wrapped by this key. Chunked backups (--builtinbackup-chunk-size) cannot be encrypted.")
+	fs.StringVar(&builtinBackupEncryptionVaultAddr, "builtinbackup-encryption-vault-addr", builtinBackupEncryptionVaultAddr, "address of the Vault server to read the key-encryption key of builtin backups from, instead of --builtinbackup-encryption-key-file.")
+	fs.DurationVar(&builtinBackupEncryptionVaultTimeout, "builtinbackup-encryption-vault-timeout", builtinBackupEncryptionVaultTimeout, "timeout for Vault API operations when reading the key-encryption key of builtin backups.")
+	fs.StringVar(&builtinBackupEncryptionVaultCACert, "builtinbackup-encryption-vault-ca", builtinBackupEncryptionVaultCACert, "path to a CA PEM file to verify the Vault server which holds the key-encryption key of builtin backups.")
+	fs.StringVar(&builtinBackupEncryptionVaultTokFile, "builtinbackup-encryption-vault-tokenfile", builtinBackupEncryptionVaultTokFile, "path to a file containing the Vault token used to read the key-encryption key of builtin backups.")
+	fs.StringVar(&builtinBackupEncryptionVaultPath, "builtinbackup-encryption-vault-path", builtinBackupEncryptionVaultPath, "Vault path of the KV secret holding the key-encryption key of builtin backups.")
+	fs.StringVar(&builtinBackupEncryptionVaultKeyField, "builtinbackup-encryption-vault-key-field", builtinBackupEncryptionVaultKeyField, "field of the Vault secret holding the hex or base64 encoded key-encryption key of builtin backups.")
+}
+
+// BackupEncryption describes how the files of a builtin backup are encrypted.
+type BackupEncryption struct {
+	// Cipher is the cipher which encrypts the files.
+	Cipher string
+	// KeyID identifies the key-encryption key which wraps the data key.
+	KeyID string
+	// WrappedKey is the data key of the backup, encrypted with the key-encryption key.
+	WrappedKey []byte
+	// SegmentSize is the size of the plaintext segments which are sealed individually.
+	SegmentSize int
+}
+
+// backupCipher encrypts and decrypts the files of a backup with its data key.
+type backupCipher struct {
+	aead        cipher.AEAD
+	segmentSize int
+	encryption  *BackupEncryption
+}
+
+// loadBackupEncryptionKey returns the configured key-encryption key, or nil if
+// backups are not encrypted.
+func loadBackupEncryptionKey() ([]byte, error) {
+	var encoded string
+	switch {
+	case builtinBackupEncryptionKeyFile != "":
+		data, err := os.ReadFile(builtinBackupEncryptionKeyFile)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot read the key-encryption key file %s", builtinBackupEncryptionKeyFile)
+		}
+		encoded = string(data)
+	case builtinBackupEncryptionVaultAddr != "":
+		var err error
+		encoded, err = vault.ReadSecretField(builtinBackupEncryptionVaultAddr, builtinBackupEncryptionVaultTimeout, builtinBackupEncryptionVaultCACert,
+			builtinBackupEncryptionVaultTokFile, builtinBackupEncryptionVaultPath, builtinBackupEncryptionVaultKeyField)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "cannot read the key-encryption key from Vault")
+		}
+	default:
+		return nil, nil
+	}
+	return decodeEncryptionKey(encoded)
+}
+
+// decodeEncryptionKey decodes a hex or base64 encoded 256-bit key.
+func decodeEncryptionKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		if key, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the key-encryption key must be hex or base64 encoded")
+		}
+	}
+	if len(key) != encryptionKeySize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the key-encryption key must be %d bytes long, got %d", encryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+// encryptionKeyID returns the identifier of a key-encryption key, which is recorded in the
+// MANIFEST so that a restore with the wrong key fails early.
+func encryptionKeyID(kek []byte) string {
+	sum := sha256.Sum256(kek)
+	return hex.EncodeToString(sum[:8])
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func wrapDataKey(kek, dataKey []byte) ([]byte, error) {
+	aead, err := newAESGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(encryptionWrappedKeyLabel)), nil
+}
+
+func unwrapDataKey(kek, wrapped []byte) ([]byte, error) {
+	aead, err := newAESGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped data key is too short")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(encryptionWrappedKeyLabel))
+}
+
+// newBackupCipher generates the data key of a new backup, or returns nil if backups
+// are not encrypted.
+func newBackupCipher() (*backupCipher, error) {
+	kek, err := loadBackupEncryptionKey()
+	if err != nil || kek == nil {
+		return nil, err
+	}
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, vterrors.Wrap(err, "cannot generate the data key of the backup")
+	}
+	wrappedKey, err := wrapDataKey(kek, dataKey)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot wrap the data key of the backup")
+	}
+	aead, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &backupCipher{
+		aead:        aead,
+		segmentSize: encryptionSegmentSize,
+		encryption: &BackupEncryption{
+			Cipher:      BackupEncryptionCipher,
+			KeyID:       encryptionKeyID(kek),
+			WrappedKey:  wrappedKey,
+			SegmentSize: encryptionSegmentSize,
+		},
+	}, nil
+}
+
+// openBackupCipher unwraps the data key of a backup, or returns nil if the backup is
+// not encrypted.
+func openBackupCipher(encryption *BackupEncryption) (*backupCipher, error) {
+	if encryption == nil {
+		return nil, nil
+	}
+	if encryption.Cipher != BackupEncryptionCipher {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported backup encryption cipher %q", encryption.Cipher)
+	}
+	if encryption.SegmentSize <= 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "invalid backup encryption segment size %d", encryption.SegmentSize)
+	}
+	kek, err := loadBackupEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	if kek == nil {
+		return nil, errNoBackupEncryptionKey
+	}
+	if keyID := encryptionKeyID(kek); keyID != encryption.KeyID {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the backup was encrypted with key-encryption key %s, but the configured key is %s", encryption.KeyID, keyID)
+	}
+	dataKey, err := unwrapDataKey(kek, encryption.WrappedKey)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot unwrap the data key of the backup")
+	}
+	aead, err := newAESGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &backupCipher{
+		aead:        aead,
+		segmentSize: encryption.SegmentSize,
+		encryption:  encryption,
+	}, nil
+}
+
+// segmentNonce derives the nonce of a segment from the base nonce of the file.
+func segmentNonce(nonce, baseNonce []byte, index uint64) []byte {
+	copy(nonce, baseNonce)
+	offset := len(nonce) - 8
+	binary.BigEndian.PutUint64(nonce[offset:], binary.BigEndian.Uint64(baseNonce[offset:])^index)
+	return nonce
+}
+
+// segmentAdditionalData authenticates whether a segment is the last one of the file.
+func segmentAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptingWriter encrypts a file written to the underlying writer.
+type encryptingWriter struct {
+	w         io.Writer
+	c         *backupCipher
+	baseNonce []byte
+	nonce     []byte
+	index     uint64
+	plaintext []byte
+	sealed    []byte
+	closed    bool
+}
+
+// newWriter returns a writer which encrypts the file written to w. It must be closed
+// to write the last segment.
+func (c *backupCipher) newWriter(w io.Writer) (io.WriteCloser, error) {
+	baseNonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(baseNonce); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(baseNonce); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		w:         w,
+		c:         c,
+		baseNonce: baseNonce,
+		nonce:     make([]byte, len(baseNonce)),
+		plaintext: make([]byte, 0, c.segmentSize),
+	}, nil
+}
+
+func (ew *encryptingWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("write to a closed encrypting writer")
+	}
+	n := 0
+	for len(p) > 0 {
+		// A full segment is only sealed once more data comes, since the last
+		// segment is sealed differently.
+		if len(ew.plaintext) == ew.c.segmentSize {
+			if err := ew.seal(false); err != nil {
+				return n, err
+			}
+		}
+		k := min(len(p), ew.c.segmentSize-len(ew.plaintext))
+		ew.plaintext = append(ew.plaintext, p[:k]...)
+		p = p[k:]
+		n += k
+	}
+	return n, nil
+}
+
+func (ew *encryptingWriter) seal(last bool) error {
+	nonce := segmentNonce(ew.nonce, ew.baseNonce, ew.index)
+	ew.sealed = ew.c.aead.Seal(ew.sealed[:0], nonce, ew.plaintext, segmentAdditionalData(last))
+	ew.index++
+	ew.plaintext = ew.plaintext[:0]
+	_, err := ew.w.Write(ew.sealed)
+	return err
+}
+
+// Close writes the last segment. It does not close the underlying writer.
+func (ew *encryptingWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	return ew.seal(true)
+}
+
+// decryptingReader decrypts a file read from the underlying reader.
+type decryptingReader struct {
+	r         *bufio.Reader
+	c         *backupCipher
+	baseNonce []byte
+	nonce     []byte
+	index     uint64
+	sealed    []byte
+	buf       []byte
+	plaintext []byte
+	done      bool
+}
+
+// newReader returns a reader which decrypts the file read from r.
+func (c *backupCipher) newReader(r io.Reader) (io.Reader, error) {
+	baseNonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(r, baseNonce); err != nil {
+		return nil, vterrors.Wrap(err, "cannot read the header of the encrypted file")
+	}
+	return &decryptingReader{
+		r:         bufio.NewReader(r),
+		c:         c,
+		baseNonce: baseNonce,
+		nonce:     make([]byte, len(baseNonce)),
+		sealed:    make([]byte, c.segmentSize+c.aead.Overhead()),
+	}, nil
+}
+
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.plaintext) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.plaintext)
+	dr.plaintext = dr.plaintext[n:]
+	return n, nil
+}
+
+func (dr *decryptingReader) open() error {
+	n, err := io.ReadFull(dr.r, dr.sealed)
+	last := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := dr.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	nonce := segmentNonce(dr.nonce, dr.baseNonce, dr.index)
+	plaintext, err := dr.c.aead.Open(dr.buf[:0], nonce, dr.sealed[:n], segmentAdditionalData(last))
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "cannot decrypt segment %d of the file, it is either corrupt, truncated or encrypted with another key", dr.index)
+	}
+	dr.buf = plaintext
+	dr.plaintext = plaintext
+	dr.index++
+	dr.done = last
+	return nil
+}
+
+func (c *backupCipher) String() string {
+	return fmt.Sprintf("%s with key-encryption key %s", c.encryption.Cipher, c.encryption.KeyID)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+)
+
+// setBackupEncryptionKey configures a new key-encryption key for the duration of the test.
+func setBackupEncryptionKey(t *testing.T) {
+	kek := make([]byte, encryptionKeySize)
+	_, err := rand.Read(kek)
+	require.NoError(t, err)
+	keyFile := path.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(keyFile, []byte(hex.EncodeToString(kek)+"\n"), 0o600))
+
+	oldKeyFile := builtinBackupEncryptionKeyFile
+	builtinBackupEncryptionKeyFile = keyFile
+	t.Cleanup(func() {
+		builtinBackupEncryptionKeyFile = oldKeyFile
+	})
+}
+
+func encryptForTest(t *testing.T, bc *backupCipher, plaintext []byte) []byte {
+	var buf bytes.Buffer
+	w, err := bc.newWriter(&buf)
+	require.NoError(t, err)
+	// Write in odd sized chunks to cross the segment boundaries.
+	for p := plaintext; len(p) > 0; {
+		n := min(len(p), 7)
+		_, err := w.Write(p[:n])
+		require.NoError(t, err)
+		p = p[n:]
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func decryptForTest(bc *backupCipher, ciphertext []byte) ([]byte, error) {
+	r, err := bc.newReader(bytes.NewReader(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestBackupEncryptionRoundTrip(t *testing.T) {
+	setBackupEncryptionKey(t)
+	bc, err := newBackupCipher()
+	require.NoError(t, err)
+	require.NotNil(t, bc)
+	bc.segmentSize = 16
+	bc.encryption.SegmentSize = 16
+
+	// The restore only has the MANIFEST and the key-encryption key.
+	restoreCipher, err := openBackupCipher(bc.encryption)
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 1, 15, 16, 17, 32, 100} {
+		plaintext := make([]byte, size)
+		_, err := rand.Read(plaintext)
+		require.NoError(t, err)
+
+		ciphertext := encryptForTest(t, bc, plaintext)
+		// A short plaintext may appear in the ciphertext by chance.
+		if size >= 16 {
+			assert.NotContains(t, string(ciphertext), string(plaintext))
+		}
+		decrypted, err := decryptForTest(restoreCipher, ciphertext)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, plaintext, append([]byte{}, decrypted...), "size %d", size)
+
+		if size > 16 {
+			// Dropping the last segment must be detected.
+			_, err := decryptForTest(restoreCipher, ciphertext[:len(ciphertext)-(size%16)-bc.aead.Overhead()])
+			assert.ErrorContains(t, err, "cannot decrypt segment", "size %d", size)
+		}
+		tampered := bytes.Clone(ciphertext)
+		tampered[len(tampered)-1] ^= 1
+		_, err = decryptForTest(restoreCipher, tampered)
+		assert.ErrorContains(t, err, "cannot decrypt segment", "size %d", size)
+	}
+}
+
+func TestOpenBackupCipher(t *testing.T) {
+	bc, err := openBackupCipher(nil)
+	require.NoError(t, err)
+	assert.Nil(t, bc)
+
+	setBackupEncryptionKey(t)
+	bc, err = newBackupCipher()
+	require.NoError(t, err)
+	encryption := bc.encryption
+
+	builtinBackupEncryptionKeyFile = ""
+	_, err = openBackupCipher(encryption)
+	assert.ErrorIs(t, err, errNoBackupEncryptionKey)
+
+	setBackupEncryptionKey(t)
+	_, err = openBackupCipher(encryption)
+	assert.ErrorContains(t, err, "the backup was encrypted with key-encryption key "+encryption.KeyID)
+
+	_, err = openBackupCipher(&BackupEncryption{Cipher: "rot13", SegmentSize: 16})
+	assert.ErrorContains(t, err, `unsupported backup encryption cipher "rot13"`)
+}
+
+func TestDecodeEncryptionKey(t *testing.T) {
+	key := make([]byte, encryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	decoded, err := decodeEncryptionKey(hex.EncodeToString(key) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, key, decoded)
+
+	decoded, err = decodeEncryptionKey(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, decoded)
+
+	_, err = decodeEncryptionKey(hex.EncodeToString(key[:16]))
+	assert.ErrorContains(t, err, "the key-encryption key must be 32 bytes long, got 16")
+
+	_, err = decodeEncryptionKey("not a key!")
+	assert.ErrorContains(t, err, "the key-encryption key must be hex or base64 encoded")
+}
+
+func TestBackupAndRestoreEncryptedFile(t *testing.T) {
+	ctx := context.Background()
+	setBackupEncryptionKey(t)
+	oldCompress := backupStorageCompress
+	backupStorageCompress = true
+	t.Cleanup(func() {
+		backupStorageCompress = oldCompress
+	})
+
+	tmpDir := t.TempDir()
+	content := bytes.Repeat([]byte("some table data "), 10000)
+	require.NoError(t, os.WriteFile(path.Join(tmpDir, "t1.ibd"), content, 0o644))
+
+	be := &BuiltinBackupEngine{}
+	bc, err := newBackupCipher()
+	require.NoError(t, err)
+	stored := &bytes.Buffer{}
+	bh := newMockBackupHandle()
+	bh.addFileReturn = &mockReadWriteCloser{mockCloser: newMockCloser(0, nil), Writer: stored}
+	fe := &FileEntry{Base: backupData, Name: "t1.ibd"}
+	err = be.backupFile(ctx, BackupParams{
+		Cnf:         &Mycnf{DataDir: tmpDir},
+		Logger:      logutil.NewMemoryLogger(),
+		Stats:       backupstats.NoStats(),
+		Concurrency: 1,
+	}, bh, fe, "0", bc)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.String(), "some table data")
+
+	restoreDir := t.TempDir()
+	bh.readFileReturn = &mockReadOnlyCloser{mockCloser: newMockCloser(0, nil), Reader: bytes.NewReader(stored.Bytes())}
+	bm := builtinBackupManifest{
+		CompressionEngine: PgzipCompressor,
+		Encryption:        bc.encryption,
+	}
+	bm.cipher, err = openBackupCipher(bm.Encryption)
+	require.NoError(t, err)
+	err = be.restoreFile(ctx, RestoreParams{
+		Cnf:    &Mycnf{DataDir: restoreDir},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NoStats(),
+	}, bh, fe, bm, "0")
+	require.NoError(t, err)
+	restored, err := os.ReadFile(path.Join(restoreDir, "t1.ibd"))
+	require.NoError(t, err)
+	assert.Equal(t, content, restored)
+}
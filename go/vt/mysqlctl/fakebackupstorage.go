@@ -102,6 +102,8 @@ type FakeBackupStorage struct {
 	RemoveBackupCalls   []FakeBackupStorageRemoveBackupCall
 	RemoveBackupReturn  error
 	RemoveBackupReturne error
+	ReopenBackupCalls   []FakeBackupStorageStartBackupCall
+	ReopenBackupReturn  FakeBackupStorageStartBackupReturn
 	StartBackupCalls    []FakeBackupStorageStartBackupCall
 	StartBackupReturn   FakeBackupStorageStartBackupReturn
 	WithParamsCalls     []backupstorage.Params
@@ -145,6 +147,11 @@ func (fbs *FakeBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	return fbs.StartBackupReturn.BackupHandle, fbs.StartBackupReturn.Err
 }
 
+func (fbs *FakeBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	fbs.ReopenBackupCalls = append(fbs.ReopenBackupCalls, FakeBackupStorageStartBackupCall{ctx, dir, name})
+	return fbs.ReopenBackupReturn.BackupHandle, fbs.ReopenBackupReturn.Err
+}
+
 func (fbs *FakeBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	fbs.RemoveBackupCalls = append(fbs.RemoveBackupCalls, FakeBackupStorageRemoveBackupCall{ctx, dir, name})
 	return fbs.RemoveBackupReturn
@@ -63,6 +63,7 @@ type FileBackupHandle struct {
 	dir      string
 	name     string
 	readOnly bool
+	ended    bool
 	mysqlctlerrors.PerFileErrorRecorder
 }
 
@@ -112,6 +113,7 @@ func (fbh *FileBackupHandle) EndBackup(ctx context.Context) error {
 	if fbh.readOnly {
 		return errors.New("EndBackup cannot be called on read-only backup")
 	}
+	fbh.ended = true
 	return nil
 }
 
@@ -125,8 +127,8 @@ func (fbh *FileBackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile is part of the BackupHandle interface
 func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
-	if !fbh.readOnly {
-		return nil, errors.New("ReadFile cannot be called on read-write backup")
+	if !fbh.readOnly && !fbh.ended {
+		return nil, errors.New("ReadFile cannot be called on read-write backup before EndBackup")
 	}
 	p, err := fileutil.SafePathJoin(FileBackupStorageRoot, fbh.dir, fbh.name, filename)
 	if err != nil {
@@ -198,6 +200,22 @@ func (fbs *FileBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	return NewBackupHandle(fbs, dir, name, false /*readOnly*/), nil
 }
 
+// ReopenBackup is part of the BackupStorage interface
+func (fbs *FileBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	p, err := fileutil.SafePathJoin(FileBackupStorageRoot, dir, name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("backup %v is not a directory", p)
+	}
+	return NewBackupHandle(fbs, dir, name, false /*readOnly*/), nil
+}
+
 // RemoveBackup is part of the BackupStorage interface
 func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	p, err := fileutil.SafePathJoin(FileBackupStorageRoot, dir, name)
@@ -192,3 +192,96 @@ func TestFileContents(t *testing.T) {
 		t.Fatalf("rc.Close failed: %v", err)
 	}
 }
+
+func TestReopenBackup(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := context.Background()
+
+	dir := "keyspace/shard"
+	name := "cell-0001-2015-01-14-10-00-00"
+
+	// a backup which does not exist cannot be reopened
+	if _, err := fbs.ReopenBackup(ctx, dir, name); err == nil {
+		t.Fatalf("was able to ReopenBackup a missing backup")
+	}
+
+	bh, err := fbs.StartBackup(ctx, dir, name)
+	if err != nil {
+		t.Fatalf("fbs.StartBackup failed: %v", err)
+	}
+	wc, err := bh.AddFile(ctx, "file1", 0)
+	if err != nil {
+		t.Fatalf("bh.AddFile failed: %v", err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatalf("wc.Close failed: %v", err)
+	}
+
+	// the backup cannot be started again, but it can be reopened
+	if _, err := fbs.StartBackup(ctx, dir, name); err == nil {
+		t.Fatalf("was able to StartBackup an existing backup")
+	}
+	bh, err = fbs.ReopenBackup(ctx, dir, name)
+	if err != nil {
+		t.Fatalf("fbs.ReopenBackup failed: %v", err)
+	}
+	wc, err = bh.AddFile(ctx, "file2", 0)
+	if err != nil {
+		t.Fatalf("bh.AddFile failed: %v", err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatalf("wc.Close failed: %v", err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("bh.EndBackup failed: %v", err)
+	}
+
+	bhs, err := fbs.ListBackups(ctx, dir)
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups returned wrong return: %v %v", err, bhs)
+	}
+	for _, filename := range []string{"file1", "file2"} {
+		rc, err := bhs[0].ReadFile(ctx, filename)
+		if err != nil {
+			t.Fatalf("bhs[0].ReadFile(%v) failed: %v", filename, err)
+		}
+		rc.Close()
+	}
+}
+
+func TestReadFileAfterEndBackup(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := context.Background()
+
+	bh, err := fbs.StartBackup(ctx, "keyspace/shard", "cell-0001-2015-01-14-10-00-00")
+	if err != nil {
+		t.Fatalf("fbs.StartBackup failed: %v", err)
+	}
+	wc, err := bh.AddFile(ctx, "file1", 0)
+	if err != nil {
+		t.Fatalf("bh.AddFile failed: %v", err)
+	}
+	if _, err := wc.Write([]byte("contents")); err != nil {
+		t.Fatalf("wc.Write failed: %v", err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatalf("wc.Close failed: %v", err)
+	}
+
+	// the files of a read-write backup can only be read back once it ended
+	if _, err := bh.ReadFile(ctx, "file1"); err == nil {
+		t.Fatalf("was able to ReadFile a backup in progress")
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("bh.EndBackup failed: %v", err)
+	}
+	rc, err := bh.ReadFile(ctx, "file1")
+	if err != nil {
+		t.Fatalf("bh.ReadFile failed: %v", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil || string(data) != "contents" {
+		t.Fatalf("ReadFile returned wrong contents: %v %q", err, data)
+	}
+}
@@ -67,6 +67,7 @@ type GCSBackupHandle struct {
 	dir      string
 	name     string
 	readOnly bool
+	ended    bool
 	mysqlctlerrors.PerFileErrorRecorder
 }
 
@@ -94,6 +95,7 @@ func (bh *GCSBackupHandle) EndBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return errors.New("EndBackup cannot be called on read-only backup")
 	}
+	bh.ended = true
 	return nil
 }
 
@@ -107,8 +109,8 @@ func (bh *GCSBackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile implements BackupHandle.
 func (bh *GCSBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
-	if !bh.readOnly {
-		return nil, errors.New("ReadFile cannot be called on read-write backup")
+	if !bh.readOnly && !bh.ended {
+		return nil, errors.New("ReadFile cannot be called on read-write backup before EndBackup")
 	}
 	object := objName(bh.dir, bh.name, filename)
 	return bh.client.Bucket(bucket).Object(object).NewReader(ctx)
@@ -195,6 +197,12 @@ func (bs *GCSBackupStorage) StartBackup(ctx context.Context, dir, name string) (
 	}, nil
 }
 
+// ReopenBackup implements BackupStorage. Objects are added to an existing
+// backup as to a new one.
+func (bs *GCSBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client(ctx)
@@ -73,9 +73,13 @@ func registerMyCnfFlags(fs *pflag.FlagSet) {
 // tabletservers deployed within a keyspace, lest there be collisions on disk.
 // mysqldPort needs to be unique per instance per machine.
 func NewMycnf(tabletUID uint32, mysqlPort int) *Mycnf {
+	return newMycnfInDir(TabletDir(tabletUID), tabletUID, mysqlPort)
+}
+
+// newMycnfInDir is like NewMycnf, for a mysqld whose files all live in tabletDir.
+func newMycnfInDir(tabletDir string, tabletUID uint32, mysqlPort int) *Mycnf {
 	cnf := new(Mycnf)
-	cnf.Path = MycnfFile(tabletUID)
-	tabletDir := TabletDir(tabletUID)
+	cnf.Path = path.Join(tabletDir, "my.cnf")
 	cnf.ServerID = tabletUID
 	cnf.MysqlPort = mysqlPort
 	cnf.DataDir = path.Join(tabletDir, dataDir)
@@ -120,6 +120,10 @@ type Mysqld struct {
 
 	capabilities capabilitySet
 
+	// mysqlctldSocket is the socket of the mysqlctld server which manages this
+	// mysqld, if any. Empty for local actions.
+	mysqlctldSocket string
+
 	// mutex protects the fields below.
 	mutex         sync.Mutex
 	onTermFuncs   []func()
@@ -179,16 +183,10 @@ func registerPoolFlags(fs *pflag.FlagSet) {
 // and connection parameters.
 func NewMysqld(dbcfgs *dbconfigs.DBConfigs) *Mysqld {
 	result := &Mysqld{
-		dbcfgs: dbcfgs,
+		dbcfgs:          dbcfgs,
+		mysqlctldSocket: socketFile,
 	}
-
-	// Create and open the connection pool for dba access.
-	result.dbaPool = dbconnpool.NewConnectionPool("DbaConnPool", nil, dbaPoolSize, DbaIdleTimeout, 0, PoolDynamicHostnameResolution)
-	result.dbaPool.Open(dbcfgs.DbaWithDB())
-
-	// Create and open the connection pool for app access.
-	result.appPool = dbconnpool.NewConnectionPool("AppConnPool", nil, appPoolSize, appIdleTimeout, 0, PoolDynamicHostnameResolution)
-	result.appPool.Open(dbcfgs.AppWithDB())
+	result.openPools()
 
 	/*
 	 If we have an external unmanaged tablet, we can't do the flavor
@@ -224,9 +222,25 @@ func NewMysqld(dbcfgs *dbconfigs.DBConfigs) *Mysqld {
 	return result
 }
 
+// openPools creates and opens the connection pools of mysqld.
+func (mysqld *Mysqld) openPools() {
+	// Create and open the connection pool for dba access.
+	mysqld.dbaPool = dbconnpool.NewConnectionPool("DbaConnPool", nil, dbaPoolSize, DbaIdleTimeout, 0, PoolDynamicHostnameResolution)
+	mysqld.dbaPool.Open(mysqld.dbcfgs.DbaWithDB())
+
+	// Create and open the connection pool for app access.
+	mysqld.appPool = dbconnpool.NewConnectionPool("AppConnPool", nil, appPoolSize, appIdleTimeout, 0, PoolDynamicHostnameResolution)
+	mysqld.appPool.Open(mysqld.dbcfgs.AppWithDB())
+}
+
 // GetVersionString runs mysqld --version and returns its output as a string
 func GetVersionString() (string, error) {
 	noSocketFile()
+	return localVersionString()
+}
+
+// localVersionString runs the local mysqld --version and returns its output as a string.
+func localVersionString() (string, error) {
 	mysqlRoot, err := vtenv.VtMysqlRoot()
 	if err != nil {
 		return "", err
@@ -278,9 +292,9 @@ func ParseVersionString(version string) (flavor MySQLFlavor, ver ServerVersion,
 // network and no grant tables.
 func (mysqld *Mysqld) RunMysqlUpgrade(ctx context.Context) error {
 	// Execute as remote action on mysqlctld if requested.
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.RunMysqlUpgrade() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.RunMysqlUpgrade() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -347,9 +361,9 @@ func (mysqld *Mysqld) RunMysqlUpgrade(ctx context.Context) error {
 // the dba user.
 func (mysqld *Mysqld) Start(ctx context.Context, cnf *Mycnf, mysqldArgs ...string) error {
 	// Execute as remote action on mysqlctld if requested.
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.Start() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.Start() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -618,9 +632,9 @@ func (mysqld *Mysqld) Shutdown(ctx context.Context, cnf *Mycnf, waitForMysqld bo
 	log.Info("Mysqld.Shutdown")
 
 	// Execute as remote action on mysqlctld if requested.
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.Shutdown() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.Shutdown() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -1026,9 +1040,9 @@ func (mysqld *Mysqld) getMycnfTemplate() string {
 // Should be called from a stable replica, server_id is not regenerated.
 func (mysqld *Mysqld) RefreshConfig(ctx context.Context, cnf *Mycnf) error {
 	// Execute as remote action on mysqlctld if requested.
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.RefreshConfig() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.RefreshConfig() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -1084,9 +1098,9 @@ func (mysqld *Mysqld) ReinitConfig(ctx context.Context, cnf *Mycnf) error {
 	log.Info("Mysqld.ReinitConfig")
 
 	// Execute as remote action on mysqlctld if requested.
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.ReinitConfig() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.ReinitConfig() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -1329,8 +1343,8 @@ func (mysqld *Mysqld) GetVersionString(ctx context.Context) (string, error) {
 	}
 	// Execute as remote action on mysqlctld to use the actual running MySQL
 	// version.
-	if socketFile != "" {
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return "", fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -1411,9 +1425,9 @@ func (mysqld *Mysqld) HostMetrics(ctx context.Context, cnf *Mycnf) (*mysqlctlpb.
 // ApplyBinlogFile extracts a binary log file and applies it to MySQL. It is the equivalent of:
 // $ mysqlbinlog --include-gtids binlog.file | mysql
 func (mysqld *Mysqld) ApplyBinlogFile(ctx context.Context, req *mysqlctlpb.ApplyBinlogFileRequest) error {
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.ApplyBinlogFile() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.ApplyBinlogFile() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -1642,9 +1656,9 @@ func (mysqld *Mysqld) ReadBinlogFilesTimestamps(ctx context.Context, req *mysqlc
 	if len(req.BinlogFileNames) == 0 {
 		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty binlog list in ReadBinlogFilesTimestampsRequest")
 	}
-	if socketFile != "" {
-		log.Info(fmt.Sprintf("executing Mysqld.ReadBinlogFilesTimestamps() remotely via mysqlctld server: %v", socketFile))
-		client, err := mysqlctlclient.New(ctx, "unix", socketFile)
+	if mysqld.mysqlctldSocket != "" {
+		log.Info(fmt.Sprintf("executing Mysqld.ReadBinlogFilesTimestamps() remotely via mysqlctld server: %v", mysqld.mysqlctldSocket))
+		client, err := mysqlctlclient.New(ctx, "unix", mysqld.mysqlctldSocket)
 		if err != nil {
 			return nil, fmt.Errorf("can't dial mysqlctld: %v", err)
 		}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakes3
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FaultType is the kind of failure injected by a Fault.
+type FaultType int
+
+const (
+	// InternalError fails the request with a 500 InternalError response.
+	InternalError FaultType = iota
+	// SlowDown throttles the request with a 503 SlowDown response.
+	SlowDown
+	// Disconnect reads half of the request body, then closes the connection
+	// without responding, like a connection lost in the middle of an upload.
+	Disconnect
+	// TruncateResponse handles the request, then sends the response headers
+	// and half of the response body before closing the connection, like a
+	// connection lost in the middle of a download.
+	TruncateResponse
+	// Delay handles the request after waiting for the delay of the fault.
+	Delay
+)
+
+// Fault injects a failure into the requests it matches.
+type Fault struct {
+	// Operation restricts the fault to the requests of an operation. An
+	// empty operation matches every request.
+	Operation Operation
+	// KeyPrefix restricts the fault to the requests on an object whose key
+	// starts with the prefix.
+	KeyPrefix string
+	// After is the number of matching requests which are let through before
+	// the fault is injected.
+	After int
+	// Times is the number of matching requests the fault is injected into.
+	// Zero injects the fault into every matching request.
+	Times int
+	// Type is the kind of failure to inject.
+	Type FaultType
+	// Delay is how long Delay faults wait before handling the request.
+	Delay time.Duration
+
+	matched  int
+	injected int
+}
+
+// InjectFault adds a fault to the requests received from now on. When
+// several faults match a request, the first one added is injected.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// FaultsInjected returns the number of requests faults were injected into.
+func (s *Server) FaultsInjected() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int
+	for _, f := range s.faults {
+		n += f.injected
+	}
+	return n
+}
+
+// matchFault returns the fault to inject into a request, if any. It must be
+// called with the lock held.
+func (s *Server) matchFault(op Operation, key string) *Fault {
+	for _, f := range s.faults {
+		if f.Operation != "" && f.Operation != op {
+			continue
+		}
+		if !strings.HasPrefix(key, f.KeyPrefix) {
+			continue
+		}
+		f.matched++
+		if f.matched <= f.After || (f.Times > 0 && f.injected >= f.Times) {
+			continue
+		}
+		f.injected++
+		return f
+	}
+	return nil
+}
+
+// injectFault injects a fault into a request. It returns the response writer
+// to handle the request with, and whether the request was already handled.
+func injectFault(w http.ResponseWriter, r *http.Request, f *Fault) (http.ResponseWriter, bool) {
+	switch f.Type {
+	case InternalError:
+		writeError(w, r, errInternalError)
+		return w, true
+	case SlowDown:
+		writeError(w, r, errSlowDown)
+		return w, true
+	case Disconnect:
+		if r.ContentLength > 0 {
+			_, _ = io.CopyN(io.Discard, r.Body, r.ContentLength/2)
+		}
+		panic(http.ErrAbortHandler)
+	case TruncateResponse:
+		return &truncatingResponseWriter{ResponseWriter: w, recorder: httptest.NewRecorder()}, false
+	case Delay:
+		return w, !sleep(r, f.Delay)
+	}
+	return w, false
+}
+
+// truncatingResponseWriter records a response, then sends half of it.
+type truncatingResponseWriter struct {
+	http.ResponseWriter
+	recorder *httptest.ResponseRecorder
+}
+
+func (tw *truncatingResponseWriter) Header() http.Header {
+	return tw.recorder.Header()
+}
+
+func (tw *truncatingResponseWriter) Write(data []byte) (int, error) {
+	return tw.recorder.Write(data)
+}
+
+func (tw *truncatingResponseWriter) WriteHeader(status int) {
+	tw.recorder.WriteHeader(status)
+}
+
+// abort sends the headers of the recorded response and half of its body,
+// then closes the connection.
+func (tw *truncatingResponseWriter) abort() {
+	header := tw.ResponseWriter.Header()
+	for name, values := range tw.recorder.Header() {
+		header[name] = values
+	}
+	body := tw.recorder.Body.Bytes()
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	tw.ResponseWriter.WriteHeader(tw.recorder.Code)
+	_, _ = tw.ResponseWriter.Write(body[:len(body)/2])
+	if flusher, ok := tw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,1012 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakes3 implements an in-process, in-memory emulator of the S3 API,
+// so that object-store backup storage can be tested without a live service.
+//
+// The emulator speaks the subset of the S3 wire API which backup storage
+// clients use: buckets, objects, multipart uploads, listings and ranged
+// reads. Requests are not authenticated and buckets must be addressed with
+// path-style URLs. Latency, throttling and failures can be injected into
+// requests to exercise the retry, resume and cleanup logic of clients.
+package fakes3
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Operation is the name of an S3 API operation.
+type Operation string
+
+// The operations implemented by the emulator.
+const (
+	CreateBucket            Operation = "CreateBucket"
+	HeadBucket              Operation = "HeadBucket"
+	DeleteBucket            Operation = "DeleteBucket"
+	GetBucketLocation       Operation = "GetBucketLocation"
+	ListObjects             Operation = "ListObjects"
+	ListObjectsV2           Operation = "ListObjectsV2"
+	PutObject               Operation = "PutObject"
+	GetObject               Operation = "GetObject"
+	HeadObject              Operation = "HeadObject"
+	DeleteObject            Operation = "DeleteObject"
+	DeleteObjects           Operation = "DeleteObjects"
+	CreateMultipartUpload   Operation = "CreateMultipartUpload"
+	UploadPart              Operation = "UploadPart"
+	CompleteMultipartUpload Operation = "CompleteMultipartUpload"
+	AbortMultipartUpload    Operation = "AbortMultipartUpload"
+	ListMultipartUploads    Operation = "ListMultipartUploads"
+)
+
+// DefaultMinPartSize is the minimum size of all but the last part of a
+// multipart upload enforced by S3.
+const DefaultMinPartSize = 5 * 1024 * 1024
+
+const (
+	defaultMaxKeys = 1000
+	maxPartNumber  = 10000
+	listTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// Options configure a Server.
+type Options struct {
+	// Latency is added to every request before it is handled.
+	Latency time.Duration
+	// MaxConcurrentRequests throttles the requests received while this many
+	// requests are already in flight with a 503 SlowDown error. Zero means
+	// requests are never throttled.
+	MaxConcurrentRequests int
+	// MinPartSize is the minimum size of all but the last part of a multipart
+	// upload. Defaults to DefaultMinPartSize.
+	MinPartSize int64
+}
+
+// Server is an S3 API emulator listening on a local port.
+type Server struct {
+	opts     Options
+	server   *httptest.Server
+	inFlight atomic.Int64
+
+	nextRequestID atomic.Int64
+
+	mu           sync.Mutex
+	buckets      map[string]*bucket
+	uploads      map[string]*multipartUpload
+	nextUploadID int
+	faults       []*Fault
+	requests     map[Operation]int
+}
+
+type object struct {
+	data         []byte
+	etag         string
+	lastModified time.Time
+}
+
+type bucket struct {
+	objects map[string]*object
+}
+
+type multipartUpload struct {
+	bucket    string
+	key       string
+	parts     map[int]*object
+	initiated time.Time
+}
+
+// NewServer starts a new emulator. Close must be called to stop it.
+func NewServer(opts Options) *Server {
+	if opts.MinPartSize == 0 {
+		opts.MinPartSize = DefaultMinPartSize
+	}
+	s := &Server{
+		opts:     opts,
+		buckets:  make(map[string]*bucket),
+		uploads:  make(map[string]*multipartUpload),
+		requests: make(map[Operation]int),
+	}
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// URL returns the endpoint of the emulator, to be used with path-style
+// addressing.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close stops the emulator.
+func (s *Server) Close() {
+	s.server.CloseClientConnections()
+	s.server.Close()
+}
+
+// CreateBucket creates an empty bucket, if it does not exist yet.
+func (s *Server) CreateBucket(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[name]; !ok {
+		s.buckets[name] = &bucket{objects: make(map[string]*object)}
+	}
+}
+
+// PutObject stores an object, creating its bucket if needed.
+func (s *Server) PutObject(bucketName, key string, data []byte) {
+	s.CreateBucket(bucketName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets[bucketName].objects[key] = newObject(bytes.Clone(data))
+}
+
+// Object returns the content of an object, and whether it exists.
+func (s *Server) Object(bucketName, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return nil, false
+	}
+	obj, ok := b.objects[key]
+	if !ok {
+		return nil, false
+	}
+	return bytes.Clone(obj.data), true
+}
+
+// Keys returns the sorted keys of the objects in a bucket.
+func (s *Server) Keys(bucketName string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return nil
+	}
+	return b.sortedKeys()
+}
+
+// MultipartUploads returns the number of multipart uploads which were
+// neither completed nor aborted.
+func (s *Server) MultipartUploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.uploads)
+}
+
+// Requests returns the number of requests received for an operation,
+// including the ones which failed.
+func (s *Server) Requests(op Operation) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[op]
+}
+
+func newObject(data []byte) *object {
+	sum := md5.Sum(data)
+	return &object{data: data, etag: hex.EncodeToString(sum[:]), lastModified: time.Now().UTC()}
+}
+
+func (b *bucket) sortedKeys() []string {
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// apiError is an error response of the S3 API.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+var (
+	errNoSuchBucket     = &apiError{http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist."}
+	errNoSuchKey        = &apiError{http.StatusNotFound, "NoSuchKey", "The specified key does not exist."}
+	errNoSuchUpload     = &apiError{http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist."}
+	errBucketNotEmpty   = &apiError{http.StatusConflict, "BucketNotEmpty", "The bucket you tried to delete is not empty."}
+	errMalformedXML     = &apiError{http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed."}
+	errInvalidPart      = &apiError{http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found."}
+	errInvalidPartOrder = &apiError{http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order."}
+	errEntityTooSmall   = &apiError{http.StatusBadRequest, "EntityTooSmall", "Your proposed upload is smaller than the minimum allowed object size."}
+	errBadDigest        = &apiError{http.StatusBadRequest, "BadDigest", "The checksum you specified did not match what we received."}
+	errInvalidRange     = &apiError{http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "The requested range is not satisfiable."}
+	errInternalError    = &apiError{http.StatusInternalServerError, "InternalError", "We encountered an internal error. Please try again."}
+	errSlowDown         = &apiError{http.StatusServiceUnavailable, "SlowDown", "Please reduce your request rate."}
+	errNotImplemented   = &apiError{http.StatusNotImplemented, "NotImplemented", "A header or query you provided implies functionality that is not implemented."}
+)
+
+func errInvalidArgument(message string) *apiError {
+	return &apiError{http.StatusBadRequest, "InvalidArgument", message}
+}
+
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err *apiError) {
+	// Drain the request body so that the client reads the response rather
+	// than failing to send the rest of its request.
+	_, _ = io.Copy(io.Discard, r.Body)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(err.status)
+		return
+	}
+	writeXML(w, err.status, errorResponse{
+		Code:      err.code,
+		Message:   err.message,
+		Resource:  r.URL.Path,
+		RequestID: w.Header().Get("x-amz-request-id"),
+	})
+}
+
+func writeXML(w http.ResponseWriter, status int, v any) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(xml.Header)+len(data)))
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header)
+	_, _ = w.Write(data)
+}
+
+// operation returns the S3 API operation of a request.
+func operation(r *http.Request, bucketName, key string) (Operation, bool) {
+	query := r.URL.Query()
+	if bucketName == "" {
+		return "", false
+	}
+	if key == "" {
+		switch r.Method {
+		case http.MethodPut:
+			return CreateBucket, true
+		case http.MethodHead:
+			return HeadBucket, true
+		case http.MethodDelete:
+			return DeleteBucket, true
+		case http.MethodPost:
+			if query.Has("delete") {
+				return DeleteObjects, true
+			}
+		case http.MethodGet:
+			switch {
+			case query.Has("location"):
+				return GetBucketLocation, true
+			case query.Has("uploads"):
+				return ListMultipartUploads, true
+			case query.Get("list-type") == "2":
+				return ListObjectsV2, true
+			case len(query) == 0 || query.Has("prefix") || query.Has("delimiter") || query.Has("marker") || query.Has("max-keys"):
+				return ListObjects, true
+			}
+		}
+		return "", false
+	}
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("x-amz-copy-source") != "" {
+			return "", false
+		}
+		if query.Has("uploadId") {
+			return UploadPart, true
+		}
+		return PutObject, true
+	case http.MethodPost:
+		if query.Has("uploads") {
+			return CreateMultipartUpload, true
+		}
+		if query.Has("uploadId") {
+			return CompleteMultipartUpload, true
+		}
+	case http.MethodGet:
+		if !query.Has("uploadId") {
+			return GetObject, true
+		}
+	case http.MethodHead:
+		return HeadObject, true
+	case http.MethodDelete:
+		if query.Has("uploadId") {
+			return AbortMultipartUpload, true
+		}
+		return DeleteObject, true
+	}
+	return "", false
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	w.Header().Set("x-amz-request-id", strconv.FormatInt(s.nextRequestID.Add(1), 10))
+
+	op, ok := operation(r, bucketName, key)
+	if !ok {
+		writeError(w, r, errNotImplemented)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests[op]++
+	fault := s.matchFault(op, key)
+	s.mu.Unlock()
+
+	inFlight := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	if s.opts.MaxConcurrentRequests > 0 && inFlight > int64(s.opts.MaxConcurrentRequests) {
+		writeError(w, r, errSlowDown)
+		return
+	}
+
+	if s.opts.Latency > 0 && !sleep(r, s.opts.Latency) {
+		return
+	}
+
+	if fault != nil {
+		var handled bool
+		if w, handled = injectFault(w, r, fault); handled {
+			return
+		}
+		if tw, ok := w.(*truncatingResponseWriter); ok {
+			defer tw.abort()
+		}
+	}
+
+	if err := s.handle(w, r, op, bucketName, key); err != nil {
+		writeError(w, r, err)
+	}
+}
+
+func sleep(r *http.Request, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, op Operation, bucketName, key string) *apiError {
+	switch op {
+	case CreateBucket:
+		s.CreateBucket(bucketName)
+		w.WriteHeader(http.StatusOK)
+		return nil
+	case HeadBucket:
+		if _, err := s.bucket(bucketName); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	case DeleteBucket:
+		return s.deleteBucket(w, bucketName)
+	case GetBucketLocation:
+		if _, err := s.bucket(bucketName); err != nil {
+			return err
+		}
+		writeXML(w, http.StatusOK, locationConstraint{})
+		return nil
+	case ListObjects:
+		return s.listObjects(w, r, bucketName)
+	case ListObjectsV2:
+		return s.listObjectsV2(w, r, bucketName)
+	case PutObject:
+		return s.putObject(w, r, bucketName, key)
+	case GetObject, HeadObject:
+		return s.getObject(w, r, bucketName, key)
+	case DeleteObject:
+		return s.deleteObject(w, bucketName, key)
+	case DeleteObjects:
+		return s.deleteObjects(w, r, bucketName)
+	case CreateMultipartUpload:
+		return s.createMultipartUpload(w, bucketName, key)
+	case UploadPart:
+		return s.uploadPart(w, r, bucketName, key)
+	case CompleteMultipartUpload:
+		return s.completeMultipartUpload(w, r, bucketName, key)
+	case AbortMultipartUpload:
+		return s.abortMultipartUpload(w, r, bucketName, key)
+	case ListMultipartUploads:
+		return s.listMultipartUploads(w, bucketName)
+	}
+	return errNotImplemented
+}
+
+// bucket must be called with the lock released.
+func (s *Server) bucket(name string) (*bucket, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[name]
+	if !ok {
+		return nil, errNoSuchBucket
+	}
+	return b, nil
+}
+
+func (s *Server) deleteBucket(w http.ResponseWriter, bucketName string) *apiError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return errNoSuchBucket
+	}
+	if len(b.objects) > 0 {
+		return errBucketNotEmpty
+	}
+	delete(s.buckets, bucketName)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Location string   `xml:",chardata"`
+}
+
+type listEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	Marker                *string        `xml:"Marker"`
+	NextMarker            string         `xml:"NextMarker,omitempty"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+	KeyCount              *int           `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	Contents              []listEntry    `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+// list returns the entries of a bucket after the marker, grouping the keys
+// which contain the delimiter after the prefix into common prefixes.
+// It returns the last entry returned if the listing is truncated.
+func (s *Server) list(b *bucket, prefix, delimiter, marker string, maxKeys int) (contents []listEntry, prefixes []commonPrefix, next string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := ""
+	for _, key := range b.sortedKeys() {
+		if key <= marker || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		entry := key
+		isPrefix := false
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				entry = key[:len(prefix)+i+len(delimiter)]
+				isPrefix = true
+			}
+		}
+		if isPrefix && (entry == last || entry <= marker) {
+			continue
+		}
+		if len(contents)+len(prefixes) == maxKeys {
+			return contents, prefixes, last
+		}
+		last = entry
+		if isPrefix {
+			prefixes = append(prefixes, commonPrefix{Prefix: entry})
+			continue
+		}
+		obj := b.objects[key]
+		contents = append(contents, listEntry{
+			Key:          key,
+			LastModified: obj.lastModified.Format(listTimeFormat),
+			ETag:         quote(obj.etag),
+			Size:         int64(len(obj.data)),
+			StorageClass: "STANDARD",
+		})
+	}
+	return contents, prefixes, ""
+}
+
+func maxKeys(r *http.Request) (int, *apiError) {
+	value := r.URL.Query().Get("max-keys")
+	if value == "" {
+		return defaultMaxKeys, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errInvalidArgument("max-keys must be a non-negative integer")
+	}
+	return min(n, defaultMaxKeys), nil
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	n, err := maxKeys(r)
+	if err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	marker := query.Get("marker")
+	contents, prefixes, next := s.list(b, query.Get("prefix"), query.Get("delimiter"), marker, n)
+	writeXML(w, http.StatusOK, listBucketResult{
+		Name:           bucketName,
+		Prefix:         query.Get("prefix"),
+		Delimiter:      query.Get("delimiter"),
+		Marker:         &marker,
+		NextMarker:     next,
+		MaxKeys:        n,
+		IsTruncated:    next != "",
+		Contents:       contents,
+		CommonPrefixes: prefixes,
+	})
+	return nil
+}
+
+func (s *Server) listObjectsV2(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	n, err := maxKeys(r)
+	if err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	marker := query.Get("start-after")
+	token := query.Get("continuation-token")
+	if token != "" {
+		decoded, decodeErr := base64.StdEncoding.DecodeString(token)
+		if decodeErr != nil {
+			return errInvalidArgument("The continuation token provided is incorrect")
+		}
+		marker = string(decoded)
+	}
+	contents, prefixes, next := s.list(b, query.Get("prefix"), query.Get("delimiter"), marker, n)
+	keyCount := len(contents) + len(prefixes)
+	result := listBucketResult{
+		Name:              bucketName,
+		Prefix:            query.Get("prefix"),
+		Delimiter:         query.Get("delimiter"),
+		ContinuationToken: token,
+		StartAfter:        query.Get("start-after"),
+		KeyCount:          &keyCount,
+		MaxKeys:           n,
+		IsTruncated:       next != "",
+		Contents:          contents,
+		CommonPrefixes:    prefixes,
+	}
+	if next != "" {
+		result.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(next))
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// checksumHeader matches the request headers carrying a checksum of the
+// request body, other than Content-MD5.
+var checksumHeader = regexp.MustCompile(`^X-Amz-Checksum-(Crc32|Crc32c)$`)
+
+// verifyChecksums checks the body of a request against the checksums sent
+// by the client which the emulator knows how to compute.
+func verifyChecksums(r *http.Request, data []byte) *apiError {
+	if value := r.Header.Get("Content-MD5"); value != "" {
+		sum := md5.Sum(data)
+		if value != base64.StdEncoding.EncodeToString(sum[:]) {
+			return errBadDigest
+		}
+	}
+	for name, values := range r.Header {
+		m := checksumHeader.FindStringSubmatch(name)
+		if m == nil || len(values) == 0 {
+			continue
+		}
+		table := crc32.IEEETable
+		if m[1] == "Crc32c" {
+			table = crc32.MakeTable(crc32.Castagnoli)
+		}
+		if values[0] != crc32Checksum(data, table) {
+			return errBadDigest
+		}
+	}
+	return nil
+}
+
+// crc32Checksum returns a CRC32 checksum as encoded in the checksum headers.
+func crc32Checksum(data []byte, table *crc32.Table) string {
+	sum := crc32.Checksum(data, table)
+	return base64.StdEncoding.EncodeToString([]byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)})
+}
+
+func readBody(r *http.Request) ([]byte, *apiError) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, &apiError{http.StatusBadRequest, "IncompleteBody", err.Error()}
+	}
+	if err := verifyChecksums(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	obj := newObject(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return errNoSuchBucket
+	}
+	b.objects[key] = obj
+	w.Header().Set("ETag", quote(obj.etag))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+var rangeHeader = regexp.MustCompile(`^bytes=(\d*)-(\d*)$`)
+
+// byteRange parses a single range of a Range header, returning the first and
+// last byte of the range.
+func byteRange(value string, size int64) (first, last int64, err *apiError) {
+	m := rangeHeader.FindStringSubmatch(value)
+	if m == nil || (m[1] == "" && m[2] == "") {
+		return 0, 0, errInvalidRange
+	}
+	if m[1] == "" {
+		// The last N bytes.
+		n, _ := strconv.ParseInt(m[2], 10, 64)
+		if n == 0 || size == 0 {
+			return 0, 0, errInvalidRange
+		}
+		return max(size-n, 0), size - 1, nil
+	}
+	first, _ = strconv.ParseInt(m[1], 10, 64)
+	last = size - 1
+	if m[2] != "" {
+		last, _ = strconv.ParseInt(m[2], 10, 64)
+		last = min(last, size-1)
+	}
+	if first >= size || first > last {
+		return 0, 0, errInvalidRange
+	}
+	return first, last, nil
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	s.mu.Lock()
+	b, ok := s.buckets[bucketName]
+	var obj *object
+	if ok {
+		obj = b.objects[key]
+	}
+	s.mu.Unlock()
+	if !ok {
+		return errNoSuchBucket
+	}
+	if obj == nil {
+		return errNoSuchKey
+	}
+
+	size := int64(len(obj.data))
+	data := obj.data
+	status := http.StatusOK
+	header := w.Header()
+	if value := r.Header.Get("Range"); value != "" {
+		first, last, err := byteRange(value, size)
+		if err != nil {
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			return err
+		}
+		data = obj.data[first : last+1]
+		status = http.StatusPartialContent
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", first, last, size))
+	} else if r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" {
+		// Whole objects are returned with a checksum the client can validate
+		// the response against.
+		header.Set("X-Amz-Checksum-Crc32", crc32Checksum(data, crc32.IEEETable))
+	}
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Content-Length", strconv.Itoa(len(data)))
+	header.Set("Content-Type", "application/octet-stream")
+	header.Set("ETag", quote(obj.etag))
+	header.Set("Last-Modified", obj.lastModified.Format(http.TimeFormat))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+	return nil
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, bucketName, key string) *apiError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return errNoSuchBucket
+	}
+	delete(b.objects, key)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type deleteRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+	Deleted []deletedObject `xml:"Deleted"`
+}
+
+func (s *Server) deleteObjects(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	var req deleteRequest
+	if xml.Unmarshal(data, &req) != nil || len(req.Objects) == 0 {
+		return errMalformedXML
+	}
+
+	s.mu.Lock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		s.mu.Unlock()
+		return errNoSuchBucket
+	}
+	var result deleteResult
+	for _, obj := range req.Objects {
+		// Deleting an object which does not exist succeeds.
+		delete(b.objects, obj.Key)
+		if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedObject{Key: obj.Key})
+		}
+	}
+	s.mu.Unlock()
+
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (s *Server) createMultipartUpload(w http.ResponseWriter, bucketName, key string) *apiError {
+	s.mu.Lock()
+	if _, ok := s.buckets[bucketName]; !ok {
+		s.mu.Unlock()
+		return errNoSuchBucket
+	}
+	s.nextUploadID++
+	uploadID := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("upload-%d", s.nextUploadID)))
+	s.uploads[uploadID] = &multipartUpload{
+		bucket:    bucketName,
+		key:       key,
+		parts:     make(map[int]*object),
+		initiated: time.Now().UTC(),
+	}
+	s.mu.Unlock()
+
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{Bucket: bucketName, Key: key, UploadID: uploadID})
+	return nil
+}
+
+// upload must be called with the lock held.
+func (s *Server) upload(r *http.Request, bucketName, key string) (string, *multipartUpload, *apiError) {
+	uploadID := r.URL.Query().Get("uploadId")
+	upload, ok := s.uploads[uploadID]
+	if !ok || upload.bucket != bucketName || upload.key != key {
+		return "", nil, errNoSuchUpload
+	}
+	return uploadID, upload, nil
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	partNumber, convErr := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if convErr != nil || partNumber < 1 || partNumber > maxPartNumber {
+		return errInvalidArgument(fmt.Sprintf("Part number must be an integer between 1 and %d, inclusive", maxPartNumber))
+	}
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	part := newObject(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, upload, err := s.upload(r, bucketName, key)
+	if err != nil {
+		return err
+	}
+	upload.parts[partNumber] = part
+	w.Header().Set("ETag", quote(part.etag))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+type completeMultipartUploadRequest struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	var req completeMultipartUploadRequest
+	if xml.Unmarshal(data, &req) != nil || len(req.Parts) == 0 {
+		return errMalformedXML
+	}
+
+	s.mu.Lock()
+	uploadID, upload, err := s.upload(r, bucketName, key)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		s.mu.Unlock()
+		return errNoSuchBucket
+	}
+	var content bytes.Buffer
+	etags := md5.New()
+	for i, p := range req.Parts {
+		if i > 0 && p.PartNumber <= req.Parts[i-1].PartNumber {
+			s.mu.Unlock()
+			return errInvalidPartOrder
+		}
+		part, ok := upload.parts[p.PartNumber]
+		if !ok || strings.Trim(p.ETag, `"`) != part.etag {
+			s.mu.Unlock()
+			return errInvalidPart
+		}
+		if i < len(req.Parts)-1 && int64(len(part.data)) < s.opts.MinPartSize {
+			s.mu.Unlock()
+			return errEntityTooSmall
+		}
+		content.Write(part.data)
+		sum, _ := hex.DecodeString(part.etag)
+		etags.Write(sum)
+	}
+	obj := newObject(content.Bytes())
+	obj.etag = fmt.Sprintf("%s-%d", hex.EncodeToString(etags.Sum(nil)), len(req.Parts))
+	b.objects[key] = obj
+	delete(s.uploads, uploadID)
+	s.mu.Unlock()
+
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Location: fmt.Sprintf("%s/%s/%s", s.server.URL, bucketName, key),
+		Bucket:   bucketName,
+		Key:      key,
+		ETag:     quote(obj.etag),
+	})
+	return nil
+}
+
+func (s *Server) abortMultipartUpload(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uploadID, _, err := s.upload(r, bucketName, key)
+	if err != nil {
+		return err
+	}
+	delete(s.uploads, uploadID)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type multipartUploadEntry struct {
+	Key       string `xml:"Key"`
+	UploadID  string `xml:"UploadId"`
+	Initiated string `xml:"Initiated"`
+}
+
+type listMultipartUploadsResult struct {
+	XMLName     xml.Name               `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListMultipartUploadsResult"`
+	Bucket      string                 `xml:"Bucket"`
+	IsTruncated bool                   `xml:"IsTruncated"`
+	Uploads     []multipartUploadEntry `xml:"Upload"`
+}
+
+func (s *Server) listMultipartUploads(w http.ResponseWriter, bucketName string) *apiError {
+	s.mu.Lock()
+	if _, ok := s.buckets[bucketName]; !ok {
+		s.mu.Unlock()
+		return errNoSuchBucket
+	}
+	result := listMultipartUploadsResult{Bucket: bucketName}
+	for uploadID, upload := range s.uploads {
+		if upload.bucket == bucketName {
+			result.Uploads = append(result.Uploads, multipartUploadEntry{
+				Key:       upload.key,
+				UploadID:  uploadID,
+				Initiated: upload.initiated.Format(listTimeFormat),
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(result.Uploads, func(a, b multipartUploadEntry) int {
+		return strings.Compare(a.Key+a.UploadID, b.Key+b.UploadID)
+	})
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+func quote(etag string) string {
+	return `"` + etag + `"`
+}
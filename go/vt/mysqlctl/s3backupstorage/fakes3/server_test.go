@@ -0,0 +1,296 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakes3
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBucket = "backups"
+
+func newTestClient(t *testing.T, opts Options) (*Server, *s3.Client) {
+	s := NewServer(opts)
+	t.Cleanup(s.Close)
+	s.CreateBucket(testBucket)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(s.URL()),
+		UsePathStyle: true,
+		Credentials:  aws.AnonymousCredentials{},
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		}),
+	})
+	return s, client
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	data := make([]byte, n)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+func TestObjects(t *testing.T) {
+	ctx := context.Background()
+	s, client := newTestClient(t, Options{})
+
+	data := randomBytes(t, 1000)
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file"), Body: bytes.NewReader(data)})
+	require.NoError(t, err)
+	stored, ok := s.Object(testBucket, "dir/file")
+	require.True(t, ok)
+	assert.Equal(t, data, stored)
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file")})
+	require.NoError(t, err)
+	read, err := io.ReadAll(out.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+
+	for _, tc := range []struct {
+		rng      string
+		expected []byte
+	}{
+		{"bytes=10-19", data[10:20]},
+		{"bytes=990-", data[990:]},
+		{"bytes=-5", data[995:]},
+		{"bytes=900-2000", data[900:]},
+	} {
+		out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file"), Range: aws.String(tc.rng)})
+		require.NoError(t, err, tc.rng)
+		read, err := io.ReadAll(out.Body)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, read, tc.rng)
+	}
+	_, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file"), Range: aws.String("bytes=1000-")})
+	assert.Equal(t, "InvalidRange", errorCode(err))
+
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file")})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, *head.ContentLength)
+
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file")})
+	require.NoError(t, err)
+	_, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(testBucket), Key: aws.String("dir/file")})
+	var noSuchKey *types.NoSuchKey
+	assert.ErrorAs(t, err, &noSuchKey)
+
+	_, err = client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String("missing")})
+	assert.Error(t, err)
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("missing"), Key: aws.String("file"), Body: bytes.NewReader(data)})
+	assert.Equal(t, "NoSuchBucket", errorCode(err))
+}
+
+func TestListObjectsV2(t *testing.T) {
+	ctx := context.Background()
+	s, client := newTestClient(t, Options{})
+
+	for _, key := range []string{"ks/0/b1/MANIFEST", "ks/0/b1/0", "ks/0/b2/MANIFEST", "ks/0/b3/0", "ks/0/file", "ks/1/b1/MANIFEST"} {
+		s.PutObject(testBucket, key, []byte(key))
+	}
+
+	// Pages of one entry, grouping the backups into common prefixes.
+	var prefixes, keys []string
+	query := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(testBucket),
+		Prefix:    aws.String("ks/0/"),
+		Delimiter: aws.String("/"),
+		MaxKeys:   aws.Int32(1),
+	}
+	for {
+		out, err := client.ListObjectsV2(ctx, query)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, *out.KeyCount)
+		for _, p := range out.CommonPrefixes {
+			prefixes = append(prefixes, *p.Prefix)
+		}
+		for _, obj := range out.Contents {
+			keys = append(keys, *obj.Key)
+		}
+		if out.NextContinuationToken == nil {
+			break
+		}
+		query.ContinuationToken = out.NextContinuationToken
+	}
+	assert.Equal(t, []string{"ks/0/b1/", "ks/0/b2/", "ks/0/b3/"}, prefixes)
+	assert.Equal(t, []string{"ks/0/file"}, keys)
+
+	out, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String(testBucket), Prefix: aws.String("ks/0/b1/")})
+	require.NoError(t, err)
+	require.Len(t, out.Contents, 2)
+	assert.Equal(t, "ks/0/b1/0", *out.Contents[0].Key)
+	assert.EqualValues(t, len("ks/0/b1/0"), *out.Contents[0].Size)
+
+	_, err = client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(testBucket),
+		Delete: &types.Delete{Objects: []types.ObjectIdentifier{{Key: out.Contents[0].Key}, {Key: out.Contents[1].Key}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ks/0/b2/MANIFEST", "ks/0/b3/0", "ks/0/file", "ks/1/b1/MANIFEST"}, s.Keys(testBucket))
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	s, client := newTestClient(t, Options{})
+
+	data := randomBytes(t, 2*DefaultMinPartSize+100)
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) { u.PartSize = DefaultMinPartSize })
+	_, err := uploader.Upload(ctx, &s3.PutObjectInput{Bucket: aws.String(testBucket), Key: aws.String("large"), Body: bytes.NewReader(data)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Requests(UploadPart))
+	assert.Equal(t, 0, s.MultipartUploads())
+	stored, ok := s.Object(testBucket, "large")
+	require.True(t, ok)
+	assert.Equal(t, data, stored)
+
+	// The parts of an upload must be large enough.
+	create, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{Bucket: aws.String(testBucket), Key: aws.String("small")})
+	require.NoError(t, err)
+	var parts []types.CompletedPart
+	for i := int32(1); i <= 2; i++ {
+		out, err := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(testBucket),
+			Key:        aws.String("small"),
+			UploadId:   create.UploadId,
+			PartNumber: aws.Int32(i),
+			Body:       bytes.NewReader([]byte("part")),
+		})
+		require.NoError(t, err)
+		parts = append(parts, types.CompletedPart{PartNumber: aws.Int32(i), ETag: out.ETag})
+	}
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(testBucket),
+		Key:             aws.String("small"),
+		UploadId:        create.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	assert.Equal(t, "EntityTooSmall", errorCode(err))
+
+	uploads, err := client.ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{Bucket: aws.String(testBucket)})
+	require.NoError(t, err)
+	require.Len(t, uploads.Uploads, 1)
+	assert.Equal(t, "small", *uploads.Uploads[0].Key)
+
+	_, err = client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{Bucket: aws.String(testBucket), Key: aws.String("small"), UploadId: create.UploadId})
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.MultipartUploads())
+	_, ok = s.Object(testBucket, "small")
+	assert.False(t, ok)
+}
+
+func TestFaults(t *testing.T) {
+	ctx := context.Background()
+	data := randomBytes(t, 2*DefaultMinPartSize+100)
+	upload := func(client *s3.Client) error {
+		uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+			u.PartSize = DefaultMinPartSize
+			u.Concurrency = 1
+		})
+		_, err := uploader.Upload(ctx, &s3.PutObjectInput{Bucket: aws.String(testBucket), Key: aws.String("large"), Body: bytes.NewReader(data)})
+		return err
+	}
+
+	for _, faultType := range []FaultType{InternalError, SlowDown, Disconnect} {
+		s, client := newTestClient(t, Options{})
+		s.InjectFault(Fault{Operation: UploadPart, After: 1, Times: 2, Type: faultType})
+		require.NoError(t, upload(client), "fault type %d", faultType)
+		assert.Equal(t, 2, s.FaultsInjected())
+		assert.Equal(t, 5, s.Requests(UploadPart))
+		stored, _ := s.Object(testBucket, "large")
+		assert.Equal(t, data, stored)
+	}
+
+	// Uploads which fail are aborted.
+	s, client := newTestClient(t, Options{})
+	s.InjectFault(Fault{Operation: UploadPart, After: 1, Type: InternalError})
+	assert.Equal(t, "InternalError", errorCode(upload(client)))
+	assert.Equal(t, 1, s.Requests(AbortMultipartUpload))
+	assert.Equal(t, 0, s.MultipartUploads())
+
+	// Downloads which are interrupted fail to read the body.
+	s.ClearFaults()
+	s.PutObject(testBucket, "file", data)
+	s.InjectFault(Fault{Operation: GetObject, KeyPrefix: "file", Times: 1, Type: TruncateResponse})
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(testBucket), Key: aws.String("file")})
+	require.NoError(t, err)
+	_, err = io.ReadAll(out.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	out, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(testBucket), Key: aws.String("file")})
+	require.NoError(t, err)
+	read, err := io.ReadAll(out.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+
+	// Delays apply to the matching requests only.
+	s.InjectFault(Fault{Operation: HeadBucket, Type: Delay, Delay: 100 * time.Millisecond})
+	start := time.Now()
+	_, err = client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(testBucket)})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestThrottling(t *testing.T) {
+	ctx := context.Background()
+	s, client := newTestClient(t, Options{Latency: 50 * time.Millisecond, MaxConcurrentRequests: 1})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Go(func() {
+			_, errs[i] = client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(testBucket)}, func(o *s3.Options) {
+				o.RetryMaxAttempts = 1
+			})
+		})
+	}
+	wg.Wait()
+
+	var throttled int
+	for _, err := range errs {
+		if err != nil {
+			throttled++
+		}
+	}
+	assert.Positive(t, throttled)
+	assert.Less(t, throttled, len(errs))
+	assert.Equal(t, len(errs), s.Requests(HeadBucket))
+}
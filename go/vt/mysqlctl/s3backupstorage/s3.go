@@ -157,6 +157,7 @@ type S3BackupHandle struct {
 	dir       string
 	name      string
 	readOnly  bool
+	ended     bool
 	waitGroup sync.WaitGroup
 	errorsbackup.PerFileErrorRecorder
 }
@@ -258,7 +259,11 @@ func (bh *S3BackupHandle) EndBackup(ctx context.Context) error {
 		return errors.New("EndBackup cannot be called on read-only backup")
 	}
 	bh.waitGroup.Wait()
-	return bh.Error()
+	if err := bh.Error(); err != nil {
+		return err
+	}
+	bh.ended = true
+	return nil
 }
 
 // AbortBackup is part of the backupstorage.BackupHandle interface.
@@ -271,8 +276,8 @@ func (bh *S3BackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile is part of the backupstorage.BackupHandle interface.
 func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
-	if !bh.readOnly {
-		return nil, errors.New("ReadFile cannot be called on read-write backup")
+	if !bh.readOnly && !bh.ended {
+		return nil, errors.New("ReadFile cannot be called on read-write backup before EndBackup")
 	}
 	object := objName(bh.dir, bh.name, filename)
 	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
@@ -432,6 +437,12 @@ func (bs *S3BackupStorage) StartBackup(ctx context.Context, dir, name string) (b
 	}, nil
 }
 
+// ReopenBackup is part of the backupstorage.BackupStorage interface. Objects
+// are added to an existing backup as to a new one.
+func (bs *S3BackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	log.Info(fmt.Sprintf("RemoveBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, bucket))
@@ -43,6 +43,9 @@ type FakeConfig struct {
 	Endpoint  string
 	Bucket    string
 	ForcePath bool
+	// Retries overrides the maximum number of attempts of each request when
+	// not zero.
+	Retries int
 }
 
 func InitFlag(cfg FakeConfig) {
@@ -50,6 +53,9 @@ func InitFlag(cfg FakeConfig) {
 	endpoint = cfg.Endpoint
 	bucket = cfg.Bucket
 	forcePath = cfg.ForcePath
+	if cfg.Retries != 0 {
+		retryCount = cfg.Retries
+	}
 }
 
 func NewFakeS3BackupHandle(ctx context.Context, dir, name string, logger logutil.Logger, stats backupstats.Stats) (*FakeS3BackupHandle, error) {
@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"testing"
@@ -23,6 +24,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/s3backupstorage/fakes3"
 )
 
 type s3FakeClient struct {
@@ -393,3 +395,128 @@ func TestCalculateUploadPartSize(t *testing.T) {
 		})
 	}
 }
+
+// setupFakeS3 points the S3 backup storage flags at an emulator, and returns
+// the emulator along with a backup storage using it.
+func setupFakeS3(t *testing.T) (*fakes3.Server, backupstorage.BackupStorage) {
+	s := fakes3.NewServer(fakes3.Options{})
+	t.Cleanup(s.Close)
+	s.CreateBucket("backups")
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "fake")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "fake")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_CA_BUNDLE", "")
+
+	oldRegion, oldEndpoint, oldBucket, oldForcePath, oldRetryCount, oldSSE := region, endpoint, bucket, forcePath, retryCount, sse
+	t.Cleanup(func() {
+		region, endpoint, bucket, forcePath, retryCount, sse = oldRegion, oldEndpoint, oldBucket, oldForcePath, oldRetryCount, oldSSE
+	})
+	region, endpoint, bucket, forcePath, retryCount, sse = "us-east-1", s.URL(), "backups", true, 3, ""
+
+	bs := newS3BackupStorage().WithParams(backupstorage.Params{
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  stats.NoStats(),
+	})
+	t.Cleanup(func() { _ = bs.Close() })
+	return s, bs
+}
+
+// writeFakeS3Backup writes a backup with a small file and a file large enough
+// to be uploaded in several parts, and returns their contents.
+func writeFakeS3Backup(t *testing.T, bs backupstorage.BackupStorage, dir, name string) (map[string][]byte, error) {
+	ctx := context.Background()
+	files := map[string][]byte{
+		"small": make([]byte, 1024),
+		"large": make([]byte, 11*1024*1024),
+	}
+	bh, err := bs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	for filename, data := range files {
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+		w, err := bh.AddFile(ctx, filename, int64(len(data)))
+		require.NoError(t, err)
+		// Write errors are reported by EndBackup.
+		_, _ = w.Write(data)
+		_ = w.Close()
+	}
+	return files, bh.EndBackup(ctx)
+}
+
+func TestFakeS3BackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, bs := setupFakeS3(t)
+
+	files, err := writeFakeS3Backup(t, bs, "ks/0", "backup1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Requests(fakes3.UploadPart))
+	assert.Equal(t, 0, s.MultipartUploads())
+
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	assert.Equal(t, "backup1", bhs[0].Name())
+	for filename, data := range files {
+		r, err := bhs[0].ReadFile(ctx, filename)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Equal(t, data, got, filename)
+	}
+
+	require.NoError(t, bs.RemoveBackup(ctx, "ks/0", "backup1"))
+	assert.Empty(t, s.Keys("backups"))
+}
+
+func TestFakeS3BackupRetriesTransientFaults(t *testing.T) {
+	ctx := context.Background()
+	s, bs := setupFakeS3(t)
+
+	// The connection is lost in the middle of the second part, and the first
+	// attempt to upload the small file is throttled.
+	s.InjectFault(fakes3.Fault{Operation: fakes3.UploadPart, After: 1, Times: 1, Type: fakes3.Disconnect})
+	s.InjectFault(fakes3.Fault{Operation: fakes3.PutObject, Times: 1, Type: fakes3.SlowDown})
+
+	files, err := writeFakeS3Backup(t, bs, "ks/0", "backup1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.FaultsInjected())
+	for filename, data := range files {
+		got, ok := s.Object("backups", "ks/0/backup1/"+filename)
+		require.True(t, ok, filename)
+		assert.Equal(t, data, got, filename)
+	}
+	assert.Equal(t, 0, s.MultipartUploads())
+
+	// A download failing in the middle of the response is reported when
+	// reading the file.
+	s.InjectFault(fakes3.Fault{Operation: fakes3.GetObject, Times: 1, Type: fakes3.TruncateResponse})
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	r, err := bhs[0].ReadFile(ctx, "small")
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	assert.Error(t, err)
+	require.NoError(t, r.Close())
+}
+
+func TestFakeS3BackupCleansUpFailedUploads(t *testing.T) {
+	ctx := context.Background()
+	s, bs := setupFakeS3(t)
+
+	s.InjectFault(fakes3.Fault{Operation: fakes3.UploadPart, Type: fakes3.InternalError})
+
+	_, err := writeFakeS3Backup(t, bs, "ks/0", "backup1")
+	require.ErrorContains(t, err, "UploadPart")
+	assert.Equal(t, 0, s.MultipartUploads())
+	assert.Equal(t, []string{"ks/0/backup1/small"}, s.Keys("backups"))
+
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	require.NoError(t, bs.RemoveBackup(ctx, "ks/0", "backup1"))
+	assert.Empty(t, s.Keys("backups"))
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// StandaloneRestoreParams holds the parameters of StandaloneRestore.
+type StandaloneRestoreParams struct {
+	Logger logutil.Logger
+	// Keyspace and Shard are used to infer the directory where backups are stored.
+	Keyspace string
+	Shard    string
+	// BackupName, if set, is the name of the full backup to restore. Otherwise, the
+	// most recent suitable full backup is restored.
+	BackupName string
+	// RestoreToPos and RestoreToTimestamp, which are mutually exclusive, request a
+	// point in time recovery: the full backup is restored along with the incremental
+	// backups which lead up to the given position or timestamp.
+	RestoreToPos       replication.Position
+	RestoreToTimestamp time.Time
+	// DbName is the name of the managed database / schema.
+	DbName string
+	// Concurrency is the number of files restored in parallel.
+	Concurrency int
+	// Dir is the directory of a new mysqld the backup is restored into, which must be
+	// empty or not exist. The new mysqld listens on MysqlPort, or on a free port if
+	// MysqlPort is 0.
+	Dir       string
+	MysqlPort int
+	// Cnf, if Dir is not set, is the configuration of an existing mysqld the backup is
+	// restored into. Its files are replaced, and it is shut down first if it is running.
+	Cnf *Mycnf
+	// DBConfigs holds the credentials used to connect to the restored mysqld.
+	DBConfigs    *dbconfigs.DBConfigs
+	CollationEnv *collations.Environment
+	// MysqlShutdownTimeout defines how long we wait for mysqld to shut down.
+	MysqlShutdownTimeout time.Duration
+	// Stats let's restore engines report detailed restore timings.
+	Stats backupstats.Stats
+}
+
+// StandaloneRestore restores a backup into a mysqld which is not managed by a
+// tablet, and doesn't need a topology server: either a new mysqld in a directory
+// of its own, or an existing mysqld. Along with RestoreToPos or RestoreToTimestamp,
+// this recovers the data of a shard as of a point in time, for instance to
+// retrieve rows which were deleted by mistake, without affecting the shard.
+//
+// Replication is disabled on the restored mysqld, which is left running. It
+// returns the manifest of the restored full backup, and the configuration of the
+// restored mysqld.
+func StandaloneRestore(ctx context.Context, params StandaloneRestoreParams) (*BackupManifest, *Mycnf, error) {
+	if !params.RestoreToPos.IsZero() && !params.RestoreToTimestamp.IsZero() {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "RestoreToPos and RestoreToTimestamp are mutually exclusive")
+	}
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+	if err := checkShardHasBackups(ctx, GetBackupDir(params.Keyspace, params.Shard)); err != nil {
+		return nil, nil, err
+	}
+
+	cnf := params.Cnf
+	if params.Dir != "" {
+		var err error
+		if cnf, err = newStandaloneMycnf(params.Dir, params.MysqlPort); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cnf == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "either the directory or the configuration of the mysqld to restore into must be given")
+	}
+	mysqld, err := newScratchMysqld(params.DBConfigs.CloneWithSocket(cnf.SocketFile, params.CollationEnv))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer mysqld.Close()
+	if params.Dir != "" {
+		if err := mysqld.InitConfig(cnf); err != nil {
+			return nil, nil, vterrors.Wrap(err, "failed to initialize the configuration of mysqld")
+		}
+	}
+
+	params.Logger.Infof("StandaloneRestore: restoring a backup of %v/%v into the mysqld of %v", params.Keyspace, params.Shard, cnf.TabletDir())
+	manifest, err := Restore(ctx, RestoreParams{
+		Cnf:                  cnf,
+		Mysqld:               mysqld,
+		Logger:               params.Logger,
+		Concurrency:          params.Concurrency,
+		DeleteBeforeRestore:  true,
+		DbName:               params.DbName,
+		Keyspace:             params.Keyspace,
+		Shard:                params.Shard,
+		BackupName:           params.BackupName,
+		RestoreToPos:         params.RestoreToPos,
+		RestoreToTimestamp:   params.RestoreToTimestamp,
+		Stats:                params.Stats,
+		MysqlShutdownTimeout: params.MysqlShutdownTimeout,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The restored mysqld must not replicate from the primary of the shard, which
+	// would undo a point in time recovery.
+	if err := mysqld.StopReplication(ctx, nil); err != nil {
+		return nil, nil, vterrors.Wrap(err, "failed to stop replication")
+	}
+	if err := mysqld.ResetReplicationParameters(ctx); err != nil {
+		return nil, nil, vterrors.Wrap(err, "failed to reset replication")
+	}
+	params.Logger.Infof("StandaloneRestore: restored backup %v, mysqld is listening on port %v and socket %v", manifest.BackupName, cnf.MysqlPort, cnf.SocketFile)
+	return manifest, cnf, nil
+}
+
+// checkShardHasBackups returns a NOT_FOUND error if there is no backup in backupDir.
+func checkShardHasBackups(ctx context.Context, backupDir string) error {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return vterrors.Wrap(err, "ListBackups failed")
+	}
+	if len(bhs) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no backup to restore in %s", backupDir)
+	}
+	return nil
+}
+
+// newStandaloneMycnf returns the configuration of a new mysqld whose files all live
+// in dir, which must be empty or not exist.
+func newStandaloneMycnf(dir string, port int) (*Mycnf, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, vterrors.Wrapf(err, "failed to read directory %v", dir)
+	}
+	if len(entries) > 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "directory %v is not empty", dir)
+	}
+	if port == 0 {
+		if port, err = scratchMysqlPort(); err != nil {
+			return nil, vterrors.Wrap(err, "failed to find a port for mysqld")
+		}
+	}
+	cnf := newMycnfInDir(dir, 0, port)
+	if err := cnf.RandomizeMysqlServerID(); err != nil {
+		return nil, vterrors.Wrap(err, "failed to generate a server_id for mysqld")
+	}
+	return cnf, nil
+}
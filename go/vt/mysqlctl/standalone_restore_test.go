@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestStandaloneRestoreChecks(t *testing.T) {
+	ctx := context.Background()
+	bs := setupChunkedBackups(t)
+	pos, _, err := replication.DecodePositionMySQL56("00000000-0000-0000-0000-000000000001:1-10")
+	require.NoError(t, err)
+	params := StandaloneRestoreParams{
+		Logger:   logutil.NewMemoryLogger(),
+		Keyspace: "ks",
+		Shard:    "-80",
+		Dir:      t.TempDir(),
+	}
+
+	p := params
+	p.RestoreToPos, p.RestoreToTimestamp = pos, time.Now()
+	_, _, err = StandaloneRestore(ctx, p)
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+
+	_, _, err = StandaloneRestore(ctx, params)
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+
+	writeChunkedBackup(t, bs, "ks/-80", "2026-10-19.000000.zone1-0000000100", nil)
+	require.NoError(t, os.WriteFile(path.Join(params.Dir, "my.cnf"), nil, 0o644))
+	_, _, err = StandaloneRestore(ctx, params)
+	assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+
+	p = params
+	p.Dir = ""
+	_, _, err = StandaloneRestore(ctx, p)
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+}
+
+func TestNewStandaloneMycnf(t *testing.T) {
+	dir := path.Join(t.TempDir(), "restore")
+	cnf, err := newStandaloneMycnf(dir, 0)
+	require.NoError(t, err)
+	assert.Positive(t, cnf.MysqlPort)
+	assert.NotZero(t, cnf.ServerID)
+	assert.Equal(t, dir, cnf.TabletDir())
+	assert.Equal(t, path.Join(dir, "my.cnf"), cnf.Path)
+	assert.Equal(t, path.Join(dir, "mysql.sock"), cnf.SocketFile)
+
+	cnf, err = newStandaloneMycnf(dir, 17100)
+	require.NoError(t, err)
+	assert.Equal(t, 17100, cnf.MysqlPort)
+}
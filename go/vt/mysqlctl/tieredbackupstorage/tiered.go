@@ -0,0 +1,560 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tieredbackupstorage implements the BackupStorage interface on top of
+// other registered implementations: backups are written to a primary backup
+// storage, and replicated asynchronously to secondary backup storages once
+// they are complete.
+package tieredbackupstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/utils"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	tieredBackupStorageName = "tiered"
+
+	// manifestFileName is the name of the file which is written last to a
+	// backup, and which marks it as complete.
+	manifestFileName = "MANIFEST"
+)
+
+var (
+	// primaryImplementation is the backup storage implementation backups are
+	// written to and read from.
+	primaryImplementation string
+
+	// secondaryImplementations are the backup storage implementations backups
+	// are replicated to.
+	secondaryImplementations []string
+
+	// replicationConcurrency is the number of backups replicated at the same time.
+	replicationConcurrency = 4
+
+	// replicationTimeout is how long the replication of a backup to a secondary
+	// backup storage may take.
+	replicationTimeout = 24 * time.Hour
+
+	replications = stats.NewCountersWithMultiLabels(
+		"TieredBackupStorageReplications",
+		"Number of backups replicated to the secondary backup storages, by secondary and result",
+		[]string{"Secondary", "Result"})
+
+	// defaultReplicator is shared by the tiered backup storages of the process,
+	// so that WaitForReplications waits for all the replications in flight.
+	defaultReplicator = &replicator{}
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	utils.SetFlagStringVar(fs, &primaryImplementation, "tiered-backup-storage-primary", primaryImplementation, "Backup storage implementation the tiered backup storage writes backups to and reads them from.")
+	utils.SetFlagStringSliceVar(fs, &secondaryImplementations, "tiered-backup-storage-secondaries", secondaryImplementations, "Comma-separated list of backup storage implementations the tiered backup storage replicates backups to.")
+	utils.SetFlagIntVar(fs, &replicationConcurrency, "tiered-backup-storage-replication-concurrency", replicationConcurrency, "Number of backups the tiered backup storage replicates at the same time.")
+	utils.SetFlagDurationVar(fs, &replicationTimeout, "tiered-backup-storage-replication-timeout", replicationTimeout, "How long the replication of a backup to a secondary backup storage may take.")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerFlags)
+	servenv.OnParseFor("vtctl", registerFlags)
+	servenv.OnParseFor("vtctld", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+
+	// The replications in flight are lost if the process exits, so they are
+	// waited for when it shuts down, within --onclose-timeout. The ones which
+	// are still lost are made again by Reconcile.
+	servenv.OnClose(WaitForReplications)
+}
+
+// WaitForReplications waits for the replications in flight of the tiered backup
+// storages of the process. Processes which exit without firing the servenv
+// OnClose hooks, such as vtbackup, call it before they exit, so that the
+// secondary backup storages have a copy of their backup.
+func WaitForReplications() {
+	defaultReplicator.wait()
+}
+
+// tier is one of the backup storages of a TieredBackupStorage.
+type tier struct {
+	name string
+	bs   backupstorage.BackupStorage
+}
+
+// TieredBackupStorage implements BackupStorage on top of a primary and secondary
+// backup storages, which are other registered implementations.
+//
+// Backups are written to the primary. Once a backup is complete, its files and
+// the chunks it references are copied to the secondaries in the background, and
+// read back to verify them. The files which are added to a complete backup, such
+// as its VERIFICATION, are copied to the secondaries when the handle which added
+// them is ended. Replications which were lost, for instance because the process
+// exited before they were done, are made again by Reconcile, but only for the
+// backups which are missing from a secondary: the files added to a backup which
+// is already in a secondary are not replicated again.
+// Backups are listed and read from the primary, and from the secondaries for the
+// backups which are missing from the primary. Backups are removed from all the
+// backup storages.
+type TieredBackupStorage struct {
+	params     backupstorage.Params
+	replicator *replicator
+
+	mu          sync.Mutex
+	primary     *tier
+	secondaries []*tier
+}
+
+func newTieredBackupStorage(params backupstorage.Params, r *replicator) *TieredBackupStorage {
+	return &TieredBackupStorage{params: params, replicator: r}
+}
+
+// backends returns the primary and secondary backup storages, which are looked
+// up among the registered implementations the first time they are needed.
+func (tbs *TieredBackupStorage) backends() (*tier, []*tier, error) {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+	if tbs.primary != nil {
+		return tbs.primary, tbs.secondaries, nil
+	}
+
+	if primaryImplementation == "" {
+		return nil, nil, errors.New("--tiered-backup-storage-primary is not set")
+	}
+	lookup := func(name string) (*tier, error) {
+		if name == tieredBackupStorageName {
+			return nil, errors.New("the tiered backup storage cannot be one of its own tiers")
+		}
+		bs, ok := backupstorage.BackupStorageMap[name]
+		if !ok {
+			return nil, fmt.Errorf("no registered implementation of BackupStorage named %q", name)
+		}
+		return &tier{name: name, bs: bs.WithParams(tbs.params)}, nil
+	}
+	primary, err := lookup(primaryImplementation)
+	if err != nil {
+		return nil, nil, err
+	}
+	secondaries := make([]*tier, 0, len(secondaryImplementations))
+	for i, name := range secondaryImplementations {
+		if name == primaryImplementation || slices.Contains(secondaryImplementations[:i], name) {
+			return nil, nil, fmt.Errorf("backup storage implementation %q is used by more than one tier", name)
+		}
+		secondary, err := lookup(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		secondaries = append(secondaries, secondary)
+	}
+	tbs.primary, tbs.secondaries = primary, secondaries
+	return primary, secondaries, nil
+}
+
+// ListBackups is part of the BackupStorage interface. Backups which are missing
+// from the primary are listed from the first secondary which has them.
+func (tbs *TieredBackupStorage) ListBackups(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	primary, secondaries, err := tbs.backends()
+	if err != nil {
+		return nil, err
+	}
+	bhs, err := primary.bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]bool, len(bhs))
+	for _, bh := range bhs {
+		listed[bh.Name()] = true
+	}
+	for _, secondary := range secondaries {
+		secondaryBhs, err := secondary.bs.ListBackups(ctx, dir)
+		if err != nil {
+			// The backups of the primary can still be used.
+			log.Warn(fmt.Sprintf("Cannot list the backups in %v of secondary backup storage %v: %v", dir, secondary.name, err))
+			continue
+		}
+		for _, bh := range secondaryBhs {
+			if !listed[bh.Name()] {
+				listed[bh.Name()] = true
+				bhs = append(bhs, bh)
+			}
+		}
+	}
+	slices.SortFunc(bhs, func(a, b backupstorage.BackupHandle) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return bhs, nil
+}
+
+// StartBackup is part of the BackupStorage interface.
+func (tbs *TieredBackupStorage) StartBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	primary, _, err := tbs.backends()
+	if err != nil {
+		return nil, err
+	}
+	bh, err := primary.bs.StartBackup(ctx, dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return &tieredBackupHandle{BackupHandle: bh, tbs: tbs}, nil
+}
+
+// ReopenBackup is part of the BackupStorage interface.
+func (tbs *TieredBackupStorage) ReopenBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	primary, _, err := tbs.backends()
+	if err != nil {
+		return nil, err
+	}
+	bh, err := primary.bs.ReopenBackup(ctx, dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return &tieredBackupHandle{BackupHandle: bh, tbs: tbs, reopened: true}, nil
+}
+
+// RemoveBackup is part of the BackupStorage interface.
+func (tbs *TieredBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
+	primary, secondaries, err := tbs.backends()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range append([]*tier{primary}, secondaries...) {
+		if err := t.bs.RemoveBackup(ctx, dir, name); err != nil {
+			errs = append(errs, fmt.Errorf("cannot remove backup %v/%v from backup storage %v: %w", dir, name, t.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close is part of the BackupStorage interface. It does not wait for the
+// replications in flight, which use backup storages of their own: see
+// WaitForReplications.
+func (tbs *TieredBackupStorage) Close() error {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+	if tbs.primary == nil {
+		return nil
+	}
+	var errs []error
+	for _, t := range append([]*tier{tbs.primary}, tbs.secondaries...) {
+		if err := t.bs.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot close backup storage %v: %w", t.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithParams is part of the BackupStorage interface.
+func (tbs *TieredBackupStorage) WithParams(params backupstorage.Params) backupstorage.BackupStorage {
+	return newTieredBackupStorage(params, tbs.replicator)
+}
+
+// Reconcile is part of the backupstorage.Reconciler interface. It replicates
+// the complete backups of the primary which are missing from a secondary, unless
+// their replication is already queued or running.
+func (tbs *TieredBackupStorage) Reconcile(ctx context.Context, dir string) error {
+	primary, secondaries, err := tbs.backends()
+	if err != nil {
+		return err
+	}
+	bhs, err := primary.bs.ListBackups(ctx, dir)
+	if err != nil {
+		return err
+	}
+	for i, secondary := range secondaries {
+		secondaryBhs, err := secondary.bs.ListBackups(ctx, dir)
+		if err != nil {
+			return fmt.Errorf("cannot list the backups in %v of secondary backup storage %v: %w", dir, secondary.name, err)
+		}
+		for _, bh := range bhs {
+			if slices.ContainsFunc(secondaryBhs, func(sbh backupstorage.BackupHandle) bool { return sbh.Name() == bh.Name() }) ||
+				tbs.replicator.pending(replicationKey(secondary, dir, bh.Name())) {
+				continue
+			}
+			if _, err := mysqlctl.GetBackupManifest(ctx, bh); err != nil {
+				// The backup is incomplete, it is replicated once it is complete.
+				continue
+			}
+			log.Info(fmt.Sprintf("Backup %v/%v is missing from secondary backup storage %v, replicating it", dir, bh.Name(), secondary.name))
+			tbs.replicateTo(i, secondary, dir, bh.Name(), nil, replicateBackup)
+		}
+	}
+	return nil
+}
+
+// replicate replicates a complete backup of the primary to the secondaries in
+// the background. added are the files which were added to it by the handle which
+// completed it.
+func (tbs *TieredBackupStorage) replicate(dir, name string, added []string) {
+	tbs.replicateAll(dir, name, added, replicateBackup)
+}
+
+// replicateFiles replicates the files which were added to a backup of the
+// primary, if it is complete, to the secondaries in the background.
+func (tbs *TieredBackupStorage) replicateFiles(dir, name string, added []string) {
+	tbs.replicateAll(dir, name, added, replicateAddedFiles)
+}
+
+// replicateAll runs replicateBackup or replicateAddedFiles for every secondary.
+func (tbs *TieredBackupStorage) replicateAll(dir, name string, added []string, f replicateFunc) {
+	_, secondaries, err := tbs.backends()
+	if err != nil {
+		// The backup was written to the primary, so the backends were resolved.
+		return
+	}
+	for i, secondary := range secondaries {
+		tbs.replicateTo(i, secondary, dir, name, added, f)
+	}
+}
+
+// replicateFunc replicates files of a backup of the primary to a secondary.
+type replicateFunc func(ctx context.Context, primary, secondary *tier, dir, name string, added []string) error
+
+// replicateTo runs f in the background for the i-th secondary. A replication
+// outlives the backup storage which wrote the backup, which may be closed in the
+// meantime, so it uses backup storages of its own, which it closes once it is
+// done. The replications of a backup to a secondary run one at a time.
+func (tbs *TieredBackupStorage) replicateTo(i int, secondary *tier, dir, name string, added []string, f replicateFunc) {
+	tbs.replicator.run(replicationKey(secondary, dir, name), func() {
+		ctx, cancel := context.WithTimeout(context.Background(), replicationTimeout)
+		defer cancel()
+
+		rtbs := newTieredBackupStorage(tbs.params, tbs.replicator)
+		defer rtbs.Close()
+		primary, ownSecondaries, err := rtbs.backends()
+		if err == nil {
+			err = f(ctx, primary, ownSecondaries[i], dir, name, added)
+		}
+		if err != nil {
+			replications.Add([]string{secondary.name, "Error"}, 1)
+			log.Error(fmt.Sprintf("Failed to replicate backup %v/%v to secondary backup storage %v: %v", dir, name, secondary.name, err))
+			return
+		}
+		replications.Add([]string{secondary.name, "OK"}, 1)
+		log.Info(fmt.Sprintf("Replicated backup %v/%v to secondary backup storage %v", dir, name, secondary.name))
+	})
+}
+
+// replicationKey identifies the replications of a backup to a secondary.
+func replicationKey(secondary *tier, dir, name string) string {
+	return secondary.name + ":" + dir + "/" + name
+}
+
+// replicateBackup copies a complete backup of the primary to a secondary, with
+// the chunks it references which the secondary does not have yet. The files of
+// the backup are the files listed by its MANIFEST, and the files which were
+// added to it: a resumed backup only adds the files which were not backed up
+// before it was interrupted. A backup which the secondary already has is not
+// copied again.
+func replicateBackup(ctx context.Context, primary, secondary *tier, dir, name string, added []string) error {
+	if _, err := backupstorage.FindBackup(ctx, secondary.bs, dir, name); err == nil {
+		return nil
+	} else if vterrors.Code(err) != vtrpcpb.Code_NOT_FOUND {
+		return err
+	}
+	bh, err := backupstorage.FindBackup(ctx, primary.bs, dir, name)
+	if err != nil {
+		return err
+	}
+	listed, chunks, err := mysqlctl.BackupFiles(ctx, bh)
+	if err != nil {
+		return err
+	}
+	if len(chunks) > 0 {
+		if _, err := mysqlctl.CopyBackupChunks(ctx, primary.bs, secondary.bs, dir, chunks); err != nil {
+			return err
+		}
+	}
+
+	var files []backupstorage.BackupFile
+	for _, filename := range added {
+		if !slices.ContainsFunc(listed, func(file backupstorage.BackupFile) bool { return file.Name == filename }) {
+			files = append(files, backupstorage.BackupFile{Name: filename})
+		}
+	}
+	files = append(files, listed...)
+
+	_, err = backupstorage.CopyBackup(ctx, bh, secondary.bs, files)
+	return err
+}
+
+// replicateAddedFiles copies the files which were added to a complete backup of
+// the primary to a secondary. The whole backup is copied if the secondary does
+// not have it yet. Nothing is copied if the backup is incomplete: the files are
+// replicated along with the backup once it is complete.
+func replicateAddedFiles(ctx context.Context, primary, secondary *tier, dir, name string, added []string) error {
+	bh, err := backupstorage.FindBackup(ctx, primary.bs, dir, name)
+	if err != nil {
+		return err
+	}
+	if _, err := mysqlctl.GetBackupManifest(ctx, bh); err != nil {
+		return nil
+	}
+	if _, err := backupstorage.FindBackup(ctx, secondary.bs, dir, name); err != nil {
+		if vterrors.Code(err) != vtrpcpb.Code_NOT_FOUND {
+			return err
+		}
+		return replicateBackup(ctx, primary, secondary, dir, name, added)
+	}
+	dst, err := secondary.bs.ReopenBackup(ctx, dir, name)
+	if err != nil {
+		return err
+	}
+	files := make([]backupstorage.BackupFile, 0, len(added))
+	for _, filename := range added {
+		files = append(files, backupstorage.BackupFile{Name: filename})
+	}
+	_, err = backupstorage.AddBackupFiles(ctx, bh, dst, files)
+	return err
+}
+
+// tieredBackupHandle is a backup being written to the primary backup storage.
+type tieredBackupHandle struct {
+	backupstorage.BackupHandle
+	tbs *TieredBackupStorage
+	// reopened is set for the handles returned by ReopenBackup.
+	reopened bool
+
+	mu    sync.Mutex
+	files []string
+	// complete is set once the MANIFEST was added to the backup.
+	complete bool
+}
+
+// AddFile is part of the BackupHandle interface.
+func (tbh *tieredBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	wc, err := tbh.BackupHandle.AddFile(ctx, filename, filesize)
+	if err != nil {
+		return nil, err
+	}
+	tbh.mu.Lock()
+	defer tbh.mu.Unlock()
+	if !slices.Contains(tbh.files, filename) {
+		tbh.files = append(tbh.files, filename)
+	}
+	if filename == manifestFileName {
+		tbh.complete = true
+	}
+	return wc, nil
+}
+
+// EndBackup is part of the BackupHandle interface. The backup is replicated to
+// the secondaries once it is complete in the primary, that is when this handle
+// added its MANIFEST. A reopened handle which adds other files to a complete
+// backup, such as the result of its verification, replicates them. The other
+// handles, such as the ones of the chunks of a chunked backup, do not replicate
+// anything: the chunks are replicated along with the backups which reference
+// them.
+func (tbh *tieredBackupHandle) EndBackup(ctx context.Context) error {
+	if err := tbh.BackupHandle.EndBackup(ctx); err != nil {
+		return err
+	}
+	tbh.mu.Lock()
+	complete, files := tbh.complete, slices.Clone(tbh.files)
+	tbh.mu.Unlock()
+	switch {
+	case complete:
+		tbh.tbs.replicate(tbh.Directory(), tbh.Name(), files)
+	case tbh.reopened && len(files) > 0:
+		tbh.tbs.replicateFiles(tbh.Directory(), tbh.Name(), files)
+	}
+	return nil
+}
+
+// replicator runs the replications of backups in the background.
+type replicator struct {
+	wg  sync.WaitGroup
+	sem chan struct{}
+	// once creates sem once the flags are parsed.
+	once sync.Once
+
+	mu sync.Mutex
+	// queued are the replications which are queued or running, by key.
+	queued map[string]*replicationQueue
+}
+
+// replicationQueue runs the replications which have the same key one at a time.
+type replicationQueue struct {
+	mu    sync.Mutex
+	count int
+}
+
+// run runs f in the background, once the replications with the same key are
+// done, and fewer than --tiered-backup-storage-replication-concurrency
+// replications are running.
+func (r *replicator) run(key string, f func()) {
+	r.once.Do(func() {
+		r.sem = make(chan struct{}, max(replicationConcurrency, 1))
+	})
+	r.mu.Lock()
+	if r.queued == nil {
+		r.queued = make(map[string]*replicationQueue)
+	}
+	q := r.queued[key]
+	if q == nil {
+		q = &replicationQueue{}
+		r.queued[key] = q
+	}
+	q.count++
+	r.mu.Unlock()
+
+	r.wg.Go(func() {
+		defer func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if q.count--; q.count == 0 {
+				delete(r.queued, key)
+			}
+		}()
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		r.sem <- struct{}{}
+		defer func() { <-r.sem }()
+		f()
+	})
+}
+
+// pending returns true if a replication with the key is queued or running.
+func (r *replicator) pending(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queued[key] != nil
+}
+
+// wait waits for the replications in flight.
+func (r *replicator) wait() {
+	r.wg.Wait()
+}
+
+func init() {
+	backupstorage.BackupStorageMap[tieredBackupStorageName] = newTieredBackupStorage(backupstorage.NoParams(), defaultReplicator)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tieredbackupstorage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage/memorybackupstorage"
+)
+
+// setupTiers registers in-memory primary and secondary backup storages, and
+// returns them with a tiered backup storage on top of them.
+func setupTiers(t *testing.T) (*TieredBackupStorage, *memorybackupstorage.MemoryBackupStorage, *memorybackupstorage.MemoryBackupStorage) {
+	primary, secondary := memorybackupstorage.NewMemoryBackupStorage(), memorybackupstorage.NewMemoryBackupStorage()
+	backupstorage.BackupStorageMap["tiered-test-primary"] = primary
+	backupstorage.BackupStorageMap["tiered-test-secondary"] = secondary
+	primaryImplementation, secondaryImplementations = "tiered-test-primary", []string{"tiered-test-secondary"}
+	t.Cleanup(func() {
+		delete(backupstorage.BackupStorageMap, "tiered-test-primary")
+		delete(backupstorage.BackupStorageMap, "tiered-test-secondary")
+		primaryImplementation, secondaryImplementations = "", nil
+	})
+	return newTieredBackupStorage(backupstorage.NoParams(), &replicator{}), primary, secondary
+}
+
+func addFile(t *testing.T, bh backupstorage.BackupHandle, name string, data []byte) {
+	wc, err := bh.AddFile(context.Background(), name, int64(len(data)))
+	require.NoError(t, err)
+	_, err = wc.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+}
+
+func readFile(t *testing.T, bh backupstorage.BackupHandle, name string) []byte {
+	rc, err := bh.ReadFile(context.Background(), name)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return data
+}
+
+func TestTieredBackupStorage(t *testing.T) {
+	ctx := context.Background()
+	tbs, primary, secondary := setupTiers(t)
+
+	bh, err := tbs.StartBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "0", []byte("data"))
+	addFile(t, bh, "MANIFEST", []byte("{}"))
+	require.NoError(t, bh.EndBackup(ctx))
+
+	// The backup is written to the primary right away.
+	data, ok := primary.ReadBackupFile("ks/0", "backup1", "0")
+	require.True(t, ok)
+	assert.Equal(t, []byte("data"), data)
+
+	// The backup is replicated to the secondary in the background.
+	tbs.replicator.wait()
+	data, ok = secondary.ReadBackupFile("ks/0", "backup1", "0")
+	require.True(t, ok)
+	assert.Equal(t, []byte("data"), data)
+	data, ok = secondary.ReadBackupFile("ks/0", "backup1", "MANIFEST")
+	require.True(t, ok)
+	assert.Equal(t, []byte("{}"), data)
+
+	// A backup which is missing from the primary is read from the secondary.
+	secondary.WriteBackupFile("ks/0", "backup0", "MANIFEST", []byte("old"))
+	bhs, err := tbs.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 2)
+	assert.Equal(t, "backup0", bhs[0].Name())
+	assert.Equal(t, []byte("old"), readFile(t, bhs[0], "MANIFEST"))
+	assert.Equal(t, "backup1", bhs[1].Name())
+	assert.Equal(t, []byte("{}"), readFile(t, bhs[1], "MANIFEST"))
+
+	// Backups are removed from all the tiers.
+	require.NoError(t, tbs.RemoveBackup(ctx, "ks/0", "backup1"))
+	_, ok = primary.ReadBackupFile("ks/0", "backup1", "0")
+	assert.False(t, ok)
+	_, ok = secondary.ReadBackupFile("ks/0", "backup1", "0")
+	assert.False(t, ok)
+}
+
+func TestTieredBackupStorageReplicatesResumedBackups(t *testing.T) {
+	ctx := context.Background()
+	tbs, primary, secondary := setupTiers(t)
+
+	// The first file was backed up before the backup was interrupted, so it is
+	// only listed by the MANIFEST.
+	primary.WriteBackupFile("ks/0", "backup1", "0", []byte("first"))
+	manifest, err := json.Marshal(map[string]any{
+		"BackupMethod": "builtin",
+		"FileEntries":  []map[string]any{{"Name": "a"}, {"Name": "b"}},
+	})
+	require.NoError(t, err)
+
+	bh, err := tbs.ReopenBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "1", []byte("second"))
+	addFile(t, bh, "MANIFEST", manifest)
+	require.NoError(t, bh.EndBackup(ctx))
+	tbs.replicator.wait()
+
+	for file, want := range map[string][]byte{"0": []byte("first"), "1": []byte("second"), "MANIFEST": manifest} {
+		data, ok := secondary.ReadBackupFile("ks/0", "backup1", file)
+		require.True(t, ok, file)
+		assert.Equal(t, want, data, file)
+	}
+}
+
+func TestTieredBackupStorageReplicatesCompleteBackups(t *testing.T) {
+	ctx := context.Background()
+	tbs, primary, secondary := setupTiers(t)
+
+	// The chunks are uploaded before the backup which references them, and
+	// the progress of the backup is saved while it is taken.
+	bh, err := tbs.StartBackup(ctx, "_vt_chunks/ks/0", "chunk1")
+	require.NoError(t, err)
+	addFile(t, bh, "chunk", []byte("c1"))
+	require.NoError(t, bh.EndBackup(ctx))
+	bh, err = tbs.StartBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "PARTIAL_MANIFEST", []byte("{}"))
+	require.NoError(t, bh.EndBackup(ctx))
+	tbs.replicator.wait()
+
+	bhs, err := secondary.ListBackups(ctx, "_vt_chunks/ks/0")
+	require.NoError(t, err)
+	assert.Empty(t, bhs)
+	bhs, err = secondary.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	assert.Empty(t, bhs)
+
+	// The chunks are replicated along with the complete backup.
+	manifest, err := json.Marshal(map[string]any{
+		"BackupMethod": "builtin",
+		"FileEntries":  []map[string]any{{"Name": "a", "Chunks": []string{"chunk1"}}},
+	})
+	require.NoError(t, err)
+	bh, err = tbs.ReopenBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "MANIFEST", manifest)
+	require.NoError(t, bh.EndBackup(ctx))
+	tbs.replicator.wait()
+
+	data, ok := secondary.ReadBackupFile("_vt_chunks/ks/0", "chunk1", "chunk")
+	require.True(t, ok)
+	assert.Equal(t, []byte("c1"), data)
+	data, ok = secondary.ReadBackupFile("ks/0", "backup1", "MANIFEST")
+	require.True(t, ok)
+	assert.Equal(t, manifest, data)
+	_, ok = primary.ReadBackupFile("ks/0", "backup1", "PARTIAL_MANIFEST")
+	assert.True(t, ok)
+}
+
+func TestTieredBackupStorageReplicatesAddedFiles(t *testing.T) {
+	ctx := context.Background()
+	tbs, primary, secondary := setupTiers(t)
+	manifest, err := json.Marshal(map[string]any{
+		"BackupMethod": "builtin",
+		"FileEntries":  []map[string]any{{"Name": "a"}},
+	})
+	require.NoError(t, err)
+
+	bh, err := tbs.StartBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "0", []byte("data"))
+	addFile(t, bh, "MANIFEST", manifest)
+	require.NoError(t, bh.EndBackup(ctx))
+
+	// The result of the verification of the backup is added once it's complete.
+	bh, err = tbs.ReopenBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "VERIFICATION", []byte("ok"))
+	require.NoError(t, bh.EndBackup(ctx))
+	tbs.replicator.wait()
+
+	for file, want := range map[string][]byte{"0": []byte("data"), "MANIFEST": manifest, "VERIFICATION": []byte("ok")} {
+		data, ok := secondary.ReadBackupFile("ks/0", "backup1", file)
+		require.True(t, ok, file)
+		assert.Equal(t, want, data, file)
+	}
+
+	// The files added to an incomplete backup are replicated along with it.
+	primary.WriteBackupFile("ks/0", "backup2", "0", []byte("data"))
+	bh, err = tbs.ReopenBackup(ctx, "ks/0", "backup2")
+	require.NoError(t, err)
+	addFile(t, bh, "PARTIAL_MANIFEST", []byte("{}"))
+	require.NoError(t, bh.EndBackup(ctx))
+	tbs.replicator.wait()
+	_, ok := secondary.ReadBackupFile("ks/0", "backup2", "PARTIAL_MANIFEST")
+	assert.False(t, ok)
+}
+
+func TestTieredBackupStorageReconcile(t *testing.T) {
+	ctx := context.Background()
+	tbs, primary, secondary := setupTiers(t)
+	manifest, err := json.Marshal(map[string]any{
+		"BackupMethod": "builtin",
+		"FileEntries":  []map[string]any{{"Name": "a"}},
+	})
+	require.NoError(t, err)
+
+	// The replication of the first backup was lost, the second one is in the
+	// secondary already, and the third one is incomplete.
+	primary.WriteBackupFile("ks/0", "backup1", "0", []byte("one"))
+	primary.WriteBackupFile("ks/0", "backup1", "MANIFEST", manifest)
+	primary.WriteBackupFile("ks/0", "backup2", "0", []byte("two"))
+	primary.WriteBackupFile("ks/0", "backup2", "MANIFEST", manifest)
+	secondary.WriteBackupFile("ks/0", "backup2", "MANIFEST", []byte("copy"))
+	primary.WriteBackupFile("ks/0", "backup3", "0", []byte("three"))
+
+	require.NoError(t, tbs.Reconcile(ctx, "ks/0"))
+	tbs.replicator.wait()
+
+	data, ok := secondary.ReadBackupFile("ks/0", "backup1", "0")
+	require.True(t, ok)
+	assert.Equal(t, []byte("one"), data)
+	data, ok = secondary.ReadBackupFile("ks/0", "backup2", "MANIFEST")
+	require.True(t, ok)
+	assert.Equal(t, []byte("copy"), data)
+	_, ok = secondary.ReadBackupFile("ks/0", "backup3", "0")
+	assert.False(t, ok)
+}
+
+func TestReplicatorSerializesReplications(t *testing.T) {
+	r := &replicator{}
+	release := make(chan struct{})
+	var running, overlaps atomic.Int32
+	for range 3 {
+		r.run("key", func() {
+			if running.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			<-release
+			running.Add(-1)
+		})
+	}
+	assert.True(t, r.pending("key"))
+	assert.False(t, r.pending("other"))
+	close(release)
+	r.wait()
+	// The replications with the same key do not run at the same time.
+	assert.Zero(t, overlaps.Load())
+	assert.False(t, r.pending("key"))
+}
+
+func TestTieredBackupStorageReplicationFailure(t *testing.T) {
+	ctx := context.Background()
+	tbs, _, secondary := setupTiers(t)
+
+	// The hash of the file does not match the one in the MANIFEST.
+	manifest, err := json.Marshal(map[string]any{
+		"BackupMethod": "builtin",
+		"FileEntries":  []map[string]any{{"Name": "a", "Hash": "00000000"}},
+	})
+	require.NoError(t, err)
+
+	bh, err := tbs.StartBackup(ctx, "ks/0", "backup1")
+	require.NoError(t, err)
+	addFile(t, bh, "0", []byte("data"))
+	addFile(t, bh, "MANIFEST", manifest)
+
+	errors := replications.Counts()["tiered-test-secondary.Error"]
+	require.NoError(t, bh.EndBackup(ctx))
+	tbs.replicator.wait()
+
+	assert.Equal(t, errors+1, replications.Counts()["tiered-test-secondary.Error"])
+	bhs, err := secondary.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	assert.Empty(t, bhs)
+}
+
+func TestTieredBackupStorageConfiguration(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		primary     string
+		secondaries []string
+		wantErr     string
+	}{
+		{
+			name:    "no primary",
+			wantErr: "--tiered-backup-storage-primary is not set",
+		},
+		{
+			name:    "unknown primary",
+			primary: "unknown",
+			wantErr: `no registered implementation of BackupStorage named "unknown"`,
+		},
+		{
+			name:        "tiered secondary",
+			primary:     "tiered-test-primary",
+			secondaries: []string{"tiered"},
+			wantErr:     "the tiered backup storage cannot be one of its own tiers",
+		},
+		{
+			name:        "primary is also a secondary",
+			primary:     "tiered-test-primary",
+			secondaries: []string{"tiered-test-secondary", "tiered-test-primary"},
+			wantErr:     `backup storage implementation "tiered-test-primary" is used by more than one tier`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbs, _, _ := setupTiers(t)
+			primaryImplementation, secondaryImplementations = tt.primary, tt.secondaries
+
+			_, err := tbs.ListBackups(ctx, "ks/0")
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/env"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// backupVerificationFileName is the name of the file holding the result of
+	// a backup verification.
+	backupVerificationFileName = "VERIFICATION"
+
+	// verifyBackupTablesQuery lists the tables to check in a restored backup.
+	verifyBackupTablesQuery = "select table_schema, table_name, ifnull(table_rows, 0) from information_schema.tables " +
+		"where table_type = 'BASE TABLE' and table_schema not in ('mysql', 'sys', 'information_schema', 'performance_schema') " +
+		"order by table_schema, table_name"
+)
+
+// VerifyBackupParams holds the parameters of VerifyBackup.
+type VerifyBackupParams struct {
+	Logger logutil.Logger
+	// Keyspace and Shard are used to infer the directory where backups are stored.
+	Keyspace string
+	Shard    string
+	// BackupName is the name of the backup to verify.
+	BackupName string
+	// DbName is the name of the managed database / schema.
+	DbName string
+	// Concurrency is the number of files restored in parallel.
+	Concurrency int
+	// DBConfigs holds the credentials of the mysqld the backup was taken from,
+	// which are used to connect to the scratch mysqld once it is restored.
+	DBConfigs    *dbconfigs.DBConfigs
+	CollationEnv *collations.Environment
+	// MysqlShutdownTimeout defines how long we wait for the scratch mysqld to shut down.
+	MysqlShutdownTimeout time.Duration
+	// Stats let's restore engines report detailed restore timings.
+	Stats backupstats.Stats
+}
+
+// VerifyBackup proves that a backup is restorable: it restores the backup into
+// a scratch mysqld, which lives in a temporary directory under VTDATAROOT and
+// on a port of its own, then runs CHECK TABLE and row count sanity checks on
+// every table. The result is recorded in the VERIFICATION file of the backup,
+// and the scratch mysqld and its directory are removed.
+//
+// A backup which cannot be restored or whose tables fail their checks is
+// reported through the returned result. An error is only returned when the
+// verification itself could not be run or recorded.
+func VerifyBackup(ctx context.Context, params VerifyBackupParams) (*mysqlctlpb.VerifyBackupResult, error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+	// The scratch mysqld is started by this process, even if the mysqld of the
+	// tablet is managed by mysqlctld, so it needs a local mysqld binary.
+	if _, err := localVersionString(); err != nil {
+		if socketFile != "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "verifying a backup needs a local mysqld binary, even when mysqld is managed by mysqlctld through --mysqlctl-socket %s: %v", socketFile, err)
+		}
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "verifying a backup needs a local mysqld binary: %v", err)
+	}
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	if err := checkBackupExists(ctx, backupDir, params.BackupName); err != nil {
+		return nil, err
+	}
+
+	result := &mysqlctlpb.VerifyBackupResult{
+		BackupName: params.BackupName,
+		Directory:  backupDir,
+		StartTime:  protoutil.TimeToProto(time.Now()),
+	}
+	if err := verifyBackup(ctx, params, result); err != nil {
+		params.Logger.Errorf("VerifyBackup: backup %v failed verification: %v", params.BackupName, err)
+		result.Error = err.Error()
+	}
+	result.Ok = result.Error == "" && !slices.ContainsFunc(result.Tables, func(table *mysqlctlpb.VerifyBackupTableResult) bool {
+		return !table.Ok
+	})
+	result.EndTime = protoutil.TimeToProto(time.Now())
+
+	if err := recordBackupVerification(ctx, result); err != nil {
+		return result, vterrors.Wrapf(err, "failed to record the verification of backup %s", params.BackupName)
+	}
+	params.Logger.Infof("VerifyBackup: backup %v verified, ok: %v", params.BackupName, result.Ok)
+	return result, nil
+}
+
+// checkBackupExists returns a NOT_FOUND error if there is no backup with
+// the given name in backupDir.
+func checkBackupExists(ctx context.Context, backupDir, backupName string) error {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return vterrors.Wrap(err, "ListBackups failed")
+	}
+	for _, bh := range bhs {
+		if bh.Name() == backupName {
+			return nil
+		}
+	}
+	return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "could not find backup %q in %s", backupName, backupDir)
+}
+
+// verifyBackup restores the backup into a scratch mysqld and checks its tables,
+// filling in the result.
+func verifyBackup(ctx context.Context, params VerifyBackupParams, result *mysqlctlpb.VerifyBackupResult) error {
+	scratchDir, err := os.MkdirTemp(env.VtDataRoot(), "vt_verify_backup_")
+	if err != nil {
+		return vterrors.Wrap(err, "failed to create the directory of the scratch mysqld")
+	}
+	defer func() {
+		params.Logger.Infof("VerifyBackup: removing scratch directory %v", scratchDir)
+		if err := os.RemoveAll(scratchDir); err != nil {
+			params.Logger.Warningf("VerifyBackup: failed to remove scratch directory %v: %v", scratchDir, err)
+		}
+	}()
+
+	port, err := scratchMysqlPort()
+	if err != nil {
+		return vterrors.Wrap(err, "failed to find a port for the scratch mysqld")
+	}
+	cnf := newMycnfInDir(scratchDir, 0, port)
+	if err := cnf.RandomizeMysqlServerID(); err != nil {
+		return vterrors.Wrap(err, "failed to generate a server_id for the scratch mysqld")
+	}
+	mysqld, err := newScratchMysqld(params.DBConfigs.CloneWithSocket(cnf.SocketFile, params.CollationEnv))
+	if err != nil {
+		return err
+	}
+	defer mysqld.Close()
+	if err := mysqld.InitConfig(cnf); err != nil {
+		return vterrors.Wrap(err, "failed to initialize the configuration of the scratch mysqld")
+	}
+	defer func() {
+		// Use a fresh context, so that mysqld is shut down even if the verification
+		// timed out.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), params.MysqlShutdownTimeout+10*time.Second)
+		defer cancel()
+		if err := mysqld.Shutdown(shutdownCtx, cnf, true, params.MysqlShutdownTimeout); err != nil {
+			params.Logger.Warningf("VerifyBackup: failed to shut down the scratch mysqld: %v", err)
+		}
+	}()
+
+	params.Logger.Infof("VerifyBackup: restoring backup %v into a scratch mysqld in %v", params.BackupName, scratchDir)
+	manifest, err := Restore(ctx, RestoreParams{
+		Cnf:                  cnf,
+		Mysqld:               mysqld,
+		Logger:               params.Logger,
+		Concurrency:          params.Concurrency,
+		DeleteBeforeRestore:  true,
+		DbName:               params.DbName,
+		Keyspace:             params.Keyspace,
+		Shard:                params.Shard,
+		BackupName:           params.BackupName,
+		Stats:                params.Stats,
+		MysqlShutdownTimeout: params.MysqlShutdownTimeout,
+	})
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to restore backup %s", params.BackupName)
+	}
+	result.Position = replication.EncodePosition(manifest.Position)
+
+	params.Logger.Infof("VerifyBackup: checking the tables of backup %v", params.BackupName)
+	result.Tables, err = checkRestoredTables(ctx, mysqld)
+	return err
+}
+
+// newScratchMysqld returns a Mysqld which acts locally on the mysqld reached
+// through dbcfgs, even if the mysqld of this process is managed by mysqlctld.
+func newScratchMysqld(dbcfgs *dbconfigs.DBConfigs) (*Mysqld, error) {
+	version, err := localVersionString()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to detect the local MySQL version")
+	}
+	f, v, err := ParseVersionString(version)
+	if err != nil {
+		return nil, err
+	}
+	mysqld := &Mysqld{
+		dbcfgs:       dbcfgs,
+		capabilities: newCapabilitySet(f, v),
+	}
+	mysqld.openPools()
+	return mysqld, nil
+}
+
+// scratchMysqlPort returns a TCP port which is currently free.
+func scratchMysqlPort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// checkRestoredTables runs CHECK TABLE and counts the rows of every table of
+// the restored mysqld.
+func checkRestoredTables(ctx context.Context, mysqld *Mysqld) ([]*mysqlctlpb.VerifyBackupTableResult, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, verifyBackupTablesQuery)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to list the restored tables")
+	}
+	tables := make([]*mysqlctlpb.VerifyBackupTableResult, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		estimatedRowCount, err := row[2].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		table := &mysqlctlpb.VerifyBackupTableResult{
+			Schema:            row[0].ToString(),
+			Name:              row[1].ToString(),
+			EstimatedRowCount: estimatedRowCount,
+			Ok:                true,
+		}
+		if err := checkRestoredTable(ctx, mysqld, table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// checkRestoredTable runs the checks of one restored table. Problems found
+// with the table are reported in it, and errors are only returned if the
+// checks could not be run at all.
+func checkRestoredTable(ctx context.Context, mysqld *Mysqld, table *mysqlctlpb.VerifyBackupTableResult) error {
+	addProblem := func(format string, args ...any) {
+		table.Ok = false
+		table.Messages = append(table.Messages, fmt.Sprintf(format, args...))
+	}
+	tableName := sqlescape.EscapeID(table.Schema) + "." + sqlescape.EscapeID(table.Name)
+
+	// CHECK TABLE returns rows of Table, Op, Msg_type and Msg_text, the last of
+	// which has the status of the table.
+	qr, err := mysqld.FetchSuperQuery(ctx, "check table "+tableName)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to check table %s", tableName)
+	}
+	for _, row := range qr.Rows {
+		msgType, msgText := row[2].ToString(), row[3].ToString()
+		switch {
+		case msgType == "error":
+			addProblem("CHECK TABLE error: %s", msgText)
+		case msgType == "warning":
+			table.Messages = append(table.Messages, "CHECK TABLE warning: "+msgText)
+		case msgType == "status" && msgText != "OK" && msgText != "Table is already up to date":
+			addProblem("CHECK TABLE status: %s", msgText)
+		}
+	}
+
+	// Reading all the rows also reads all the pages of the clustered index.
+	qr, err = mysqld.FetchSuperQuery(ctx, "select count(*) from "+tableName)
+	if err != nil {
+		addProblem("cannot count the rows: %v", err)
+		return nil
+	}
+	if table.RowCount, err = qr.Rows[0][0].ToInt64(); err != nil {
+		return err
+	}
+	if table.RowCount == 0 && table.EstimatedRowCount > 0 {
+		addProblem("the table is empty, but its statistics estimate %d rows", table.EstimatedRowCount)
+	}
+	return nil
+}
+
+// recordBackupVerification stores the result of a verification in the
+// VERIFICATION file of the verified backup, replacing the result of any
+// previous verification.
+func recordBackupVerification(ctx context.Context, result *mysqlctlpb.VerifyBackupResult) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+
+	// The backup is complete, so the handle must not be aborted if the file
+	// cannot be written, which would remove the backup.
+	bh, err := bs.ReopenBackup(ctx, result.Directory, result.BackupName)
+	if err != nil {
+		return err
+	}
+	wc, err := bh.AddFile(ctx, backupVerificationFileName, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	return bh.EndBackup(ctx)
+}
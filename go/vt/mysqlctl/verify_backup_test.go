@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestCheckRestoredTables(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	cp := *db.ConnParams()
+	mysqld := &Mysqld{dbcfgs: dbconfigs.NewTestDBConfigs(cp, cp, "fakesqldb")}
+	mysqld.openPools()
+	defer mysqld.Close()
+
+	db.AddQuery("SELECT 1", &sqltypes.Result{})
+	db.AddQuery(verifyBackupTablesQuery, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("table_schema|table_name|table_rows", "varchar|varchar|int64"),
+		"_vt|broken|3",
+		"vt_ks|emptied|100",
+		"vt_ks|t1|10",
+	))
+	checkFields := sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar")
+	countFields := sqltypes.MakeTestFields("count(*)", "int64")
+	db.AddQuery("check table `_vt`.`broken`", sqltypes.MakeTestResult(checkFields,
+		"_vt.broken|check|error|Corrupt page 4",
+		"_vt.broken|check|status|Corrupt",
+	))
+	db.AddQuery("select count(*) from `_vt`.`broken`", sqltypes.MakeTestResult(countFields, "3"))
+	db.AddQuery("check table `vt_ks`.`emptied`", sqltypes.MakeTestResult(checkFields, "vt_ks.emptied|check|status|OK"))
+	db.AddQuery("select count(*) from `vt_ks`.`emptied`", sqltypes.MakeTestResult(countFields, "0"))
+	db.AddQuery("check table `vt_ks`.`t1`", sqltypes.MakeTestResult(checkFields,
+		"vt_ks.t1|check|warning|Table uses an old row format",
+		"vt_ks.t1|check|status|OK",
+	))
+	db.AddQuery("select count(*) from `vt_ks`.`t1`", sqltypes.MakeTestResult(countFields, "10"))
+
+	tables, err := checkRestoredTables(context.Background(), mysqld)
+	require.NoError(t, err)
+	want := []*mysqlctlpb.VerifyBackupTableResult{
+		{
+			Schema:            "_vt",
+			Name:              "broken",
+			RowCount:          3,
+			EstimatedRowCount: 3,
+			Messages:          []string{"CHECK TABLE error: Corrupt page 4", "CHECK TABLE status: Corrupt"},
+		},
+		{
+			Schema:            "vt_ks",
+			Name:              "emptied",
+			EstimatedRowCount: 100,
+			Messages:          []string{"the table is empty, but its statistics estimate 100 rows"},
+		},
+		{
+			Schema:            "vt_ks",
+			Name:              "t1",
+			RowCount:          10,
+			EstimatedRowCount: 10,
+			Ok:                true,
+			Messages:          []string{"CHECK TABLE warning: Table uses an old row format"},
+		},
+	}
+	utils.MustMatch(t, want, tables)
+}
+
+type verificationWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *verificationWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestRecordBackupVerification(t *testing.T) {
+	writer := &verificationWriter{}
+	bh := &FakeBackupHandle{AddFileReturn: FakeBackupHandleAddFileReturn{WriteCloser: writer}}
+	bs := &FakeBackupStorage{ReopenBackupReturn: FakeBackupStorageStartBackupReturn{BackupHandle: bh}}
+	backupstorage.BackupStorageMap["fake-verify"] = bs
+	previousBackupStorageImplementation := backupstorage.BackupStorageImplementation
+	backupstorage.BackupStorageImplementation = "fake-verify"
+	defer func() {
+		delete(backupstorage.BackupStorageMap, "fake-verify")
+		backupstorage.BackupStorageImplementation = previousBackupStorageImplementation
+	}()
+
+	endTime := time.Date(2026, 10, 19, 1, 2, 3, 0, time.UTC)
+	result := &mysqlctlpb.VerifyBackupResult{
+		BackupName: "2026-10-18.000000.zone1-0000000101",
+		Directory:  "ks/-80",
+		EndTime:    protoutil.TimeToProto(endTime),
+		Ok:         true,
+		Tables:     []*mysqlctlpb.VerifyBackupTableResult{{Schema: "vt_ks", Name: "t1", RowCount: 10, Ok: true}},
+	}
+	require.NoError(t, recordBackupVerification(context.Background(), result))
+
+	assert.Empty(t, bs.StartBackupCalls)
+	require.Len(t, bs.ReopenBackupCalls, 1)
+	assert.Equal(t, "ks/-80", bs.ReopenBackupCalls[0].Dir)
+	assert.Equal(t, "2026-10-18.000000.zone1-0000000101", bs.ReopenBackupCalls[0].Name)
+	require.Len(t, bh.AddFileCalls, 1)
+	assert.Equal(t, backupVerificationFileName, bh.AddFileCalls[0].Filename)
+	assert.True(t, writer.closed)
+	assert.Len(t, bh.EndBackupCalls, 1)
+
+	recorded := &mysqlctlpb.VerifyBackupResult{}
+	require.NoError(t, protojson.Unmarshal(writer.Bytes(), recorded))
+	utils.MustMatch(t, result, recorded)
+}
+
+func TestVerifyBackupNeedsLocalMysqld(t *testing.T) {
+	t.Setenv("VT_MYSQL_ROOT", t.TempDir())
+	oldSocketFile := socketFile
+	defer func() {
+		socketFile = oldSocketFile
+	}()
+
+	_, err := VerifyBackup(context.Background(), VerifyBackupParams{BackupName: "backup"})
+	assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	assert.ErrorContains(t, err, "verifying a backup needs a local mysqld binary: mysqld not found")
+
+	socketFile = "/vt/socket/mysqlctl.sock"
+	_, err = VerifyBackup(context.Background(), VerifyBackupParams{BackupName: "backup"})
+	assert.ErrorContains(t, err, "even when mysqld is managed by mysqlctld through --mysqlctl-socket /vt/socket/mysqlctl.sock")
+}
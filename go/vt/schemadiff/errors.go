@@ -534,3 +534,25 @@ type DuplicateForeignKeyConstraintNameError struct {
 func (e *DuplicateForeignKeyConstraintNameError) Error() string {
 	return fmt.Sprintf("duplicate foreign key constraint name %s in table %s", sqlescape.EscapeID(e.Constraint), sqlescape.EscapeID(e.Table))
 }
+
+// InvalidPartitionPolicyError is returned when a table comment has a malformed partition policy.
+type InvalidPartitionPolicyError struct {
+	Table  string
+	Policy string
+	Reason string
+}
+
+func (e *InvalidPartitionPolicyError) Error() string {
+	return fmt.Sprintf("invalid partition policy %s in table %s: %s", e.Policy, sqlescape.EscapeID(e.Table), e.Reason)
+}
+
+// UnsupportedPartitionPolicyTableError is returned when a table has a partition policy, but its
+// partitioning scheme or current partitions do not allow the policy to be applied.
+type UnsupportedPartitionPolicyTableError struct {
+	Table  string
+	Reason string
+}
+
+func (e *UnsupportedPartitionPolicyTableError) Error() string {
+	return fmt.Sprintf("cannot apply partition policy to table %s: %s", sqlescape.EscapeID(e.Table), e.Reason)
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// PartitionInterval is the time span covered by each partition of a table
+// with a partition policy.
+type PartitionInterval string
+
+const (
+	PartitionIntervalHour  PartitionInterval = "hour"
+	PartitionIntervalDay   PartitionInterval = "day"
+	PartitionIntervalWeek  PartitionInterval = "week"
+	PartitionIntervalMonth PartitionInterval = "month"
+	PartitionIntervalYear  PartitionInterval = "year"
+)
+
+// maxPartitionPolicyAddedPartitions is the maximum number of partitions that
+// a partition policy adds at once, so that a policy whose last partition is
+// very old doesn't generate a runaway number of migrations.
+const maxPartitionPolicyAddedPartitions = 1000
+
+// partitionPolicyRegexp matches a partition policy in a table comment, e.g.
+// `vitess_partition_policy(interval=day, ahead=7, retention=30)`
+var partitionPolicyRegexp = regexp.MustCompile(`(?i)vitess_partition_policy\s*\(([^)]*)\)`)
+
+// PartitionPolicy describes how the range partitions of a temporal table are
+// managed:
+//   - Interval is the span of each partition.
+//   - Ahead is the number of intervals, after the current one, for which
+//     partitions must exist.
+//   - Retention is the number of intervals for which partitions are kept. Older
+//     partitions are dropped. Zero means partitions are never dropped.
+type PartitionPolicy struct {
+	Interval  PartitionInterval
+	Ahead     int
+	Retention int
+}
+
+// addIntervals returns the given time moved by n intervals.
+func (p *PartitionPolicy) addIntervals(t time.Time, n int) time.Time {
+	switch p.Interval {
+	case PartitionIntervalHour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case PartitionIntervalDay:
+		return t.AddDate(0, 0, n)
+	case PartitionIntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case PartitionIntervalMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(n, 0, 0)
+	}
+}
+
+// partitionName returns the name of the partition which starts at the given time.
+func (p *PartitionPolicy) partitionName(t time.Time) string {
+	switch p.Interval {
+	case PartitionIntervalHour:
+		return "p" + t.Format("2006010215")
+	case PartitionIntervalDay, PartitionIntervalWeek:
+		return "p" + t.Format("20060102")
+	case PartitionIntervalMonth:
+		return "p" + t.Format("200601")
+	default:
+		return "p" + t.Format("2006")
+	}
+}
+
+// ParsePartitionPolicy parses the partition policy found in a table comment.
+// It returns nil when the comment has no policy.
+func ParsePartitionPolicy(table string, comment string) (*PartitionPolicy, error) {
+	match := partitionPolicyRegexp.FindStringSubmatch(comment)
+	if match == nil {
+		return nil, nil
+	}
+	invalid := func(reason string) error {
+		return &InvalidPartitionPolicyError{Table: table, Policy: match[0], Reason: reason}
+	}
+	policy := &PartitionPolicy{}
+	for _, param := range strings.Split(match[1], ",") {
+		if strings.TrimSpace(param) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return nil, invalid("expected key=value, found " + strings.TrimSpace(param))
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch key {
+		case "interval":
+			switch interval := PartitionInterval(value); interval {
+			case PartitionIntervalHour, PartitionIntervalDay, PartitionIntervalWeek, PartitionIntervalMonth, PartitionIntervalYear:
+				policy.Interval = interval
+			default:
+				return nil, invalid("unknown interval " + value)
+			}
+		case "ahead", "retention":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, invalid("invalid " + key + " " + value)
+			}
+			if key == "ahead" {
+				policy.Ahead = n
+			} else {
+				policy.Retention = n
+			}
+		default:
+			return nil, invalid("unknown parameter " + key)
+		}
+	}
+	if policy.Interval == "" {
+		return nil, invalid("interval is required")
+	}
+	return policy, nil
+}
+
+// CreateTablePartitionPolicy returns the partition policy declared in the
+// comment of the given table, or nil if there is none.
+func CreateTablePartitionPolicy(createTable *sqlparser.CreateTable) (*PartitionPolicy, error) {
+	for _, option := range createTable.TableSpec.Options {
+		if strings.EqualFold(option.Name, "comment") && option.Value != nil {
+			return ParsePartitionPolicy(createTable.GetTable().Name.String(), option.Value.Val)
+		}
+	}
+	return nil, nil
+}
+
+// partitionScheme converts the boundaries of the range partitions of a table
+// to time, and back. The supported schemes are RANGE COLUMNS on a single
+// temporal column, and RANGE on TO_DAYS(), UNIX_TIMESTAMP() or YEAR() of a
+// column.
+type partitionScheme struct {
+	// function is the name of the partitioning function, or empty for
+	// RANGE COLUMNS.
+	function string
+	// withTime is set for RANGE COLUMNS boundaries that have a time part.
+	withTime bool
+}
+
+// toDaysEpoch is TO_DAYS('1970-01-01')
+const toDaysEpoch = 719528
+
+func parseTemporalLiteral(s string) (time.Time, bool) {
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// boundary returns the time of a partition boundary value.
+func (s *partitionScheme) boundary(expr sqlparser.Expr) (time.Time, bool) {
+	if s.function == "" {
+		lit, ok := expr.(*sqlparser.Literal)
+		if !ok || lit.Type != sqlparser.StrVal {
+			return time.Time{}, false
+		}
+		return parseTemporalLiteral(lit.Val)
+	}
+	switch expr := expr.(type) {
+	case *sqlparser.Literal:
+		if expr.Type != sqlparser.IntVal {
+			return time.Time{}, false
+		}
+		n, err := strconv.ParseInt(expr.Val, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch s.function {
+		case "to_days":
+			return time.Unix(0, 0).UTC().AddDate(0, 0, int(n-toDaysEpoch)), true
+		case "unix_timestamp":
+			return time.Unix(n, 0).UTC(), true
+		default:
+			return time.Date(int(n), time.January, 1, 0, 0, 0, 0, time.UTC), true
+		}
+	case *sqlparser.FuncExpr:
+		// e.g. TO_DAYS('2024-01-01')
+		if !expr.Name.EqualString(s.function) || len(expr.Exprs) != 1 {
+			return time.Time{}, false
+		}
+		lit, ok := expr.Exprs[0].(*sqlparser.Literal)
+		if !ok || lit.Type != sqlparser.StrVal {
+			return time.Time{}, false
+		}
+		return parseTemporalLiteral(lit.Val)
+	}
+	return time.Time{}, false
+}
+
+// value returns the partition boundary value of the given time.
+func (s *partitionScheme) value(t time.Time) sqlparser.Expr {
+	switch s.function {
+	case "":
+		if s.withTime {
+			return sqlparser.NewStrLiteral(t.Format(time.DateTime))
+		}
+		return sqlparser.NewStrLiteral(t.Format(time.DateOnly))
+	case "to_days":
+		days := int64(t.Sub(time.Unix(0, 0).UTC())/(24*time.Hour)) + toDaysEpoch
+		return sqlparser.NewIntLiteral(strconv.FormatInt(days, 10))
+	case "unix_timestamp":
+		return sqlparser.NewIntLiteral(strconv.FormatInt(t.Unix(), 10))
+	default:
+		return sqlparser.NewIntLiteral(strconv.Itoa(t.Year()))
+	}
+}
+
+// supports answers whether the scheme can represent the boundaries of the interval.
+func (s *partitionScheme) supports(interval PartitionInterval) bool {
+	switch s.function {
+	case "to_days":
+		return interval != PartitionIntervalHour
+	case "year":
+		return interval == PartitionIntervalYear
+	}
+	return true
+}
+
+// PartitionPolicyAlters returns the ALTER TABLE statements that apply the
+// partition policy of the given table at the given time: one statement for
+// each partition to add, followed by a statement that drops all the expired
+// partitions. It returns no statements when the table has no policy, or when
+// its partitions already satisfy the policy. Each statement is a range
+// partition rotation, which Online DDL runs directly without copying data.
+func PartitionPolicyAlters(createTable *sqlparser.CreateTable, now time.Time) ([]*sqlparser.AlterTable, error) {
+	policy, err := CreateTablePartitionPolicy(createTable)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+	tableName := createTable.GetTable().Name.String()
+	unsupported := func(reason string) error {
+		return &UnsupportedPartitionPolicyTableError{Table: tableName, Reason: reason}
+	}
+
+	partitionOption := createTable.TableSpec.PartitionOption
+	if partitionOption == nil || partitionOption.Type != sqlparser.RangeType || partitionOption.SubPartition != nil {
+		return nil, unsupported("table must be partitioned by RANGE, without subpartitions")
+	}
+	if len(partitionOption.Definitions) == 0 {
+		return nil, unsupported("table has no partitions")
+	}
+	scheme := &partitionScheme{}
+	switch expr := partitionOption.Expr.(type) {
+	case nil:
+		if len(partitionOption.ColList) != 1 {
+			return nil, unsupported("RANGE COLUMNS must have a single column")
+		}
+	case *sqlparser.FuncExpr:
+		scheme.function = expr.Name.Lowered()
+		switch scheme.function {
+		case "to_days", "unix_timestamp", "year":
+		default:
+			return nil, unsupported("unsupported partitioning expression " + sqlparser.CanonicalString(expr))
+		}
+	default:
+		return nil, unsupported("unsupported partitioning expression " + sqlparser.CanonicalString(expr))
+	}
+
+	// Read the boundaries. Only the last partition may be a MAXVALUE one.
+	names := make(map[string]bool)
+	var boundaries []time.Time
+	var maxValuePartition string
+	for _, definition := range partitionOption.Definitions {
+		names[definition.Name.Lowered()] = true
+		valueRange := definition.Options.ValueRange
+		if valueRange.Maxvalue {
+			maxValuePartition = definition.Name.String()
+			continue
+		}
+		if len(valueRange.Range) != 1 {
+			return nil, unsupported("partition " + definition.Name.String() + " must have a single boundary value")
+		}
+		if lit, ok := valueRange.Range[0].(*sqlparser.Literal); ok && scheme.function == "" && strings.Contains(lit.Val, " ") {
+			scheme.withTime = true
+		}
+		boundary, ok := scheme.boundary(valueRange.Range[0])
+		if !ok {
+			return nil, unsupported("cannot read the boundary of partition " + definition.Name.String() + ": " + sqlparser.CanonicalString(valueRange.Range[0]))
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	if len(boundaries) == 0 {
+		return nil, unsupported("table has no partition with a boundary value")
+	}
+	if !scheme.supports(policy.Interval) {
+		return nil, unsupported("interval " + string(policy.Interval) + " is not supported by " + scheme.function + "() partitioning")
+	}
+
+	var alters []*sqlparser.AlterTable
+	tableNameNode := createTable.GetTable()
+
+	// Add the partitions that are due, each starting at the end of the previous one.
+	now = now.UTC()
+	horizon := policy.addIntervals(now, policy.Ahead)
+	last := boundaries[len(boundaries)-1]
+	for !last.After(horizon) {
+		if maxValuePartition != "" {
+			return nil, unsupported("cannot add partitions after the MAXVALUE partition " + maxValuePartition)
+		}
+		if len(alters) == maxPartitionPolicyAddedPartitions {
+			return nil, unsupported("too many partitions to add; the last partition ends at " + last.Format(time.DateTime))
+		}
+		name := policy.partitionName(last)
+		if names[strings.ToLower(name)] {
+			return nil, unsupported("partition " + name + " already exists")
+		}
+		names[strings.ToLower(name)] = true
+		next := policy.addIntervals(last, 1)
+		alters = append(alters, &sqlparser.AlterTable{
+			Table: tableNameNode,
+			PartitionSpec: &sqlparser.PartitionSpec{
+				Action: sqlparser.AddAction,
+				Definitions: []*sqlparser.PartitionDefinition{{
+					Name: sqlparser.NewIdentifierCI(name),
+					Options: &sqlparser.PartitionDefinitionOptions{
+						ValueRange: &sqlparser.PartitionValueRange{
+							Type:  sqlparser.LessThanType,
+							Range: sqlparser.ValTuple{scheme.value(next)},
+						},
+					},
+				}},
+			},
+		})
+		last = next
+	}
+
+	// Drop the partitions whose rows are all past the retention. The last
+	// partition is always kept, as a table must have at least one partition.
+	if policy.Retention > 0 {
+		cutoff := policy.addIntervals(now, -policy.Retention)
+		var expired sqlparser.Partitions
+		for i, boundary := range boundaries {
+			if i == len(partitionOption.Definitions)-1 || boundary.After(cutoff) {
+				break
+			}
+			expired = append(expired, partitionOption.Definitions[i].Name)
+		}
+		if len(expired) > 0 {
+			alters = append(alters, &sqlparser.AlterTable{
+				Table: tableNameNode,
+				PartitionSpec: &sqlparser.PartitionSpec{
+					Action: sqlparser.DropAction,
+					Names:  expired,
+				},
+			})
+		}
+	}
+	return alters, nil
+}
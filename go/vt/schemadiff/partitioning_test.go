@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemadiff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestParsePartitionPolicy(t *testing.T) {
+	tcases := []struct {
+		comment string
+		expect  *PartitionPolicy
+		isErr   bool
+	}{
+		{
+			comment: "just a table",
+		},
+		{
+			comment: "vitess_partition_policy(interval=day, ahead=7, retention=30)",
+			expect:  &PartitionPolicy{Interval: PartitionIntervalDay, Ahead: 7, Retention: 30},
+		},
+		{
+			comment: "events table; VITESS_PARTITION_POLICY( Interval = Month )",
+			expect:  &PartitionPolicy{Interval: PartitionIntervalMonth},
+		},
+		{
+			comment: "vitess_partition_policy(ahead=3)",
+			isErr:   true,
+		},
+		{
+			comment: "vitess_partition_policy(interval=minute)",
+			isErr:   true,
+		},
+		{
+			comment: "vitess_partition_policy(interval=day, ahead=-1)",
+			isErr:   true,
+		},
+		{
+			comment: "vitess_partition_policy(interval=day, keep=3)",
+			isErr:   true,
+		},
+		{
+			comment: "vitess_partition_policy(interval)",
+			isErr:   true,
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.comment, func(t *testing.T) {
+			policy, err := ParsePartitionPolicy("t", tcase.comment)
+			if tcase.isErr {
+				assert.ErrorAs(t, err, new(*InvalidPartitionPolicyError))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expect, policy)
+		})
+	}
+}
+
+func TestPartitionPolicyAlters(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 12, 30, 0, 0, time.UTC)
+	tcases := []struct {
+		name   string
+		create string
+		expect []string
+		isErr  bool
+	}{
+		{
+			name:   "no policy",
+			create: "create table t (id int, d date, primary key (id, d)) partition by range columns (d) (partition p0 values less than ('2024-01-01'))",
+		},
+		{
+			name:   "range columns, day",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=day, ahead=2, retention=3)' partition by range columns (d) (partition p20240105 values less than ('2024-01-06'), partition p20240106 values less than ('2024-01-07'), partition p20240107 values less than ('2024-01-08'), partition p20240108 values less than ('2024-01-09'), partition p20240109 values less than ('2024-01-10'), partition p20240110 values less than ('2024-01-11'), partition p20240111 values less than ('2024-01-12'))",
+			expect: []string{
+				"ALTER TABLE `t` ADD PARTITION (PARTITION `p20240112` VALUES LESS THAN ('2024-01-13'))",
+				"ALTER TABLE `t` DROP PARTITION `p20240105`, `p20240106`",
+			},
+		},
+		{
+			name:   "range columns, datetime, hour",
+			create: "create table t (id int, d datetime, primary key (id, d)) comment 'vitess_partition_policy(interval=hour, ahead=1)' partition by range columns (d) (partition p0 values less than ('2024-01-10 13:00:00'))",
+			expect: []string{
+				"ALTER TABLE `t` ADD PARTITION (PARTITION `p2024011013` VALUES LESS THAN ('2024-01-10 14:00:00'))",
+			},
+		},
+		{
+			name:   "to_days, month",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=month, ahead=1, retention=1)' partition by range (to_days(d)) (partition p202311 values less than (to_days('2023-12-01')), partition p202312 values less than (739251), partition p202401 values less than (to_days('2024-02-01')))",
+			expect: []string{
+				"ALTER TABLE `t` ADD PARTITION (PARTITION `p202402` VALUES LESS THAN (739311))",
+				"ALTER TABLE `t` DROP PARTITION `p202311`",
+			},
+		},
+		{
+			name:   "unix_timestamp, week",
+			create: "create table t (id int, ts timestamp, primary key (id, ts)) comment 'vitess_partition_policy(interval=week)' partition by range (unix_timestamp(ts)) (partition p0 values less than (1704758400))",
+			expect: []string{
+				"ALTER TABLE `t` ADD PARTITION (PARTITION `p20240109` VALUES LESS THAN (1705363200))",
+			},
+		},
+		{
+			name:   "year",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=year, retention=2)' partition by range (year(d)) (partition p2020 values less than (2021), partition p2021 values less than (2022), partition p2022 values less than (2023), partition p2023 values less than (2024), partition p2024 values less than (2025))",
+			expect: []string{
+				"ALTER TABLE `t` DROP PARTITION `p2020`, `p2021`",
+			},
+		},
+		{
+			name:   "satisfied",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=year, retention=10)' partition by range (year(d)) (partition p2023 values less than (2024), partition p2024 values less than (2025))",
+		},
+		{
+			name:   "maxvalue, drop only",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=day, retention=1)' partition by range columns (d) (partition p0 values less than ('2023-01-01'), partition p1 values less than ('2024-02-01'), partition pmax values less than maxvalue)",
+			expect: []string{
+				"ALTER TABLE `t` DROP PARTITION `p0`",
+			},
+		},
+		{
+			name:   "maxvalue",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=day)' partition by range columns (d) (partition p0 values less than ('2024-01-01'), partition pmax values less than maxvalue)",
+			isErr:  true,
+		},
+		{
+			name:   "hour with to_days",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=hour)' partition by range (to_days(d)) (partition p0 values less than (739341))",
+			isErr:  true,
+		},
+		{
+			name:   "integer range",
+			create: "create table t (id int primary key) comment 'vitess_partition_policy(interval=day)' partition by range (id) (partition p0 values less than (10))",
+			isErr:  true,
+		},
+		{
+			name:   "hash",
+			create: "create table t (id int primary key) comment 'vitess_partition_policy(interval=day)' partition by hash (id) partitions 4",
+			isErr:  true,
+		},
+		{
+			name:   "name collision",
+			create: "create table t (id int, d date, primary key (id, d)) comment 'vitess_partition_policy(interval=day)' partition by range columns (d) (partition p20240110 values less than ('2024-01-10'))",
+			isErr:  true,
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			stmt, err := sqlparser.NewTestParser().ParseStrictDDL(tcase.create)
+			require.NoError(t, err)
+			createTable, ok := stmt.(*sqlparser.CreateTable)
+			require.True(t, ok)
+
+			alters, err := PartitionPolicyAlters(createTable, now)
+			if tcase.isErr {
+				assert.ErrorAs(t, err, new(*UnsupportedPartitionPolicyTableError))
+				return
+			}
+			require.NoError(t, err)
+			var got []string
+			for _, alter := range alters {
+				got = append(got, sqlparser.CanonicalString(alter))
+				rotates, err := AlterTableRotatesRangePartition(createTable, alter)
+				require.NoError(t, err)
+				assert.True(t, rotates)
+			}
+			assert.Equal(t, tcase.expect, got)
+		})
+	}
+}
@@ -394,6 +394,32 @@ func IsConstant(node Expr) bool {
 	return err == nil
 }
 
+// NonDeterministicFuncs are the functions whose result can change between
+// two evaluations with the same arguments, because it depends on when, where
+// or by whom the function is evaluated.
+var NonDeterministicFuncs = map[string]bool{
+	"connection_id":  true,
+	"current_role":   true,
+	"current_user":   true,
+	"database":       true,
+	"found_rows":     true,
+	"get_lock":       true,
+	"is_free_lock":   true,
+	"is_used_lock":   true,
+	"last_insert_id": true,
+	"rand":           true,
+	"random_bytes":   true,
+	"release_lock":   true,
+	"row_count":      true,
+	"schema":         true,
+	"session_user":   true,
+	"sleep":          true,
+	"system_user":    true,
+	"user":           true,
+	"uuid":           true,
+	"uuid_short":     true,
+}
+
 // IsValue returns true if the Expr is a string, integral or value arg.
 // NULL is not considered to be a value.
 func IsValue(node Expr) bool {
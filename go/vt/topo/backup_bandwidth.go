@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// backupBandwidthBucketMaxAttempts is the number of times the token bucket of a
+	// cell is read and written by UpdateBackupBandwidthBucket, when it is updated
+	// concurrently.
+	backupBandwidthBucketMaxAttempts = 10
+
+	// backupBandwidthBucketRetryDelay is the maximum delay before the token bucket
+	// of a cell is updated again, after a concurrent update.
+	backupBandwidthBucketRetryDelay = 20 * time.Millisecond
+)
+
+// GetBackupBandwidthBucket returns the token bucket which limits the bandwidth
+// of the backups and restores of the tablets of a cell. It returns a NoNode
+// error if no tablet of the cell took tokens from the bucket yet.
+func (ts *Server) GetBackupBandwidthBucket(ctx context.Context, cell string) (*topodatapb.BackupBandwidthBucket, error) {
+	conn, err := ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := conn.Get(ctx, BackupBandwidthFile)
+	if err != nil {
+		return nil, err
+	}
+	bucket := &topodatapb.BackupBandwidthBucket{}
+	if err := bucket.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrap(err, "bad BackupBandwidthBucket data")
+	}
+	return bucket, nil
+}
+
+// UpdateBackupBandwidthBucket updates the token bucket which limits the
+// bandwidth of the backups and restores of the tablets of a cell. The update
+// function is called with an empty bucket if it doesn't exist yet, and is
+// called again after a random delay if the bucket was updated concurrently.
+// After backupBandwidthBucketMaxAttempts concurrent updates, the BadVersion or
+// NodeExists error of the last one is returned.
+func (ts *Server) UpdateBackupBandwidthBucket(ctx context.Context, cell string, update func(*topodatapb.BackupBandwidthBucket) error) error {
+	conn, err := ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(rand.N(backupBandwidthBucketRetryDelay)):
+			}
+		}
+
+		data, version, err := conn.Get(ctx, BackupBandwidthFile)
+		bucket := &topodatapb.BackupBandwidthBucket{}
+		switch {
+		case IsErrType(err, NoNode):
+			// Empty node, version is nil
+		case err == nil:
+			if err = bucket.UnmarshalVT(data); err != nil {
+				return vterrors.Wrap(err, "bad BackupBandwidthBucket data")
+			}
+		default:
+			return err
+		}
+
+		err = update(bucket)
+		switch {
+		case IsErrType(err, NoUpdateNeeded):
+			return nil
+		case err == nil:
+			// keep going
+		default:
+			return err
+		}
+
+		data, err = bucket.MarshalVT()
+		if err != nil {
+			return err
+		}
+		if version == nil {
+			_, err = conn.Create(ctx, BackupBandwidthFile, data)
+			if IsErrType(err, NodeExists) && attempt < backupBandwidthBucketMaxAttempts {
+				// The bucket was created by another tablet, try again.
+				continue
+			}
+			return err
+		}
+		_, err = conn.Update(ctx, BackupBandwidthFile, data, version)
+		if IsErrType(err, BadVersion) && attempt < backupBandwidthBucketMaxAttempts {
+			// The bucket was updated by another tablet, try again.
+			continue
+		}
+		return err
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestUpdateBackupBandwidthBucket(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+
+	_, err := ts.GetBackupBandwidthBucket(ctx, "zone1")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+
+	// Concurrent updates are retried, so that none of them is lost.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			assert.NoError(t, ts.UpdateBackupBandwidthBucket(ctx, "zone1", func(bucket *topodatapb.BackupBandwidthBucket) error {
+				bucket.Tokens++
+				return nil
+			}))
+		})
+	}
+	wg.Wait()
+	bucket, err := ts.GetBackupBandwidthBucket(ctx, "zone1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, bucket.Tokens)
+
+	require.NoError(t, ts.UpdateBackupBandwidthBucket(ctx, "zone1", func(bucket *topodatapb.BackupBandwidthBucket) error {
+		return topo.NewError(topo.NoUpdateNeeded, "zone1")
+	}))
+	bucket, err = ts.GetBackupBandwidthBucket(ctx, "zone1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, bucket.Tokens)
+
+	// The update fails once the bucket was updated concurrently too many times.
+	attempts := 0
+	err = ts.UpdateBackupBandwidthBucket(ctx, "zone1", func(bucket *topodatapb.BackupBandwidthBucket) error {
+		attempts++
+		require.NoError(t, ts.UpdateBackupBandwidthBucket(ctx, "zone1", func(bucket *topodatapb.BackupBandwidthBucket) error {
+			bucket.Tokens++
+			return nil
+		}))
+		bucket.Tokens = 0
+		return nil
+	})
+	assert.True(t, topo.IsErrType(err, topo.BadVersion), "%v", err)
+	assert.Equal(t, 10, attempts)
+	bucket, err = ts.GetBackupBandwidthBucket(ctx, "zone1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 20, bucket.Tokens)
+
+	// The bucket of each cell is separate.
+	_, err = ts.GetBackupBandwidthBucket(ctx, "zone2")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+}
@@ -86,6 +86,7 @@ const (
 	ShardRoutingRulesFile  = "ShardRoutingRules"
 	CommonRoutingRulesFile = "Rules"
 	MirrorRulesFile        = "MirrorRules"
+	BackupBandwidthFile    = "BackupBandwidth"
 )
 
 // Path for all object types.
@@ -100,6 +101,7 @@ const (
 	RoutingRulesPath         = "routing_rules"
 	KeyspaceRoutingRulesPath = "keyspace"
 	NamedLocksPath           = "internal/named_locks"
+	VStreamSubscriptionsPath = "vstream_subscriptions"
 )
 
 // Factory is a factory interface to create Conn objects.
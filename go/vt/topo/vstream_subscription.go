@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// VStream subscriptions are named VStreams whose position is kept by vtgate.
+// The position of a subscription is the last VGtid that was acknowledged by
+// its client, and is stored in the global topo so that the streams of the
+// subscription can resume from it on any vtgate.
+
+// GetVStreamSubscriptionPath returns the node path of a VStream subscription.
+func GetVStreamSubscriptionPath(name string) string {
+	return path.Join(VStreamSubscriptionsPath, name)
+}
+
+// GetVStreamSubscription returns the acknowledged position of the named
+// VStream subscription. It returns a NoNode error if the subscription
+// doesn't exist.
+func (ts *Server) GetVStreamSubscription(ctx context.Context, name string) (*binlogdatapb.VGtid, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, _, err := ts.globalCell.Get(ctx, GetVStreamSubscriptionPath(name))
+	if err != nil {
+		return nil, err
+	}
+	vgtid := &binlogdatapb.VGtid{}
+	if err := vgtid.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrapf(err, "bad vstream subscription data for %s", name)
+	}
+	return vgtid, nil
+}
+
+// SaveVStreamSubscription stores the acknowledged position of the named
+// VStream subscription, creating the subscription if it doesn't exist.
+func (ts *Server) SaveVStreamSubscription(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := validateObjectName(name); err != nil {
+		return err
+	}
+	data, err := vgtid.MarshalVT()
+	if err != nil {
+		return err
+	}
+	// A nil version creates the node if it does not exist.
+	_, err = ts.globalCell.Update(ctx, GetVStreamSubscriptionPath(name), data, nil)
+	return err
+}
+
+// DeleteVStreamSubscription deletes the named VStream subscription.
+func (ts *Server) DeleteVStreamSubscription(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ts.globalCell.Delete(ctx, GetVStreamSubscriptionPath(name), nil)
+}
+
+// GetVStreamSubscriptionNames returns the names of all the VStream
+// subscriptions.
+func (ts *Server) GetVStreamSubscriptionNames(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	children, err := ts.globalCell.ListDir(ctx, VStreamSubscriptionsPath, false /*full*/)
+	switch {
+	case err == nil:
+		return DirEntriesToStringArray(children), nil
+	case IsErrType(err, NoNode):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func TestVStreamSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	names, err := ts.GetVStreamSubscriptionNames(ctx)
+	require.NoError(t, err)
+	require.Empty(t, names)
+	_, err = ts.GetVStreamSubscription(ctx, "orders")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{
+		Keyspace: "ks",
+		Shard:    "-80",
+		Gtid:     "MySQL56/00000000-0000-0000-0000-000000000001:1-10",
+		TablePKs: []*binlogdatapb.TableLastPK{{TableName: "t1"}},
+	}}}
+	require.NoError(t, ts.SaveVStreamSubscription(ctx, "orders", vgtid))
+	got, err := ts.GetVStreamSubscription(ctx, "orders")
+	require.NoError(t, err)
+	utils.MustMatch(t, vgtid, got)
+
+	vgtid.ShardGtids[0].Gtid = "MySQL56/00000000-0000-0000-0000-000000000001:1-20"
+	vgtid.ShardGtids[0].TablePKs = nil
+	require.NoError(t, ts.SaveVStreamSubscription(ctx, "orders", vgtid))
+	got, err = ts.GetVStreamSubscription(ctx, "orders")
+	require.NoError(t, err)
+	utils.MustMatch(t, vgtid, got)
+
+	names, err = ts.GetVStreamSubscriptionNames(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"orders"}, names)
+
+	require.ErrorContains(t, ts.SaveVStreamSubscription(ctx, "a/b", vgtid), "invalid character / in name a/b")
+
+	require.NoError(t, ts.DeleteVStreamSubscription(ctx, "orders"))
+	_, err = ts.GetVStreamSubscription(ctx, "orders")
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+}
@@ -178,6 +178,18 @@ func (f *fakeVTGateService) VStream(ctx context.Context, tabletType topodatapb.T
 	return nil
 }
 
+func (f *fakeVTGateService) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	return nil
+}
+
+func (f *fakeVTGateService) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	return nil, nil
+}
+
+func (f *fakeVTGateService) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	return nil
+}
+
 // ExecuteMulti is part of the VTGateService interface
 func (f *fakeVTGateService) ExecuteMulti(ctx context.Context, mysqlCtx vtgateservice.MySQLConnection, session *vtgatepb.Session, sqlString string) (newSession *vtgatepb.Session, qrs []*sqltypes.Result, err error) {
 	queries, err := sqlparser.NewTestParser().SplitStatementToPieces(sqlString)
@@ -1054,6 +1054,14 @@ func (itmc *internalTabletManagerClient) RestoreFromBackup(context.Context, *top
 	return nil, errors.New("not implemented in vtcombo")
 }
 
+func (itmc *internalTabletManagerClient) VerifyBackup(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	return nil, errors.New("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) CloneFrom(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.CloneFromRequest) (logutil.EventStream, error) {
+	return nil, errors.New("not implemented in vtcombo")
+}
+
 func (itmc *internalTabletManagerClient) CheckThrottler(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
 	return nil, errors.New("not implemented in vtcombo")
 }
@@ -155,6 +155,15 @@ func (client *gRPCVtctldClient) CleanupSchemaMigration(ctx context.Context, in *
 	return client.c.CleanupSchemaMigration(ctx, in, opts...)
 }
 
+// CloneTablet is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) CloneTablet(ctx context.Context, in *vtctldatapb.CloneTabletRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_CloneTabletClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.CloneTablet(ctx, in, opts...)
+}
+
 // CompleteSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CompleteSchemaMigration(ctx context.Context, in *vtctldatapb.CompleteSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -173,6 +182,15 @@ func (client *gRPCVtctldClient) ConcludeTransaction(ctx context.Context, in *vtc
 	return client.c.ConcludeTransaction(ctx, in, opts...)
 }
 
+// CopyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) CopyBackup(ctx context.Context, in *vtctldatapb.CopyBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.CopyBackupResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.CopyBackup(ctx, in, opts...)
+}
+
 // CopySchemaShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CopySchemaShard(ctx context.Context, in *vtctldatapb.CopySchemaShardRequest, opts ...grpc.CallOption) (*vtctldatapb.CopySchemaShardResponse, error) {
 	if client.c == nil {
@@ -722,6 +740,15 @@ func (client *gRPCVtctldClient) PlannedReparentShard(ctx context.Context, in *vt
 	return client.c.PlannedReparentShard(ctx, in, opts...)
 }
 
+// PruneBackups is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) PruneBackups(ctx context.Context, in *vtctldatapb.PruneBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.PruneBackupsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.PruneBackups(ctx, in, opts...)
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RebuildKeyspaceGraph(ctx context.Context, in *vtctldatapb.RebuildKeyspaceGraphRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildKeyspaceGraphResponse, error) {
 	if client.c == nil {
@@ -857,6 +884,24 @@ func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldat
 	return client.c.RunHealthCheck(ctx, in, opts...)
 }
 
+// SetCellBackupBandwidthLimit is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetCellBackupBandwidthLimit(ctx context.Context, in *vtctldatapb.SetCellBackupBandwidthLimitRequest, opts ...grpc.CallOption) (*vtctldatapb.SetCellBackupBandwidthLimitResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetCellBackupBandwidthLimit(ctx, in, opts...)
+}
+
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetKeyspaceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetKeyspaceBackupRetentionPolicy(ctx, in, opts...)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	if client.c == nil {
@@ -1037,6 +1082,15 @@ func (client *gRPCVtctldClient) VDiffDelete(ctx context.Context, in *vtctldatapb
 	return client.c.VDiffDelete(ctx, in, opts...)
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) VDiffRepair(ctx context.Context, in *vtctldatapb.VDiffRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffRepairResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.VDiffRepair(ctx, in, opts...)
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) VDiffResume(ctx context.Context, in *vtctldatapb.VDiffResumeRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffResumeResponse, error) {
 	if client.c == nil {
@@ -1145,6 +1199,15 @@ func (client *gRPCVtctldClient) WorkflowAddTables(ctx context.Context, in *vtctl
 	return client.c.WorkflowAddTables(ctx, in, opts...)
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) VerifyBackup(ctx context.Context, in *vtctldatapb.VerifyBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.VerifyBackupResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.VerifyBackup(ctx, in, opts...)
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) WorkflowDelete(ctx context.Context, in *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	if client.c == nil {
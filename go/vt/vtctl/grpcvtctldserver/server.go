@@ -44,6 +44,7 @@ import (
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/dtids"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/key"
@@ -64,6 +65,7 @@ import (
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -88,11 +90,21 @@ const (
 	// DefaultWaitReplicasTimeout is the default value for waitReplicasTimeout, which is used when calling method ApplySchema.
 	DefaultWaitReplicasTimeout = 10 * time.Second
 
+	// DefaultCloneDonorWaitTimeout is the default time CloneTablet waits for a
+	// healthy donor to be picked.
+	DefaultCloneDonorWaitTimeout = time.Minute
+
 	// maxBackupLimit is a safety cap on the number of backups that can be requested
 	// at once, to avoid excessive memory allocation from untrusted input.
 	maxBackupLimit = 10000
 )
 
+// defaultCloneDonorTabletTypes are the tablet types CloneTablet picks a donor
+// from when the request does not name any. They do not serve queries, so that
+// the load of the clone on the donor does not slow down the queries of the shard.
+// Serving tablets are only picked when their types are requested.
+var defaultCloneDonorTabletTypes = []topodatapb.TabletType{topodatapb.TabletType_SPARE, topodatapb.TabletType_BACKUP, topodatapb.TabletType_DRAINED}
+
 // VtctldServer implements the Vtctld RPC service protocol.
 type VtctldServer struct {
 	vtctlservicepb.UnimplementedVtctldServer
@@ -859,6 +871,124 @@ func (s *VtctldServer) ForceCutOverSchemaMigration(ctx context.Context, req *vtc
 	return resp, nil
 }
 
+// CloneTablet is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CloneTablet(req *vtctldatapb.CloneTabletRequest, stream vtctlservicepb.Vtctld_CloneTabletServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.CloneTablet")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("to_alias", topoproto.TabletAliasString(req.ToAlias))
+	span.Annotate("from_alias", topoproto.TabletAliasString(req.FromAlias))
+
+	if req.ToAlias == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the alias of the tablet to clone into is required")
+		return err
+	}
+
+	ti, err := s.ts.GetTablet(ctx, req.ToAlias)
+	if err != nil {
+		return err
+	}
+	if ti.Type == topodatapb.TabletType_PRIMARY {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot clone into primary tablet %s", topoproto.TabletAliasString(ti.Alias))
+		return err
+	}
+
+	span.Annotate("keyspace", ti.Keyspace)
+	span.Annotate("shard", ti.Shard)
+
+	donor, err := s.pickCloneDonor(ctx, ti.Tablet, req)
+	if err != nil {
+		return err
+	}
+
+	span.Annotate("donor_alias", topoproto.TabletAliasString(donor.Alias))
+
+	logStream, err := s.tmc.CloneFrom(ctx, ti.Tablet, &tabletmanagerdatapb.CloneFromRequest{DonorAlias: donor.Alias})
+	if err != nil {
+		return err
+	}
+
+	logger := logutil.NewConsoleLogger()
+	for {
+		var event *logutilpb.Event
+		event, err = logStream.Recv()
+		switch err {
+		case nil:
+			logutil.LogEvent(logger, event)
+			resp := &vtctldatapb.CloneTabletResponse{
+				TabletAlias: ti.Alias,
+				DonorAlias:  donor.Alias,
+				Keyspace:    ti.Keyspace,
+				Shard:       ti.Shard,
+				Event:       event,
+			}
+			if err := stream.Send(resp); err != nil {
+				logger.Errorf("failed to send stream response %+v: %v", resp, err)
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// pickCloneDonor returns the donor named by the request, or else picks a healthy
+// tablet in the shard of the recipient. As MySQL CLONE talks to the mysqld of the
+// donor directly, tablets whose query service is not serving are candidates too.
+func (s *VtctldServer) pickCloneDonor(ctx context.Context, recipient *topodatapb.Tablet, req *vtctldatapb.CloneTabletRequest) (*topodatapb.Tablet, error) {
+	if req.FromAlias != nil {
+		if topoproto.TabletAliasEqual(req.FromAlias, recipient.Alias) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tablet %s cannot clone from itself", topoproto.TabletAliasString(recipient.Alias))
+		}
+		donor, err := s.ts.GetTablet(ctx, req.FromAlias)
+		if err != nil {
+			return nil, err
+		}
+		if donor.Keyspace != recipient.Keyspace || donor.Shard != recipient.Shard {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "donor tablet %s is in shard %s/%s, not in shard %s/%s of tablet %s",
+				topoproto.TabletAliasString(donor.Alias), donor.Keyspace, donor.Shard, recipient.Keyspace, recipient.Shard, topoproto.TabletAliasString(recipient.Alias))
+		}
+		return donor.Tablet, nil
+	}
+
+	waitTimeout, ok, err := protoutil.DurationFromProto(req.WaitForDonorTimeout)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "unable to parse WaitForDonorTimeout into a valid duration")
+	} else if !ok {
+		waitTimeout = DefaultCloneDonorWaitTimeout
+	}
+
+	cells := req.Cells
+	if len(cells) == 0 {
+		cells = []string{recipient.Alias.Cell}
+	}
+	tabletTypes := req.TabletTypes
+	hint := ""
+	if len(tabletTypes) == 0 {
+		tabletTypes = defaultCloneDonorTabletTypes
+		hint = " (serving tablets are only picked when their types are requested)"
+	}
+
+	tp, err := discovery.NewTabletPicker(ctx, s.ts, cells, recipient.Alias.Cell, recipient.Keyspace, recipient.Shard,
+		discovery.BuildTabletTypesString(tabletTypes, req.TabletSelectionPreference),
+		discovery.TabletPickerOptions{IncludeNonServingTablets: true}, recipient.Alias)
+	if err != nil {
+		return nil, err
+	}
+
+	pickCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+	donor, err := tp.PickForStreaming(pickCtx)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "no healthy donor of types %v found for shard %s/%s within %v%s",
+			tabletTypes, recipient.Keyspace, recipient.Shard, waitTimeout, hint)
+	}
+	return donor, nil
+}
+
 // CompleteSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest) (resp *vtctldatapb.CompleteSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CompleteSchemaMigration")
@@ -891,6 +1021,87 @@ func (s *VtctldServer) CompleteSchemaMigration(ctx context.Context, req *vtctlda
 	return resp, nil
 }
 
+// CopyBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CopyBackup(ctx context.Context, req *vtctldatapb.CopyBackupRequest) (resp *vtctldatapb.CopyBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.CopyBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	bucket := fmt.Sprintf("%v/%v", req.Keyspace, req.Shard)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("bucket", bucket)
+	span.Annotate("backup_name", req.Name)
+	span.Annotate("from_storage", req.FromStorage)
+	span.Annotate("to_storage", req.ToStorage)
+	span.Annotate("remove_source", req.RemoveSource)
+
+	if req.FromStorage == req.ToStorage {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot copy backup %v/%v to the backup storage it is in", bucket, req.Name)
+		return nil, err
+	}
+	from, err := getBackupStorageByName(req.FromStorage)
+	if err != nil {
+		return nil, err
+	}
+	defer from.Close()
+	to, err := getBackupStorageByName(req.ToStorage)
+	if err != nil {
+		return nil, err
+	}
+	defer to.Close()
+
+	files, err := mysqlctl.CopyBackup(ctx, mysqlctl.CopyBackupParams{
+		Logger:     logutil.NewConsoleLogger(),
+		Keyspace:   req.Keyspace,
+		Shard:      req.Shard,
+		BackupName: req.Name,
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RemoveSource {
+		log.Info(fmt.Sprintf("Removing backup %v/%v from backup storage %v, which it was moved from", bucket, req.Name, req.FromStorage))
+		if err = from.RemoveBackup(ctx, bucket, req.Name); err != nil {
+			err = vterrors.Wrapf(err, "failed to remove backup %v/%v from backup storage %v", bucket, req.Name, req.FromStorage)
+			return nil, err
+		}
+		// Remove the chunks which were only referenced by this backup, if it was chunked.
+		if removed, err := mysqlctl.RemoveUnreferencedBackupChunks(ctx, from, bucket, false); err != nil {
+			log.Warn(fmt.Sprintf("Failed to remove the unreferenced backup chunks of %v: %v", bucket, err))
+		} else if len(removed) > 0 {
+			log.Info(fmt.Sprintf("Removed %d unreferenced backup chunks of %v", len(removed), bucket))
+		}
+	}
+
+	resp = &vtctldatapb.CopyBackupResponse{
+		Files: make([]*vtctldatapb.CopyBackupResponse_File, 0, len(files)),
+	}
+	for _, file := range files {
+		resp.Files = append(resp.Files, &vtctldatapb.CopyBackupResponse_File{
+			Name: file.Name,
+			Hash: file.Hash,
+			Size: file.Size,
+		})
+	}
+	return resp, nil
+}
+
+// getBackupStorageByName returns the registered backup storage implementation
+// with the given name.
+func getBackupStorageByName(name string) (backupstorage.BackupStorage, error) {
+	bs, ok := backupstorage.BackupStorageMap[name]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no registered backup storage implementation named %q", name)
+	}
+	return bs, nil
+}
+
 // CopySchemaShard is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CopySchemaShard(ctx context.Context, req *vtctldatapb.CopySchemaShardRequest) (resp *vtctldatapb.CopySchemaShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CompleteSchemaMigration")
@@ -3351,6 +3562,91 @@ func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatap
 	return resp, err
 }
 
+// PruneBackups is part of the vtctldservicepb.VtctldServer interface.
+func (s *VtctldServer) PruneBackups(ctx context.Context, req *vtctldatapb.PruneBackupsRequest) (resp *vtctldatapb.PruneBackupsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PruneBackups")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shards", strings.Join(req.Shards, ","))
+	span.Annotate("dry_run", req.DryRun)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if mysqlctl.BackupRetentionPolicyIsEmpty(ki.BackupRetentionPolicy) {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %v has no backup retention policy", req.Keyspace)
+		return nil, err
+	}
+
+	shards := req.Shards
+	if len(shards) == 0 {
+		shards, err = s.ts.GetShardNames(ctx, req.Keyspace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	resp = &vtctldatapb.PruneBackupsResponse{}
+	now := time.Now().UTC()
+	for _, shard := range shards {
+		bucket := filepath.Join(req.Keyspace, shard)
+		bhs, err := bs.ListBackups(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		pruned, err := mysqlctl.FindBackupsToPrune(ctx, bucket, bhs, ki.BackupRetentionPolicy, now)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bh := range pruned {
+			if !req.DryRun {
+				log.Info(fmt.Sprintf("Removing backup %v/%v which is not kept by the backup retention policy", bucket, bh.Name()))
+				if err := bs.RemoveBackup(ctx, bucket, bh.Name()); err != nil {
+					return nil, vterrors.Wrapf(err, "failed to remove backup %v/%v", bucket, bh.Name())
+				}
+			}
+
+			bi := mysqlctlproto.BackupHandleToProto(bh)
+			bi.Keyspace = req.Keyspace
+			bi.Shard = shard
+			resp.PrunedBackups = append(resp.PrunedBackups, bi)
+		}
+
+		// Replicate the backups whose replication was lost, if the backup storage
+		// replicates them to other backup storages.
+		if r, ok := bs.(backupstorage.Reconciler); ok && !req.DryRun {
+			if err := r.Reconcile(ctx, bucket); err != nil {
+				log.Warn(fmt.Sprintf("Failed to reconcile the backups of %v: %v", bucket, err))
+			}
+		}
+
+		if req.DryRun || len(pruned) == 0 {
+			continue
+		}
+
+		// Remove the chunks which were only referenced by the pruned backups, if they were chunked.
+		if removed, err := mysqlctl.RemoveUnreferencedBackupChunks(ctx, bs, bucket, false); err != nil {
+			log.Warn(fmt.Sprintf("Failed to remove the unreferenced backup chunks of %v: %v", bucket, err))
+		} else if len(removed) > 0 {
+			log.Info(fmt.Sprintf("Removed %d unreferenced backup chunks of %v", len(removed), bucket))
+		}
+	}
+
+	return resp, nil
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RebuildKeyspaceGraph(ctx context.Context, req *vtctldatapb.RebuildKeyspaceGraphRequest) (resp *vtctldatapb.RebuildKeyspaceGraphResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RebuildKeyspaceGraph")
@@ -3590,6 +3886,13 @@ func (s *VtctldServer) RemoveBackup(ctx context.Context, req *vtctldatapb.Remove
 		return nil, err
 	}
 
+	// Remove the chunks which were only referenced by this backup, if it was chunked.
+	if removed, err := mysqlctl.RemoveUnreferencedBackupChunks(ctx, bs, bucket, false); err != nil {
+		log.Warn(fmt.Sprintf("Failed to remove the unreferenced backup chunks of %v: %v", bucket, err))
+	} else if len(removed) > 0 {
+		log.Info(fmt.Sprintf("Removed %d unreferenced backup chunks of %v", len(removed), bucket))
+	}
+
 	return &vtctldatapb.RemoveBackupResponse{}, nil
 }
 
@@ -3871,6 +4174,84 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// SetCellBackupBandwidthLimit is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetCellBackupBandwidthLimit(ctx context.Context, req *vtctldatapb.SetCellBackupBandwidthLimitRequest) (resp *vtctldatapb.SetCellBackupBandwidthLimitResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetCellBackupBandwidthLimit")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("cell", req.Cell)
+	span.Annotate("limit", req.Limit)
+
+	if req.Limit < 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "limit must not be negative")
+		return nil, err
+	}
+
+	var bucket *topodatapb.BackupBandwidthBucket
+	err = s.ts.UpdateBackupBandwidthBucket(ctx, req.Cell, func(b *topodatapb.BackupBandwidthBucket) error {
+		b.Limit = req.Limit
+		bucket = b
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetCellBackupBandwidthLimitResponse{
+		Bucket: bucket,
+	}, nil
+}
+
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest) (resp *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("backup_retention_policy", req.BackupRetentionPolicy.String())
+
+	for name, d := range map[string]*vttimepb.Duration{
+		"keep_newer_than":               req.BackupRetentionPolicy.GetKeepNewerThan(),
+		"point_in_time_recovery_window": req.BackupRetentionPolicy.GetPointInTimeRecoveryWindow(),
+	} {
+		if duration, ok, derr := protoutil.DurationFromProto(d); derr != nil || (ok && duration <= 0) {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v must be a positive duration", name)
+			return nil, err
+		}
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceBackupRetentionPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.BackupRetentionPolicy = req.BackupRetentionPolicy
+	if mysqlctl.BackupRetentionPolicyIsEmpty(ki.BackupRetentionPolicy) {
+		ki.BackupRetentionPolicy = nil
+	}
+
+	err = s.ts.UpdateKeyspace(ctx, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+		Keyspace: ki.Keyspace,
+	}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -5561,6 +5942,23 @@ func (s *VtctldServer) VDiffDelete(ctx context.Context, req *vtctldatapb.VDiffDe
 	return resp, err
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VDiffRepair(ctx context.Context, req *vtctldatapb.VDiffRepairRequest) (resp *vtctldatapb.VDiffRepairResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffRepair")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("shards", req.TargetShards)
+	span.Annotate("batch_size", req.BatchSize)
+
+	resp, err = s.ws.VDiffRepair(ctx, req)
+	return resp, err
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) VDiffResume(ctx context.Context, req *vtctldatapb.VDiffResumeRequest) (resp *vtctldatapb.VDiffResumeResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffResume")
@@ -5608,6 +6006,33 @@ func (s *VtctldServer) VDiffStop(ctx context.Context, req *vtctldatapb.VDiffStop
 	return resp, err
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VerifyBackup(ctx context.Context, req *vtctldatapb.VerifyBackupRequest) (resp *vtctldatapb.VerifyBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VerifyBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("backup_name", req.BackupName)
+	span.Annotate("concurrency", req.Concurrency)
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.tmc.VerifyBackup(ctx, ti.Tablet, &tabletmanagerdatapb.VerifyBackupRequest{
+		BackupName:  req.BackupName,
+		Concurrency: req.Concurrency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.VerifyBackupResponse{Result: r.Result}, nil
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (resp *vtctldatapb.WorkflowDeleteResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.WorkflowDelete")
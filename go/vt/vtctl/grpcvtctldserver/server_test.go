@@ -46,7 +46,9 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/callerid"
 	hk "vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage/memorybackupstorage"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/topo"
@@ -2423,6 +2425,196 @@ func TestForceCutOverSchemaMigration(t *testing.T) {
 	}
 }
 
+func TestCloneTablet(t *testing.T) {
+	ctx := t.Context()
+
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_RDONLY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+			Keyspace: "other",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+	}
+
+	tests := []struct {
+		name             string
+		tablets          []*topodatapb.Tablet
+		cloneFromResults map[string]struct {
+			Donor  string
+			Events []*logutilpb.Event
+			Error  error
+		}
+		req               *vtctldatapb.CloneTabletRequest
+		expectedResponses int
+		expectedErr       string
+	}{
+		{
+			name:    "ok",
+			tablets: tablets,
+			cloneFromResults: map[string]struct {
+				Donor  string
+				Events []*logutilpb.Event
+				Error  error
+			}{
+				"zone1-0000000100": {
+					Donor:  "zone1-0000000101",
+					Events: []*logutilpb.Event{{}, {}, {}},
+				},
+			},
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				FromAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			},
+			expectedResponses: 3,
+		},
+		{
+			name:    "clone fails on the recipient",
+			tablets: tablets,
+			cloneFromResults: map[string]struct {
+				Donor  string
+				Events []*logutilpb.Event
+				Error  error
+			}{
+				"zone1-0000000100": {
+					Donor:  "zone1-0000000101",
+					Events: []*logutilpb.Event{{}},
+					Error:  errors.New("clone failed"),
+				},
+			},
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				FromAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			},
+			expectedResponses: 1,
+			expectedErr:       "clone failed",
+		},
+		{
+			name:        "no recipient",
+			tablets:     tablets,
+			req:         &vtctldatapb.CloneTabletRequest{},
+			expectedErr: "the alias of the tablet to clone into is required",
+		},
+		{
+			name:    "recipient is primary",
+			tablets: tablets,
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+				FromAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			},
+			expectedErr: "cannot clone into primary tablet zone1-0000000200",
+		},
+		{
+			name:    "clone from itself",
+			tablets: tablets,
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				FromAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			},
+			expectedErr: "cannot clone from itself",
+		},
+		{
+			name:    "donor in another shard",
+			tablets: tablets,
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				FromAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+			},
+			expectedErr: "donor tablet zone1-0000000300 is in shard other/-, not in shard ks/- of tablet zone1-0000000100",
+		},
+		{
+			name:    "no donor to pick",
+			tablets: tablets[:1],
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:             &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				WaitForDonorTimeout: protoutil.DurationToProto(10 * time.Millisecond),
+			},
+			expectedErr: "no healthy donor of types [SPARE BACKUP DRAINED] found for shard ks/-",
+		},
+		{
+			name:    "serving donors are not picked by default",
+			tablets: tablets[:2],
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:             &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				WaitForDonorTimeout: protoutil.DurationToProto(10 * time.Millisecond),
+			},
+			expectedErr: "serving tablets are only picked when their types are requested",
+		},
+		{
+			name:    "no donor of the requested types",
+			tablets: tablets[:1],
+			req: &vtctldatapb.CloneTabletRequest{
+				ToAlias:             &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				TabletTypes:         []topodatapb.TabletType{topodatapb.TabletType_RDONLY},
+				WaitForDonorTimeout: protoutil.DurationToProto(10 * time.Millisecond),
+			},
+			expectedErr: "no healthy donor of types [RDONLY] found for shard ks/- within 10ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
+			tmc := &testutil.TabletManagerClient{
+				CloneFromResults: tt.cloneFromResults,
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			client := localvtctldclient.New(vtctld)
+			stream, err := client.CloneTablet(ctx, tt.req)
+			require.NoError(t, err)
+
+			var responses []*vtctldatapb.CloneTabletResponse
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if tt.expectedErr != "" {
+					if err != nil {
+						assert.ErrorContains(t, err, tt.expectedErr)
+						break
+					}
+				} else {
+					require.NoError(t, err)
+				}
+				responses = append(responses, resp)
+			}
+			if tt.expectedErr != "" {
+				assert.Len(t, responses, tt.expectedResponses)
+				return
+			}
+			require.Len(t, responses, tt.expectedResponses)
+			for _, resp := range responses {
+				utils.MustMatch(t, tt.req.ToAlias, resp.TabletAlias)
+				utils.MustMatch(t, tt.req.FromAlias, resp.DonorAlias)
+				assert.Equal(t, "ks", resp.Keyspace)
+				assert.Equal(t, "-", resp.Shard)
+			}
+		})
+	}
+}
+
 func TestCompleteSchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -2626,6 +2818,129 @@ func TestCompleteSchemaMigration(t *testing.T) {
 	}
 }
 
+func TestCopyBackup(t *testing.T) {
+	ctx := t.Context()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	const manifest = `{"BackupMethod":"builtin","FileEntries":[{"Name":"a","Hash":"adf3f363"}]}`
+	var from, to *memorybackupstorage.MemoryBackupStorage
+	setup := func() {
+		from, to = memorybackupstorage.NewMemoryBackupStorage(), memorybackupstorage.NewMemoryBackupStorage()
+		from.WriteBackupFile("testkeyspace/-", "backup1", "0", []byte("data"))
+		from.WriteBackupFile("testkeyspace/-", "backup1", "MANIFEST", []byte(manifest))
+		backupstorage.BackupStorageMap["copy-test-from"] = from
+		backupstorage.BackupStorageMap["copy-test-to"] = to
+	}
+	defer func() {
+		delete(backupstorage.BackupStorageMap, "copy-test-from")
+		delete(backupstorage.BackupStorageMap, "copy-test-to")
+	}()
+
+	expected := &vtctldatapb.CopyBackupResponse{
+		Files: []*vtctldatapb.CopyBackupResponse_File{
+			{Name: "0", Hash: "adf3f363", Size: 4},
+			{Name: "MANIFEST", Hash: "afb18859", Size: int64(len(manifest))},
+		},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.CopyBackup(ctx, &vtctldatapb.CopyBackupRequest{
+			Keyspace:    "testkeyspace",
+			Shard:       "-",
+			Name:        "backup1",
+			FromStorage: "copy-test-from",
+			ToStorage:   "copy-test-to",
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, expected, resp)
+
+		data, ok := to.ReadBackupFile("testkeyspace/-", "backup1", "0")
+		require.True(t, ok)
+		assert.Equal(t, []byte("data"), data)
+		_, ok = from.ReadBackupFile("testkeyspace/-", "backup1", "0")
+		assert.True(t, ok)
+	})
+
+	t.Run("remove source", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.CopyBackup(ctx, &vtctldatapb.CopyBackupRequest{
+			Keyspace:     "testkeyspace",
+			Shard:        "-",
+			Name:         "backup1",
+			FromStorage:  "copy-test-from",
+			ToStorage:    "copy-test-to",
+			RemoveSource: true,
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, expected, resp)
+
+		_, ok := to.ReadBackupFile("testkeyspace/-", "backup1", "MANIFEST")
+		assert.True(t, ok)
+		_, ok = from.ReadBackupFile("testkeyspace/-", "backup1", "MANIFEST")
+		assert.False(t, ok)
+	})
+
+	t.Run("corrupted file", func(t *testing.T) {
+		setup()
+		from.WriteBackupFile("testkeyspace/-", "backup1", "0", []byte("corrupted"))
+		_, err := vtctld.CopyBackup(ctx, &vtctldatapb.CopyBackupRequest{
+			Keyspace:     "testkeyspace",
+			Shard:        "-",
+			Name:         "backup1",
+			FromStorage:  "copy-test-from",
+			ToStorage:    "copy-test-to",
+			RemoveSource: true,
+		})
+		assert.ErrorContains(t, err, "hash mismatch for 0")
+
+		// The source is kept.
+		_, ok := from.ReadBackupFile("testkeyspace/-", "backup1", "MANIFEST")
+		assert.True(t, ok)
+		_, ok = to.ReadBackupFile("testkeyspace/-", "backup1", "MANIFEST")
+		assert.False(t, ok)
+	})
+
+	t.Run("backup not found", func(t *testing.T) {
+		setup()
+		_, err := vtctld.CopyBackup(ctx, &vtctldatapb.CopyBackupRequest{
+			Keyspace:    "testkeyspace",
+			Shard:       "-",
+			Name:        "backup2",
+			FromStorage: "copy-test-from",
+			ToStorage:   "copy-test-to",
+		})
+		assert.Equal(t, vtrpc.Code_NOT_FOUND, vterrors.Code(err))
+	})
+
+	t.Run("unknown backup storage", func(t *testing.T) {
+		setup()
+		_, err := vtctld.CopyBackup(ctx, &vtctldatapb.CopyBackupRequest{
+			Keyspace:    "testkeyspace",
+			Shard:       "-",
+			Name:        "backup1",
+			FromStorage: "copy-test-from",
+			ToStorage:   "doesnotexist",
+		})
+		assert.Equal(t, vtrpc.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	})
+
+	t.Run("same backup storage", func(t *testing.T) {
+		setup()
+		_, err := vtctld.CopyBackup(ctx, &vtctldatapb.CopyBackupRequest{
+			Keyspace:    "testkeyspace",
+			Shard:       "-",
+			Name:        "backup1",
+			FromStorage: "copy-test-from",
+			ToStorage:   "copy-test-from",
+		})
+		assert.Equal(t, vtrpc.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	})
+}
+
 func TestCreateKeyspace(t *testing.T) {
 	t.Parallel()
 
@@ -9051,6 +9366,96 @@ func TestPlannedReparentShard(t *testing.T) {
 	}
 }
 
+func TestPruneBackups(t *testing.T) {
+	ctx := t.Context()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddKeyspaces(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name: "testkeyspace",
+		Keyspace: &topodatapb.Keyspace{
+			BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepFullBackups: 1},
+		},
+	}, &vtctldatapb.Keyspace{
+		Name:     "nopolicy",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "-"})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	now := time.Now().UTC()
+	name := func(days int) string {
+		return now.Add(-time.Duration(days)*24*time.Hour).Format(mysqlctl.BackupTimestampFormat) + ".zone1-0000000100"
+	}
+	setup := func() {
+		testutil.BackupStorage.Backups = map[string][]string{
+			"testkeyspace/-": {name(5), name(4), name(3), name(1)},
+		}
+		testutil.BackupStorage.Manifests = map[string]string{
+			path.Join("testkeyspace/-", name(5)): `{}`,
+			path.Join("testkeyspace/-", name(4)): fmt.Sprintf(`{"Incremental": true, "FromBackup": %q}`, name(5)),
+			path.Join("testkeyspace/-", name(3)): `{}`,
+			path.Join("testkeyspace/-", name(1)): fmt.Sprintf(`{"Incremental": true, "FromBackup": %q}`, name(3)),
+		}
+		testutil.BackupStorage.Reconciled = nil
+	}
+	defer func() {
+		testutil.BackupStorage.Manifests = nil
+		testutil.BackupStorage.Reconciled = nil
+	}()
+
+	pruned := func(resp *vtctldatapb.PruneBackupsResponse) []string {
+		var names []string
+		for _, bi := range resp.PrunedBackups {
+			assert.Equal(t, "testkeyspace", bi.Keyspace)
+			assert.Equal(t, "-", bi.Shard)
+			names = append(names, bi.Name)
+		}
+		return names
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{name(5), name(4)}, pruned(resp))
+		assert.Len(t, testutil.BackupStorage.Backups["testkeyspace/-"], 4)
+		assert.Empty(t, testutil.BackupStorage.Reconciled)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shards:   []string{"-"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{name(5), name(4)}, pruned(resp))
+		assert.Equal(t, []string{name(3), name(1)}, testutil.BackupStorage.Backups["testkeyspace/-"])
+		// The backups whose replication was lost are replicated again.
+		assert.Equal(t, []string{"testkeyspace/-"}, testutil.BackupStorage.Reconciled)
+	})
+
+	t.Run("no backup retention policy", func(t *testing.T) {
+		setup()
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "nopolicy",
+		})
+		assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	})
+
+	t.Run("keyspace not found", func(t *testing.T) {
+		setup()
+		_, err := vtctld.PruneBackups(ctx, &vtctldatapb.PruneBackupsRequest{
+			Keyspace: "notfound",
+		})
+		assert.Error(t, err)
+	})
+}
+
 func TestRebuildKeyspaceGraph(t *testing.T) {
 	t.Parallel()
 
@@ -11367,6 +11772,139 @@ func TestRunHealthCheck(t *testing.T) {
 	}
 }
 
+func TestSetCellBackupBandwidthLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	resp, err := vtctld.SetCellBackupBandwidthLimit(ctx, &vtctldatapb.SetCellBackupBandwidthLimitRequest{Cell: "zone1", Limit: 1024})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.BackupBandwidthBucket{Limit: 1024}, resp.Bucket)
+
+	// The tokens of the bucket are kept.
+	require.NoError(t, ts.UpdateBackupBandwidthBucket(ctx, "zone1", func(bucket *topodatapb.BackupBandwidthBucket) error {
+		bucket.Tokens = 512
+		return nil
+	}))
+	resp, err = vtctld.SetCellBackupBandwidthLimit(ctx, &vtctldatapb.SetCellBackupBandwidthLimitRequest{Cell: "zone1"})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.BackupBandwidthBucket{Tokens: 512}, resp.Bucket)
+	bucket, err := ts.GetBackupBandwidthBucket(ctx, "zone1")
+	require.NoError(t, err)
+	utils.MustMatch(t, resp.Bucket, bucket)
+
+	_, err = vtctld.SetCellBackupBandwidthLimit(ctx, &vtctldatapb.SetCellBackupBandwidthLimitRequest{Cell: "zone1", Limit: -1})
+	assert.EqualError(t, err, "limit must not be negative")
+
+	_, err = vtctld.SetCellBackupBandwidthLimit(ctx, &vtctldatapb.SetCellBackupBandwidthLimitRequest{Cell: "zone2", Limit: 1024})
+	assert.Error(t, err)
+}
+
+func TestSetKeyspaceBackupRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+					KeepFullBackups:           7,
+					PointInTimeRecoveryWindow: protoutil.DurationToProto(72 * time.Hour),
+				},
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+						KeepFullBackups:           7,
+						PointInTimeRecoveryWindow: protoutil.DurationToProto(72 * time.Hour),
+					},
+				},
+			},
+		},
+		{
+			name: "empty policy removes the policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepFullBackups: 7},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace:              "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{},
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+		{
+			name: "invalid duration",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+					KeepNewerThan: protoutil.DurationToProto(-time.Hour),
+				},
+			},
+			expectedErr: "keep_newer_than must be a positive duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceBackupRetentionPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -14994,6 +15532,138 @@ func TestValidateShard(t *testing.T) {
 	}
 }
 
+func TestVerifyBackup(t *testing.T) {
+	t.Parallel()
+
+	result := &mysqlctlpb.VerifyBackupResult{
+		BackupName: "2026-10-18.000000.zone1-0000000100",
+		Directory:  "ks/-",
+		Tables: []*mysqlctlpb.VerifyBackupTableResult{
+			{Schema: "vt_ks", Name: "t1", RowCount: 10, EstimatedRowCount: 10, Ok: true},
+		},
+		Ok: true,
+	}
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.VerifyBackupRequest
+		expected  *vtctldatapb.VerifyBackupResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Response *tabletmanagerdatapb.VerifyBackupResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.VerifyBackupResponse{Result: result},
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				BackupName: "2026-10-18.000000.zone1-0000000100",
+			},
+			expected: &vtctldatapb.VerifyBackupResponse{Result: result},
+		},
+		{
+			name: "no tablet",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  404,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Response *tabletmanagerdatapb.VerifyBackupResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.VerifyBackupResponse{Result: result},
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				BackupName: "2026-10-18.000000.zone1-0000000100",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tmc call failed",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Response *tabletmanagerdatapb.VerifyBackupResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				BackupName: "2026-10-18.000000.zone1-0000000100",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.VerifyBackup(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	_flag.ParseFlagsForTest()
 	os.Exit(m.Run())
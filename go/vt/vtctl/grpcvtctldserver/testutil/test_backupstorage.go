@@ -19,7 +19,10 @@ package testutil
 import (
 	"context"
 	"fmt"
+	"io"
+	"path"
 	"sort"
+	"strings"
 
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 )
@@ -30,8 +33,13 @@ type backupStorage struct {
 	// Backups is a mapping of directory to list of backup names stored in that
 	// directory.
 	Backups map[string][]string
+	// Manifests is a mapping of "directory/name" to the MANIFEST file of that
+	// backup. Backups without a MANIFEST are incomplete.
+	Manifests map[string]string
 	// ListBackupsError is returned from ListBackups when it is non-nil.
 	ListBackupsError error
+	// Reconciled are the directories passed to Reconcile.
+	Reconciled []string
 }
 
 // ListBackups is part of the backupstorage.BackupStorage interface.
@@ -45,7 +53,7 @@ func (bs *backupStorage) ListBackups(ctx context.Context, dir string) ([]backups
 	for k, v := range bs.Backups {
 		if k == dir {
 			for _, name := range v {
-				handles = append(handles, &backupHandle{directory: k, name: name})
+				handles = append(handles, &backupHandle{directory: k, name: name, manifest: bs.Manifests[path.Join(k, name)]})
 			}
 		}
 	}
@@ -81,16 +89,33 @@ func (bs *backupStorage) RemoveBackup(ctx context.Context, dir string, name stri
 // Close is part of the backupstorage.BackupStorage interface.
 func (bs *backupStorage) Close() error { return nil }
 
+// Reconcile is part of the backupstorage.Reconciler interface.
+func (bs *backupStorage) Reconcile(ctx context.Context, dir string) error {
+	bs.Reconciled = append(bs.Reconciled, dir)
+	return nil
+}
+
 // backupHandle implements a subset of the backupstorage.backupHandle interface.
 type backupHandle struct {
 	backupstorage.BackupHandle
 
 	directory string
 	name      string
+	manifest  string
 }
 
 func (bh *backupHandle) Directory() string { return bh.directory }
 func (bh *backupHandle) Name() string      { return bh.name }
+func (bh *backupHandle) Error() error      { return nil }
+
+// ReadFile is part of the backupstorage.BackupHandle interface. Only the
+// MANIFEST file can be read.
+func (bh *backupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	if filename != "MANIFEST" || bh.manifest == "" {
+		return nil, fmt.Errorf("no file %s in backup %s/%s", filename, bh.directory, bh.name)
+	}
+	return io.NopCloser(strings.NewReader(bh.manifest)), nil
+}
 
 // handlesByName implements the sort interface for backup handles by Name().
 type handlesByName []backupstorage.BackupHandle
@@ -199,6 +199,13 @@ type TabletManagerClient struct {
 	ChangeTagsDelays       map[string]time.Duration
 	ChangeTabletTypeResult map[string]error
 	ChangeTabletTypeDelays map[string]time.Duration
+	// keyed by tablet alias of the recipient.
+	CloneFromResults map[string]struct {
+		// Donor is the alias of the donor the request is expected to name.
+		Donor  string
+		Events []*logutilpb.Event
+		Error  error
+	}
 	// keyed by tablet alias.
 	DemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias.
@@ -374,6 +381,11 @@ type TabletManagerClient struct {
 	UndoDemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias
 	UndoDemotePrimaryResults map[string]error
+	// keyed by tablet alias
+	VerifyBackupResults map[string]struct {
+		Response *tabletmanagerdatapb.VerifyBackupResponse
+		Error    error
+	}
 	// tablet alias => duration
 	VReplicationExecDelays map[string]time.Duration
 	// tablet alias => query string => result
@@ -736,6 +748,35 @@ func (fake *TabletManagerClient) GetTransactionInfo(ctx context.Context, tablet
 	return fake.GetTransactionInfoResult[tablet.Shard], nil
 }
 
+// CloneFrom is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) CloneFrom(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CloneFromRequest) (logutil.EventStream, error) {
+	key := topoproto.TabletAliasString(tablet.Alias)
+	testdata, ok := fake.CloneFromResults[key]
+	if !ok {
+		return nil, fmt.Errorf("no CloneFrom fake result set for %s", key)
+	}
+	if donor := topoproto.TabletAliasString(req.DonorAlias); donor != testdata.Donor {
+		return nil, fmt.Errorf("%w: CloneFrom for %s got donor %s, want %s", assert.AnError, key, donor, testdata.Donor)
+	}
+
+	// The channel is unbuffered so that every event is received before the
+	// stream is closed.
+	stream := &backupRestoreStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *logutilpb.Event),
+	}
+	go func() {
+		for _, event := range testdata.Events {
+			if err := stream.Send(event); err != nil {
+				return
+			}
+		}
+		stream.CloseWithError(testdata.Error)
+	}()
+
+	return stream, nil
+}
+
 // ConcludeTransaction is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) ConcludeTransaction(ctx context.Context, tablet *topodatapb.Tablet, dtid string, mm bool) error {
 	if fake.CallError {
@@ -1564,6 +1605,24 @@ func (fake *TabletManagerClient) UndoDemotePrimary(ctx context.Context, tablet *
 	return assert.AnError
 }
 
+// VerifyBackup is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	if fake.VerifyBackupResults == nil {
+		return nil, assert.AnError
+	}
+
+	if tablet.Alias == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.VerifyBackupResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no VerifyBackup result set for %s", assert.AnError, key)
+}
+
 // VReplicationExec is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
 	if fake.VReplicationExecResults == nil {
@@ -191,6 +191,57 @@ func (client *localVtctldClient) CleanupSchemaMigration(ctx context.Context, in
 	return client.s.CleanupSchemaMigration(ctx, in)
 }
 
+type cloneTabletStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.CloneTabletResponse
+}
+
+func (stream *cloneTabletStreamAdapter) Recv() (*vtctldatapb.CloneTabletResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *cloneTabletStreamAdapter) Send(msg *vtctldatapb.CloneTabletResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// CloneTablet is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) CloneTablet(ctx context.Context, in *vtctldatapb.CloneTabletRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_CloneTabletClient, error) {
+	stream := &cloneTabletStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.CloneTabletResponse, 1),
+	}
+	go func() {
+		err := client.s.CloneTablet(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // CompleteSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CompleteSchemaMigration(ctx context.Context, in *vtctldatapb.CompleteSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CompleteSchemaMigrationResponse, error) {
 	return client.s.CompleteSchemaMigration(ctx, in)
@@ -201,6 +252,11 @@ func (client *localVtctldClient) ConcludeTransaction(ctx context.Context, in *vt
 	return client.s.ConcludeTransaction(ctx, in)
 }
 
+// CopyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) CopyBackup(ctx context.Context, in *vtctldatapb.CopyBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.CopyBackupResponse, error) {
+	return client.s.CopyBackup(ctx, in)
+}
+
 // CopySchemaShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CopySchemaShard(ctx context.Context, in *vtctldatapb.CopySchemaShardRequest, opts ...grpc.CallOption) (*vtctldatapb.CopySchemaShardResponse, error) {
 	return client.s.CopySchemaShard(ctx, in)
@@ -506,6 +562,11 @@ func (client *localVtctldClient) PlannedReparentShard(ctx context.Context, in *v
 	return client.s.PlannedReparentShard(ctx, in)
 }
 
+// PruneBackups is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) PruneBackups(ctx context.Context, in *vtctldatapb.PruneBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.PruneBackupsResponse, error) {
+	return client.s.PruneBackups(ctx, in)
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RebuildKeyspaceGraph(ctx context.Context, in *vtctldatapb.RebuildKeyspaceGraphRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildKeyspaceGraphResponse, error) {
 	return client.s.RebuildKeyspaceGraph(ctx, in)
@@ -627,6 +688,16 @@ func (client *localVtctldClient) RunHealthCheck(ctx context.Context, in *vtctlda
 	return client.s.RunHealthCheck(ctx, in)
 }
 
+// SetCellBackupBandwidthLimit is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetCellBackupBandwidthLimit(ctx context.Context, in *vtctldatapb.SetCellBackupBandwidthLimitRequest, opts ...grpc.CallOption) (*vtctldatapb.SetCellBackupBandwidthLimitResponse, error) {
+	return client.s.SetCellBackupBandwidthLimit(ctx, in)
+}
+
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetKeyspaceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, error) {
+	return client.s.SetKeyspaceBackupRetentionPolicy(ctx, in)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
@@ -727,6 +798,11 @@ func (client *localVtctldClient) VDiffDelete(ctx context.Context, in *vtctldatap
 	return client.s.VDiffDelete(ctx, in)
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) VDiffRepair(ctx context.Context, in *vtctldatapb.VDiffRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffRepairResponse, error) {
+	return client.s.VDiffRepair(ctx, in)
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) VDiffResume(ctx context.Context, in *vtctldatapb.VDiffResumeRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffResumeResponse, error) {
 	return client.s.VDiffResume(ctx, in)
@@ -787,6 +863,11 @@ func (client *localVtctldClient) WorkflowAddTables(ctx context.Context, in *vtct
 	return client.s.WorkflowAddTables(ctx, in)
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) VerifyBackup(ctx context.Context, in *vtctldatapb.VerifyBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.VerifyBackupResponse, error) {
+	return client.s.VerifyBackup(ctx, in)
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) WorkflowDelete(ctx context.Context, in *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	return client.s.WorkflowDelete(ctx, in)
@@ -2666,7 +2666,8 @@ func (s *Server) refreshPrimaryTablets(ctx context.Context, shards []*topo.Shard
 }
 
 // finalizeMigrateWorkflow deletes the streams for the Migrate workflow.
-// We only cleanup the target for external sources.
+// We only cleanup the target for external sources, and the reverse streams
+// which a bidirectional Migrate created on the external source primaries.
 func (s *Server) finalizeMigrateWorkflow(ctx context.Context, ts *trafficSwitcher, tableSpecs string, cancel, keepData, keepRoutingRules, dryRun bool) (*[]string, error) {
 	var (
 		sw  iswitcher
@@ -2688,6 +2689,11 @@ func (s *Server) finalizeMigrateWorkflow(ctx context.Context, ts *trafficSwitche
 	if err := sw.dropTargetVReplicationStreams(ctx); err != nil {
 		return nil, err
 	}
+	if ts.isBidirectional() {
+		if err := sw.dropSourceReverseVReplicationStreams(ctx); err != nil {
+			return nil, err
+		}
+	}
 	if !cancel {
 		if err := sw.addParticipatingTablesToKeyspace(ctx, ts.targetKeyspace, tableSpecs); err != nil {
 			return nil, err
@@ -3529,6 +3535,14 @@ func fillStringTemplate(tmpl string, vars any) (string, error) {
 }
 
 func (s *Server) MigrateCreate(ctx context.Context, req *vtctldatapb.MigrateCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if req.Bidirectional {
+		if req.ReverseMountName == "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a reverse mount name is required for a bidirectional workflow")
+		}
+		if req.StopAfterCopy {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a bidirectional workflow cannot stop after the copy phase")
+		}
+	}
 	moveTablesCreateRequest := &vtctldatapb.MoveTablesCreateRequest{
 		Workflow:                  req.Workflow,
 		SourceKeyspace:            req.SourceKeyspace,
@@ -3548,7 +3562,31 @@ func (s *Server) MigrateCreate(ctx context.Context, req *vtctldatapb.MigrateCrea
 		AutoStart:                 req.AutoStart,
 		NoRoutingRules:            req.NoRoutingRules,
 	}
-	return s.moveTablesCreate(ctx, moveTablesCreateRequest, binlogdatapb.VReplicationWorkflowType_Migrate)
+	if !req.Bidirectional {
+		return s.moveTablesCreate(ctx, moveTablesCreateRequest, binlogdatapb.VReplicationWorkflowType_Migrate)
+	}
+
+	moveTablesCreateRequest.WorkflowOptions = &vtctldatapb.WorkflowOptions{
+		Config: map[string]string{
+			"vreplication-bidirectional": "true",
+		},
+	}
+	resp, err := s.moveTablesCreate(ctx, moveTablesCreateRequest, binlogdatapb.VReplicationWorkflowType_Migrate)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := s.buildTrafficSwitcher(ctx, req.TargetKeyspace, req.Workflow)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to create the reverse workflow %s", ReverseWorkflowName(req.Workflow))
+	}
+	state := binlogdatapb.VReplicationWorkflowState_Stopped
+	if req.AutoStart {
+		state = binlogdatapb.VReplicationWorkflowState_Running
+	}
+	if err := ts.createBidirectionalReverseVReplication(ctx, req.ReverseMountName, state); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to create the reverse workflow %s", ts.ReverseWorkflowName())
+	}
+	return resp, nil
 }
 
 // getWorkflowStatus gets the overall status of the workflow by checking the status of all the streams. If all streams are not
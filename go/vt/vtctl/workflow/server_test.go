@@ -2684,10 +2684,12 @@ func TestFinalizeMigrateWorkflow(t *testing.T) {
 	}
 
 	testcases := []struct {
-		name          string
-		expectQueries []string
-		cancel        bool
-		keepData      bool
+		name                string
+		expectQueries       []string
+		expectSourceQueries []string
+		cancel              bool
+		keepData            bool
+		bidirectional       bool
 	}{
 		{
 			name: "cancel false, keepData true",
@@ -2707,6 +2709,18 @@ func TestFinalizeMigrateWorkflow(t *testing.T) {
 			cancel:   true,
 			keepData: false,
 		},
+		{
+			name: "bidirectional, cancel true, keepData true",
+			expectQueries: []string{
+				"delete from _vt.vreplication where db_name = 'vt_target_keyspace' and workflow = 'wf1'",
+			},
+			expectSourceQueries: []string{
+				"delete from _vt.vreplication where db_name = 'vt_source_keyspace' and workflow = 'wf1_reverse'",
+			},
+			cancel:        true,
+			keepData:      true,
+			bidirectional: true,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -2717,11 +2731,20 @@ func TestFinalizeMigrateWorkflow(t *testing.T) {
 
 			ts, _, err := te.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
 			require.NoError(t, err)
+			if tc.bidirectional {
+				ts.workflowType = binlogdatapb.VReplicationWorkflowType_Migrate
+				ts.options = &vtctldatapb.WorkflowOptions{
+					Config: map[string]string{"vreplication-bidirectional": "true"},
+				}
+			}
 
 			for _, q := range tc.expectQueries {
 				te.tmc.expectVRQuery(200, q, nil)
 				te.tmc.expectVRQuery(210, q, nil)
 			}
+			for _, q := range tc.expectSourceQueries {
+				te.tmc.expectVRQuery(100, q, nil)
+			}
 
 			_, err = te.ws.finalizeMigrateWorkflow(ctx, ts, "", tc.cancel, tc.keepData, false, false)
 			assert.NoError(t, err)
@@ -2744,6 +2767,7 @@ func TestFinalizeMigrateWorkflow(t *testing.T) {
 			// Expect queries to be used.
 			assert.Empty(t, te.tmc.applySchemaRequests[200])
 			assert.Empty(t, te.tmc.applySchemaRequests[210])
+			assert.Empty(t, te.tmc.vrQueries[100])
 		})
 	}
 }
@@ -2943,3 +2967,31 @@ func TestMigrateAllowsSourceEqualsTarget(t *testing.T) {
 	})
 	require.NotContains(t, err.Error(), "source and target keyspace must be different for MoveTables workflows")
 }
+
+func TestMigrateBidirectionalValidation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	s := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	_, err := s.MigrateCreate(ctx, &vtctldatapb.MigrateCreateRequest{
+		SourceKeyspace: "ks1",
+		TargetKeyspace: "ks2",
+		Workflow:       "wf1",
+		MountName:      "ext1",
+		Bidirectional:  true,
+	})
+	require.ErrorContains(t, err, "a reverse mount name is required for a bidirectional workflow")
+
+	_, err = s.MigrateCreate(ctx, &vtctldatapb.MigrateCreateRequest{
+		SourceKeyspace:   "ks1",
+		TargetKeyspace:   "ks2",
+		Workflow:         "wf1",
+		MountName:        "ext1",
+		Bidirectional:    true,
+		ReverseMountName: "ext2",
+		StopAfterCopy:    true,
+	})
+	require.ErrorContains(t, err, "a bidirectional workflow cannot stop after the copy phase")
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"maps"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -898,63 +899,9 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 	err := ts.ForAllUIDs(func(target *MigrationTarget, uid int32) error {
 		bls := target.Sources[uid]
 		source := ts.Sources()[bls.Shard]
-		reverseBls := &binlogdatapb.BinlogSource{
-			Keyspace:       ts.TargetKeyspaceName(),
-			Shard:          target.GetShard().ShardName(),
-			TabletType:     bls.TabletType,
-			Filter:         &binlogdatapb.Filter{},
-			OnDdl:          bls.OnDdl,
-			SourceTimeZone: bls.TargetTimeZone,
-			TargetTimeZone: bls.SourceTimeZone,
-		}
-		var err error
-		for _, rule := range bls.Filter.Rules {
-			if rule.Filter == "exclude" {
-				reverseBls.Filter.Rules = append(reverseBls.Filter.Rules, rule)
-				continue
-			}
-			var filter string
-			if strings.HasPrefix(rule.Match, "/") {
-				if ts.SourceKeyspaceSchema().Keyspace.Sharded {
-					filter = key.KeyRangeString(source.GetShard().KeyRange)
-				}
-			} else {
-				var inKeyrange string
-				if ts.SourceKeyspaceSchema().Keyspace.Sharded {
-					vtable, ok := ts.SourceKeyspaceSchema().Tables[rule.Match]
-					if !ok {
-						return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "table %s not found in vschema", rule.Match)
-					}
-					// We currently assume the primary vindex is the best way to filter rows
-					// for the table, which may not always be true.
-					// TODO: handle more of these edge cases explicitly, e.g. sequence tables.
-					switch vtable.Type {
-					case vindexes.TypeReference:
-						// For reference tables there are no vindexes and thus no filter to apply.
-					default:
-						// For non-reference tables we return an error if there's no primary
-						// vindex as it's not clear what to do.
-						if len(vtable.ColumnVindexes) > 0 && len(vtable.ColumnVindexes[0].Columns) > 0 {
-							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', %s)", sqlparser.String(vtable.ColumnVindexes[0].Columns[0]),
-								ts.SourceKeyspaceName(), vtable.ColumnVindexes[0].Name, encodeString(key.KeyRangeString(source.GetShard().KeyRange)))
-						} else {
-							return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary vindex found for the %s table in the %s keyspace",
-								vtable.Name.String(), ts.SourceKeyspaceName())
-						}
-					}
-				}
-				filter = fmt.Sprintf("select * from %s%s", sqlescape.EscapeID(rule.Match), inKeyrange)
-				if ts.IsMultiTenantMigration() {
-					filter, err = ts.addTenantFilter(ctx, filter)
-					if err != nil {
-						return err
-					}
-				}
-			}
-			reverseBls.Filter.Rules = append(reverseBls.Filter.Rules, &binlogdatapb.Rule{
-				Match:  rule.Match,
-				Filter: filter,
-			})
+		reverseBls, err := ts.buildReverseBinlogSource(ctx, target, bls)
+		if err != nil {
+			return err
 		}
 		ts.Logger().Infof("Creating reverse workflow vreplication stream on tablet %s: workflow %s, startPos %s",
 			source.GetPrimary().GetAlias(), ts.ReverseWorkflowName(), target.Position)
@@ -982,6 +929,121 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 	return err
 }
 
+// isBidirectional returns true if the workflow has reverse streams on the external
+// source cluster, which were created by a bidirectional Migrate.
+func (ts *trafficSwitcher) isBidirectional() bool {
+	bidirectional, _ := strconv.ParseBool(ts.options.GetConfig()["vreplication-bidirectional"])
+	return bidirectional && ts.workflowType == binlogdatapb.VReplicationWorkflowType_Migrate
+}
+
+// createBidirectionalReverseVReplication creates the reverse streams of a bidirectional
+// Migrate workflow on the primaries of the external source cluster. The streams replicate
+// from the target keyspace, which the external cluster reaches through the reverseMountName
+// mount, starting at the current position of the target primaries.
+func (ts *trafficSwitcher) createBidirectionalReverseVReplication(ctx context.Context, reverseMountName string, state binlogdatapb.VReplicationWorkflowState) error {
+	err := ts.ForAllTargets(func(target *MigrationTarget) error {
+		var err error
+		target.Position, err = ts.ws.tmc.PrimaryPosition(ctx, target.GetPrimary().Tablet)
+		ts.Logger().Infof("Position for target %v:%v: %v", ts.TargetKeyspaceName(), target.GetShard().ShardName(), target.Position)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return ts.ForAllUIDs(func(target *MigrationTarget, uid int32) error {
+		bls := target.Sources[uid]
+		source := ts.Sources()[bls.Shard]
+		reverseBls, err := ts.buildReverseBinlogSource(ctx, target, bls)
+		if err != nil {
+			return err
+		}
+		reverseBls.ExternalCluster = reverseMountName
+		ts.Logger().Infof("Creating bidirectional reverse vreplication stream on tablet %s: workflow %s, startPos %s",
+			source.GetPrimary().GetAlias(), ts.ReverseWorkflowName(), target.Position)
+		// The source primaries belong to the external cluster, so we cannot resolve their
+		// aliases in our topo.
+		_, err = ts.TabletManagerClient().VReplicationExec(ctx, source.GetPrimary().Tablet,
+			binlogplayer.CreateVReplicationState(ts.ReverseWorkflowName(), reverseBls, target.Position,
+				state, source.GetPrimary().DbName(), ts.workflowType, ts.workflowSubType))
+		if err != nil {
+			return err
+		}
+		optionsJSON, err := json.Marshal(ts.options)
+		if err != nil {
+			return err
+		}
+		query := fmt.Sprintf("update _vt.vreplication set options = %s where workflow = %s and db_name = %s",
+			encodeString(string(optionsJSON)), encodeString(ts.ReverseWorkflowName()), encodeString(source.GetPrimary().DbName()))
+		_, err = ts.TabletManagerClient().VReplicationExec(ctx, source.GetPrimary().Tablet, query)
+		return err
+	})
+}
+
+// buildReverseBinlogSource returns the binlog source of the reverse stream which
+// replicates from the given target shard back to the source shard of bls.
+func (ts *trafficSwitcher) buildReverseBinlogSource(ctx context.Context, target *MigrationTarget, bls *binlogdatapb.BinlogSource) (*binlogdatapb.BinlogSource, error) {
+	source := ts.Sources()[bls.Shard]
+	reverseBls := &binlogdatapb.BinlogSource{
+		Keyspace:       ts.TargetKeyspaceName(),
+		Shard:          target.GetShard().ShardName(),
+		TabletType:     bls.TabletType,
+		Filter:         &binlogdatapb.Filter{},
+		OnDdl:          bls.OnDdl,
+		SourceTimeZone: bls.TargetTimeZone,
+		TargetTimeZone: bls.SourceTimeZone,
+	}
+	var err error
+	for _, rule := range bls.Filter.Rules {
+		if rule.Filter == "exclude" {
+			reverseBls.Filter.Rules = append(reverseBls.Filter.Rules, rule)
+			continue
+		}
+		var filter string
+		if strings.HasPrefix(rule.Match, "/") {
+			if ts.SourceKeyspaceSchema().Keyspace.Sharded {
+				filter = key.KeyRangeString(source.GetShard().KeyRange)
+			}
+		} else {
+			var inKeyrange string
+			if ts.SourceKeyspaceSchema().Keyspace.Sharded {
+				vtable, ok := ts.SourceKeyspaceSchema().Tables[rule.Match]
+				if !ok {
+					return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "table %s not found in vschema", rule.Match)
+				}
+				// We currently assume the primary vindex is the best way to filter rows
+				// for the table, which may not always be true.
+				// TODO: handle more of these edge cases explicitly, e.g. sequence tables.
+				switch vtable.Type {
+				case vindexes.TypeReference:
+					// For reference tables there are no vindexes and thus no filter to apply.
+				default:
+					// For non-reference tables we return an error if there's no primary
+					// vindex as it's not clear what to do.
+					if len(vtable.ColumnVindexes) > 0 && len(vtable.ColumnVindexes[0].Columns) > 0 {
+						inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', %s)", sqlparser.String(vtable.ColumnVindexes[0].Columns[0]),
+							ts.SourceKeyspaceName(), vtable.ColumnVindexes[0].Name, encodeString(key.KeyRangeString(source.GetShard().KeyRange)))
+					} else {
+						return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary vindex found for the %s table in the %s keyspace",
+							vtable.Name.String(), ts.SourceKeyspaceName())
+					}
+				}
+			}
+			filter = fmt.Sprintf("select * from %s%s", sqlescape.EscapeID(rule.Match), inKeyrange)
+			if ts.IsMultiTenantMigration() {
+				filter, err = ts.addTenantFilter(ctx, filter)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		reverseBls.Filter.Rules = append(reverseBls.Filter.Rules, &binlogdatapb.Rule{
+			Match:  rule.Match,
+			Filter: filter,
+		})
+	}
+	return reverseBls, nil
+}
+
 func (ts *trafficSwitcher) addTenantFilter(ctx context.Context, filter string) (string, error) {
 	parser := ts.ws.env.Parser()
 	tenantClause, err := ts.buildTenantPredicate(ctx)
@@ -660,6 +660,114 @@ func TestVDiffStop(t *testing.T) {
 	}
 }
 
+func TestVDiffRepair(t *testing.T) {
+	ctx := context.Background()
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	workflow := "testwf"
+	uuid := uuid.New().String()
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	env.tmc.strict = true
+	action := string(vdiff.RepairAction)
+	repairOptions := &tabletmanagerdatapb.VDiffOptions{
+		RepairOptions: &tabletmanagerdatapb.VDiffRepairOptions{
+			BatchSize: 10,
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		req                   *vtctldatapb.VDiffRepairRequest              // vtctld requests
+		expectedVDiffRequests map[*topodatapb.Tablet]*vdiffRequestResponse // tablet requests
+		wantErr               string
+	}{
+		{
+			name: "basic repair", // Both target shards
+			req: &vtctldatapb.VDiffRepairRequest{
+				TargetKeyspace: targetKeyspace.KeyspaceName,
+				Workflow:       workflow,
+				Uuid:           uuid,
+				BatchSize:      10,
+			},
+			expectedVDiffRequests: map[*topodatapb.Tablet]*vdiffRequestResponse{
+				env.tablets[targetKeyspace.KeyspaceName][startingTargetTabletUID]: {
+					req: &tabletmanagerdatapb.VDiffRequest{
+						Keyspace:  targetKeyspace.KeyspaceName,
+						Workflow:  workflow,
+						Action:    action,
+						VdiffUuid: uuid,
+						Options:   repairOptions,
+					},
+				},
+				env.tablets[targetKeyspace.KeyspaceName][startingTargetTabletUID+tabletUIDStep]: {
+					req: &tabletmanagerdatapb.VDiffRequest{
+						Keyspace:  targetKeyspace.KeyspaceName,
+						Workflow:  workflow,
+						Action:    action,
+						VdiffUuid: uuid,
+						Options:   repairOptions,
+					},
+				},
+			},
+		},
+		{
+			name: "repair on first shard",
+			req: &vtctldatapb.VDiffRepairRequest{
+				TargetKeyspace: targetKeyspace.KeyspaceName,
+				TargetShards:   targetKeyspace.ShardNames[:1],
+				Workflow:       workflow,
+				Uuid:           uuid,
+				BatchSize:      10,
+			},
+			expectedVDiffRequests: map[*topodatapb.Tablet]*vdiffRequestResponse{
+				env.tablets[targetKeyspace.KeyspaceName][startingTargetTabletUID]: {
+					req: &tabletmanagerdatapb.VDiffRequest{
+						Keyspace:  targetKeyspace.KeyspaceName,
+						Workflow:  workflow,
+						Action:    action,
+						VdiffUuid: uuid,
+						Options:   repairOptions,
+					},
+				},
+			},
+		},
+		{
+			name: "repair on invalid shard",
+			req: &vtctldatapb.VDiffRepairRequest{
+				TargetKeyspace: targetKeyspace.KeyspaceName,
+				TargetShards:   []string{"0"},
+				Workflow:       workflow,
+				Uuid:           uuid,
+				BatchSize:      10,
+			},
+			wantErr: "specified target shard 0 not a valid target for workflow " + workflow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for tab, vdr := range tt.expectedVDiffRequests {
+				env.tmc.expectVDiffRequest(tab, vdr)
+			}
+			got, err := env.ws.VDiffRepair(ctx, tt.req)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, got)
+			}
+			env.tmc.confirmVDiffRequests(t)
+		})
+	}
+}
+
 func TestVDiffDelete(t *testing.T) {
 	ctx := context.Background()
 	sourceKeyspace := &testKeyspace{
@@ -207,6 +207,21 @@ func (conn *FakeVTGateConn) VStream(ctx context.Context, tabletType topodatapb.T
 	return nil, errors.New("NYI")
 }
 
+// VStreamAck please see vtgateconn.Impl.VStreamAck
+func (conn *FakeVTGateConn) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	return errors.New("NYI")
+}
+
+// VStreamSubscriptions please see vtgateconn.Impl.VStreamSubscriptions
+func (conn *FakeVTGateConn) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	return nil, errors.New("NYI")
+}
+
+// DeleteVStreamSubscription please see vtgateconn.Impl.DeleteVStreamSubscription
+func (conn *FakeVTGateConn) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	return errors.New("NYI")
+}
+
 // Close please see vtgateconn.Impl.Close
 func (conn *FakeVTGateConn) Close() {
 }
@@ -344,6 +344,40 @@ func (conn *vtgateConn) VStream(ctx context.Context, tabletType topodatapb.Table
 	}, nil
 }
 
+func (conn *vtgateConn) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	req := &vtgatepb.VStreamAckRequest{
+		CallerId:         callerid.EffectiveCallerIDFromContext(ctx),
+		SubscriptionName: subscriptionName,
+		Vgtid:            vgtid,
+	}
+	if _, err := conn.c.VStreamAck(ctx, req); err != nil {
+		return vterrors.FromGRPC(err)
+	}
+	return nil
+}
+
+func (conn *vtgateConn) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	req := &vtgatepb.VStreamSubscriptionsRequest{
+		CallerId: callerid.EffectiveCallerIDFromContext(ctx),
+	}
+	response, err := conn.c.VStreamSubscriptions(ctx, req)
+	if err != nil {
+		return nil, vterrors.FromGRPC(err)
+	}
+	return response.Subscriptions, nil
+}
+
+func (conn *vtgateConn) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	req := &vtgatepb.DeleteVStreamSubscriptionRequest{
+		CallerId:         callerid.EffectiveCallerIDFromContext(ctx),
+		SubscriptionName: subscriptionName,
+	}
+	if _, err := conn.c.DeleteVStreamSubscription(ctx, req); err != nil {
+		return vterrors.FromGRPC(err)
+	}
+	return nil
+}
+
 func (conn *vtgateConn) Close() {
 	conn.cc.Close()
 }
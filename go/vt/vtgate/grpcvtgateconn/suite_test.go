@@ -298,6 +298,18 @@ func (f *fakeVTGateService) VStream(ctx context.Context, tabletType topodatapb.T
 	panic("unimplemented")
 }
 
+func (f *fakeVTGateService) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	panic("unimplemented")
+}
+
+func (f *fakeVTGateService) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	panic("unimplemented")
+}
+
+func (f *fakeVTGateService) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	panic("unimplemented")
+}
+
 // CreateFakeServer returns the fake server for the tests
 func CreateFakeServer(t *testing.T) vtgateservice.VTGateService {
 	return &fakeVTGateService{
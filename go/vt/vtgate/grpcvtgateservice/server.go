@@ -335,6 +335,37 @@ func (vtg *VTGate) VStream(request *vtgatepb.VStreamRequest, stream vtgateservic
 	return vterrors.ToGRPC(vtgErr)
 }
 
+// VStreamAck is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) VStreamAck(ctx context.Context, request *vtgatepb.VStreamAckRequest) (response *vtgatepb.VStreamAckResponse, err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx = withCallerIDContext(ctx, request.CallerId)
+	if err := vtg.server.VStreamAck(ctx, request.SubscriptionName, request.Vgtid); err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vtgatepb.VStreamAckResponse{}, nil
+}
+
+// VStreamSubscriptions is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) VStreamSubscriptions(ctx context.Context, request *vtgatepb.VStreamSubscriptionsRequest) (response *vtgatepb.VStreamSubscriptionsResponse, err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx = withCallerIDContext(ctx, request.CallerId)
+	subscriptions, err := vtg.server.VStreamSubscriptions(ctx)
+	if err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vtgatepb.VStreamSubscriptionsResponse{Subscriptions: subscriptions}, nil
+}
+
+// DeleteVStreamSubscription is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) DeleteVStreamSubscription(ctx context.Context, request *vtgatepb.DeleteVStreamSubscriptionRequest) (response *vtgatepb.DeleteVStreamSubscriptionResponse, err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx = withCallerIDContext(ctx, request.CallerId)
+	if err := vtg.server.DeleteVStreamSubscription(ctx, request.SubscriptionName); err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+	return &vtgatepb.DeleteVStreamSubscriptionResponse{}, nil
+}
+
 func init() {
 	vtgate.RegisterVTGates = append(vtgate.RegisterVTGates, func(vtGate vtgateservice.VTGateService) {
 		if servenv.GRPCCheckServiceMap("vtgateservice") {
@@ -58,6 +58,12 @@ type vstreamManager struct {
 	vstreamsEventsStreamed      *stats.CountersWithMultiLabels
 	vstreamsEndedWithErrors     *stats.CountersWithMultiLabels
 	vstreamsTransactionsChunked *stats.CountersWithMultiLabels
+
+	// subscriptions are the VStream subscriptions acknowledged through
+	// this vtgate, keyed by their names. subscriptionsMu only protects the
+	// map, each subscription has its own lock.
+	subscriptionsMu sync.Mutex
+	subscriptions   map[string]*vstreamSubscription
 }
 
 // maxSkewTimeoutSeconds is the maximum allowed skew between two streams when the MinimizeSkew flag is set
@@ -171,9 +177,10 @@ func newVStreamManager(resolver *srvtopo.Resolver, serv srvtopo.Server, cell str
 	labels := []string{"Keyspace", "ShardName", "TabletType"}
 
 	return &vstreamManager{
-		resolver: resolver,
-		toposerv: serv,
-		cell:     cell,
+		resolver:      resolver,
+		toposerv:      serv,
+		cell:          cell,
+		subscriptions: make(map[string]*vstreamSubscription),
 		vstreamsCreated: exporter.NewCountersWithMultiLabels(
 			"VStreamsCreated",
 			"Number of vstreams created",
@@ -204,12 +211,22 @@ func newVStreamManager(resolver *srvtopo.Resolver, serv srvtopo.Server, cell str
 func (vsm *vstreamManager) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
 	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error,
 ) error {
+	if name := flags.GetSubscriptionName(); name != "" {
+		ackedVgtid, err := vsm.getSubscriptionVGtid(ctx, name)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to get the position of vstream subscription %s", name)
+		}
+		if ackedVgtid != nil {
+			log.Info(fmt.Sprintf("Resuming vstream subscription %s from %v", name, ackedVgtid))
+			vgtid = ackedVgtid
+		}
+	}
 	vgtid, filter, flags, err := vsm.resolveParams(ctx, tabletType, vgtid, filter, flags)
 	if err != nil {
 		return vterrors.Wrap(err, "failed to resolve vstream parameters")
 	}
-	log.Info(fmt.Sprintf("VStream flags: minimize_skew=%v, heartbeat_interval=%v, stop_on_reshard=%v, cells=%v, cell_preference=%v, tablet_order=%v, stream_keyspace_heartbeats=%v, include_reshard_journal_events=%v, tables_to_copy=%v, exclude_keyspace_from_table_name=%v, transaction_chunk_size=%v", flags.GetMinimizeSkew(), flags.GetHeartbeatInterval(), flags.GetStopOnReshard(), flags.Cells, flags.CellPreference, flags.TabletOrder,
-		flags.GetStreamKeyspaceHeartbeats(), flags.GetIncludeReshardJournalEvents(), flags.TablesToCopy, flags.GetExcludeKeyspaceFromTableName(), flags.TransactionChunkSize))
+	log.Info(fmt.Sprintf("VStream flags: minimize_skew=%v, heartbeat_interval=%v, stop_on_reshard=%v, cells=%v, cell_preference=%v, tablet_order=%v, stream_keyspace_heartbeats=%v, include_reshard_journal_events=%v, tables_to_copy=%v, exclude_keyspace_from_table_name=%v, transaction_chunk_size=%v, subscription_name=%v", flags.GetMinimizeSkew(), flags.GetHeartbeatInterval(), flags.GetStopOnReshard(), flags.Cells, flags.CellPreference, flags.TabletOrder,
+		flags.GetStreamKeyspaceHeartbeats(), flags.GetIncludeReshardJournalEvents(), flags.TablesToCopy, flags.GetExcludeKeyspaceFromTableName(), flags.TransactionChunkSize, flags.GetSubscriptionName()))
 	ts, err := vsm.toposerv.GetTopoServer()
 	if err != nil {
 		return vterrors.Wrap(err, "failed to get topology server")
@@ -0,0 +1,325 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// vstreamSubscription is a VStream subscription whose position was
+// acknowledged through this vtgate. The positions are saved to the topo at
+// most once every vstreamSubscriptionSaveInterval, so a client that resumes
+// after a vtgate failure may get again the events it acknowledged during
+// the last interval.
+type vstreamSubscription struct {
+	// mu serializes the acknowledgements of the subscription and the saves
+	// of its position, so that the topo is not accessed under the lock of
+	// all the subscriptions.
+	mu sync.Mutex
+	// loaded is true once the position saved in the topo was read.
+	loaded bool
+	// deleted is true once the subscription was removed from the vtgate.
+	deleted bool
+	// vgtid is the last acknowledged position.
+	vgtid *binlogdatapb.VGtid
+	// saved is the last time the position was saved to the topo.
+	saved time.Time
+	// timer saves the position when the interval elapses. It's nil when
+	// the last acknowledged position was saved.
+	timer *time.Timer
+}
+
+// vstreamSubscriptionAuthorized returns true if the caller can acknowledge
+// the positions of the VStream subscriptions and delete them.
+func vstreamSubscriptionAuthorized(caller *querypb.VTGateCallerID) bool {
+	if vstreamSubscriptionAuthorizedUsers == "%" {
+		return true
+	}
+	for user := range strings.SplitSeq(vstreamSubscriptionAuthorizedUsers, ",") {
+		if user = strings.TrimSpace(user); user != "" && user == caller.GetUsername() {
+			return true
+		}
+	}
+	return false
+}
+
+// lockSubscription returns the named subscription with its lock held,
+// adding it if the vtgate does not have it yet.
+func (vsm *vstreamManager) lockSubscription(name string) *vstreamSubscription {
+	for {
+		vsm.subscriptionsMu.Lock()
+		sub := vsm.subscriptions[name]
+		if sub == nil {
+			sub = &vstreamSubscription{}
+			vsm.subscriptions[name] = sub
+		}
+		vsm.subscriptionsMu.Unlock()
+		sub.mu.Lock()
+		if !sub.deleted {
+			return sub
+		}
+		// The subscription was deleted meanwhile, a new one is added.
+		sub.mu.Unlock()
+	}
+}
+
+// getSubscriptionVGtid returns the last position that was acknowledged for
+// the named subscription, or nil if none was.
+func (vsm *vstreamManager) getSubscriptionVGtid(ctx context.Context, name string) (*binlogdatapb.VGtid, error) {
+	vsm.subscriptionsMu.Lock()
+	sub := vsm.subscriptions[name]
+	vsm.subscriptionsMu.Unlock()
+	if sub != nil {
+		sub.mu.Lock()
+		if !sub.deleted && sub.timer != nil {
+			// The last acknowledged position is not saved yet.
+			defer sub.mu.Unlock()
+			return sub.vgtid.CloneVT(), nil
+		}
+		sub.mu.Unlock()
+	}
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return nil, err
+	}
+	vgtid, err := ts.GetVStreamSubscription(ctx, name)
+	if topo.IsErrType(err, topo.NoNode) {
+		return nil, nil
+	}
+	return vgtid, err
+}
+
+// VStreamAck stores the position that was processed by the client of the
+// named subscription. The streams of the subscription resume from it.
+func (vsm *vstreamManager) VStreamAck(ctx context.Context, name string, vgtid *binlogdatapb.VGtid) error {
+	if !vstreamSubscriptionAuthorized(callerid.ImmediateCallerIDFromContext(ctx)) {
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "user %s is not authorized to acknowledge the positions of vstream subscriptions",
+			callerid.ImmediateCallerIDFromContext(ctx).GetUsername())
+	}
+	if name == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "subscription name must be specified")
+	}
+	if len(vgtid.GetShardGtids()) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vgtid must have at least one value in ShardGtids")
+	}
+	for _, sgtid := range vgtid.ShardGtids {
+		// Only the positions sent by VStream can be acknowledged, and they
+		// always have a keyspace and shard.
+		if sgtid.Keyspace == "" || sgtid.Shard == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace and shard must be specified in the acknowledged vgtid: %v", sgtid)
+		}
+	}
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return vterrors.Wrap(err, "failed to get topology server")
+	}
+
+	sub := vsm.lockSubscription(name)
+	defer sub.mu.Unlock()
+	if !sub.loaded {
+		prev, err := ts.GetVStreamSubscription(ctx, name)
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return vterrors.Wrapf(err, "failed to get the position of vstream subscription %s", name)
+		}
+		sub.vgtid = prev
+		sub.loaded = true
+	}
+	if err := validateSubscriptionAck(ctx, ts, name, sub.vgtid, vgtid); err != nil {
+		return err
+	}
+	sub.vgtid = vgtid.CloneVT()
+	if sub.timer != nil {
+		// The position is saved when the timer fires.
+		return nil
+	}
+	if wait := vstreamSubscriptionSaveInterval - time.Since(sub.saved); wait > 0 {
+		sub.timer = time.AfterFunc(wait, func() {
+			vsm.saveSubscription(name)
+		})
+		return nil
+	}
+	if err := ts.SaveVStreamSubscription(ctx, name, sub.vgtid); err != nil {
+		return vterrors.Wrapf(err, "failed to save the position of vstream subscription %s", name)
+	}
+	sub.saved = time.Now()
+	return nil
+}
+
+// saveSubscription saves the last acknowledged position of the subscription
+// once the save interval elapsed.
+func (vsm *vstreamManager) saveSubscription(name string) {
+	vsm.subscriptionsMu.Lock()
+	sub := vsm.subscriptions[name]
+	vsm.subscriptionsMu.Unlock()
+	if sub == nil {
+		// The subscription was deleted.
+		return
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.deleted || sub.timer == nil {
+		// The subscription was deleted.
+		return
+	}
+	sub.timer = nil
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err == nil {
+		err = ts.SaveVStreamSubscription(ctx, name, sub.vgtid)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to save the position of vstream subscription %s, retrying in %v: %v", name, vstreamSubscriptionSaveInterval, err))
+		sub.timer = time.AfterFunc(vstreamSubscriptionSaveInterval, func() {
+			vsm.saveSubscription(name)
+		})
+		return
+	}
+	sub.saved = time.Now()
+}
+
+// validateSubscriptionAck checks that the acknowledged vgtid belongs to the
+// subscription, whose last acknowledged position is prev, and that it does
+// not move the position of any shard backwards. The shards can change when
+// the keyspaces are resharded, but they must exist.
+func validateSubscriptionAck(ctx context.Context, ts *topo.Server, name string, prev, vgtid *binlogdatapb.VGtid) error {
+	prevKeyspaces := make(map[string]bool)
+	prevShards := make(map[string]*binlogdatapb.ShardGtid)
+	for _, sgtid := range prev.GetShardGtids() {
+		prevKeyspaces[sgtid.Keyspace] = true
+		prevShards[sgtid.Keyspace+"/"+sgtid.Shard] = sgtid
+	}
+	shardNames := make(map[string][]string)
+	for _, sgtid := range vgtid.ShardGtids {
+		if prev != nil && !prevKeyspaces[sgtid.Keyspace] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace %s is not streamed by vstream subscription %s", sgtid.Keyspace, name)
+		}
+		prevSgtid := prevShards[sgtid.Keyspace+"/"+sgtid.Shard]
+		if prevSgtid == nil {
+			names, ok := shardNames[sgtid.Keyspace]
+			if !ok {
+				var err error
+				if names, err = ts.GetShardNames(ctx, sgtid.Keyspace); err != nil {
+					return vterrors.Wrapf(err, "failed to get the shards of keyspace %s", sgtid.Keyspace)
+				}
+				shardNames[sgtid.Keyspace] = names
+			}
+			if !slices.Contains(names, sgtid.Shard) {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "shard %s/%s of the acknowledged vgtid does not exist", sgtid.Keyspace, sgtid.Shard)
+			}
+			continue
+		}
+		if isVGtidPositionUnset(sgtid.Gtid) || isVGtidPositionUnset(prevSgtid.Gtid) {
+			continue
+		}
+		pos, err := replication.DecodePosition(sgtid.Gtid)
+		if err != nil {
+			return vterrors.Wrapf(err, "invalid position for shard %s/%s in the acknowledged vgtid", sgtid.Keyspace, sgtid.Shard)
+		}
+		prevPos, err := replication.DecodePosition(prevSgtid.Gtid)
+		if err != nil {
+			return vterrors.Wrapf(err, "invalid position for shard %s/%s in vstream subscription %s", sgtid.Keyspace, sgtid.Shard, name)
+		}
+		if !pos.AtLeast(prevPos) {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "position %s of shard %s/%s is before the position %s that was acknowledged for vstream subscription %s",
+				sgtid.Gtid, sgtid.Keyspace, sgtid.Shard, prevSgtid.Gtid, name)
+		}
+	}
+	return nil
+}
+
+// isVGtidPositionUnset returns true for the positions that VStream resolves
+// when it starts, which can't be compared.
+func isVGtidPositionUnset(gtid string) bool {
+	return gtid == "" || gtid == "current"
+}
+
+// VStreamSubscriptions returns the last acknowledged positions of all the
+// VStream subscriptions, keyed by their names.
+func (vsm *vstreamManager) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to get topology server")
+	}
+	names, err := ts.GetVStreamSubscriptionNames(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to get the vstream subscriptions")
+	}
+	subscriptions := make(map[string]*binlogdatapb.VGtid, len(names))
+	for _, name := range names {
+		vgtid, err := vsm.getSubscriptionVGtid(ctx, name)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to get the position of vstream subscription %s", name)
+		}
+		if vgtid != nil {
+			subscriptions[name] = vgtid
+		}
+	}
+	return subscriptions, nil
+}
+
+// DeleteVStreamSubscription deletes the named subscription. The streams of
+// the subscription then start from their requested positions again.
+func (vsm *vstreamManager) DeleteVStreamSubscription(ctx context.Context, name string) error {
+	if !vstreamSubscriptionAuthorized(callerid.ImmediateCallerIDFromContext(ctx)) {
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "user %s is not authorized to delete vstream subscriptions",
+			callerid.ImmediateCallerIDFromContext(ctx).GetUsername())
+	}
+	if name == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "subscription name must be specified")
+	}
+	ts, err := vsm.toposerv.GetTopoServer()
+	if err != nil {
+		return vterrors.Wrap(err, "failed to get topology server")
+	}
+	vsm.subscriptionsMu.Lock()
+	sub := vsm.subscriptions[name]
+	delete(vsm.subscriptions, name)
+	vsm.subscriptionsMu.Unlock()
+	if sub != nil {
+		// Wait for the acknowledgement or the save in progress, so that it
+		// does not save the position again once it's deleted.
+		sub.mu.Lock()
+		defer sub.mu.Unlock()
+		sub.deleted = true
+		if sub.timer != nil {
+			sub.timer.Stop()
+		}
+	}
+	if err := ts.DeleteVStreamSubscription(ctx, name); err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vstream subscription %s does not exist", name)
+		}
+		return vterrors.Wrapf(err, "failed to delete vstream subscription %s", name)
+	}
+	return nil
+}
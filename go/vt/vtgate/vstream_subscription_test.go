@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	subscriptionGtid1 = "MySQL56/00000000-0000-0000-0000-000000000001:1-1"
+	subscriptionGtid2 = "MySQL56/00000000-0000-0000-0000-000000000001:1-2"
+	subscriptionGtid3 = "MySQL56/00000000-0000-0000-0000-000000000001:1-3"
+)
+
+func setVStreamSubscriptionFlags(t *testing.T, users string, saveInterval time.Duration) {
+	oldUsers, oldInterval := vstreamSubscriptionAuthorizedUsers, vstreamSubscriptionSaveInterval
+	vstreamSubscriptionAuthorizedUsers, vstreamSubscriptionSaveInterval = users, saveInterval
+	t.Cleanup(func() {
+		vstreamSubscriptionAuthorizedUsers, vstreamSubscriptionSaveInterval = oldUsers, oldInterval
+	})
+}
+
+func subscriptionVGtid(ks, shard, gtid string) *binlogdatapb.VGtid {
+	return &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: ks,
+			Shard:    shard,
+			Gtid:     gtid,
+		}},
+	}
+}
+
+func TestVStreamSubscription(t *testing.T) {
+	setVStreamSubscriptionFlags(t, "%", 0)
+	ctx := t.Context()
+	cell := "aa"
+	ks := "TestVStream"
+	_ = createSandbox(ks)
+	hc := discovery.NewFakeHealthCheck(nil)
+	st := getSandboxTopo(ctx, cell, ks, []string{"-20"})
+
+	vsm := newTestVStreamManager(ctx, hc, st, cell)
+	sbc0 := hc.AddTestTablet(cell, "1.1.1.1", 1001, ks, "-20", topodatapb.TabletType_PRIMARY, true, 1, nil)
+	addTabletToSandboxTopo(t, ctx, st, ks, "-20", sbc0.Tablet())
+
+	flags := &vtgatepb.VStreamFlags{SubscriptionName: "orders"}
+	stream := func(events []*binlogdatapb.VEvent) *binlogdatapb.VGtid {
+		sbc0.AddVStreamEvents(events, nil)
+		vstreamCtx, vstreamCancel := context.WithCancel(ctx)
+		defer vstreamCancel()
+		var received *binlogdatapb.VGtid
+		err := vsm.VStream(vstreamCtx, topodatapb.TabletType_PRIMARY, subscriptionVGtid(ks, "-20", "pos"), nil, flags, func(events []*binlogdatapb.VEvent) error {
+			for _, ev := range events {
+				if ev.Type == binlogdatapb.VEventType_VGTID {
+					received = ev.Vgtid
+					vstreamCancel()
+				}
+			}
+			return nil
+		})
+		require.ErrorIs(t, vterrors.UnwrapAll(err), context.Canceled)
+		return received
+	}
+
+	// A new subscription starts from the requested position.
+	sbc0.StartPos = "pos"
+	received := stream([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: subscriptionGtid1},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.NotNil(t, received)
+
+	// Until it's acknowledged, the position isn't used.
+	received = stream([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: subscriptionGtid1},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.NoError(t, vsm.VStreamAck(ctx, "orders", received))
+
+	// The subscription resumes from the acknowledged position.
+	sbc0.StartPos = subscriptionGtid1
+	received = stream([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: subscriptionGtid2},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.Equal(t, subscriptionGtid2, received.ShardGtids[0].Gtid)
+
+	// Another vtgate resumes from it too.
+	require.NoError(t, vsm.VStreamAck(ctx, "orders", received))
+	vsm = newTestVStreamManager(ctx, hc, st, cell)
+	sbc0.StartPos = subscriptionGtid2
+	received = stream([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: subscriptionGtid3},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.Equal(t, subscriptionGtid3, received.ShardGtids[0].Gtid)
+
+	require.ErrorContains(t, vsm.VStreamAck(ctx, "", received), "subscription name must be specified")
+	require.ErrorContains(t, vsm.VStreamAck(ctx, "orders", nil), "vgtid must have at least one value in ShardGtids")
+	require.ErrorContains(t, vsm.VStreamAck(ctx, "orders", &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: ks, Gtid: "current"}},
+	}), "keyspace and shard must be specified")
+
+	// The acknowledged positions must belong to the subscription, and can't
+	// move backwards.
+	err := vsm.VStreamAck(ctx, "orders", subscriptionVGtid(ks, "-20", subscriptionGtid1))
+	require.ErrorContains(t, err, "is before the position")
+	require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	require.ErrorContains(t, vsm.VStreamAck(ctx, "orders", subscriptionVGtid("other", "-20", subscriptionGtid3)),
+		"keyspace other is not streamed by vstream subscription orders")
+	require.ErrorContains(t, vsm.VStreamAck(ctx, "orders", subscriptionVGtid(ks, "20-", subscriptionGtid3)),
+		"shard TestVStream/20- of the acknowledged vgtid does not exist")
+	require.ErrorContains(t, vsm.VStreamAck(ctx, "new", subscriptionVGtid(ks, "20-", subscriptionGtid3)),
+		"shard TestVStream/20- of the acknowledged vgtid does not exist")
+	require.NoError(t, vsm.VStreamAck(ctx, "orders", received))
+
+	subscriptions, err := vsm.VStreamSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, subscriptionGtid3, subscriptions["orders"].ShardGtids[0].Gtid)
+
+	// Once deleted, the subscription starts from the requested position again.
+	require.NoError(t, vsm.DeleteVStreamSubscription(ctx, "orders"))
+	err = vsm.DeleteVStreamSubscription(ctx, "orders")
+	require.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+	subscriptions, err = vsm.VStreamSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+	sbc0.StartPos = "pos"
+	received = stream([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: subscriptionGtid1},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.Equal(t, subscriptionGtid1, received.ShardGtids[0].Gtid)
+}
+
+func TestVStreamSubscriptionSaveInterval(t *testing.T) {
+	setVStreamSubscriptionFlags(t, "%", time.Hour)
+	ctx := t.Context()
+	cell := "aa"
+	ks := "TestVStream"
+	_ = createSandbox(ks)
+	hc := discovery.NewFakeHealthCheck(nil)
+	st := getSandboxTopo(ctx, cell, ks, []string{"-20"})
+	vsm := newTestVStreamManager(ctx, hc, st, cell)
+	ts, err := st.GetTopoServer()
+	require.NoError(t, err)
+
+	// The first acknowledged position is saved right away, and the next
+	// ones once the interval elapsed.
+	require.NoError(t, vsm.VStreamAck(ctx, "orders", subscriptionVGtid(ks, "-20", subscriptionGtid1)))
+	require.NoError(t, vsm.VStreamAck(ctx, "orders", subscriptionVGtid(ks, "-20", subscriptionGtid2)))
+	saved, err := ts.GetVStreamSubscription(ctx, "orders")
+	require.NoError(t, err)
+	require.Equal(t, subscriptionGtid1, saved.ShardGtids[0].Gtid)
+
+	// The streams started on this vtgate use the last acknowledged position.
+	vgtid, err := vsm.getSubscriptionVGtid(ctx, "orders")
+	require.NoError(t, err)
+	require.Equal(t, subscriptionGtid2, vgtid.ShardGtids[0].Gtid)
+
+	vsm.subscriptionsMu.Lock()
+	sub := vsm.subscriptions["orders"]
+	vsm.subscriptionsMu.Unlock()
+	sub.mu.Lock()
+	sub.timer.Reset(0)
+	sub.mu.Unlock()
+	require.Eventually(t, func() bool {
+		saved, err := ts.GetVStreamSubscription(ctx, "orders")
+		return err == nil && saved.ShardGtids[0].Gtid == subscriptionGtid2
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestVStreamSubscriptionAuthorization(t *testing.T) {
+	setVStreamSubscriptionFlags(t, "alice, bob", 0)
+	ctx := t.Context()
+	cell := "aa"
+	ks := "TestVStream"
+	_ = createSandbox(ks)
+	hc := discovery.NewFakeHealthCheck(nil)
+	st := getSandboxTopo(ctx, cell, ks, []string{"-20"})
+	vsm := newTestVStreamManager(ctx, hc, st, cell)
+	vgtid := subscriptionVGtid(ks, "-20", subscriptionGtid1)
+
+	err := vsm.VStreamAck(ctx, "orders", vgtid)
+	require.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err))
+	eveCtx := callerid.NewContext(ctx, nil, callerid.NewImmediateCallerID("eve"))
+	require.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(vsm.VStreamAck(eveCtx, "orders", vgtid)))
+	require.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(vsm.DeleteVStreamSubscription(eveCtx, "orders")))
+
+	bobCtx := callerid.NewContext(ctx, nil, callerid.NewImmediateCallerID("bob"))
+	require.NoError(t, vsm.VStreamAck(bobCtx, "orders", vgtid))
+	require.NoError(t, vsm.DeleteVStreamSubscription(bobCtx, "orders"))
+}
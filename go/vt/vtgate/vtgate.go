@@ -168,6 +168,13 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// vstreamSubscriptionAuthorizedUsers are the users that can acknowledge
+	// the positions of the VStream subscriptions and delete them.
+	vstreamSubscriptionAuthorizedUsers string
+	// vstreamSubscriptionSaveInterval is the minimum time between two saves
+	// of the position of a VStream subscription to the topo.
+	vstreamSubscriptionSaveInterval = time.Second
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -205,6 +212,8 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.StringVar(&vstreamSubscriptionAuthorizedUsers, "vstream-subscription-authorized-users", vstreamSubscriptionAuthorizedUsers, "List of users authorized to acknowledge the positions of VStream subscriptions and to delete them, or '%' to allow all users.")
+	fs.DurationVar(&vstreamSubscriptionSaveInterval, "vstream-subscription-save-interval", vstreamSubscriptionSaveInterval, "Minimum time between two saves of the acknowledged position of a VStream subscription to the topo. The positions acknowledged in the meantime are kept in memory.")
 
 	viperutil.BindFlags(fs,
 		enableOnlineDDL,
@@ -765,6 +774,21 @@ func (vtg *VTGate) VStream(ctx context.Context, tabletType topodatapb.TabletType
 	return vtg.vsm.VStream(ctx, tabletType, vgtid, filter, flags, send)
 }
 
+// VStreamAck stores the position processed by the client of a VStream subscription.
+func (vtg *VTGate) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	return vtg.vsm.VStreamAck(ctx, subscriptionName, vgtid)
+}
+
+// VStreamSubscriptions returns the acknowledged positions of the VStream subscriptions.
+func (vtg *VTGate) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	return vtg.vsm.VStreamSubscriptions(ctx)
+}
+
+// DeleteVStreamSubscription deletes a VStream subscription.
+func (vtg *VTGate) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	return vtg.vsm.DeleteVStreamSubscription(ctx, subscriptionName)
+}
+
 // GetGatewayCacheStatus returns a displayable version of the Gateway cache.
 func (vtg *VTGate) GetGatewayCacheStatus() TabletCacheStatusList {
 	return vtg.gw.CacheStatus()
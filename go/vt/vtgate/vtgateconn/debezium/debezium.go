@@ -0,0 +1,584 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debezium converts the events of a vtgate VStream into change
+// events that have the format of the ones produced by the Debezium
+// connectors, so that they can be consumed by the existing CDC tooling.
+//
+// Each row change becomes a change event that has a key, the primary key of
+// the row, and a value, the envelope of the change:
+//
+//	{
+//	  "before": {...},
+//	  "after": {...},
+//	  "source": {"connector": "vitess", "name": ..., "keyspace": ..., "table": ..., "shard": ..., "vgtid": ..., ...},
+//	  "op": "c" | "u" | "d" | "r",
+//	  "ts_ms": ...
+//	}
+//
+// The key and the value are encoded like the JsonConverter does, optionally
+// with their schema. Rows copied in the copy phase of the stream have the
+// "r" (read) op, and are flagged as snapshot rows in the source. The changes
+// replicated while the tables are being copied keep the "c", "u" and "d" ops.
+package debezium
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// ConnectorName is the name of the connector in the source of the events.
+const ConnectorName = "vitess"
+
+// The ops of the change events.
+const (
+	OpCreate = "c"
+	OpUpdate = "u"
+	OpDelete = "d"
+	OpRead   = "r"
+)
+
+// Options are the options of an Encoder.
+type Options struct {
+	// ServerName is the logical name of the Vitess cluster. It's the name
+	// in the source of the events, and the prefix of their topics.
+	ServerName string
+	// IncludeSchema adds the schema to the keys and the values, like the
+	// JsonConverter does when schemas.enable is true.
+	IncludeSchema bool
+}
+
+// ChangeEvent is a change to a row.
+type ChangeEvent struct {
+	// Topic is <server name>.<keyspace>.<table>.
+	Topic    string
+	Keyspace string
+	Table    string
+	Shard    string
+	// Key and Value are JSON documents. Key is nil for tables that don't
+	// have a primary key.
+	Key   []byte
+	Value []byte
+}
+
+// Encoder converts VStream events into change events. It keeps the state of
+// the stream, e.g. the fields of the tables, so all the events of a stream
+// must be passed to the same Encoder, in order. An Encoder must not be used
+// concurrently.
+type Encoder struct {
+	opts Options
+	// tables are keyed by keyspace, shard and table name.
+	tables map[string]*table
+	vgtid  *binlogdatapb.VGtid
+	// copying contains the keyspace/shard of the shards that are in the
+	// copy phase.
+	copying map[string]bool
+	// pending contains the row events of the current transaction. They are
+	// encoded when its VGTID is known.
+	pending []*binlogdatapb.VEvent
+}
+
+// NewEncoder returns an Encoder.
+func NewEncoder(opts Options) *Encoder {
+	return &Encoder{
+		opts:    opts,
+		tables:  make(map[string]*table),
+		copying: make(map[string]bool),
+	}
+}
+
+// Encode processes the events returned by a VStreamReader, and returns the
+// change events of the rows that changed. The row changes of a transaction
+// are returned once the VGTID that includes them is received, so that it can
+// be used as the position of the changes.
+func (enc *Encoder) Encode(events []*binlogdatapb.VEvent) ([]*ChangeEvent, error) {
+	var changes []*ChangeEvent
+	for _, ev := range events {
+		switch ev.Type {
+		case binlogdatapb.VEventType_FIELD:
+			fe := ev.FieldEvent
+			enc.tables[tableKey(fe.Keyspace, fe.Shard, fe.TableName)] = newTable(enc.opts.ServerName, fe)
+		case binlogdatapb.VEventType_ROW:
+			if ev.RowEvent.IsInternalTable {
+				continue
+			}
+			enc.pending = append(enc.pending, ev)
+		case binlogdatapb.VEventType_VGTID:
+			prev := enc.vgtid
+			enc.vgtid = ev.Vgtid
+			for _, sgtid := range ev.Vgtid.ShardGtids {
+				// The table primary keys are only sent while the tables of
+				// the shard are being copied.
+				enc.copying[sgtid.Keyspace+"/"+sgtid.Shard] = len(sgtid.TablePKs) > 0
+			}
+			evChanges, err := enc.flush(prev)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, evChanges...)
+		case binlogdatapb.VEventType_COPY_COMPLETED:
+			if ev.Keyspace == "" {
+				// The copy of all the shards is completed.
+				clear(enc.copying)
+				continue
+			}
+			enc.copying[ev.Keyspace+"/"+ev.Shard] = false
+		}
+	}
+	return changes, nil
+}
+
+// Flush returns the change events of the rows that are still waiting for
+// the VGTID of their transaction, using the last VGTID that was received.
+func (enc *Encoder) Flush() ([]*ChangeEvent, error) {
+	return enc.flush(enc.vgtid)
+}
+
+// flush returns the change events of the pending rows. prev is the VGTID
+// that was received before the one of their transaction.
+func (enc *Encoder) flush(prev *binlogdatapb.VGtid) ([]*ChangeEvent, error) {
+	var changes []*ChangeEvent
+	for _, ev := range enc.pending {
+		evChanges, err := enc.encodeRowEvent(ev, enc.isCopiedRow(ev.RowEvent, prev))
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, evChanges...)
+	}
+	enc.pending = nil
+	return changes, nil
+}
+
+// isCopiedRow returns true if the row event was sent by the copy phase of
+// its shard, rather than replicated from the binary log. The rows that are
+// copied are followed by the VGTID that has the last primary key copied for
+// their table, and that doesn't change the position of the shard, while the
+// changes replicated during the copy phase move the position forward.
+func (enc *Encoder) isCopiedRow(re *binlogdatapb.RowEvent, prev *binlogdatapb.VGtid) bool {
+	if !enc.copying[re.Keyspace+"/"+re.Shard] {
+		return false
+	}
+	sgtid := findShardGtid(enc.vgtid, re.Keyspace, re.Shard)
+	if sgtid == nil {
+		return false
+	}
+	tableName := strings.TrimPrefix(re.TableName, re.Keyspace+".")
+	if !slices.ContainsFunc(sgtid.TablePKs, func(tablePK *binlogdatapb.TableLastPK) bool {
+		return tablePK.TableName == tableName
+	}) {
+		return false
+	}
+	if prevSgtid := findShardGtid(prev, re.Keyspace, re.Shard); prevSgtid != nil && prevSgtid.Gtid != sgtid.Gtid {
+		return false
+	}
+	return true
+}
+
+func findShardGtid(vgtid *binlogdatapb.VGtid, keyspace, shard string) *binlogdatapb.ShardGtid {
+	for _, sgtid := range vgtid.GetShardGtids() {
+		if sgtid.Keyspace == keyspace && sgtid.Shard == shard {
+			return sgtid
+		}
+	}
+	return nil
+}
+
+func (enc *Encoder) encodeRowEvent(ev *binlogdatapb.VEvent, snapshot bool) ([]*ChangeEvent, error) {
+	re := ev.RowEvent
+	tbl := enc.tables[tableKey(re.Keyspace, re.Shard, re.TableName)]
+	if tbl == nil {
+		return nil, fmt.Errorf("no fields for table %s in shard %s/%s", re.TableName, re.Keyspace, re.Shard)
+	}
+	source := map[string]any{
+		"connector": ConnectorName,
+		"name":      enc.opts.ServerName,
+		"ts_ms":     ev.Timestamp * 1000,
+		"snapshot":  strconv.FormatBool(snapshot),
+		"db":        re.Keyspace,
+		"keyspace":  re.Keyspace,
+		"table":     tbl.name,
+		"shard":     re.Shard,
+		"vgtid":     vgtidJSON(enc.vgtid),
+	}
+	tsMs := ev.CurrentTime / int64(time.Millisecond)
+	if tsMs == 0 {
+		tsMs = time.Now().UnixMilli()
+	}
+
+	changes := make([]*ChangeEvent, 0, len(re.RowChanges))
+	for _, rc := range re.RowChanges {
+		payload := map[string]any{
+			"source": source,
+			"ts_ms":  tsMs,
+		}
+		var err error
+		if payload["before"], err = tbl.row(rc.Before, rc.DataColumns); err != nil {
+			return nil, err
+		}
+		if payload["after"], err = tbl.row(rc.After, rc.DataColumns); err != nil {
+			return nil, err
+		}
+		switch {
+		case snapshot:
+			payload["op"] = OpRead
+		case rc.Before == nil:
+			payload["op"] = OpCreate
+		case rc.After == nil:
+			payload["op"] = OpDelete
+		default:
+			payload["op"] = OpUpdate
+		}
+		change := &ChangeEvent{
+			Topic:    tbl.topic,
+			Keyspace: re.Keyspace,
+			Table:    tbl.name,
+			Shard:    re.Shard,
+		}
+		if change.Value, err = enc.marshal(tbl.valueSchema, payload); err != nil {
+			return nil, err
+		}
+		if len(tbl.pkColumns) > 0 {
+			keyRow := rc.After
+			if keyRow == nil {
+				keyRow = rc.Before
+			}
+			key, err := tbl.key(keyRow)
+			if err != nil {
+				return nil, err
+			}
+			if change.Key, err = enc.marshal(tbl.keySchema, key); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (enc *Encoder) marshal(schema *Schema, payload any) ([]byte, error) {
+	if !enc.opts.IncludeSchema {
+		return json.Marshal(payload)
+	}
+	return json.Marshal(struct {
+		Schema  *Schema `json:"schema"`
+		Payload any     `json:"payload"`
+	}{schema, payload})
+}
+
+// Reader returns the change events of a VStream.
+type Reader struct {
+	reader  vtgateconn.VStreamReader
+	encoder *Encoder
+}
+
+// NewReader returns a Reader of the change events of the VStream.
+func NewReader(reader vtgateconn.VStreamReader, opts Options) *Reader {
+	return &Reader{
+		reader:  reader,
+		encoder: NewEncoder(opts),
+	}
+}
+
+// Recv returns the next change events. It blocks until there is at least
+// one change event, or the stream fails.
+func (r *Reader) Recv() ([]*ChangeEvent, error) {
+	for {
+		events, err := r.reader.Recv()
+		if err != nil {
+			return nil, err
+		}
+		changes, err := r.encoder.Encode(events)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			return changes, nil
+		}
+	}
+}
+
+func tableKey(keyspace, shard, tableName string) string {
+	return keyspace + "/" + shard + "/" + tableName
+}
+
+// vgtidJSON returns the VGTID as the JSON array used by the Debezium Vitess
+// connector.
+func vgtidJSON(vgtid *binlogdatapb.VGtid) string {
+	type shardGtid struct {
+		Keyspace string `json:"keyspace"`
+		Shard    string `json:"shard"`
+		Gtid     string `json:"gtid"`
+	}
+	sgtids := make([]shardGtid, 0, len(vgtid.GetShardGtids()))
+	for _, sgtid := range vgtid.GetShardGtids() {
+		sgtids = append(sgtids, shardGtid{sgtid.Keyspace, sgtid.Shard, sgtid.Gtid})
+	}
+	b, _ := json.Marshal(sgtids)
+	return string(b)
+}
+
+// table has the fields of a table, and the schemas of its change events.
+type table struct {
+	name        string
+	topic       string
+	fields      []*querypb.Field
+	pkColumns   []int
+	keySchema   *Schema
+	valueSchema *Schema
+}
+
+func newTable(serverName string, fe *binlogdatapb.FieldEvent) *table {
+	name := strings.TrimPrefix(fe.TableName, fe.Keyspace+".")
+	tbl := &table{
+		name:   name,
+		topic:  serverName + "." + fe.Keyspace + "." + name,
+		fields: fe.Fields,
+	}
+	rowSchema := &Schema{
+		Type:     "struct",
+		Optional: true,
+		Name:     tbl.topic + ".Value",
+	}
+	keySchema := &Schema{
+		Type: "struct",
+		Name: tbl.topic + ".Key",
+	}
+	for i, field := range fe.Fields {
+		fieldSchema := columnSchema(field)
+		rowSchema.Fields = append(rowSchema.Fields, fieldSchema)
+		if field.Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG) != 0 {
+			tbl.pkColumns = append(tbl.pkColumns, i)
+			keySchema.Fields = append(keySchema.Fields, fieldSchema)
+		}
+	}
+	tbl.keySchema = keySchema
+	before, after := *rowSchema, *rowSchema
+	before.Field, after.Field = "before", "after"
+	tbl.valueSchema = &Schema{
+		Type: "struct",
+		Name: tbl.topic + ".Envelope",
+		Fields: []*Schema{
+			&before,
+			&after,
+			sourceSchema,
+			{Type: "string", Field: "op"},
+			{Type: "int64", Optional: true, Field: "ts_ms"},
+		},
+	}
+	return tbl
+}
+
+// row returns the row image as a map of column names to values. Columns
+// that are not in a partial row image are omitted.
+func (tbl *table) row(row *querypb.Row, dataColumns *binlogdatapb.RowChange_Bitmap) (map[string]any, error) {
+	if row == nil {
+		return nil, nil
+	}
+	vals := sqltypes.MakeRowTrusted(tbl.fields, row)
+	out := make(map[string]any, len(vals))
+	for i, val := range vals {
+		if dataColumns != nil && i/8 < len(dataColumns.Cols) && dataColumns.Cols[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		v, err := columnValue(tbl.fields[i], val)
+		if err != nil {
+			return nil, err
+		}
+		out[tbl.fields[i].Name] = v
+	}
+	return out, nil
+}
+
+func (tbl *table) key(row *querypb.Row) (map[string]any, error) {
+	vals := sqltypes.MakeRowTrusted(tbl.fields, row)
+	out := make(map[string]any, len(tbl.pkColumns))
+	for _, i := range tbl.pkColumns {
+		v, err := columnValue(tbl.fields[i], vals[i])
+		if err != nil {
+			return nil, err
+		}
+		out[tbl.fields[i].Name] = v
+	}
+	return out, nil
+}
+
+// Schema is the schema of a value, in the format of the JsonConverter.
+type Schema struct {
+	Type     string    `json:"type"`
+	Fields   []*Schema `json:"fields,omitempty"`
+	Optional bool      `json:"optional"`
+	Name     string    `json:"name,omitempty"`
+	Field    string    `json:"field,omitempty"`
+}
+
+var sourceSchema = &Schema{
+	Type: "struct",
+	Name: "io.debezium.connector.vitess.Source",
+	Fields: []*Schema{
+		{Type: "string", Field: "connector"},
+		{Type: "string", Field: "name"},
+		{Type: "int64", Field: "ts_ms"},
+		{Type: "string", Optional: true, Name: "io.debezium.data.Enum", Field: "snapshot"},
+		{Type: "string", Field: "db"},
+		{Type: "string", Field: "keyspace"},
+		{Type: "string", Field: "table"},
+		{Type: "string", Field: "shard"},
+		{Type: "string", Field: "vgtid"},
+	},
+	Field: "source",
+}
+
+// columnSchema returns the schema of a column. The types are mapped like
+// the Debezium MySQL connector does by default.
+func columnSchema(field *querypb.Field) *Schema {
+	s := &Schema{
+		Optional: field.Flags&uint32(querypb.MySqlFlag_NOT_NULL_FLAG) == 0,
+		Field:    field.Name,
+	}
+	switch field.Type {
+	case sqltypes.Int8, sqltypes.Uint8, sqltypes.Int16:
+		s.Type = "int16"
+	case sqltypes.Uint16, sqltypes.Int24, sqltypes.Uint24, sqltypes.Int32:
+		s.Type = "int32"
+	case sqltypes.Uint32, sqltypes.Int64, sqltypes.Uint64:
+		s.Type = "int64"
+	case sqltypes.Float32:
+		s.Type = "float32"
+	case sqltypes.Float64:
+		s.Type = "float64"
+	case sqltypes.Year:
+		s.Type, s.Name = "int32", "io.debezium.time.Year"
+	case sqltypes.Date:
+		s.Type, s.Name = "int32", "io.debezium.time.Date"
+	case sqltypes.Datetime:
+		s.Type, s.Name = "int64", "io.debezium.time.Timestamp"
+	case sqltypes.Timestamp:
+		s.Type, s.Name = "string", "io.debezium.time.ZonedTimestamp"
+	case sqltypes.Time:
+		s.Type, s.Name = "int64", "io.debezium.time.MicroTime"
+	case sqltypes.TypeJSON:
+		s.Type, s.Name = "string", "io.debezium.data.Json"
+	case sqltypes.Enum:
+		s.Type, s.Name = "string", "io.debezium.data.Enum"
+	case sqltypes.Set:
+		s.Type, s.Name = "string", "io.debezium.data.EnumSet"
+	case sqltypes.Bit:
+		s.Type, s.Name = "bytes", "io.debezium.data.Bits"
+	case sqltypes.Binary, sqltypes.VarBinary, sqltypes.Blob, sqltypes.Geometry:
+		s.Type = "bytes"
+	default:
+		// Decimals are strings, like with decimal.handling.mode=string.
+		s.Type = "string"
+	}
+	return s
+}
+
+// columnValue returns the value of a column in the representation that
+// matches its schema.
+func columnValue(field *querypb.Field, v sqltypes.Value) (any, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	switch field.Type {
+	case sqltypes.Int8, sqltypes.Uint8, sqltypes.Int16, sqltypes.Uint16, sqltypes.Int24, sqltypes.Uint24,
+		sqltypes.Int32, sqltypes.Uint32, sqltypes.Int64, sqltypes.Uint64, sqltypes.Float32, sqltypes.Float64, sqltypes.Year:
+		return json.RawMessage(v.Raw()), nil
+	case sqltypes.Date:
+		t, ok, err := parseTime(field, "2006-01-02", v)
+		if !ok || err != nil {
+			return nil, err
+		}
+		return t.Unix() / (24 * 60 * 60), nil
+	case sqltypes.Datetime:
+		t, ok, err := parseTime(field, "2006-01-02 15:04:05.999999", v)
+		if !ok || err != nil {
+			return nil, err
+		}
+		return t.UnixMilli(), nil
+	case sqltypes.Timestamp:
+		t, ok, err := parseTime(field, "2006-01-02 15:04:05.999999", v)
+		if !ok || err != nil {
+			return nil, err
+		}
+		return t.Format(time.RFC3339Nano), nil
+	case sqltypes.Time:
+		return parseMicroTime(field, v)
+	case sqltypes.Bit, sqltypes.Binary, sqltypes.VarBinary, sqltypes.Blob, sqltypes.Geometry:
+		return base64.StdEncoding.EncodeToString(v.Raw()), nil
+	default:
+		return v.ToString(), nil
+	}
+}
+
+// parseTime parses a date or time value in UTC. Zero dates, which can't be
+// represented, are returned as not ok.
+func parseTime(field *querypb.Field, layout string, v sqltypes.Value) (time.Time, bool, error) {
+	s := v.ToString()
+	if strings.HasPrefix(s, "0000-00-00") {
+		return time.Time{}, false, nil
+	}
+	t, err := time.ParseInLocation(layout, s, time.UTC)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid value %q for column %s: %v", s, field.Name, err)
+	}
+	return t, true, nil
+}
+
+// parseMicroTime returns a time value, which has the [-]hhh:mm:ss[.ffffff]
+// format, in microseconds.
+func parseMicroTime(field *querypb.Field, v sqltypes.Value) (int64, error) {
+	s := v.ToString()
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var frac string
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s, frac = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 || len(frac) > 6 {
+		return 0, fmt.Errorf("invalid value %q for column %s", v.ToString(), field.Name)
+	}
+	var micros int64
+	for i, unit := range []int64{3600, 60, 1} {
+		n, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for column %s: %v", v.ToString(), field.Name, err)
+		}
+		micros += n * unit * 1e6
+	}
+	if frac != "" {
+		n, err := strconv.ParseInt(frac+strings.Repeat("0", 6-len(frac)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for column %s: %v", v.ToString(), field.Name, err)
+		}
+		micros += n
+	}
+	if neg {
+		micros = -micros
+	}
+	return micros, nil
+}
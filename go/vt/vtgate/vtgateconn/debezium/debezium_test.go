@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debezium
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var testFields = []*querypb.Field{
+	{Name: "id", Type: sqltypes.Int64, Flags: uint32(querypb.MySqlFlag_PRI_KEY_FLAG | querypb.MySqlFlag_NOT_NULL_FLAG)},
+	{Name: "name", Type: sqltypes.VarChar},
+	{Name: "amount", Type: sqltypes.Decimal},
+	{Name: "created", Type: sqltypes.Datetime},
+	{Name: "day", Type: sqltypes.Date},
+	{Name: "dur", Type: sqltypes.Time},
+	{Name: "data", Type: sqltypes.VarBinary},
+	{Name: "doc", Type: sqltypes.TypeJSON},
+}
+
+func testRow(id int64, name string) *querypb.Row {
+	return sqltypes.RowToProto3([]sqltypes.Value{
+		sqltypes.NewInt64(id),
+		sqltypes.NewVarChar(name),
+		sqltypes.MakeTrusted(sqltypes.Decimal, []byte("12.50")),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2024-01-02 03:04:05.5")),
+		sqltypes.MakeTrusted(sqltypes.Date, []byte("1970-01-11")),
+		sqltypes.MakeTrusted(sqltypes.Time, []byte("-01:00:00.25")),
+		sqltypes.NewVarBinary("\x00\x01"),
+		sqltypes.NULL,
+	})
+}
+
+func testVGtid(gtid string, copying bool) *binlogdatapb.VEvent {
+	sgtid := &binlogdatapb.ShardGtid{Keyspace: "ks", Shard: "-80", Gtid: gtid}
+	if copying {
+		sgtid.TablePKs = []*binlogdatapb.TableLastPK{{TableName: "t1"}}
+	}
+	return &binlogdatapb.VEvent{
+		Type:  binlogdatapb.VEventType_VGTID,
+		Vgtid: &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{sgtid}},
+	}
+}
+
+func testRowEvent(changes ...*binlogdatapb.RowChange) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:        binlogdatapb.VEventType_ROW,
+		Timestamp:   1700000000,
+		CurrentTime: 1700000001 * 1e9,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName:  "ks.t1",
+			Keyspace:   "ks",
+			Shard:      "-80",
+			RowChanges: changes,
+		},
+	}
+}
+
+func payload(t *testing.T, b []byte) map[string]any {
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(b, &out))
+	return out
+}
+
+func TestEncoder(t *testing.T) {
+	enc := NewEncoder(Options{ServerName: "srv"})
+	field := &binlogdatapb.VEvent{
+		Type: binlogdatapb.VEventType_FIELD,
+		FieldEvent: &binlogdatapb.FieldEvent{
+			TableName: "ks.t1",
+			Keyspace:  "ks",
+			Shard:     "-80",
+			Fields:    testFields,
+		},
+	}
+
+	// Rows of the copy phase are snapshot reads.
+	changes, err := enc.Encode([]*binlogdatapb.VEvent{
+		testVGtid("pos1", true),
+		field,
+		testRowEvent(&binlogdatapb.RowChange{After: testRow(1, "a")}),
+	})
+	require.NoError(t, err)
+	require.Empty(t, changes, "rows must wait for the vgtid of their transaction")
+	changes, err = enc.Encode([]*binlogdatapb.VEvent{testVGtid("pos1", true)})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "srv.ks.t1", changes[0].Topic)
+	require.JSONEq(t, `{"id":1}`, string(changes[0].Key))
+	require.JSONEq(t, `{
+		"before": null,
+		"after": {"id": 1, "name": "a", "amount": "12.50", "created": 1704164645500, "day": 10, "dur": -3600250000, "data": "AAE=", "doc": null},
+		"source": {"connector": "vitess", "name": "srv", "ts_ms": 1700000000000, "snapshot": "true", "db": "ks", "keyspace": "ks", "table": "t1", "shard": "-80",
+			"vgtid": "[{\"keyspace\":\"ks\",\"shard\":\"-80\",\"gtid\":\"pos1\"}]"},
+		"op": "r",
+		"ts_ms": 1700000001000
+	}`, string(changes[0].Value))
+
+	// Changes replicated while the table is being copied move the position
+	// forward, and are not snapshot reads.
+	changes, err = enc.Encode([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_BEGIN},
+		testRowEvent(&binlogdatapb.RowChange{Before: testRow(1, "a"), After: testRow(1, "b")}),
+		testVGtid("pos2", true),
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	p := payload(t, changes[0].Value)
+	require.Equal(t, OpUpdate, p["op"])
+	require.Equal(t, "false", p["source"].(map[string]any)["snapshot"])
+
+	changes, err = enc.Encode([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_COPY_COMPLETED, Keyspace: "ks", Shard: "-80"},
+		{Type: binlogdatapb.VEventType_BEGIN},
+		testRowEvent(
+			&binlogdatapb.RowChange{After: testRow(2, "b")},
+			&binlogdatapb.RowChange{Before: testRow(2, "b"), After: testRow(2, "c")},
+			&binlogdatapb.RowChange{Before: testRow(1, "a")},
+		),
+		testVGtid("pos3", false),
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	var ops []string
+	for _, change := range changes {
+		p := payload(t, change.Value)
+		ops = append(ops, p["op"].(string))
+		source := p["source"].(map[string]any)
+		require.Equal(t, "false", source["snapshot"])
+		require.Contains(t, source["vgtid"], "pos3")
+	}
+	require.Equal(t, []string{OpCreate, OpUpdate, OpDelete}, ops)
+	require.JSONEq(t, `{"id":1}`, string(changes[2].Key))
+	p = payload(t, changes[1].Value)
+	require.Equal(t, "b", p["before"].(map[string]any)["name"])
+	require.Equal(t, "c", p["after"].(map[string]any)["name"])
+
+	// Columns missing from partial row images are omitted.
+	changes, err = enc.Encode([]*binlogdatapb.VEvent{
+		testRowEvent(&binlogdatapb.RowChange{
+			After:       testRow(3, "d"),
+			DataColumns: &binlogdatapb.RowChange_Bitmap{Count: 8, Cols: []byte{0x03}},
+		}),
+	})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	changes, err = enc.Flush()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, map[string]any{"id": float64(3), "name": "d"}, payload(t, changes[0].Value)["after"])
+}
+
+func TestEncoderSchema(t *testing.T) {
+	enc := NewEncoder(Options{ServerName: "srv", IncludeSchema: true})
+	changes, err := enc.Encode([]*binlogdatapb.VEvent{
+		{
+			Type: binlogdatapb.VEventType_FIELD,
+			FieldEvent: &binlogdatapb.FieldEvent{
+				TableName: "t1",
+				Keyspace:  "ks",
+				Shard:     "0",
+				Fields:    testFields[:2],
+			},
+		},
+		{
+			Type: binlogdatapb.VEventType_ROW,
+			RowEvent: &binlogdatapb.RowEvent{
+				TableName: "t1",
+				Keyspace:  "ks",
+				Shard:     "0",
+				RowChanges: []*binlogdatapb.RowChange{{
+					After: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")}),
+				}},
+			},
+		},
+		testVGtid("pos", false),
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.JSONEq(t, `{
+		"schema": {"type": "struct", "optional": false, "name": "srv.ks.t1.Key", "fields": [{"type": "int64", "optional": false, "field": "id"}]},
+		"payload": {"id": 1}
+	}`, string(changes[0].Key))
+
+	value := payload(t, changes[0].Value)
+	schema := value["schema"].(map[string]any)
+	require.Equal(t, "srv.ks.t1.Envelope", schema["name"])
+	fields := schema["fields"].([]any)
+	require.Len(t, fields, 5)
+	before := fields[0].(map[string]any)
+	require.Equal(t, "before", before["field"])
+	require.Equal(t, "srv.ks.t1.Value", before["name"])
+	require.Equal(t, []any{
+		map[string]any{"type": "int64", "optional": false, "field": "id"},
+		map[string]any{"type": "string", "optional": true, "field": "name"},
+	}, before["fields"])
+	require.Equal(t, "io.debezium.connector.vitess.Source", fields[2].(map[string]any)["name"])
+	require.Equal(t, "c", value["payload"].(map[string]any)["op"])
+}
+
+func TestEncoderErrors(t *testing.T) {
+	enc := NewEncoder(Options{ServerName: "srv"})
+	_, err := enc.Encode([]*binlogdatapb.VEvent{
+		testRowEvent(&binlogdatapb.RowChange{After: testRow(1, "a")}),
+		testVGtid("pos", false),
+	})
+	require.ErrorContains(t, err, "no fields for table ks.t1 in shard ks/-80")
+}
+
+type fakeReader struct {
+	batches [][]*binlogdatapb.VEvent
+}
+
+func (fr *fakeReader) Recv() ([]*binlogdatapb.VEvent, error) {
+	if len(fr.batches) == 0 {
+		return nil, io.EOF
+	}
+	batch := fr.batches[0]
+	fr.batches = fr.batches[1:]
+	return batch, nil
+}
+
+func TestReader(t *testing.T) {
+	reader := NewReader(&fakeReader{batches: [][]*binlogdatapb.VEvent{
+		{{
+			Type: binlogdatapb.VEventType_FIELD,
+			FieldEvent: &binlogdatapb.FieldEvent{
+				TableName: "ks.t1",
+				Keyspace:  "ks",
+				Shard:     "-80",
+				Fields:    testFields,
+			},
+		}},
+		{{Type: binlogdatapb.VEventType_HEARTBEAT}},
+		{testRowEvent(&binlogdatapb.RowChange{After: testRow(1, "a")}), testVGtid("pos", false)},
+	}}, Options{ServerName: "srv"})
+	changes, err := reader.Recv()
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	_, err = reader.Recv()
+	require.Equal(t, io.EOF, err)
+}
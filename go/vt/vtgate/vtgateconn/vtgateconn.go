@@ -108,6 +108,25 @@ func (conn *VTGateConn) VStream(ctx context.Context, tabletType topodatapb.Table
 	return conn.impl.VStream(ctx, tabletType, vgtid, filter, flags)
 }
 
+// VStreamAck acknowledges that the events of a VStream subscription were
+// processed up to vgtid. The streams started with the subscription name in
+// their flags resume from the last acknowledged vgtid, even on another vtgate.
+func (conn *VTGateConn) VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error {
+	return conn.impl.VStreamAck(ctx, subscriptionName, vgtid)
+}
+
+// VStreamSubscriptions returns the last acknowledged positions of the VStream
+// subscriptions, keyed by their names.
+func (conn *VTGateConn) VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error) {
+	return conn.impl.VStreamSubscriptions(ctx)
+}
+
+// DeleteVStreamSubscription deletes a VStream subscription. Its streams then
+// start from their requested positions again.
+func (conn *VTGateConn) DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error {
+	return conn.impl.DeleteVStreamSubscription(ctx, subscriptionName)
+}
+
 // VTGateSession exposes the Vitess Execution API to the clients.
 // The object maintains client-side state and is comparable to a native MySQL connection.
 // For example, if you enable autocommit on a Session object, all subsequent calls will respect this.
@@ -209,6 +228,15 @@ type Impl interface {
 	// VStream streams binlogevents
 	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (VStreamReader, error)
 
+	// VStreamAck stores the position processed by the client of a VStream subscription.
+	VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error
+
+	// VStreamSubscriptions returns the acknowledged positions of the VStream subscriptions.
+	VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error)
+
+	// DeleteVStreamSubscription deletes a VStream subscription.
+	DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error
+
 	// Close must be called for releasing resources.
 	Close()
 }
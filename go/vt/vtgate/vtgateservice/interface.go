@@ -51,6 +51,17 @@ type VTGateService interface {
 	// Update Stream methods
 	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error
 
+	// VStreamAck stores the position processed by the client of a VStream
+	// subscription, from which the streams of the subscription resume.
+	VStreamAck(ctx context.Context, subscriptionName string, vgtid *binlogdatapb.VGtid) error
+
+	// VStreamSubscriptions returns the acknowledged positions of the VStream
+	// subscriptions, keyed by their names.
+	VStreamSubscriptions(ctx context.Context) (map[string]*binlogdatapb.VGtid, error)
+
+	// DeleteVStreamSubscription deletes a VStream subscription.
+	DeleteVStreamSubscription(ctx context.Context, subscriptionName string) error
+
 	// HandlePanic should be called with defer at the beginning of each
 	// RPC implementation method, before calling any of the previous methods
 	HandlePanic(err *error)
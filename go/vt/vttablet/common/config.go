@@ -49,8 +49,24 @@ type VReplicationConfig struct {
 	HeartbeatUpdateInterval int
 	StoreCompressedGTID     bool
 	ParallelInsertWorkers   int
+	ParallelApplyWorkers    int
 	TabletTypesStr          string
 	EnableHttpLog           bool // Enable the /debug/vrlog endpoint
+	// Sink is the URL of the sink that the row changes are written to instead of the
+	// target tables, e.g. file:///var/vt/cdc or kafka://broker:9092. Other sinks can be
+	// added with RegisterSink.
+	// It's empty when the row changes are written to the target tables.
+	Sink string
+	// Bidirectional is set for the workflows which replicate in both directions between
+	// two keyspaces, e.g. during the cutover window of a Migrate. The changes applied by
+	// the workflow are tagged in the binary log, so that the workflow of the opposite
+	// direction does not apply them back, and conflicting changes are logged.
+	Bidirectional bool
+	// EvaluateFilterExpressions is set for the workflows whose filter expressions are
+	// evaluated by the evalengine for every row, rather than by the target MySQL in the
+	// generated queries. Non-deterministic expressions are still evaluated by MySQL.
+	// The filter expressions of the workflows that use a Sink are always evaluated.
+	EvaluateFilterExpressions bool
 
 	// Config parameters applicable to the source side (vstreamer)
 	// The coresponding Override fields are used to determine if the user has provided a value for the parameter so
@@ -94,6 +110,7 @@ func GetVReplicationConfigDefaults(useCached bool) *VReplicationConfig {
 		HeartbeatUpdateInterval: vreplicationHeartbeatUpdateInterval,
 		StoreCompressedGTID:     vreplicationStoreCompressedGTID,
 		ParallelInsertWorkers:   vreplicationParallelInsertWorkers,
+		ParallelApplyWorkers:    vreplicationParallelApplyWorkers,
 		TabletTypesStr:          vreplicationTabletTypesStr,
 		EnableHttpLog:           vreplicationEnableHttpLog,
 
@@ -220,6 +237,29 @@ func NewVReplicationConfig(overrides map[string]string) (*VReplicationConfig, er
 			} else {
 				c.ParallelInsertWorkers = value
 			}
+		case "vreplication-parallel-apply-workers":
+			value, err := strconv.Atoi(v)
+			if err != nil {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.ParallelApplyWorkers = value
+			}
+		case "vreplication-sink":
+			c.Sink = v
+		case "vreplication-bidirectional":
+			value, err := strconv.ParseBool(v)
+			if err != nil {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.Bidirectional = value
+			}
+		case "vreplication-evaluate-filter-expressions":
+			value, err := strconv.ParseBool(v)
+			if err != nil {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.EvaluateFilterExpressions = value
+			}
 		case "vstream-packet-size", "vstream_packet_size":
 			value, err := strconv.Atoi(v)
 			if err != nil {
@@ -259,25 +299,29 @@ func NewVReplicationConfig(overrides map[string]string) (*VReplicationConfig, er
 // keys are one of those that are supported.
 func (c VReplicationConfig) Map() map[string]string {
 	return map[string]string{
-		"vreplication-experimental-flags":         strconv.FormatInt(c.ExperimentalFlags, 10),
-		"vreplication-net-read-timeout":           strconv.Itoa(c.NetReadTimeout),
-		"vreplication-net-write-timeout":          strconv.Itoa(c.NetWriteTimeout),
-		"vreplication-copy-phase-duration":        c.CopyPhaseDuration.String(),
-		"vreplication-retry-delay":                c.RetryDelay.String(),
-		"vreplication-max-time-to-retry-on-error": c.MaxTimeToRetryError.String(),
-		"relay-log-max-size":                      strconv.Itoa(c.RelayLogMaxSize),
-		"relay_log_max_size":                      strconv.Itoa(c.RelayLogMaxSize),
-		"relay-log-max-items":                     strconv.Itoa(c.RelayLogMaxItems),
-		"relay_log_max_items":                     strconv.Itoa(c.RelayLogMaxItems),
-		"vreplication-replica-lag-tolerance":      c.ReplicaLagTolerance.String(),
-		"vreplication-heartbeat-update-interval":  strconv.Itoa(c.HeartbeatUpdateInterval),
-		"vreplication-store-compressed-gtid":      strconv.FormatBool(c.StoreCompressedGTID),
-		"vreplication-parallel-insert-workers":    strconv.Itoa(c.ParallelInsertWorkers),
-		"vstream-packet-size":                     strconv.Itoa(c.VStreamPacketSize),
-		"vstream_packet_size":                     strconv.Itoa(c.VStreamPacketSize),
-		"vstream-dynamic-packet-size":             strconv.FormatBool(c.VStreamDynamicPacketSize),
-		"vstream_dynamic_packet_size":             strconv.FormatBool(c.VStreamDynamicPacketSize),
-		"vstream_binlog_rotation_threshold":       strconv.FormatInt(c.VStreamBinlogRotationThreshold, 10),
+		"vreplication-experimental-flags":          strconv.FormatInt(c.ExperimentalFlags, 10),
+		"vreplication-net-read-timeout":            strconv.Itoa(c.NetReadTimeout),
+		"vreplication-net-write-timeout":           strconv.Itoa(c.NetWriteTimeout),
+		"vreplication-copy-phase-duration":         c.CopyPhaseDuration.String(),
+		"vreplication-retry-delay":                 c.RetryDelay.String(),
+		"vreplication-max-time-to-retry-on-error":  c.MaxTimeToRetryError.String(),
+		"relay-log-max-size":                       strconv.Itoa(c.RelayLogMaxSize),
+		"relay_log_max_size":                       strconv.Itoa(c.RelayLogMaxSize),
+		"relay-log-max-items":                      strconv.Itoa(c.RelayLogMaxItems),
+		"relay_log_max_items":                      strconv.Itoa(c.RelayLogMaxItems),
+		"vreplication-replica-lag-tolerance":       c.ReplicaLagTolerance.String(),
+		"vreplication-heartbeat-update-interval":   strconv.Itoa(c.HeartbeatUpdateInterval),
+		"vreplication-store-compressed-gtid":       strconv.FormatBool(c.StoreCompressedGTID),
+		"vreplication-parallel-insert-workers":     strconv.Itoa(c.ParallelInsertWorkers),
+		"vreplication-parallel-apply-workers":      strconv.Itoa(c.ParallelApplyWorkers),
+		"vreplication-sink":                        c.Sink,
+		"vreplication-bidirectional":               strconv.FormatBool(c.Bidirectional),
+		"vreplication-evaluate-filter-expressions": strconv.FormatBool(c.EvaluateFilterExpressions),
+		"vstream-packet-size":                      strconv.Itoa(c.VStreamPacketSize),
+		"vstream_packet_size":                      strconv.Itoa(c.VStreamPacketSize),
+		"vstream-dynamic-packet-size":              strconv.FormatBool(c.VStreamDynamicPacketSize),
+		"vstream_dynamic_packet_size":              strconv.FormatBool(c.VStreamDynamicPacketSize),
+		"vstream_binlog_rotation_threshold":        strconv.FormatInt(c.VStreamBinlogRotationThreshold, 10),
 	}
 }
 
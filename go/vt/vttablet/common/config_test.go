@@ -50,6 +50,10 @@ func TestNewVReplicationConfig(t *testing.T) {
 				"vreplication-heartbeat-update-interval":            "2",
 				"vreplication-store-compressed-gtid":                "true",
 				"vreplication-parallel-insert-workers":              "4",
+				"vreplication-parallel-apply-workers":               "8",
+				"vreplication-sink":                                 "file:///tmp/cdc",
+				"vreplication-bidirectional":                        "true",
+				"vreplication-evaluate-filter-expressions":          "true",
 				"vstream-packet-size":                               "1024",
 				"vstream_packet_size":                               "1024",
 				"vstream-dynamic-packet-size":                       "false",
@@ -70,6 +74,10 @@ func TestNewVReplicationConfig(t *testing.T) {
 				HeartbeatUpdateInterval:                2,
 				StoreCompressedGTID:                    true,
 				ParallelInsertWorkers:                  4,
+				ParallelApplyWorkers:                   8,
+				Sink:                                   "file:///tmp/cdc",
+				Bidirectional:                          true,
+				EvaluateFilterExpressions:              true,
 				VStreamPacketSize:                      1024,
 				VStreamDynamicPacketSize:               false,
 				VStreamBinlogRotationThreshold:         2048,
@@ -94,13 +102,14 @@ func TestNewVReplicationConfig(t *testing.T) {
 				"vreplication-heartbeat-update-interval":            "invalid",
 				"vreplication-store-compressed-gtid":                "nottrue",
 				"vreplication-parallel-insert-workers":              "invalid",
+				"vreplication-parallel-apply-workers":               "invalid",
 				"vstream-packet-size":                               "invalid",
 				"vstream_packet_size":                               "invalid",
 				"vstream-dynamic-packet-size":                       "waar",
 				"vstream_dynamic_packet_size":                       "waar",
 				"vstream_binlog_rotation_threshold":                 "invalid",
 			},
-			wantErr: 17,
+			wantErr: 18,
 		},
 		{
 			name: "Partial values",
@@ -124,6 +133,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				HeartbeatUpdateInterval:          DefaultVReplicationConfig.HeartbeatUpdateInterval,
 				StoreCompressedGTID:              !DefaultVReplicationConfig.StoreCompressedGTID,
 				ParallelInsertWorkers:            DefaultVReplicationConfig.ParallelInsertWorkers,
+				ParallelApplyWorkers:             DefaultVReplicationConfig.ParallelApplyWorkers,
 				VStreamPacketSize:                DefaultVReplicationConfig.VStreamPacketSize,
 				VStreamDynamicPacketSize:         !DefaultVReplicationConfig.VStreamDynamicPacketSize,
 				VStreamBinlogRotationThreshold:   DefaultVReplicationConfig.VStreamBinlogRotationThreshold,
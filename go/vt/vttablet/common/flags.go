@@ -52,6 +52,7 @@ var (
 
 	vreplicationStoreCompressedGTID   = false
 	vreplicationParallelInsertWorkers = 1
+	vreplicationParallelApplyWorkers  = 1
 
 	// VStreamerBinlogRotationThreshold is the threshold, above which we rotate binlogs, before taking a GTID snapshot
 	VStreamerBinlogRotationThreshold = int64(64 * 1024 * 1024) // 64MiB
@@ -100,6 +101,8 @@ func registerFlags(fs *pflag.FlagSet) {
 
 	fs.IntVar(&vreplicationParallelInsertWorkers, "vreplication-parallel-insert-workers", vreplicationParallelInsertWorkers, "Number of parallel insertion workers to use during copy phase. Set <= 1 to disable parallelism, or > 1 to enable concurrent insertion during copy phase.")
 
+	fs.IntVar(&vreplicationParallelApplyWorkers, "vreplication-parallel-apply-workers", vreplicationParallelApplyWorkers, "Number of parallel workers to use for applying transactions in the running phase. Transactions which change different rows are applied concurrently and committed in source order. Set <= 1 to disable parallelism.")
+
 	fs.Uint64Var(&mysql.ZstdInMemoryDecompressorMaxSize, "binlog-in-memory-decompressor-max-size", mysql.ZstdInMemoryDecompressorMaxSize, "This value sets the uncompressed transaction payload size at which we switch from in-memory buffer based decompression to the slower streaming mode.")
 
 	fs.BoolVar(&vreplicationEnableHttpLog, "vreplication-enable-http-log", vreplicationEnableHttpLog, "Enable the /debug/vrlog HTTP endpoint, which will produce a log of the events replicated on primary tablets in the target keyspace by all VReplication workflows that are in the running/replicating phase.")
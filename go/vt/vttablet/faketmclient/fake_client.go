@@ -422,6 +422,16 @@ func (client *FakeTabletManagerClient) RestoreFromBackup(ctx context.Context, ta
 	return &eofEventStream{}, nil
 }
 
+// VerifyBackup is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	return &tabletmanagerdatapb.VerifyBackupResponse{}, nil
+}
+
+// CloneFrom is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) CloneFrom(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CloneFromRequest) (logutil.EventStream, error) {
+	return &eofEventStream{}, nil
+}
+
 // Throttler related methods
 
 func (client *FakeTabletManagerClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
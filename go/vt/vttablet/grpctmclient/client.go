@@ -1516,6 +1516,53 @@ func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.
 	}, nil
 }
 
+// VerifyBackup is part of the tmclient.TabletManagerClient interface.
+func (client *Client) VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	response, err := c.VerifyBackup(ctx, req)
+	if err != nil {
+		return nil, vterrors.FromGRPC(err)
+	}
+	return response, nil
+}
+
+type cloneFromStreamAdapter struct {
+	stream tabletmanagerservicepb.TabletManager_CloneFromClient
+	closer io.Closer
+}
+
+func (e *cloneFromStreamAdapter) Recv() (*logutilpb.Event, error) {
+	br, err := e.stream.Recv()
+	if err != nil {
+		e.closer.Close()
+		return nil, vterrors.FromGRPC(err)
+	}
+	return br.Event, nil
+}
+
+// CloneFrom is part of the tmclient.TabletManagerClient interface.
+func (client *Client) CloneFrom(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CloneFromRequest) (logutil.EventStream, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.CloneFrom(ctx, req)
+	if err != nil {
+		closer.Close()
+		return nil, vterrors.FromGRPC(err)
+	}
+	return &cloneFromStreamAdapter{
+		stream: stream,
+		closer: closer,
+	}, nil
+}
+
 // Close is part of the tmclient.TabletManagerClient interface.
 func (client *Client) Close() {
 	client.dialer.Close()
@@ -709,6 +709,30 @@ func (s *server) RestoreFromBackup(request *tabletmanagerdatapb.RestoreFromBacku
 	return s.tm.RestoreFromBackup(ctx, logger, request)
 }
 
+func (s *server) VerifyBackup(ctx context.Context, request *tabletmanagerdatapb.VerifyBackupRequest) (response *tabletmanagerdatapb.VerifyBackupResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "VerifyBackup", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	return s.tm.VerifyBackup(ctx, request)
+}
+
+func (s *server) CloneFrom(request *tabletmanagerdatapb.CloneFromRequest, stream tabletmanagerservicepb.TabletManager_CloneFromServer) (err error) {
+	ctx := stream.Context()
+	defer s.tm.HandleRPCPanic(ctx, "CloneFrom", request, nil, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+
+	// create a logger, send the result back to the caller
+	logger := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
+		// If the client disconnects, we will just fail
+		// to send the log events, but won't interrupt
+		// the clone.
+		stream.Send(&tabletmanagerdatapb.CloneFromResponse{
+			Event: e,
+		})
+	})
+
+	return s.tm.CloneFrom(ctx, logger, request)
+}
+
 func (s *server) CheckThrottler(ctx context.Context, request *tabletmanagerdatapb.CheckThrottlerRequest) (response *tabletmanagerdatapb.CheckThrottlerResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "CheckThrottler", request, response, false /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
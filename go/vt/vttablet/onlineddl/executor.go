@@ -60,6 +60,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
@@ -90,6 +91,9 @@ var (
 	migrationCheckInterval  = 1 * time.Minute
 	retainOnlineDDLTables   = 24 * time.Hour
 	maxConcurrentOnlineDDLs = 256
+	// partitionManagementInterval is the interval between applications of table partition policies.
+	// Zero disables partition management.
+	partitionManagementInterval time.Duration
 
 	migrationNextCheckIntervals = []time.Duration{1 * time.Second, 5 * time.Second, 10 * time.Second, 20 * time.Second}
 	cutoverIntervals            = []time.Duration{0, 1 * time.Minute, 5 * time.Minute, 10 * time.Minute, 30 * time.Minute}
@@ -110,6 +114,7 @@ func registerOnlineDDLFlags(fs *pflag.FlagSet) {
 	utils.SetFlagDurationVar(fs, &migrationCheckInterval, "migration-check-interval", migrationCheckInterval, "Interval between migration checks")
 	utils.SetFlagDurationVar(fs, &retainOnlineDDLTables, "retain-online-ddl-tables", retainOnlineDDLTables, "How long should vttablet keep an old migrated table before purging it")
 	utils.SetFlagIntVar(fs, &maxConcurrentOnlineDDLs, "max-concurrent-online-ddl", maxConcurrentOnlineDDLs, "Maximum number of online DDL changes that may run concurrently")
+	utils.SetFlagDurationVar(fs, &partitionManagementInterval, "partition-management-interval", partitionManagementInterval, "Interval between applications of the partition policies declared in table comments, which submit migrations to add and drop range partitions. 0 disables partition management")
 }
 
 const (
@@ -158,6 +163,9 @@ type Executor struct {
 	tickReentranceFlag            int64
 	reviewedRunningMigrationsFlag bool
 
+	partitionsThrottlerClient *throttle.Client
+	lastPartitionManagement   time.Time
+
 	ticks  *timer.Timer
 	isOpen int64
 
@@ -224,6 +232,9 @@ func NewExecutor(env tabletenv.Env, tabletAlias *topodatapb.TabletAlias, ts *top
 		isPreparedPoolEmpty:   isPreparedPoolEmpty,
 		requestGCChecksFunc:   requestGCChecksFunc,
 		ticks:                 timer.NewTimer(migrationCheckInterval),
+
+		partitionsThrottlerClient: throttle.NewBackgroundClient(lagThrottler, throttlerapp.OnlineDDLName.Concatenate(throttlerapp.PartitionManagementName), base.UndefinedScope),
+
 		// Gracefully return an error if any caller tries to execute
 		// a query before the executor has been fully opened.
 		execQuery: func(ctx context.Context, query string) (result *sqltypes.Result, err error) {
@@ -3670,6 +3681,9 @@ func (e *Executor) onMigrationCheckTick() {
 	if err := e.gcArtifacts(ctx); err != nil {
 		log.Error(fmt.Sprint(err))
 	}
+	if err := e.managePartitions(ctx); err != nil {
+		log.Error(fmt.Sprint(err))
+	}
 }
 
 func (e *Executor) updateMigrationStartedTimestamp(ctx context.Context, uuid string) error {
@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/dbconnpool"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -263,6 +264,20 @@ func TestGetInOrderCompletionPendingCount(t *testing.T) {
 	}
 }
 
+func TestPartitionManagementMigrationUUID(t *testing.T) {
+	sql := "ALTER TABLE `t` ADD PARTITION (PARTITION `p20240112` VALUES LESS THAN ('2024-01-13'))"
+	u := partitionManagementMigrationUUID("ks", "t", sql)
+	assert.True(t, schema.IsOnlineDDLUUID(u))
+	assert.Equal(t, u, partitionManagementMigrationUUID("ks", "t", sql))
+	assert.NotEqual(t, u, partitionManagementMigrationUUID("ks2", "t", sql))
+	assert.NotEqual(t, u, partitionManagementMigrationUUID("ks", "t", "ALTER TABLE `t` DROP PARTITION `p20240101`"))
+
+	onlineDDL, err := schema.NewOnlineDDL("ks", "t", sql, schema.NewDDLStrategySetting(schema.DDLStrategyVitess, ""), partitionManagementMigrationContext, u, sqlparser.NewTestParser())
+	require.NoError(t, err)
+	assert.Equal(t, u, onlineDDL.UUID)
+	assert.Equal(t, partitionManagementMigrationContext, onlineDDL.MigrationContext)
+}
+
 func TestInitDBConnectionLockWaitTimeout(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// partitionManagementMigrationContext is the migration context of migrations submitted by partition management.
+const partitionManagementMigrationContext = "vitess:partition-management"
+
+// partitionManagementUUIDNamespace is the namespace of the name based UUIDs of partition management migrations.
+var partitionManagementUUIDNamespace = uuid.MustParse("4d1b8c5e-5a0e-4f8b-9a7e-3c2d1f0e6b9a")
+
+// partitionManagementMigrationUUID returns the UUID of the migration which runs the given partition ALTER.
+// The UUID is derived from the statement, so that submitting the same ALTER on consecutive checks, while
+// a previous submission is still pending, is idempotent.
+func partitionManagementMigrationUUID(keyspace string, table string, sql string) string {
+	u := uuid.NewSHA1(partitionManagementUUIDNamespace, []byte(keyspace+"."+table+":"+sql))
+	return strings.ReplaceAll(u.String(), "-", "_")
+}
+
+// managePartitions applies the partition policies declared in table comments, e.g.
+// `COMMENT 'vitess_partition_policy(interval=day, ahead=7, retention=30)'`. For each such table, it
+// submits migrations which add the partitions due ahead of time, and drop the expired partitions.
+// These are range partition rotations, which the executor runs directly and without copying data.
+// This function runs at most once per --partition-management-interval, and backs off while the
+// throttler is not satisfied.
+func (e *Executor) managePartitions(ctx context.Context) error {
+	if partitionManagementInterval <= 0 {
+		return nil
+	}
+	if time.Since(e.lastPartitionManagement) < partitionManagementInterval {
+		return nil
+	}
+	if _, ok := e.partitionsThrottlerClient.ThrottleCheckOK(ctx, ""); !ok {
+		// Try again on the next tick.
+		return nil
+	}
+	e.lastPartitionManagement = time.Now()
+
+	query, err := sqlparser.ParseAndBind(selSelectPartitionPolicyTables,
+		sqltypes.StringBindVariable(e.dbName),
+		sqltypes.StringBindVariable("%partitioned%"),
+		sqltypes.StringBindVariable("%vitess_partition_policy%"),
+	)
+	if err != nil {
+		return err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, row := range r.Named().Rows {
+		table := row.AsString("table_name", "")
+		if err := e.applyPartitionPolicy(ctx, table, now); err != nil {
+			log.Error(fmt.Sprintf("Executor.managePartitions: table=%s, error=%v", table, err))
+		}
+	}
+	return nil
+}
+
+// applyPartitionPolicy submits the migrations which apply the partition policy of the given table.
+func (e *Executor) applyPartitionPolicy(ctx context.Context, table string, now time.Time) error {
+	createTable, err := e.getCreateTableStatement(ctx, table)
+	if err != nil {
+		return err
+	}
+	alters, err := schemadiff.PartitionPolicyAlters(createTable, now)
+	if err != nil {
+		return err
+	}
+	parser := e.env.Environment().Parser()
+	for _, alter := range alters {
+		sql := sqlparser.CanonicalString(alter)
+		onlineDDL, err := schema.NewOnlineDDL(e.keyspace, table, sql,
+			schema.NewDDLStrategySetting(schema.DDLStrategyVitess, ""),
+			partitionManagementMigrationContext,
+			partitionManagementMigrationUUID(e.keyspace, table, sql),
+			parser,
+		)
+		if err != nil {
+			return err
+		}
+		stmt, err := parser.Parse(onlineDDL.SQL)
+		if err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Executor.applyPartitionPolicy: submitting migration %s: %s", onlineDDL.UUID, sql))
+		if _, err := e.SubmitMigration(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
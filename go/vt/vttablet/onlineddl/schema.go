@@ -498,6 +498,15 @@ const (
 			TABLE_SCHEMA=%a AND TABLE_NAME=%a
 			AND REFERENCED_TABLE_NAME IS NOT NULL
 		`
+	selSelectPartitionPolicyTables = `
+		SELECT
+			TABLE_NAME as table_name
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE
+			TABLE_SCHEMA=%a
+			AND CREATE_OPTIONS LIKE %a
+			AND TABLE_COMMENT LIKE %a
+		`
 	sqlShowTablesLike                      = "SHOW TABLES LIKE '%a'"
 	sqlDropTable                           = "DROP TABLE `%a`"
 	sqlDropTableIfExists                   = "DROP TABLE IF EXISTS `%a`"
@@ -175,6 +175,8 @@ func (tm *TabletManager) restoreBackupLocked(ctx context.Context, logger logutil
 		Stats:                backupstats.RestoreStats(),
 		MysqlShutdownTimeout: mysqlShutdownTimeout,
 		AllowedBackupEngines: request.AllowedBackupEngines,
+		TopoServer:           tm.TopoServer,
+		TabletAlias:          topoproto.TabletAliasString(tablet.Alias),
 	}
 	restoreToTimestamp := protoutil.TimeFromProto(request.RestoreToTimestamp).UTC()
 	if request.RestoreToPos != "" && !restoreToTimestamp.IsZero() {
@@ -310,6 +312,21 @@ func (tm *TabletManager) restoreFromCloneLocked(
 	ctx context.Context,
 	logger logutil.Logger,
 	deleteBeforeRestore bool,
+) error {
+	return tm.cloneLocked(ctx, logger, deleteBeforeRestore, func() (replication.Position, error) {
+		tablet := tm.Tablet()
+		return mysqlctl.CloneFromDonor(ctx, tm.TopoServer, tm.MysqlDaemon, tablet.Keyspace, tablet.Shard)
+	})
+}
+
+// cloneLocked replaces the data of the tablet using the given clone function,
+// which returns the position of the cloned data, then starts replication from
+// that position. The tablet is of type RESTORE while the clone runs.
+func (tm *TabletManager) cloneLocked(
+	ctx context.Context,
+	logger logutil.Logger,
+	deleteBeforeRestore bool,
+	clone func() (replication.Position, error),
 ) error {
 	rsm := tm.newRestoreStateManager(logger, deleteBeforeRestore)
 
@@ -322,8 +339,7 @@ func (tm *TabletManager) restoreFromCloneLocked(
 		return nil
 	}
 
-	tablet := tm.Tablet()
-	pos, err := mysqlctl.CloneFromDonor(ctx, tm.TopoServer, tm.MysqlDaemon, tablet.Keyspace, tablet.Shard)
+	pos, err := clone()
 	if err != nil {
 		err = vterrors.Wrap(err, "failed to clone from donor")
 		if err := rsm.abort(); err != nil {
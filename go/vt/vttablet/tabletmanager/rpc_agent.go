@@ -170,6 +170,10 @@ type RPCTM interface {
 
 	RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest) error
 
+	VerifyBackup(ctx context.Context, request *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error)
+
+	CloneFrom(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.CloneFromRequest) error
+
 	IsBackupRunning() bool
 
 	// HandleRPCPanic is to be called in a defer statement in each
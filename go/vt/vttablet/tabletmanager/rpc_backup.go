@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"time"
 
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/topotools"
@@ -35,6 +36,7 @@ import (
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 const (
@@ -215,6 +217,124 @@ func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.L
 	return err
 }
 
+// CloneFrom deletes all local data and replaces it with a MySQL CLONE of the
+// mysqld of the donor tablet. The tablet is of type RESTORE, and so not serving,
+// while the clone runs. Once it is done, replication is started from the
+// position of the clone and the tablet goes back to its previous type.
+func (tm *TabletManager) CloneFrom(ctx context.Context, logger logutil.Logger, req *tabletmanagerdatapb.CloneFromRequest) error {
+	if req.DonorAlias == nil {
+		return vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "a donor tablet alias is required")
+	}
+
+	if err := tm.lock(ctx); err != nil {
+		return err
+	}
+	defer tm.unlock()
+
+	tablet, err := tm.TopoServer.GetTablet(ctx, tm.tabletAlias)
+	if err != nil {
+		return err
+	}
+	if tablet.Type == topodatapb.TabletType_PRIMARY {
+		return errors.New("type PRIMARY cannot be cloned into, if you really need to do this, restart vttablet in replica mode")
+	}
+	if topoproto.TabletAliasEqual(req.DonorAlias, tablet.Alias) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tablet %v cannot clone from itself", topoproto.TabletAliasString(tablet.Alias))
+	}
+	donor, err := tm.TopoServer.GetTablet(ctx, req.DonorAlias)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to get donor tablet %v", topoproto.TabletAliasString(req.DonorAlias))
+	}
+	if donor.Keyspace != tablet.Keyspace || donor.Shard != tablet.Shard {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "donor tablet %v is in shard %v/%v, not in shard %v/%v of the tablet",
+			topoproto.TabletAliasString(donor.Alias), donor.Keyspace, donor.Shard, tablet.Keyspace, tablet.Shard)
+	}
+
+	// Create the logger: tee to console and source.
+	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
+	l.Infof("Cloning tablet %v from donor %v", topoproto.TabletAliasString(tablet.Alias), topoproto.TabletAliasString(donor.Alias))
+
+	startTime := time.Now()
+	err = tm.cloneLocked(ctx, l, true /* deleteBeforeRestore */, func() (replication.Position, error) {
+		return mysqlctl.CloneFromTablet(ctx, tm.MysqlDaemon, donor.Tablet, l)
+	})
+	tm.invokeRestoreDoneHook(startTime, err, "")
+
+	// Re-run health check to be sure to capture any replication delay.
+	tm.QueryServiceControl.BroadcastHealth()
+
+	return err
+}
+
+// VerifyBackup restores a backup of the shard of the tablet into a scratch mysqld
+// and checks its tables. The mysqld of the tablet is left alone, but the scratch
+// mysqld competes with it for disk, memory and CPU, so only SPARE and BACKUP
+// tablets verify backups. The verification can take hours, so it does not take
+// the action lock, and only one runs at a time on a tablet.
+func (tm *TabletManager) VerifyBackup(ctx context.Context, req *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	if req.BackupName == "" {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "a backup name is required")
+	}
+
+	if err := tm.beginVerifyBackup(); err != nil {
+		return nil, err
+	}
+	defer tm.endVerifyBackup()
+
+	tablet := tm.Tablet()
+	switch tablet.Type {
+	case topodatapb.TabletType_SPARE, topodatapb.TabletType_BACKUP:
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "type %v cannot verify backups, only SPARE and BACKUP tablets can", tablet.Type)
+	}
+	keyspace := tablet.Keyspace
+	keyspaceInfo, err := tm.TopoServer.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	// Backups of a SNAPSHOT keyspace are the ones of its BaseKeyspace.
+	if keyspaceInfo.KeyspaceType == topodatapb.KeyspaceType_SNAPSHOT && keyspaceInfo.BaseKeyspace != "" {
+		keyspace = keyspaceInfo.BaseKeyspace
+	}
+	concurrency := int(req.Concurrency)
+	if concurrency <= 0 {
+		concurrency = restoreConcurrency
+	}
+
+	result, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyBackupParams{
+		Logger:               logutil.NewConsoleLogger(),
+		Keyspace:             keyspace,
+		Shard:                tablet.Shard,
+		BackupName:           req.BackupName,
+		DbName:               topoproto.TabletDbName(tablet),
+		Concurrency:          concurrency,
+		DBConfigs:            tm.DBConfigs,
+		CollationEnv:         tm.Env.CollationEnv(),
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		Stats:                backupstats.VerifyStats(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.VerifyBackupResponse{Result: result}, nil
+}
+
+func (tm *TabletManager) beginVerifyBackup() error {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	if tm._isVerifyBackupRunning {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "a backup verification is already running on tablet: %v", tm.tabletAlias)
+	}
+	tm._isVerifyBackupRunning = true
+	return nil
+}
+
+func (tm *TabletManager) endVerifyBackup() {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm._isVerifyBackupRunning = false
+}
+
 func (tm *TabletManager) IsBackupRunning() bool {
 	return tm._isBackupRunning
 }
@@ -52,3 +52,13 @@ func TestShutdownTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestBeginVerifyBackup(t *testing.T) {
+	tm := &TabletManager{}
+	assert.NoError(t, tm.beginVerifyBackup())
+	// Only one verification runs at a time.
+	assert.ErrorContains(t, tm.beginVerifyBackup(), "a backup verification is already running")
+	tm.endVerifyBackup()
+	assert.NoError(t, tm.beginVerifyBackup())
+	tm.endVerifyBackup()
+}
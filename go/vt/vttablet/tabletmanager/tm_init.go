@@ -226,6 +226,9 @@ type TabletManager struct {
 	_lockTablesTimer      *time.Timer
 	// _isBackupRunning tells us whether there is a backup that is currently running
 	_isBackupRunning bool
+	// _isVerifyBackupRunning tells us whether there is a backup verification that
+	// is currently running
+	_isVerifyBackupRunning bool
 }
 
 // BuildTabletFromInput builds a tablet record from input parameters.
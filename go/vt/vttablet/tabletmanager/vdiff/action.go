@@ -46,6 +46,7 @@ const (
 	StopAction    VDiffAction = "stop"
 	ResumeAction  VDiffAction = "resume"
 	DeleteAction  VDiffAction = "delete"
+	RepairAction  VDiffAction = "repair" // Only supported by vtctldclient
 	AllActionArg              = "all"
 	LastActionArg             = "last"
 
@@ -109,6 +110,10 @@ func (vde *Engine) PerformVDiffAction(ctx context.Context, req *tabletmanagerdat
 		if err := vde.handleDeleteAction(ctx, dbClient, req, resp); err != nil {
 			return nil, err
 		}
+	case RepairAction:
+		if err := vde.handleRepairAction(ctx, dbClient, req, resp); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("action %s not supported", action)
 	}
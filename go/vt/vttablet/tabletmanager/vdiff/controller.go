@@ -197,6 +197,32 @@ func (ct *controller) start(ctx context.Context, dbClient binlogplayer.DBClient)
 		return ErrVDiffStoppedByUser
 	default:
 	}
+	if err := ct.loadSources(ctx, dbClient); err != nil {
+		return err
+	}
+
+	if err := ct.validate(); err != nil {
+		return err
+	}
+
+	wd, err := newWorkflowDiffer(ct, ct.options, ct.vde.collationEnv)
+	if err != nil {
+		return err
+	}
+	if err := ct.updateState(dbClient, StartedState, nil); err != nil {
+		return err
+	}
+	if err := wd.diff(ctx); err != nil {
+		log.Error(fmt.Sprintf("Encountered an error performing workflow diff for vdiff %s: %v", ct.uuid, err))
+		return err
+	}
+
+	return nil
+}
+
+// loadSources reads the workflow's streams from _vt.vreplication and sets up
+// the source shards, filter, and time zones that the diff (or repair) needs.
+func (ct *controller) loadSources(ctx context.Context, dbClient binlogplayer.DBClient) error {
 	ct.workflowFilter = fmt.Sprintf("where workflow = %s and db_name = %s", encodeString(ct.workflow),
 		encodeString(ct.vde.dbName))
 	query := sqlparser.BuildParsedQuery(sqlGetVReplicationEntry, ct.workflowFilter)
@@ -245,22 +271,6 @@ func (ct *controller) start(ctx context.Context, dbClient binlogplayer.DBClient)
 		ct.workflowType = binlogdatapb.VReplicationWorkflowType(workflowType)
 	}
 
-	if err := ct.validate(); err != nil {
-		return err
-	}
-
-	wd, err := newWorkflowDiffer(ct, ct.options, ct.vde.collationEnv)
-	if err != nil {
-		return err
-	}
-	if err := ct.updateState(dbClient, StartedState, nil); err != nil {
-		return err
-	}
-	if err := wd.diff(ctx); err != nil {
-		log.Error(fmt.Sprintf("Encountered an error performing workflow diff for vdiff %s: %v", ct.uuid, err))
-		return err
-	}
-
 	return nil
 }
 
@@ -0,0 +1,657 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// DefaultRepairBatchSize is the number of rows that are re-read from the
+// source and written to the target in a single transaction by default.
+const DefaultRepairBatchSize = 100
+
+// repairResult records what was done to the target for one table.
+type repairResult struct {
+	table    string
+	upserted int64
+	deleted  int64
+}
+
+// handleRepairAction applies the differences recorded in the report of a
+// completed vdiff to the target. The rows are identified by the primary key
+// values in the report samples, or by diffing the table again if the report
+// only has a sample of them, and their current contents are then re-read
+// from the source, at a consistent snapshot, and copied to the target. Rows
+// that no longer exist on the source are deleted from the target.
+func (vde *Engine) handleRepairAction(ctx context.Context, dbClient binlogplayer.DBClient, req *tabletmanagerdatapb.VDiffRequest, resp *tabletmanagerdatapb.VDiffResponse) error {
+	vdiffUUID, err := uuid.Parse(req.VdiffUuid)
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid vdiff UUID %q: %v", req.VdiffUuid, err)
+	}
+	if vde.vre == nil {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vreplication engine is not available on tablet %s",
+			topoproto.TabletAliasString(vde.thisTablet.Alias))
+	}
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffByKeyspaceWorkflowUUID,
+		sqltypes.StringBindVariable(req.Keyspace),
+		sqltypes.StringBindVariable(req.Workflow),
+		sqltypes.StringBindVariable(vdiffUUID.String()),
+		sqltypes.StringBindVariable(vde.dbName),
+	)
+	if err != nil {
+		return err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return err
+	}
+	row := qr.Named().Row()
+	if row == nil {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no vdiff found for UUID %s keyspace %s and workflow %s on tablet %s",
+			vdiffUUID, req.Keyspace, req.Workflow, topoproto.TabletAliasString(vde.thisTablet.Alias))
+	}
+	if state := VDiffState(strings.ToLower(row["state"].ToString())); state != CompletedState {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vdiff %s is in the %s state on tablet %s; only completed vdiffs can be repaired",
+			vdiffUUID, state, topoproto.TabletAliasString(vde.thisTablet.Alias))
+	}
+	resp.Id, _ = row["id"].ToInt64()
+	resp.VdiffUuid = vdiffUUID.String()
+
+	// Use the options from the vdiff record so that we build the same table
+	// plans that were used for the diff.
+	options := optionsZeroVal.CloneVT()
+	if err := protojson.Unmarshal(row.AsBytes("options", []byte("{}")), options); err != nil {
+		return err
+	}
+	batchSize := int(req.GetOptions().GetRepairOptions().GetBatchSize())
+	if batchSize <= 0 {
+		batchSize = DefaultRepairBatchSize
+	}
+
+	ct, err := newController(row, vde.dbClientFactoryDba, vde.ts, vde, options)
+	if err != nil {
+		return err
+	}
+	ctx, ct.cancel = context.WithCancel(ctx)
+	defer func() {
+		ct.cancel()
+		ct.tmc.Close()
+	}()
+	if err := ct.loadSources(ctx, dbClient); err != nil {
+		return err
+	}
+	wd, err := newWorkflowDiffer(ct, options, vde.collationEnv)
+	if err != nil {
+		return err
+	}
+	schm, err := schematools.GetSchema(ctx, ct.ts, ct.tmc, vde.thisTablet.Alias, &tabletmanagerdatapb.GetSchemaRequest{})
+	if err != nil {
+		return vterrors.Wrap(err, "GetSchema")
+	}
+	if err := wd.buildPlan(dbClient, ct.filter, schm); err != nil {
+		return vterrors.Wrap(err, "buildPlan")
+	}
+
+	query, err = sqlparser.ParseAndBind(sqlGetMismatchedTables, sqltypes.Int64BindVariable(ct.id))
+	if err != nil {
+		return err
+	}
+	if qr, err = dbClient.ExecuteFetch(query, -1); err != nil {
+		return err
+	}
+
+	insertVDiffLog(ctx, dbClient, ct.id, fmt.Sprintf("Repair started for %d table(s) with differences", len(qr.Rows)))
+	var results []*repairResult
+	for _, row := range qr.Named().Rows {
+		tableName := row.AsString("table_name", "")
+		td, ok := wd.tableDiffers[tableName]
+		if !ok {
+			log.Warn(fmt.Sprintf("Skipping repair of table %s for vdiff %s as it is no longer part of the workflow", tableName, ct.uuid))
+			continue
+		}
+		dr := &DiffReport{}
+		if err := json.Unmarshal(row.AsBytes("report", []byte("{}")), dr); err != nil {
+			return vterrors.Wrapf(err, "failed to unmarshal the report for table %s", tableName)
+		}
+		res, err := td.repair(ctx, dbClient, dr, batchSize)
+		if err != nil {
+			insertVDiffLog(ctx, dbClient, ct.id, fmt.Sprintf("Repair of table %s failed: %s", tableName, err))
+			return err
+		}
+		insertVDiffLog(ctx, dbClient, ct.id, fmt.Sprintf("Repaired table %s: %d row(s) upserted, %d row(s) deleted",
+			tableName, res.upserted, res.deleted))
+		results = append(results, res)
+	}
+	insertVDiffLog(ctx, dbClient, ct.id, "Repair completed; run a new vdiff to confirm that the tables now match")
+
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "table_name", Type: sqltypes.VarChar},
+			{Name: "rows_upserted", Type: sqltypes.Int64},
+			{Name: "rows_deleted", Type: sqltypes.Int64},
+		},
+	}
+	for _, res := range results {
+		result.Rows = append(result.Rows, []sqltypes.Value{
+			sqltypes.NewVarChar(res.table),
+			sqltypes.NewInt64(res.upserted),
+			sqltypes.NewInt64(res.deleted),
+		})
+	}
+	resp.Output = sqltypes.ResultToProto3(result)
+	return nil
+}
+
+// repair copies the rows identified in the diff report from the source to
+// the target, in batches. The workflow is locked and its target streams are
+// stopped for the duration, as when initializing a table diff.
+func (td *tableDiffer) repair(ctx context.Context, dbClient binlogplayer.DBClient, dr *DiffReport, batchSize int) (*repairResult, error) {
+	if len(td.tablePlan.aggregates) != 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s cannot be repaired as its workflow filter uses aggregates", td.table.Name)
+	}
+	pks, err := td.repairPKs(dr)
+	if err != nil {
+		return nil, err
+	}
+	sampled := int64(len(dr.MismatchedRowsDiffs) + len(dr.ExtraRowsSourceDiffs) + len(dr.ExtraRowsTargetDiffs))
+	if total := dr.MismatchedRows + dr.ExtraRowsSource + dr.ExtraRowsTarget; total > sampled {
+		insertVDiffLog(ctx, dbClient, td.wd.ct.id, fmt.Sprintf("Only %d of the %d differing rows in table %s were sampled in the report; diffing the table again to find all of them",
+			sampled, total, td.table.Name))
+		if pks, err = td.scanDifferences(ctx); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to diff table %s", td.table.Name)
+		}
+	}
+	res := &repairResult{table: td.table.Name}
+	if len(pks) == 0 {
+		return res, nil
+	}
+
+	vdiffEngine := td.wd.ct.vde
+	vdiffEngine.snapshotMu.Lock()
+	defer vdiffEngine.snapshotMu.Unlock()
+
+	vctx, unlock, err := td.lockWorkflow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := td.stopTargetVReplicationStreams(vctx, dbClient); err != nil {
+		return nil, err
+	}
+	defer func() {
+		// We use a new context as we want to reset the state even
+		// when the parent context has timed out or been canceled.
+		restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
+		defer restartCancel()
+		if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
+			log.Error(fmt.Sprintf("error restarting target streams for vdiff %s repair: %v", td.wd.ct.uuid, err))
+		}
+	}()
+	if err := td.selectTablets(vctx); err != nil {
+		return nil, err
+	}
+	if err := td.syncSourceStreams(vctx); err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(pks); start += batchSize {
+		batch := pks[start:min(start+batchSize, len(pks))]
+		if err := td.throttleRepair(vctx); err != nil {
+			return nil, err
+		}
+		upserted, deleted, err := td.repairBatch(vctx, dbClient, batch)
+		if err != nil {
+			return nil, err
+		}
+		res.upserted += upserted
+		res.deleted += deleted
+	}
+	return res, nil
+}
+
+// scanDifferences diffs the table again from the start, at new consistent
+// snapshots, and returns all of the rows that differ. Rows that only exist
+// on the target are returned as read from the target, all others as read
+// from the source. Only their primary key values are used for the repair.
+func (td *tableDiffer) scanDifferences(ctx context.Context) ([][]sqltypes.Value, error) {
+	td.lastSourcePK, td.lastTargetPK = nil, nil
+	defer func() {
+		if td.shardStreamsCancel != nil {
+			td.shardStreamsCancel()
+		}
+		td.wgShardStreamers.Wait()
+	}()
+	if err := td.initialize(ctx); err != nil {
+		return nil, err
+	}
+	execCtx, cancelExec := context.WithCancel(ctx)
+	defer cancelExec()
+	return td.diffRows(ctx, newPrimitiveExecutor(execCtx, td.sourcePrimitive, "source"),
+		newPrimitiveExecutor(execCtx, td.targetPrimitive, "target"))
+}
+
+// diffRows compares the rows of the source and the target executors, in the
+// same way as diff, and returns all of the rows that differ.
+func (td *tableDiffer) diffRows(ctx context.Context, sourceExecutor, targetExecutor *primitiveExecutor) ([][]sqltypes.Value, error) {
+	var (
+		rows                 [][]sqltypes.Value
+		sourceRow, targetRow []sqltypes.Value
+		err                  error
+		advanceSource        = true
+		advanceTarget        = true
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+		case <-td.wd.ct.done:
+			return nil, ErrVDiffStoppedByUser
+		default:
+		}
+		if advanceSource {
+			if sourceRow, err = sourceExecutor.next(); err != nil {
+				return nil, err
+			}
+		}
+		if advanceTarget {
+			if targetRow, err = targetExecutor.next(); err != nil {
+				return nil, err
+			}
+		}
+		advanceSource = sourceRow != nil
+		advanceTarget = targetRow != nil
+		switch {
+		case sourceRow == nil && targetRow == nil:
+			return rows, nil
+		case sourceRow == nil:
+			rows = append(rows, targetRow)
+			continue
+		case targetRow == nil:
+			rows = append(rows, sourceRow)
+			continue
+		}
+
+		c, err := td.compare(sourceRow, targetRow, td.tablePlan.comparePKs, false)
+		switch {
+		case err != nil:
+			return nil, err
+		case c < 0:
+			rows = append(rows, sourceRow)
+			advanceTarget = false
+			continue
+		case c > 0:
+			rows = append(rows, targetRow)
+			advanceSource = false
+			continue
+		}
+		if c, err = td.compare(sourceRow, targetRow, td.tablePlan.compareCols, true); err != nil {
+			return nil, err
+		}
+		if c != 0 {
+			rows = append(rows, sourceRow)
+		}
+	}
+}
+
+// throttleRepair waits until the tablet throttler allows the repair to
+// proceed with its next batch of writes.
+func (td *tableDiffer) throttleRepair(ctx context.Context) error {
+	client := td.wd.ct.vde.vre.ThrottlerClient()
+	if client == nil {
+		return nil
+	}
+	for {
+		if _, ok := client.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.VDiffRepairName); ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+		default:
+		}
+	}
+}
+
+// repairBatch re-reads the given rows from the sources and makes the target
+// match them in a single transaction. It returns the number of rows that
+// were upserted and deleted.
+func (td *tableDiffer) repairBatch(ctx context.Context, dbClient binlogplayer.DBClient, batch [][]sqltypes.Value) (int64, int64, error) {
+	query, err := td.repairSourceQuery(batch)
+	if err != nil {
+		return 0, 0, err
+	}
+	var (
+		mu         sync.Mutex
+		sourceRows [][]sqltypes.Value
+	)
+	if err := td.forEachSource(func(source *migrationSource) error {
+		rows, gtid, err := td.streamRepairRows(ctx, source.shardStreamer, query)
+		if err != nil {
+			return err
+		}
+		source.snapshotPosition = gtid
+		mu.Lock()
+		defer mu.Unlock()
+		sourceRows = append(sourceRows, rows...)
+		return nil
+	}); err != nil {
+		return 0, 0, err
+	}
+	// Bring the target up to the source snapshots before we write to it so
+	// that the rows we write are not overwritten by older events.
+	if err := td.syncTargetStreams(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	var upserts, deletes [][]sqltypes.Value
+	for _, pk := range batch {
+		var found []sqltypes.Value
+		for _, row := range sourceRows {
+			c, err := td.compare(pk, row, td.tablePlan.comparePKs, false)
+			if err != nil {
+				return 0, 0, err
+			}
+			if c == 0 {
+				found = row
+				break
+			}
+		}
+		if found != nil {
+			upserts = append(upserts, found)
+		} else {
+			deletes = append(deletes, pk)
+		}
+	}
+
+	if err := dbClient.Begin(); err != nil {
+		return 0, 0, err
+	}
+	for _, row := range upserts {
+		if _, err := dbClient.ExecuteFetch(td.repairUpsertQuery(row), 1); err != nil {
+			_ = dbClient.Rollback()
+			return 0, 0, err
+		}
+	}
+	for _, pk := range deletes {
+		if _, err := dbClient.ExecuteFetch(td.repairDeleteQuery(pk), 1); err != nil {
+			_ = dbClient.Rollback()
+			return 0, 0, err
+		}
+	}
+	if err := dbClient.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return int64(len(upserts)), int64(len(deletes)), nil
+}
+
+// repairPKs extracts the distinct primary key values of all of the rows in
+// the diff report. Each key is returned as a row of the table plan's select
+// list with only the PK columns set, so that it can be compared with rows
+// read from the source.
+func (td *tableDiffer) repairPKs(dr *DiffReport) ([][]sqltypes.Value, error) {
+	// The report rows are keyed by the select expressions of either the
+	// source or the target query.
+	var names [][]string
+	for _, q := range []string{td.tablePlan.sourceQuery, td.tablePlan.targetQuery} {
+		stmt, err := td.wd.ct.vde.parser.Parse(q)
+		if err != nil {
+			return nil, err
+		}
+		sel, ok := stmt.(*sqlparser.Select)
+		if !ok {
+			return nil, fmt.Errorf("unexpected: %+v", sqlparser.String(stmt))
+		}
+		cols := make([]string, len(sel.SelectExprs.Exprs))
+		for i, expr := range sel.SelectExprs.Exprs {
+			cols[i] = sqlparser.String(expr)
+		}
+		names = append(names, cols)
+	}
+	types := make(map[string]querypb.Type, len(td.table.Fields))
+	for _, fld := range td.table.Fields {
+		types[strings.ToLower(fld.Name)] = fld.Type
+	}
+
+	var rows []*RowDiff
+	for _, m := range dr.MismatchedRowsDiffs {
+		if m.Target != nil {
+			rows = append(rows, m.Target)
+		} else if m.Source != nil {
+			rows = append(rows, m.Source)
+		}
+	}
+	rows = append(rows, dr.ExtraRowsSourceDiffs...)
+	rows = append(rows, dr.ExtraRowsTargetDiffs...)
+
+	var (
+		pks [][]sqltypes.Value
+		err error
+	)
+	seen := make(map[string]bool, len(rows))
+	for _, rd := range rows {
+		pk := make([]sqltypes.Value, len(td.tablePlan.compareCols))
+		var key strings.Builder
+		for _, idx := range td.tablePlan.selectPks {
+			var (
+				val   string
+				found bool
+			)
+			for _, cols := range names {
+				if val, found = rd.Row[cols[idx]]; found {
+					break
+				}
+			}
+			if !found {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary key column %s not found in the report for table %s",
+					td.tablePlan.compareCols[idx].colName, td.table.Name)
+			}
+			typ := types[td.tablePlan.compareCols[idx].colName]
+			raw := []byte(val)
+			if sqltypes.IsBinary(typ) && strings.HasPrefix(val, "0x") {
+				// Binary values are reported as HEX strings, the same way the
+				// MySQL client prints them.
+				if raw, err = hex.DecodeString(val[2:]); err != nil {
+					return nil, vterrors.Wrapf(err, "invalid value %s for primary key column %s in table %s",
+						val, td.tablePlan.compareCols[idx].colName, td.table.Name)
+				}
+			}
+			pk[idx] = sqltypes.MakeTrusted(typ, raw)
+			key.WriteString(val)
+			key.WriteByte(0)
+		}
+		if seen[key.String()] {
+			continue
+		}
+		seen[key.String()] = true
+		pks = append(pks, pk)
+	}
+	return pks, nil
+}
+
+// repairSourceQuery adds a filter to the table's source query so that only
+// the rows in the batch are streamed. VStreamer filters only support
+// comparisons against a single column, so for composite keys we stream the
+// cross product of the values and pick the rows we need afterwards.
+func (td *tableDiffer) repairSourceQuery(batch [][]sqltypes.Value) (string, error) {
+	stmt, err := td.wd.ct.vde.parser.Parse(td.tablePlan.sourceQuery)
+	if err != nil {
+		return "", err
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return "", fmt.Errorf("unexpected: %+v", sqlparser.String(stmt))
+	}
+	for _, idx := range td.tablePlan.selectPks {
+		aliased, ok := sel.SelectExprs.Exprs[idx].(*sqlparser.AliasedExpr)
+		if !ok {
+			return "", fmt.Errorf("unexpected: %+v", sqlparser.String(sel.SelectExprs.Exprs[idx]))
+		}
+		col, ok := aliased.Expr.(*sqlparser.ColName)
+		if !ok {
+			return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s cannot be repaired as its primary key column %s is computed on the source",
+				td.table.Name, sqlparser.String(aliased))
+		}
+		var tuple sqlparser.ValTuple
+		seen := make(map[string]bool, len(batch))
+		for _, pk := range batch {
+			buf := sqlparser.NewTrackedBuffer(nil)
+			pk[idx].EncodeSQL(buf)
+			if seen[buf.String()] {
+				continue
+			}
+			seen[buf.String()] = true
+			expr, err := td.wd.ct.vde.parser.ParseExpr(buf.String())
+			if err != nil {
+				return "", err
+			}
+			tuple = append(tuple, expr)
+		}
+		sel.AddWhere(&sqlparser.ComparisonExpr{
+			Operator: sqlparser.InOp,
+			Left:     sqlparser.NewColName(col.Name.String()),
+			Right:    tuple,
+		})
+	}
+	return sqlparser.String(sel), nil
+}
+
+// repairUpsertQuery builds the statement that writes the source row to the
+// target, replacing the existing row if there is one.
+func (td *tableDiffer) repairUpsertQuery(row []sqltypes.Value) string {
+	ct := td.wd.ct
+	types := make(map[string]querypb.Type, len(td.table.Fields))
+	for _, fld := range td.table.Fields {
+		types[strings.ToLower(fld.Name)] = fld.Type
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("insert into %v.%v (", sqlparser.NewIdentifierCS(ct.vde.dbName), sqlparser.NewIdentifierCS(td.table.Name))
+	for i, col := range td.tablePlan.compareCols {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("%v", sqlparser.NewIdentifierCI(col.colName))
+	}
+	buf.Myprintf(") values (")
+	for i, col := range td.tablePlan.compareCols {
+		if i > 0 {
+			buf.Myprintf(", ")
+		}
+		// Convert datetime values the same way that the workflow does when
+		// the source and target are in different time zones.
+		if ct.sourceTimeZone != "" && types[col.colName] == querypb.Type_DATETIME && !row[i].IsNull() {
+			buf.Myprintf("convert_tz(")
+			row[i].EncodeSQL(buf)
+			buf.Myprintf(", %v, %v)", sqlparser.NewStrLiteral(ct.sourceTimeZone), sqlparser.NewStrLiteral(ct.targetTimeZone))
+			continue
+		}
+		row[i].EncodeSQL(buf)
+	}
+	buf.Myprintf(")")
+	sep := " on duplicate key update "
+	for _, col := range td.tablePlan.compareCols {
+		if col.isPK {
+			continue
+		}
+		buf.Myprintf("%s%v=values(%v)", sep, sqlparser.NewIdentifierCI(col.colName), sqlparser.NewIdentifierCI(col.colName))
+		sep = ", "
+	}
+	if sep != ", " { // Every column is part of the PK
+		buf.Myprintf(" on duplicate key update %v=%v", sqlparser.NewIdentifierCI(td.tablePlan.compareCols[0].colName),
+			sqlparser.NewIdentifierCI(td.tablePlan.compareCols[0].colName))
+	}
+	return buf.String()
+}
+
+// repairDeleteQuery builds the statement that deletes the row with the given
+// primary key from the target.
+func (td *tableDiffer) repairDeleteQuery(pk []sqltypes.Value) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("delete from %v.%v where ", sqlparser.NewIdentifierCS(td.wd.ct.vde.dbName), sqlparser.NewIdentifierCS(td.table.Name))
+	for i, idx := range td.tablePlan.selectPks {
+		if i > 0 {
+			buf.Myprintf(" and ")
+		}
+		buf.Myprintf("%v = ", sqlparser.NewIdentifierCI(td.tablePlan.compareCols[idx].colName))
+		pk[idx].EncodeSQL(buf)
+	}
+	return buf.String()
+}
+
+// streamRepairRows reads all of the rows returned by the query from the
+// participant's tablet, along with the GTID position of the snapshot.
+func (td *tableDiffer) streamRepairRows(ctx context.Context, participant *shardStreamer, query string) ([][]sqltypes.Value, string, error) {
+	conn, err := tabletconn.GetDialer()(ctx, participant.tablet, false)
+	if err != nil {
+		return nil, "", err
+	}
+	defer conn.Close(ctx)
+
+	target := &querypb.Target{
+		Keyspace:   participant.tablet.Keyspace,
+		Shard:      participant.shard,
+		TabletType: participant.tablet.Type,
+	}
+	var (
+		fields []*querypb.Field
+		rows   [][]sqltypes.Value
+		gtid   string
+	)
+	req := &binlogdatapb.VStreamRowsRequest{
+		Target: target, Query: query, Options: &binlogdatapb.VStreamOptions{NoTimeouts: true},
+	}
+	err = conn.VStreamRows(ctx, req, func(vsr *binlogdatapb.VStreamRowsResponse) error {
+		if len(fields) == 0 {
+			if len(vsr.Fields) == 0 {
+				return fmt.Errorf("did not received expected fields in response %+v on tablet %v",
+					vsr, td.wd.ct.vde.thisTablet.Alias)
+			}
+			fields = vsr.Fields
+			gtid = vsr.Gtid
+		}
+		result := sqltypes.Proto3ToResult(&querypb.QueryResult{Fields: fields, Rows: vsr.Rows})
+		rows = append(rows, result.Rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, "", vterrors.Wrapf(err, "VStreamRows on tablet %s", topoproto.TabletAliasString(participant.tablet.Alias))
+	}
+	return rows, gtid, nil
+}
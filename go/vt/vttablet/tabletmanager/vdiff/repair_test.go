@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func newRepairTestTableDiffer(sourceTimeZone, targetTimeZone string) *tableDiffer {
+	table := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"c1", "c2", "c3", "c4"},
+		PrimaryKeyColumns: []string{"c1", "c3"},
+		Fields:            sqltypes.MakeTestFields("c1|c2|c3|c4", "int64|varchar|varbinary|datetime"),
+	}
+	compareCols := []compareColInfo{
+		{colIndex: 0, isPK: true, colName: "c1"},
+		{colIndex: 1, colName: "c2"},
+		{colIndex: 2, isPK: true, colName: "c3"},
+		{colIndex: 3, colName: "c4"},
+	}
+	return &tableDiffer{
+		wd: &workflowDiffer{
+			ct: &controller{
+				vde:            &Engine{dbName: "vt_ks", parser: sqlparser.NewTestParser()},
+				sourceTimeZone: sourceTimeZone,
+				targetTimeZone: targetTimeZone,
+			},
+		},
+		table: table,
+		tablePlan: &tablePlan{
+			sourceQuery: "select c1, c2, c3, c4 from t1 order by c1 asc, c3 asc",
+			targetQuery: "select c1, c2, c3, c4 from t1 order by c1 asc, c3 asc",
+			compareCols: compareCols,
+			comparePKs:  []compareColInfo{compareCols[0], compareCols[2]},
+			selectPks:   []int{0, 2},
+			table:       table,
+		},
+	}
+}
+
+func TestRepairPKs(t *testing.T) {
+	td := newRepairTestTableDiffer("", "")
+	dr := &DiffReport{
+		MismatchedRowsDiffs: []*DiffMismatch{{
+			Source: &RowDiff{Row: map[string]string{"c1": "1", "c2": "a", "c3": "0x6869"}},
+			Target: &RowDiff{Row: map[string]string{"c1": "1", "c2": "b", "c3": "0x6869"}},
+		}},
+		ExtraRowsSourceDiffs: []*RowDiff{
+			{Row: map[string]string{"c1": "2", "c3": "0x6869"}},
+			{Row: map[string]string{"c1": "1", "c3": "0x6869"}}, // Duplicate
+		},
+		ExtraRowsTargetDiffs: []*RowDiff{
+			{Row: map[string]string{"c1": "3", "c3": "0x"}},
+		},
+	}
+	pks, err := td.repairPKs(dr)
+	require.NoError(t, err)
+	require.Len(t, pks, 3)
+	want := [][]string{{"1", "hi"}, {"2", "hi"}, {"3", ""}}
+	for i, pk := range pks {
+		require.Len(t, pk, 4)
+		require.Equal(t, want[i][0], pk[0].ToString())
+		require.Equal(t, want[i][1], pk[2].ToString())
+		require.True(t, pk[1].IsNull())
+	}
+
+	_, err = td.repairPKs(&DiffReport{ExtraRowsTargetDiffs: []*RowDiff{{Row: map[string]string{"c1": "3"}}}})
+	require.ErrorContains(t, err, "primary key column c3 not found in the report for table t1")
+	_, err = td.repairPKs(&DiffReport{ExtraRowsTargetDiffs: []*RowDiff{{Row: map[string]string{"c1": "3", "c3": "0xzz"}}}})
+	require.ErrorContains(t, err, "invalid value 0xzz for primary key column c3 in table t1")
+}
+
+func TestRepairDiffRows(t *testing.T) {
+	td := newRepairTestTableDiffer("", "")
+	td.wd.collationEnv = collations.MySQL8()
+	row := func(c1 int64, c2 string) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewInt64(c1), sqltypes.NewVarChar(c2), sqltypes.NewVarBinary("k"), sqltypes.NULL}
+	}
+	executor := func(rows ...[]sqltypes.Value) *primitiveExecutor {
+		resultch := make(chan *sqltypes.Result)
+		close(resultch)
+		return &primitiveExecutor{rows: rows, resultch: resultch}
+	}
+	// Every differing row is returned, unlike in the report which only has
+	// a sample of them: a mismatch, extra rows on both sides and the rows
+	// left over on either side.
+	rows, err := td.diffRows(context.Background(),
+		executor(row(1, "a"), row(2, "b"), row(4, "d"), row(6, "f"), row(7, "g")),
+		executor(row(1, "a"), row(2, "x"), row(3, "c"), row(4, "d"), row(5, "e")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, [][]sqltypes.Value{row(2, "b"), row(3, "c"), row(5, "e"), row(6, "f"), row(7, "g")}, rows)
+
+	rows, err = td.diffRows(context.Background(), executor(), executor(row(1, "a"), row(2, "b")))
+	require.NoError(t, err)
+	require.Equal(t, [][]sqltypes.Value{row(1, "a"), row(2, "b")}, rows)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = td.diffRows(ctx, executor(row(1, "a")), executor())
+	require.ErrorContains(t, err, "context has expired")
+}
+
+func TestRepairQueries(t *testing.T) {
+	pk := func(c1 int64, c3 string) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewInt64(c1), sqltypes.NULL, sqltypes.NewVarBinary(c3), sqltypes.NULL}
+	}
+	batch := [][]sqltypes.Value{pk(1, "a"), pk(2, "a"), pk(2, "b")}
+	row := []sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewVarChar("x'y"),
+		sqltypes.NewVarBinary("a"),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2026-01-02 03:04:05")),
+	}
+
+	td := newRepairTestTableDiffer("", "")
+	query, err := td.repairSourceQuery(batch)
+	require.NoError(t, err)
+	require.Equal(t, "select c1, c2, c3, c4 from t1 where c1 in (1, 2) and c3 in (_binary 'a', _binary 'b') order by c1 asc, c3 asc", query)
+	require.Equal(t, "insert into vt_ks.t1 (c1, c2, c3, c4) values (1, 'x\\'y', _binary'a', '2026-01-02 03:04:05') on duplicate key update c2=values(c2), c4=values(c4)",
+		td.repairUpsertQuery(row))
+	require.Equal(t, "delete from vt_ks.t1 where c1 = 2 and c3 = _binary'b'", td.repairDeleteQuery(batch[2]))
+
+	// Datetime values are converted when the workflow converts them.
+	td = newRepairTestTableDiffer("US/Pacific", "UTC")
+	require.Equal(t, "insert into vt_ks.t1 (c1, c2, c3, c4) values (1, 'x\\'y', _binary'a', convert_tz('2026-01-02 03:04:05', 'US/Pacific', 'UTC')) on duplicate key update c2=values(c2), c4=values(c4)",
+		td.repairUpsertQuery(row))
+
+	// Computed PK columns on the source cannot be used to filter the rows.
+	td.tablePlan.sourceQuery = "select c1 + 1 as c1, c2, c3, c4 from t1"
+	_, err = td.repairSourceQuery(batch)
+	require.ErrorContains(t, err, "table t1 cannot be repaired as its primary key column c1 + 1 as c1 is computed on the source")
+}
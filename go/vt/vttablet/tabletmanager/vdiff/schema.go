@@ -66,4 +66,5 @@ const (
 	sqlUpdateTableMismatch       = "update _vt.vdiff_table set mismatch = true where vdiff_id = %a and table_name = %a"
 
 	sqlGetIncompleteTables = "select table_name as table_name from _vt.vdiff_table where vdiff_id = %a and state != 'completed' order by table_name"
+	sqlGetMismatchedTables = "select table_name as table_name, report as report from _vt.vdiff_table where vdiff_id = %a and mismatch = 1 order by table_name"
 )
@@ -122,6 +122,52 @@ func (td *tableDiffer) initialize(ctx context.Context) error {
 	}
 	defer dbClient.Close()
 
+	targetKeyspace := td.wd.ct.vde.thisTablet.Keyspace
+	vctx, unlock, err := td.lockWorkflow(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := td.stopTargetVReplicationStreams(vctx, dbClient); err != nil {
+		return err
+	}
+	defer func() {
+		// We use a new context as we want to reset the state even
+		// when the parent context has timed out or been canceled.
+		log.Info(fmt.Sprintf("Restarting the %q VReplication workflow for vdiff %s on target tablets in keyspace %q", td.wd.ct.workflow, td.wd.ct.uuid, targetKeyspace))
+		restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
+		defer restartCancel()
+		if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
+			log.Error(fmt.Sprintf("error restarting target streams for vdiff %s: %v", td.wd.ct.uuid, err))
+		}
+	}()
+
+	td.shardStreamsCtx, td.shardStreamsCancel = context.WithCancel(vctx)
+
+	if err := td.selectTablets(vctx); err != nil {
+		return err
+	}
+	if err := td.syncSourceStreams(vctx); err != nil {
+		return err
+	}
+	if err := td.startSourceDataStreams(td.shardStreamsCtx); err != nil {
+		return err
+	}
+	if err := td.syncTargetStreams(vctx); err != nil {
+		return err
+	}
+	if err := td.startTargetDataStream(td.shardStreamsCtx); err != nil {
+		return err
+	}
+	td.setupRowSorters()
+	return nil
+}
+
+// lockWorkflow takes the topo lock for the workflow, retrying with an
+// exponential backoff until it's acquired or the vdiff is stopped. The
+// returned function releases the lock.
+func (td *tableDiffer) lockWorkflow(ctx context.Context) (context.Context, func(), error) {
 	targetKeyspace := td.wd.ct.vde.thisTablet.Keyspace
 	lockName := fmt.Sprintf("%s/%s", targetKeyspace, td.wd.ct.workflow)
 	log.Info(fmt.Sprintf("Locking workflow %s for VDiff %s", lockName, td.wd.ct.uuid))
@@ -142,9 +188,9 @@ func (td *tableDiffer) initialize(ctx context.Context) error {
 		log.Warn(fmt.Sprintf("Locking workflow %s for VDiff %s initialization (stream ID: %d) failed, will wait %v before retrying: %v", lockName, td.wd.ct.uuid, td.wd.ct.id, retryDelay, lockErr))
 		select {
 		case <-ctx.Done():
-			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "engine is shutting down")
+			return nil, nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "engine is shutting down")
 		case <-td.wd.ct.done:
-			return ErrVDiffStoppedByUser
+			return nil, nil, ErrVDiffStoppedByUser
 		case <-time.After(retryDelay):
 			if retryDelay < maxRetryDelay {
 				retryDelay = min(time.Duration(float64(retryDelay)*backoffFactor), maxRetryDelay)
@@ -158,47 +204,13 @@ func (td *tableDiffer) initialize(ctx context.Context) error {
 		}
 	}
 
-	var err error
-	defer func() {
+	return vctx, func() {
+		var err error
 		unlock(&err)
 		if err != nil {
 			log.Error(fmt.Sprintf("Unlocking workflow %s for vdiff %s failed: %v", lockName, td.wd.ct.uuid, err))
 		}
-	}()
-
-	if err := td.stopTargetVReplicationStreams(vctx, dbClient); err != nil {
-		return err
-	}
-	defer func() {
-		// We use a new context as we want to reset the state even
-		// when the parent context has timed out or been canceled.
-		log.Info(fmt.Sprintf("Restarting the %q VReplication workflow for vdiff %s on target tablets in keyspace %q", td.wd.ct.workflow, td.wd.ct.uuid, targetKeyspace))
-		restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
-		defer restartCancel()
-		if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
-			log.Error(fmt.Sprintf("error restarting target streams for vdiff %s: %v", td.wd.ct.uuid, err))
-		}
-	}()
-
-	td.shardStreamsCtx, td.shardStreamsCancel = context.WithCancel(vctx)
-
-	if err := td.selectTablets(vctx); err != nil {
-		return err
-	}
-	if err := td.syncSourceStreams(vctx); err != nil {
-		return err
-	}
-	if err := td.startSourceDataStreams(td.shardStreamsCtx); err != nil {
-		return err
-	}
-	if err := td.syncTargetStreams(vctx); err != nil {
-		return err
-	}
-	if err := td.startTargetDataStream(td.shardStreamsCtx); err != nil {
-		return err
-	}
-	td.setupRowSorters()
-	return nil
+	}, nil
 }
 
 func (td *tableDiffer) stopTargetVReplicationStreams(ctx context.Context, dbClient binlogplayer.DBClient) error {
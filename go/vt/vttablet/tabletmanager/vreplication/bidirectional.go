@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// Bidirectional workflows replicate the same tables in both directions, e.g. between
+// the source and the target of a Migrate during its cutover window. The two directions
+// are named <workflow> and <workflow>_reverse.
+//
+// To prevent changes from looping between the two sides, each stream tags the
+// statements it applies with a comment naming its workflow and its _vt.vreplication id.
+// With binlog_rows_query_log_events enabled on the source, the statement, including the
+// comment, is recorded in the ROWS_QUERY event which precedes its row events in the
+// binary log. The stream of the opposite direction skips the row events which are
+// tagged by its peer, and only applies the changes made by the applications.
+//
+// Changes made on both sides to the same row are not merged. A change which conflicts
+// with the row found on the target is skipped and logged in _vt.vreplication_log, so
+// that it can be reconciled. These are the insert of a row which already exists, and
+// the update or delete of a row which does not exist or whose values differ from the
+// before image of the change. The values can't be compared when the binary log only
+// has partial row images, in which case only the missing rows are detected.
+
+const reverseWorkflowSuffix = "_reverse"
+
+// originCommentRegexp matches the comment which tags the statements applied by a bidirectional workflow.
+var originCommentRegexp = regexp.MustCompile(`/\*vrepl_origin=(.+?):(\d+)\*/`)
+
+// originComment returns the comment which tags the statements applied by the given stream.
+func originComment(workflow string, id int32) string {
+	return fmt.Sprintf("/*vrepl_origin=%s:%d*/ ", strings.ReplaceAll(workflow, "*/", ""), id)
+}
+
+// parseOriginComment returns the workflow and stream id found in the origin comment of
+// the given statement, if any.
+func parseOriginComment(query string) (workflow string, id int32, ok bool) {
+	match := originCommentRegexp.FindStringSubmatch(query)
+	if match == nil {
+		return "", 0, false
+	}
+	n, err := strconv.ParseInt(match[2], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return match[1], int32(n), true
+}
+
+// isPeerWorkflow returns true if the two workflows are the directions of the same
+// bidirectional workflow.
+func isPeerWorkflow(workflow, other string) bool {
+	return strings.TrimSuffix(workflow, reverseWorkflowSuffix) == strings.TrimSuffix(other, reverseWorkflowSuffix)
+}
+
+// isPeerRowsQuery returns true if the statement of the ROWS_QUERY event was applied by
+// the peer of this bidirectional workflow, in which case its row events are skipped.
+func (vp *vplayer) isPeerRowsQuery(event *binlogdatapb.VEvent) bool {
+	workflow, _, ok := parseOriginComment(event.Statement)
+	return ok && isPeerWorkflow(vp.vr.WorkflowName, workflow)
+}
+
+// applyBidirectionalChange applies a row change of a bidirectional workflow. Conflicting
+// changes are logged and skipped.
+func (vp *vplayer) applyBidirectionalChange(tplan *TablePlan, change *binlogdatapb.RowChange, applyFunc func(string) (*sqltypes.Result, error)) error {
+	checked := false
+	if change.Before != nil && tplan.BeforeImageCheck != nil && !tplan.isPartial(change) {
+		conflict, err := tplan.checkBeforeImage(change, applyFunc)
+		if err != nil {
+			return err
+		}
+		if conflict != "" {
+			vp.logConflict(tplan, conflict)
+			return nil
+		}
+		checked = true
+	}
+	qr, err := tplan.applyChange(change, applyFunc)
+	switch {
+	case err != nil:
+		sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+		if !ok || sqlErr.Number() != sqlerror.ERDupEntry || change.Before != nil {
+			return err
+		}
+		// The failed statement did not abort the transaction, and must not be replayed
+		// if the transaction is retried.
+		vp.vr.dbClient.forgetLastQuery()
+		vp.logConflict(tplan, "row to insert already exists: "+sqlErr.Message)
+	case !checked && qr != nil && qr.RowsAffected == 0 && change.Before != nil && tplan.Lastpk == nil:
+		// Without the before image check, an update which does not change the
+		// row can't be told apart from one whose row does not exist.
+		vp.logConflict(tplan, fmt.Sprintf("row to %s does not exist or is unchanged", changeKind(change)))
+	}
+	return nil
+}
+
+// checkBeforeImage compares the row found on the target with the before image of an
+// update or a delete. It returns the reason of the conflict if they differ. Until the
+// table is fully copied, a missing row may not be copied yet, and is not a conflict.
+func (tp *TablePlan) checkBeforeImage(change *binlogdatapb.RowChange, executor func(string) (*sqltypes.Result, error)) (string, error) {
+	bindvars := make(map[string]*querypb.BindVariable, len(tp.Fields))
+	if err := tp.bindBeforeImage(bindvars, change.Before); err != nil {
+		return "", err
+	}
+	qr, err := execParsedQuery(tp.BeforeImageCheck, bindvars, executor)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case len(qr.Rows) == 0:
+		if tp.Lastpk != nil {
+			return "", nil
+		}
+		return fmt.Sprintf("row to %s does not exist", changeKind(change)), nil
+	case qr.Rows[0][0].ToString() != "1":
+		return fmt.Sprintf("row to %s was changed on the target", changeKind(change)), nil
+	}
+	return "", nil
+}
+
+// changeKind returns the kind of statement which applies the row change.
+func changeKind(change *binlogdatapb.RowChange) string {
+	switch {
+	case change.Before == nil:
+		return "insert"
+	case change.After == nil:
+		return "delete"
+	}
+	return "update"
+}
+
+func (vp *vplayer) logConflict(tplan *TablePlan, reason string) {
+	msg := fmt.Sprintf("Skipped conflicting change to table %s at position %s: %s", tplan.TargetName, replication.EncodePosition(vp.pos), reason)
+	log.Warn(fmt.Sprintf("Bidirectional workflow %s, stream %d: %s", vp.vr.WorkflowName, vp.vr.id, msg))
+	vp.vr.stats.ErrorCounts.Add([]string{"Conflict"}, 1)
+	vp.vr.insertLog(LogConflict, msg)
+}
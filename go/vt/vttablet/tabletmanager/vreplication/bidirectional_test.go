@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/vtenv"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestOriginComment(t *testing.T) {
+	comment := originComment("wf", 7)
+	assert.Equal(t, "/*vrepl_origin=wf:7*/ ", comment)
+
+	workflow, id, ok := parseOriginComment(comment + "insert into t1(id) values (1)")
+	require.True(t, ok)
+	assert.Equal(t, "wf", workflow)
+	assert.Equal(t, int32(7), id)
+
+	_, _, ok = parseOriginComment("insert into t1(id) values (1)")
+	assert.False(t, ok)
+
+	assert.True(t, isPeerWorkflow("wf", "wf_reverse"))
+	assert.True(t, isPeerWorkflow("wf_reverse", "wf"))
+	assert.True(t, isPeerWorkflow("wf", "wf"))
+	assert.False(t, isPeerWorkflow("wf", "other_reverse"))
+}
+
+func TestPlayerBidirectional(t *testing.T) {
+	dbClient := binlogplayer.NewMockDBClient(t)
+	stats := binlogplayer.NewStats()
+	defer stats.Stop()
+	config, err := vttablet.NewVReplicationConfig(map[string]string{"vreplication-bidirectional": "true"})
+	require.NoError(t, err)
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select * from t1",
+		}},
+	}
+	vr := &vreplicator{
+		id:             1,
+		WorkflowName:   "wf",
+		source:         getSource(filter),
+		workflowConfig: config,
+		dbClient:       newVDBClient(dbClient, stats, config.RelayLogMaxItems),
+		stats:          stats,
+	}
+	primaryKeyInfos := map[string][]*ColumnInfo{
+		"t1": {&ColumnInfo{Name: "id", IsPK: true}, &ColumnInfo{Name: "val"}},
+	}
+	plan, err := vr.buildReplicatorPlan(vr.source, primaryKeyInfos, nil, stats, vtenv.NewTestEnv())
+	require.NoError(t, err)
+	vp := newVPlayer(vr, binlogplayer.VRSettings{}, nil, replication.Position{}, "replicate")
+	vp.replicatorPlan = plan
+	vp.foreignKeyChecksStateInitialized = true
+	vp.foreignKeyChecksEnabled = true
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "t1",
+		Fields: []*querypb.Field{
+			{Name: "id", Type: querypb.Type_INT64},
+			{Name: "val", Type: querypb.Type_VARCHAR},
+		},
+	})
+	require.NoError(t, err)
+	vp.tablePlans["t1"] = tplan
+
+	row := func(id int64, val string) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(val)})
+	}
+	rowEvent := func(change *binlogdatapb.RowChange) *binlogdatapb.VEvent {
+		return &binlogdatapb.VEvent{
+			Type:     binlogdatapb.VEventType_ROW,
+			RowEvent: &binlogdatapb.RowEvent{TableName: "t1", RowChanges: []*binlogdatapb.RowChange{change}},
+		}
+	}
+	ctx := context.Background()
+
+	// Changes applied by the peer are skipped, until the next statement.
+	require.NoError(t, vp.applyEvent(ctx, &binlogdatapb.VEvent{
+		Type:      binlogdatapb.VEventType_ROWS_QUERY,
+		Statement: "/*vrepl_origin=wf_reverse:3*/ insert into t1(id, val) values (1, 'a')",
+	}, false))
+	require.NoError(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{After: row(1, "a")}), false))
+
+	// Changes made by the applications are applied, and tagged.
+	dbClient.ExpectRequest("begin", nil, nil)
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ insert into t1(id,val) values (2,'b')", &sqltypes.Result{RowsAffected: 1}, nil)
+	require.NoError(t, vp.applyEvent(ctx, &binlogdatapb.VEvent{
+		Type:      binlogdatapb.VEventType_ROWS_QUERY,
+		Statement: "insert into t1(id, val) values (2, 'b')",
+	}, false))
+	require.NoError(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{After: row(2, "b")}), false))
+
+	// Conflicting changes are skipped and logged.
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ insert into t1(id,val) values (3,'c')", nil,
+		sqlerror.NewSQLError(sqlerror.ERDupEntry, sqlerror.SSConstraintViolation, "Duplicate entry '3' for key 't1.PRIMARY'"))
+	require.NoError(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{After: row(3, "c")}), false))
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ select val<=>'d' from t1 where id=4", &sqltypes.Result{}, nil)
+	require.NoError(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{Before: row(4, "d")}), false))
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ select val<=>'e' from t1 where id=5",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("match", "int64"), "0"), nil)
+	require.NoError(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{Before: row(5, "e"), After: row(5, "f")}), false))
+	dbClient.Wait()
+	assert.Equal(t, int64(3), stats.ErrorCounts.Counts()["Conflict"])
+	assert.NotContains(t, vr.dbClient.queries, "/*vrepl_origin=wf:1*/ insert into t1(id,val) values (3,'c')")
+
+	// Changes whose before image matches the target are applied.
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ select val<=>'g' from t1 where id=6",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("match", "int64"), "1"), nil)
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ delete from t1 where id=6", &sqltypes.Result{RowsAffected: 1}, nil)
+	require.NoError(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{Before: row(6, "g")}), false))
+	dbClient.Wait()
+	assert.Equal(t, int64(3), stats.ErrorCounts.Counts()["Conflict"])
+
+	// Other errors fail the stream.
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ select val<=>'e' from t1 where id=5",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("match", "int64"), "1"), nil)
+	dbClient.ExpectRequest("/*vrepl_origin=wf:1*/ update t1 set val='f' where id=5", nil,
+		sqlerror.NewSQLError(sqlerror.ERLockNowait, sqlerror.SSUnknownSQLState, "lock"))
+	require.Error(t, vp.applyEvent(ctx, rowEvent(&binlogdatapb.RowChange{Before: row(5, "e"), After: row(5, "f")}), false))
+}
+
+func TestValidateBinlogRowsQueryLogEvents(t *testing.T) {
+	dbClient := binlogplayer.NewMockDBClient(t)
+	stats := binlogplayer.NewStats()
+	defer stats.Stop()
+	config, err := vttablet.NewVReplicationConfig(map[string]string{"vreplication-bidirectional": "true"})
+	require.NoError(t, err)
+	vr := &vreplicator{
+		workflowConfig: config,
+		dbClient:       newVDBClient(dbClient, stats, config.RelayLogMaxItems),
+	}
+	query := "select @@global.binlog_rows_query_log_events, @@session.binlog_rows_query_log_events"
+	fields := sqltypes.MakeTestFields("global|session", "int64|int64")
+
+	dbClient.ExpectRequest(query, sqltypes.MakeTestResult(fields, "1|1"), nil)
+	require.NoError(t, vr.validateBinlogRowsQueryLogEvents())
+	dbClient.ExpectRequest(query, sqltypes.MakeTestResult(fields, "1|0"), nil)
+	require.ErrorContains(t, vr.validateBinlogRowsQueryLogEvents(), "binlog_rows_query_log_events must be enabled")
+
+	// Other workflows don't need it.
+	vr.workflowConfig = vttablet.DefaultVReplicationConfig
+	require.NoError(t, vr.validateBinlogRowsQueryLogEvents())
+	dbClient.Wait()
+}
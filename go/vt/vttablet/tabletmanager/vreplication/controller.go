@@ -68,6 +68,11 @@ type controller struct {
 	stopPos      string
 	tabletPicker *discovery.TabletPicker
 
+	// replicatorSource is the source of the last vreplicator, whose filter may
+	// have been changed by a DDL with the TRANSFORM action. It's only used by
+	// runBlp, so that the stream restarts with the new filter.
+	replicatorSource *binlogdatapb.BinlogSource
+
 	cancel context.CancelFunc
 	done   chan struct{}
 
@@ -297,8 +302,13 @@ func (ct *controller) runBlp(ctx context.Context) (err error) {
 		}
 		defer vsClient.Close(ctx)
 
-		vr := newVReplicator(ct.id, ct.source, vsClient, ct.blpStats, dbClient, ct.mysqld, ct.vre, ct.WorkflowConfig)
+		source := ct.source
+		if ct.replicatorSource != nil {
+			source = ct.replicatorSource
+		}
+		vr := newVReplicator(ct.id, source, vsClient, ct.blpStats, dbClient, ct.mysqld, ct.vre, ct.WorkflowConfig)
 		err = vr.Replicate(ctx)
+		ct.replicatorSource = vr.source
 		ct.lastWorkflowError.Record(err)
 
 		// If this is a MySQL error that we know needs manual intervention or
@@ -0,0 +1,482 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// The TRANSFORM OnDDL action translates the DDLs of the source onto the target tables
+// of the stream, instead of executing them verbatim:
+//   - A DDL which does not affect any table selected by the filter is ignored.
+//   - If a rule selects all the columns of the source table, the column and index
+//     changes of an ALTER TABLE are applied to its target table.
+//   - If a rule selects specific columns, new columns are ignored, renamed columns are
+//     renamed in the rule, and the definition changes of the selected columns are
+//     applied to the target columns. Dropping a selected column cannot be translated.
+//   - Constraints, table options and partitioning are not propagated.
+//
+// The translated ALTER is validated against the target table with schemadiff before
+// it is executed. When a DDL cannot be translated, the stream is stopped, as with the
+// STOP action, and the reason is recorded in its message. The new filter and position
+// are saved before the translated DDLs are executed, so that a source DDL is never
+// replayed. If one of them fails, the stream is stopped and its message lists the DDLs
+// which remain to be applied on the target before it's started again.
+
+// errDDLTransformed is returned by the vplayer after it applied a transformed DDL, so
+// that it's restarted with a plan which reflects the new target schema and filter.
+var errDDLTransformed = errors.New("DDL transformed, restarting the player")
+
+// untranslatableDDLError is returned when a DDL cannot be translated onto the targets.
+type untranslatableDDLError struct {
+	reason string
+}
+
+func (e *untranslatableDDLError) Error() string {
+	return e.reason
+}
+
+func untranslatable(format string, args ...any) error {
+	return &untranslatableDDLError{reason: fmt.Sprintf(format, args...)}
+}
+
+// ddlTransform is the translation of a source DDL onto the targets of a stream.
+type ddlTransform struct {
+	// targetDDLs are the statements to execute on the target.
+	targetDDLs []string
+	// filter is the new filter of the stream, if any of its rules was rewritten.
+	filter *binlogdatapb.Filter
+}
+
+func (t *ddlTransform) isEmpty() bool {
+	return len(t.targetDDLs) == 0 && t.filter == nil
+}
+
+// ruleTarget is a target table which a rule of the filter selects from a source table.
+type ruleTarget struct {
+	index int
+	name  string
+	// sel is the select of the rule, or nil if the rule selects all the columns.
+	sel *sqlparser.Select
+}
+
+// selectedAs returns the names of the target columns which select the given
+// source column as is.
+func (rt *ruleTarget) selectedAs(column string) []string {
+	var names []string
+	for _, expr := range rt.sel.GetColumns() {
+		aliased, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			continue
+		}
+		col, ok := aliased.Expr.(*sqlparser.ColName)
+		if !ok || !col.Name.EqualString(column) {
+			continue
+		}
+		if aliased.As.IsEmpty() {
+			names = append(names, col.Name.String())
+		} else {
+			names = append(names, aliased.As.String())
+		}
+	}
+	return names
+}
+
+// references returns true if the given source column is used anywhere in the rule.
+func (rt *ruleTarget) references(column string) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && col.Name.EqualString(column) {
+			found = true
+		}
+		return !found, nil
+	}, rt.sel)
+	return found
+}
+
+// referencedInExpressions returns true if the given source column is used by the rule
+// other than as a plain select expression.
+func (rt *ruleTarget) referencedInExpressions(column string) bool {
+	count := 0
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && col.Name.EqualString(column) {
+			count++
+		}
+		return true, nil
+	}, rt.sel)
+	return count > len(rt.selectedAs(column))
+}
+
+// renameColumn renames the given source column in the rule, keeping the name of the
+// target columns which selected it as is.
+func (rt *ruleTarget) renameColumn(from, to string) {
+	for _, expr := range rt.sel.GetColumns() {
+		aliased, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			continue
+		}
+		if col, ok := aliased.Expr.(*sqlparser.ColName); ok && col.Name.EqualString(from) && aliased.As.IsEmpty() {
+			aliased.As = col.Name
+		}
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && col.Name.EqualString(from) {
+			col.Name = sqlparser.NewIdentifierCI(to)
+		}
+		return true, nil
+	}, rt.sel)
+}
+
+// ruleTargets returns the targets which the rules of the filter select from the given
+// source table. A table which is excluded by the filter has no targets.
+func ruleTargets(parser *sqlparser.Parser, filter *binlogdatapb.Filter, sourceTable string) ([]*ruleTarget, error) {
+	var targets []*ruleTarget
+	for i, rule := range filter.Rules {
+		switch {
+		case strings.HasPrefix(rule.Match, "/"):
+			matched, err := regexp.MatchString(strings.Trim(rule.Match, "/"), sourceTable)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+			if rule.Filter == ExcludeStr {
+				return nil, nil
+			}
+			targets = append(targets, &ruleTarget{index: i, name: sourceTable})
+			return targets, nil
+		case rule.Filter == "" || key.IsValidKeyRange(rule.Filter):
+			if rule.Match == sourceTable {
+				targets = append(targets, &ruleTarget{index: i, name: rule.Match})
+			}
+		case rule.Filter == ExcludeStr:
+			if rule.Match == sourceTable {
+				return nil, nil
+			}
+		default:
+			sel, fromTable, err := analyzeSelectFrom(rule.Filter, parser)
+			if err != nil {
+				return nil, err
+			}
+			if fromTable != sourceTable {
+				continue
+			}
+			rt := &ruleTarget{index: i, name: rule.Match, sel: sel}
+			if _, ok := sel.GetColumns()[0].(*sqlparser.StarExpr); ok {
+				rt.sel = nil
+			}
+			targets = append(targets, rt)
+		}
+	}
+	return targets, nil
+}
+
+// translateAlterOptions returns the options of the source ALTER TABLE which apply to the
+// given target. Renamed columns are renamed in the rule of the target.
+func translateAlterOptions(rt *ruleTarget, options []sqlparser.AlterOption) ([]sqlparser.AlterOption, bool, error) {
+	var translated []sqlparser.AlterOption
+	ruleChanged := false
+	for _, option := range options {
+		switch option := option.(type) {
+		case *sqlparser.AddIndexDefinition:
+			if option.IndexDefinition.Info.Type == sqlparser.IndexTypePrimary {
+				return nil, false, untranslatable("changing the primary key of table %s is not supported", rt.name)
+			}
+			if rt.sel == nil {
+				translated = append(translated, option)
+			}
+		case *sqlparser.DropKey:
+			switch option.Type {
+			case sqlparser.PrimaryKeyType:
+				return nil, false, untranslatable("changing the primary key of table %s is not supported", rt.name)
+			case sqlparser.NormalKeyType:
+				if rt.sel == nil {
+					translated = append(translated, option)
+				}
+			}
+		case *sqlparser.RenameIndex:
+			if rt.sel == nil {
+				translated = append(translated, option)
+			}
+		case *sqlparser.AddConstraintDefinition, *sqlparser.AlterCheck, sqlparser.TableOptions, *sqlparser.AlterCharset,
+			*sqlparser.Force, *sqlparser.LockOption, sqlparser.AlgorithmValue, *sqlparser.KeyState,
+			*sqlparser.OrderByOption, *sqlparser.Validation, *sqlparser.TablespaceOperation, *sqlparser.AlterIndex:
+			// These do not change the rows which are streamed.
+		case *sqlparser.RenameTableName:
+			return nil, false, untranslatable("renaming table %s is not supported", rt.name)
+		case *sqlparser.AddColumns:
+			if rt.sel == nil {
+				translated = append(translated, option)
+			}
+		case *sqlparser.DropColumn:
+			switch {
+			case rt.sel == nil:
+				translated = append(translated, option)
+			case rt.references(option.Name.Name.String()):
+				return nil, false, untranslatable("column %s is used by the filter of table %s", option.Name.Name.String(), rt.name)
+			}
+		case *sqlparser.RenameColumn:
+			switch {
+			case rt.sel == nil:
+				translated = append(translated, option)
+			case rt.references(option.OldName.Name.String()):
+				rt.renameColumn(option.OldName.Name.String(), option.NewName.Name.String())
+				ruleChanged = true
+			}
+		case *sqlparser.ModifyColumn:
+			if rt.sel == nil {
+				translated = append(translated, option)
+				continue
+			}
+			column := option.NewColDefinition.Name.String()
+			if rt.referencedInExpressions(column) {
+				return nil, false, untranslatable("column %s is used in an expression by the filter of table %s", column, rt.name)
+			}
+			for _, name := range rt.selectedAs(column) {
+				translated = append(translated, &sqlparser.ModifyColumn{NewColDefinition: targetColumnDefinition(option.NewColDefinition, name)})
+			}
+		case *sqlparser.ChangeColumn:
+			if rt.sel == nil {
+				translated = append(translated, option)
+				continue
+			}
+			column := option.OldColumn.Name.String()
+			if rt.referencedInExpressions(column) {
+				return nil, false, untranslatable("column %s is used in an expression by the filter of table %s", column, rt.name)
+			}
+			for _, name := range rt.selectedAs(column) {
+				translated = append(translated, &sqlparser.ModifyColumn{NewColDefinition: targetColumnDefinition(option.NewColDefinition, name)})
+			}
+			if newName := option.NewColDefinition.Name.String(); rt.references(column) && !strings.EqualFold(column, newName) {
+				rt.renameColumn(column, newName)
+				ruleChanged = true
+			}
+		case *sqlparser.AlterColumn:
+			if rt.sel == nil {
+				translated = append(translated, option)
+				continue
+			}
+			for _, name := range rt.selectedAs(option.Column.Name.String()) {
+				alterColumn := sqlparser.Clone(option)
+				alterColumn.Column = sqlparser.NewColName(name)
+				translated = append(translated, alterColumn)
+			}
+		default:
+			return nil, false, untranslatable("unsupported alter option %s on table %s", sqlparser.String(option), rt.name)
+		}
+	}
+	return translated, ruleChanged, nil
+}
+
+func targetColumnDefinition(def *sqlparser.ColumnDefinition, name string) *sqlparser.ColumnDefinition {
+	def = sqlparser.Clone(def)
+	def.Name = sqlparser.NewIdentifierCI(name)
+	return def
+}
+
+// buildDDLTransform translates a DDL of the source onto the targets of the filter. The
+// showCreateTable function returns the current CREATE TABLE statement of a target table.
+// An untranslatableDDLError is returned if the DDL cannot be translated.
+func buildDDLTransform(env *schemadiff.Environment, filter *binlogdatapb.Filter, ddl string, showCreateTable func(table string) (string, error)) (*ddlTransform, error) {
+	parser := env.Parser()
+	stmt, err := parser.Parse(ddl)
+	if err != nil {
+		return nil, untranslatable("cannot parse DDL: %v", err)
+	}
+	ddlStmt, ok := stmt.(sqlparser.DDLStatement)
+	if !ok {
+		// Database level statements do not affect the tables of the filter.
+		return &ddlTransform{}, nil
+	}
+	alter, ok := ddlStmt.(*sqlparser.AlterTable)
+	if !ok {
+		for _, table := range ddlStmt.AffectedTables() {
+			targets, err := ruleTargets(parser, filter, table.Name.String())
+			if err != nil {
+				return nil, err
+			}
+			if len(targets) > 0 {
+				return nil, untranslatable("%s of table %s is not supported", strings.ToUpper(ddlStmt.GetAction().ToString()), table.Name.String())
+			}
+		}
+		return &ddlTransform{}, nil
+	}
+
+	targets, err := ruleTargets(parser, filter, alter.Table.Name.String())
+	if err != nil {
+		return nil, err
+	}
+	transform := &ddlTransform{}
+	for _, rt := range targets {
+		options, ruleChanged, err := translateAlterOptions(rt, alter.AlterOptions)
+		if err != nil {
+			return nil, err
+		}
+		if ruleChanged {
+			if transform.filter == nil {
+				transform.filter = filter.CloneVT()
+			}
+			transform.filter.Rules[rt.index].Filter = sqlparser.String(rt.sel)
+		}
+		if len(options) == 0 {
+			continue
+		}
+		targetAlter := &sqlparser.AlterTable{
+			Table:        sqlparser.NewTableName(rt.name),
+			AlterOptions: options,
+		}
+		createTable, err := showCreateTable(rt.name)
+		if err != nil {
+			return nil, err
+		}
+		entity, err := schemadiff.NewCreateTableEntityFromSQL(env, createTable)
+		if err != nil {
+			return nil, err
+		}
+		// Validate the translated statement against the target table.
+		if _, err := entity.Apply(schemadiff.EntityDiffByStatement(targetAlter)); err != nil {
+			return nil, untranslatable("cannot apply %s to table %s: %v", sqlparser.String(targetAlter), rt.name, err)
+		}
+		transform.targetDDLs = append(transform.targetDDLs, sqlparser.String(targetAlter))
+	}
+	return transform, nil
+}
+
+// applyTransformedDDL applies a DDL with the TRANSFORM OnDDL action.
+func (vp *vplayer) applyTransformedDDL(ctx context.Context, event *binlogdatapb.VEvent) error {
+	venv := vp.vr.vre.env
+	env := schemadiff.NewEnv(venv, venv.CollationEnv().DefaultConnectionCharset())
+	transform, err := buildDDLTransform(env, vp.vr.source.Filter, event.Statement, func(table string) (string, error) {
+		qr, err := vp.query(ctx, "show create table "+sqlescape.EscapeID(table))
+		if err != nil {
+			return "", err
+		}
+		if len(qr.Rows) != 1 || len(qr.Rows[0]) < 2 {
+			return "", fmt.Errorf("unexpected result for show create table %s: %v", table, qr.Rows)
+		}
+		return qr.Rows[0][1].ToString(), nil
+	})
+	var untranslatableErr *untranslatableDDLError
+	if errors.As(err, &untranslatableErr) {
+		if err := vp.vr.dbClient.Begin(); err != nil {
+			return err
+		}
+		if _, err := vp.updatePos(ctx, event.Timestamp); err != nil {
+			return err
+		}
+		if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, fmt.Sprintf("Stopped at DDL %s: %s", event.Statement, untranslatableErr.reason)); err != nil {
+			return err
+		}
+		if err := vp.commit(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	if err != nil {
+		return err
+	}
+
+	// The DDLs cannot be applied transactionally with the position. If they were applied
+	// first, a failure after some of them succeeded would replay the source DDL against
+	// the partially altered targets. So the filter and the position are saved first, and
+	// the stream is marked as stopped until all the DDLs are applied. It's left stopped,
+	// with the DDLs which remain to be applied in its message, if one of them fails.
+	if err := vp.vr.dbClient.Begin(); err != nil {
+		return err
+	}
+	source := vp.vr.source
+	if transform.filter != nil {
+		// The source of the stream is shared with its controller, so it's not modified in place.
+		source = vp.vr.source.CloneVT()
+		source.Filter = transform.filter
+		query := fmt.Sprintf("update _vt.vreplication set source = %s where id = %d", encodeString(source.String()), vp.vr.id)
+		if _, err := vp.query(ctx, query); err != nil {
+			return err
+		}
+	}
+	state := vp.vr.state
+	posReached, err := vp.updatePos(ctx, event.Timestamp)
+	if err != nil {
+		return err
+	}
+	if len(transform.targetDDLs) > 0 {
+		if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, fmt.Sprintf("Stopped while applying the DDLs %s transformed from DDL %s",
+			strings.Join(transform.targetDDLs, "; "), event.Statement)); err != nil {
+			return err
+		}
+	}
+	if err := vp.commit(); err != nil {
+		return err
+	}
+	vp.vr.source = source
+	if transform.isEmpty() {
+		if posReached {
+			return io.EOF
+		}
+		return nil
+	}
+
+	for i, ddl := range transform.targetDDLs {
+		if _, err := vp.query(ctx, ddl); err != nil {
+			msg := fmt.Sprintf("Stopped at DDL %s: failed to apply the transformed DDL %s: %v. Apply the DDLs %s on the target and start the workflow",
+				event.Statement, ddl, err, strings.Join(transform.targetDDLs[i:], "; "))
+			if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, msg); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+	}
+	if len(transform.targetDDLs) > 0 {
+		var err error
+		if posReached && vp.saveStop {
+			err = vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, fmt.Sprintf("Stopped at position %v", vp.stopPos))
+		} else {
+			err = vp.vr.setState(state, "")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	msg := fmt.Sprintf("Transformed DDL %s", event.Statement)
+	if len(transform.targetDDLs) > 0 {
+		msg += fmt.Sprintf(", applied %s", strings.Join(transform.targetDDLs, "; "))
+	}
+	if transform.filter != nil {
+		msg += fmt.Sprintf(", new filter %v", transform.filter)
+	}
+	log.Info(fmt.Sprintf("Workflow %s, stream %d: %s", vp.vr.WorkflowName, vp.vr.id, msg))
+	vp.vr.insertLog(LogDDLTransform, msg)
+	if posReached {
+		return io.EOF
+	}
+	return errDDLTransformed
+}
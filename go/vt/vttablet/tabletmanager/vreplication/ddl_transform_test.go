@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/vtenv"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func TestBuildDDLTransform(t *testing.T) {
+	targetTables := map[string]string{
+		"t1":   "create table t1 (id int, val varchar(128), primary key (id))",
+		"mat1": "create table mat1 (id int, v varchar(128), total int, primary key (id))",
+	}
+	showCreateTable := func(table string) (string, error) {
+		createTable, ok := targetTables[table]
+		if !ok {
+			return "", fmt.Errorf("table %s not found", table)
+		}
+		return createTable, nil
+	}
+	starFilter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t1"}},
+	}
+	columnsFilter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{Match: "mat1", Filter: "select id, val as v, price * qty as total from t1"}},
+	}
+
+	testCases := []struct {
+		name           string
+		filter         *binlogdatapb.Filter
+		ddl            string
+		wantDDLs       []string
+		wantFilter     string
+		untranslatable string
+	}{
+		{
+			name:     "add column selected by star",
+			filter:   starFilter,
+			ddl:      "alter table t1 add column c int",
+			wantDDLs: []string{"alter table t1 add column c int"},
+		},
+		{
+			name:   "add column not selected",
+			filter: columnsFilter,
+			ddl:    "alter table t1 add column c int",
+		},
+		{
+			name:   "other table",
+			filter: columnsFilter,
+			ddl:    "alter table t2 drop column val",
+		},
+		{
+			name:   "create other table",
+			filter: starFilter,
+			ddl:    "create table t2 (id int primary key)",
+		},
+		{
+			name:       "rename selected column",
+			filter:     columnsFilter,
+			ddl:        "alter table t1 rename column val to val2",
+			wantFilter: "select id, val2 as v, price * qty as total from t1",
+		},
+		{
+			name:       "rename column used in expression",
+			filter:     columnsFilter,
+			ddl:        "alter table t1 rename column qty to quantity",
+			wantFilter: "select id, val as v, price * quantity as total from t1",
+		},
+		{
+			name:     "modify selected column",
+			filter:   columnsFilter,
+			ddl:      "alter table t1 modify column val varchar(256), add column c int",
+			wantDDLs: []string{"alter table mat1 modify column v varchar(256)"},
+		},
+		{
+			name:           "modify column used in expression",
+			filter:         columnsFilter,
+			ddl:            "alter table t1 modify column price decimal(10,2)",
+			untranslatable: "column price is used in an expression by the filter of table mat1",
+		},
+		{
+			name:           "drop selected column",
+			filter:         columnsFilter,
+			ddl:            "alter table t1 drop column val",
+			untranslatable: "column val is used by the filter of table mat1",
+		},
+		{
+			name:   "drop column not selected",
+			filter: columnsFilter,
+			ddl:    "alter table t1 drop column c",
+		},
+		{
+			name:           "drop column missing on target",
+			filter:         starFilter,
+			ddl:            "alter table t1 drop column c",
+			untranslatable: "cannot apply alter table t1 drop column c to table t1",
+		},
+		{
+			name:           "change primary key",
+			filter:         starFilter,
+			ddl:            "alter table t1 drop primary key, add primary key (id, val)",
+			untranslatable: "changing the primary key of table t1 is not supported",
+		},
+		{
+			name:           "drop selected table",
+			filter:         starFilter,
+			ddl:            "drop table t1",
+			untranslatable: "DROP of table t1 is not supported",
+		},
+		{
+			name:   "excluded table",
+			filter: &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "t1", Filter: ExcludeStr}, {Match: "/.*"}}},
+			ddl:    "drop table t1",
+		},
+		{
+			name:     "table matched by regexp",
+			filter:   &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "/.*"}}},
+			ddl:      "alter table t1 add key val_idx (val), engine=InnoDB",
+			wantDDLs: []string{"alter table t1 add key val_idx (val)"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transform, err := buildDDLTransform(schemadiff.NewTestEnv(), tc.filter, tc.ddl, showCreateTable)
+			if tc.untranslatable != "" {
+				var untranslatableErr *untranslatableDDLError
+				require.ErrorAs(t, err, &untranslatableErr)
+				require.ErrorContains(t, err, tc.untranslatable)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantDDLs, transform.targetDDLs)
+			if tc.wantFilter == "" {
+				require.Nil(t, transform.filter)
+				return
+			}
+			require.NotNil(t, transform.filter)
+			require.Equal(t, tc.wantFilter, transform.filter.Rules[0].Filter)
+			// The filter of the stream itself must not be modified.
+			require.NotEqual(t, tc.wantFilter, tc.filter.Rules[0].Filter)
+		})
+	}
+}
+
+func TestApplyTransformedDDL(t *testing.T) {
+	dbClient := binlogplayer.NewMockDBClient(t)
+	stats := binlogplayer.NewStats()
+	defer stats.Stop()
+	config, err := vttablet.NewVReplicationConfig(nil)
+	require.NoError(t, err)
+	newPlayer := func(source *binlogdatapb.BinlogSource) *vplayer {
+		vr := &vreplicator{
+			id:             1,
+			WorkflowName:   "wf",
+			source:         source,
+			state:          binlogdatapb.VReplicationWorkflowState_Running,
+			workflowConfig: config,
+			dbClient:       newVDBClient(dbClient, stats, config.RelayLogMaxItems),
+			stats:          stats,
+			vre:            &Engine{env: vtenv.NewTestEnv()},
+		}
+		dbClient.ExpectRequest("select @@session.max_allowed_packet as max_allowed_packet",
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("max_allowed_packet", "int64"), "65536"), nil)
+		return newVPlayer(vr, binlogplayer.VRSettings{}, nil, replication.Position{}, "replicate")
+	}
+	ctx := context.Background()
+
+	// Renaming a column which is selected by the filter only changes the filter,
+	// which is not changed in place.
+	source := &binlogdatapb.BinlogSource{
+		Keyspace: "ks",
+		Shard:    "0",
+		OnDdl:    binlogdatapb.OnDDLAction_TRANSFORM,
+		Filter: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{Match: "mat1", Filter: "select id, val as v from t1"}},
+		},
+	}
+	vp := newPlayer(source)
+	dbClient.ExpectRequest("begin", nil, nil)
+	dbClient.ExpectRequestRE("update _vt.vreplication set source = .*val2 as v.* where id = 1", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequestRE("update _vt.vreplication set pos=.*", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("commit", nil, nil)
+	err = vp.applyTransformedDDL(ctx, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_DDL, Statement: "alter table t1 rename column val to val2"})
+	require.ErrorIs(t, err, errDDLTransformed)
+	require.Equal(t, "select id, val as v from t1", source.Filter.Rules[0].Filter)
+	require.Equal(t, "select id, val2 as v from t1", vp.vr.source.Filter.Rules[0].Filter)
+	dbClient.Wait()
+
+	// The position is saved before the DDLs are applied, and the stream is stopped
+	// with the remaining DDLs if one of them fails.
+	vp = newPlayer(&binlogdatapb.BinlogSource{
+		Keyspace: "ks",
+		Shard:    "0",
+		OnDdl:    binlogdatapb.OnDDLAction_TRANSFORM,
+		Filter: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{
+				{Match: "t1", Filter: "select * from t1"},
+				{Match: "t1copy", Filter: "select * from t1"},
+			},
+		},
+	})
+	for _, table := range []string{"t1", "t1copy"} {
+		dbClient.ExpectRequest(fmt.Sprintf("show create table `%s`", table), sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Create Table", "varchar|varchar"),
+			fmt.Sprintf("%s|create table %s (id int, val varchar(128), primary key (id))", table, table)), nil)
+	}
+	dbClient.ExpectRequest("begin", nil, nil)
+	dbClient.ExpectRequestRE("update _vt.vreplication set pos=.*", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequestRE("update _vt.vreplication set state='Stopped', message=left\\('Stopped while applying the DDLs alter table t1 add column c int; alter table t1copy add column c int.*", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("commit", nil, nil)
+	dbClient.ExpectRequest("alter table t1 add column c int", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("alter table t1copy add column c int", nil, fmt.Errorf("lock wait timeout"))
+	dbClient.ExpectRequestRE("update _vt.vreplication set state='Stopped', message=left\\('Stopped at DDL .*lock wait timeout. Apply the DDLs alter table t1copy add column c int on the target.*", &sqltypes.Result{}, nil)
+	err = vp.applyTransformedDDL(ctx, &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_DDL, Statement: "alter table t1 add column c int"})
+	require.ErrorIs(t, err, io.EOF)
+	dbClient.Wait()
+}
@@ -28,6 +28,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"google.golang.org/protobuf/encoding/prototext"
+
 	"vitess.io/vitess/go/constants/sidecar"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
@@ -688,10 +690,17 @@ func (vre *Engine) transitionJournal(je *journalEvent) {
 		log.Error(fmt.Sprintf("transitionJournal: %v", err))
 		return
 	}
+	// The filter of the stream may have been changed since it was started, by a DDL
+	// with the TRANSFORM action, so the source is read from its row.
+	refSource := &binlogdatapb.BinlogSource{}
+	if err := prototext.Unmarshal([]byte(params["source"]), refSource); err != nil {
+		log.Error(fmt.Sprintf("transitionJournal: %v", err))
+		return
+	}
 	var newids []int32
 	for _, shard := range shardGTIDs {
 		sgtid := je.shardGTIDs[shard]
-		bls := vre.controllers[refid].source.CloneVT()
+		bls := refSource.CloneVT()
 		bls.Keyspace, bls.Shard = sgtid.Keyspace, sgtid.Shard
 
 		workflowType, _ := strconv.ParseInt(params["workflow_type"], 10, 32)
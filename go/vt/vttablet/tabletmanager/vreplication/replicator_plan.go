@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	vttablet "vitess.io/vitess/go/vt/vttablet/common"
@@ -64,6 +65,7 @@ type ReplicatorPlan struct {
 	stats          *binlogplayer.Stats
 	Source         *binlogdatapb.BinlogSource
 	collationEnv   *collations.Environment
+	env            *vtenv.Environment
 	workflowConfig *vttablet.VReplicationConfig
 }
 
@@ -88,6 +90,9 @@ func (rp *ReplicatorPlan) buildExecutionPlan(fieldEvent *binlogdatapb.FieldEvent
 			trimmed.Name = strings.Trim(trimmed.Name, "`")
 			tplanv.Fields = append(tplanv.Fields, trimmed)
 		}
+		if err := tplanv.translateEvalColumns(); err != nil {
+			return nil, err
+		}
 		return &tplanv, nil
 	}
 	// select * construct was used. We need to use the field names.
@@ -110,6 +115,7 @@ func (rp *ReplicatorPlan) buildFromFields(tableName string, lastpk *sqltypes.Res
 		stats:          rp.stats,
 		source:         rp.Source,
 		collationEnv:   rp.collationEnv,
+		env:            rp.env,
 		workflowConfig: rp.workflowConfig,
 	}
 	for _, field := range fields {
@@ -201,10 +207,14 @@ type TablePlan struct {
 	// If the plan is an insertIgnore type, then Insert
 	// and Update contain 'insert ignore' statements and
 	// Delete is nil.
-	Insert           *sqlparser.ParsedQuery
-	Update           *sqlparser.ParsedQuery
-	Delete           *sqlparser.ParsedQuery
-	MultiDelete      *sqlparser.ParsedQuery
+	Insert      *sqlparser.ParsedQuery
+	Update      *sqlparser.ParsedQuery
+	Delete      *sqlparser.ParsedQuery
+	MultiDelete *sqlparser.ParsedQuery
+	// BeforeImageCheck is used by the bidirectional workflows to check
+	// that the row found on the target matches the before image of an
+	// update or a delete. It's nil for the other workflows.
+	BeforeImageCheck *sqlparser.ParsedQuery
 	Fields           []*querypb.Field
 	ConvertIntToEnum map[string]bool
 	// PKReferences is used to check if an event changed
@@ -216,6 +226,9 @@ type TablePlan struct {
 	FieldsToSkip            map[string]bool
 	ConvertCharset          map[string](*binlogdatapb.CharsetConversion)
 	HasExtraSourcePkColumns bool
+	// EvalColumns are the computed columns whose values are evaluated
+	// for every row before the generated queries are executed.
+	EvalColumns []*evalColumn
 
 	TablePlanBuilder *tablePlanBuilder
 	// PartialInserts is a dynamically generated cache of insert ParsedQueries, which update only some columns.
@@ -260,10 +273,24 @@ func (tp *TablePlan) applyBulkInsert(sqlbuffer *bytes2.Buffer, rows []*querypb.R
 	sqlbuffer.WriteString(tp.BulkInsertFront.Query)
 	sqlbuffer.WriteString(" values ")
 
+	// The bind locations of the computed columns do not match the fields
+	// one for one, so their rows have to go through the bind variables.
+	var rowValues *strings.Builder
+	if len(tp.EvalColumns) > 0 {
+		rowValues = &strings.Builder{}
+	}
 	for i, row := range rows {
 		if i > 0 {
 			sqlbuffer.WriteString(", ")
 		}
+		if rowValues != nil {
+			rowValues.Reset()
+			if err := tp.appendInsertValues(rowValues, row); err != nil {
+				return nil, err
+			}
+			sqlbuffer.WriteString(rowValues.String())
+			continue
+		}
 		if err := tp.appendFromRow(sqlbuffer, row); err != nil {
 			return nil, err
 		}
@@ -364,22 +391,36 @@ func (tp *TablePlan) bindFieldVal(field *querypb.Field, val *sqltypes.Value) (*q
 	return sqltypes.ValueBindVariable(*val), nil
 }
 
+// bindBeforeImage adds the values of the before image of a row change to the
+// bind variables, using the "b_" prefix.
+func (tp *TablePlan) bindBeforeImage(bindvars map[string]*querypb.BindVariable, before *querypb.Row) error {
+	vals := sqltypes.MakeRowTrusted(tp.Fields, before)
+	for i, field := range tp.Fields {
+		bindVar, err := tp.bindFieldVal(field, &vals[i])
+		if err != nil {
+			return err
+		}
+		bindvars["b_"+field.Name] = bindVar
+	}
+	return tp.evaluateColumns(bindvars, "b_", vals)
+}
+
 func (tp *TablePlan) applyChange(rowChange *binlogdatapb.RowChange, executor func(string) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
 	// MakeRowTrusted is needed here because Proto3ToResult is not convenient.
 	var (
 		before, after bool
 		afterVals     []sqltypes.Value
 	)
+	if len(tp.EvalColumns) > 0 && tp.isPartial(rowChange) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+			"binary log event for %s has partial values, which cannot be used to evaluate the expressions in the filter; you will need to re-run the workflow with binlog-row-image=FULL and binlog-row-value-options=''",
+			tp.TargetName)
+	}
 	bindvars := make(map[string]*querypb.BindVariable, len(tp.Fields))
 	if rowChange.Before != nil {
 		before = true
-		vals := sqltypes.MakeRowTrusted(tp.Fields, rowChange.Before)
-		for i, field := range tp.Fields {
-			bindVar, err := tp.bindFieldVal(field, &vals[i])
-			if err != nil {
-				return nil, err
-			}
-			bindvars["b_"+field.Name] = bindVar
+		if err := tp.bindBeforeImage(bindvars, rowChange.Before); err != nil {
+			return nil, err
 		}
 	}
 	if rowChange.After != nil {
@@ -429,6 +470,9 @@ func (tp *TablePlan) applyChange(rowChange *binlogdatapb.RowChange, executor fun
 			}
 			bindvars["a_"+field.Name] = bindVar
 		}
+		if err := tp.evaluateColumns(bindvars, "a_", afterVals); err != nil {
+			return nil, err
+		}
 	}
 	switch {
 	case !before && after:
@@ -622,34 +666,8 @@ func (tp *TablePlan) applyBulkInsertChanges(rowInserts []*binlogdatapb.RowChange
 
 	newStmt := true
 	for _, rowInsert := range rowInserts {
-		var (
-			err     error
-			bindVar *querypb.BindVariable
-		)
 		rowValues := &strings.Builder{}
-		bindvars := make(map[string]*querypb.BindVariable, len(tp.Fields))
-		vals := sqltypes.MakeRowTrusted(tp.Fields, rowInsert.After)
-		for n, field := range tp.Fields {
-			if field.Type == querypb.Type_JSON {
-				var jsVal *sqltypes.Value
-				if vals[n].IsNull() { // An SQL NULL and not an actual JSON value
-					jsVal = &sqltypes.NULL
-				} else { // A JSON value (which may be a JSON null literal value)
-					jsVal, err = vjson.MarshalSQLValue(vals[n].Raw())
-					if err != nil {
-						return nil, err
-					}
-				}
-				bindVar, err = tp.bindFieldVal(field, jsVal)
-			} else {
-				bindVar, err = tp.bindFieldVal(field, &vals[n])
-			}
-			if err != nil {
-				return nil, err
-			}
-			bindvars["a_"+field.Name] = bindVar
-		}
-		if err := tp.BulkInsertValues.Append(rowValues, bindvars, nil); err != nil {
+		if err := tp.appendInsertValues(rowValues, rowInsert.After); err != nil {
 			return nil, err
 		}
 		if int64(values.Len()+2+rowValues.Len()) > maxQuerySize { // Plus 2 for the comma and space
@@ -669,6 +687,41 @@ func (tp *TablePlan) applyBulkInsertChanges(rowInserts []*binlogdatapb.RowChange
 	return execQuery(values)
 }
 
+// appendInsertValues binds the values of the row, and of the computed columns,
+// to the BulkInsertValues query and appends the result to buf.
+func (tp *TablePlan) appendInsertValues(buf *strings.Builder, row *querypb.Row) error {
+	var (
+		err     error
+		bindVar *querypb.BindVariable
+	)
+	bindvars := make(map[string]*querypb.BindVariable, len(tp.Fields)+len(tp.EvalColumns))
+	vals := sqltypes.MakeRowTrusted(tp.Fields, row)
+	for n, field := range tp.Fields {
+		if field.Type == querypb.Type_JSON {
+			var jsVal *sqltypes.Value
+			if vals[n].IsNull() { // An SQL NULL and not an actual JSON value
+				jsVal = &sqltypes.NULL
+			} else { // A JSON value (which may be a JSON null literal value)
+				jsVal, err = vjson.MarshalSQLValue(vals[n].Raw())
+				if err != nil {
+					return err
+				}
+			}
+			bindVar, err = tp.bindFieldVal(field, jsVal)
+		} else {
+			bindVar, err = tp.bindFieldVal(field, &vals[n])
+		}
+		if err != nil {
+			return err
+		}
+		bindvars["a_"+field.Name] = bindVar
+	}
+	if err := tp.evaluateColumns(bindvars, "a_", vals); err != nil {
+		return err
+	}
+	return tp.BulkInsertValues.Append(buf, bindvars, nil)
+}
+
 func getQuery(pq *sqlparser.ParsedQuery, bindvars map[string]*querypb.BindVariable) (string, error) {
 	sql, err := pq.GenerateQuery(bindvars, nil)
 	if err != nil {
@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -743,7 +744,7 @@ func TestBuildPlayerPlan(t *testing.T) {
 		vr := &vreplicator{
 			workflowConfig: vttablet.DefaultVReplicationConfig,
 		}
-		plan, err := vr.buildReplicatorPlan(getSource(tcase.input), PrimaryKeyInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+		plan, err := vr.buildReplicatorPlan(getSource(tcase.input), PrimaryKeyInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
 		gotErr := ""
 		if err != nil {
 			gotErr = err.Error()
@@ -752,7 +753,7 @@ func TestBuildPlayerPlan(t *testing.T) {
 		gotPlan, _ := json.Marshal(plan)
 		wantPlan, _ := json.Marshal(tcase.plan)
 		require.Equal(t, string(wantPlan), string(gotPlan), "Filter(%v):\n%s, want\n%s", tcase.input, gotPlan, wantPlan)
-		plan, err = vr.buildReplicatorPlan(getSource(tcase.input), PrimaryKeyInfos, copyState, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+		plan, err = vr.buildReplicatorPlan(getSource(tcase.input), PrimaryKeyInfos, copyState, binlogplayer.NewStats(), vtenv.NewTestEnv())
 		if err != nil {
 			continue
 		}
@@ -783,7 +784,7 @@ func TestBuildPlayerPlanNoDup(t *testing.T) {
 	vr := &vreplicator{
 		workflowConfig: vttablet.DefaultVReplicationConfig,
 	}
-	_, err := vr.buildReplicatorPlan(getSource(input), PrimaryKeyInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+	_, err := vr.buildReplicatorPlan(getSource(input), PrimaryKeyInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
 	want := "more than one target for source table t"
 	if err == nil || !strings.Contains(err.Error(), want) {
 		t.Errorf("buildReplicatorPlan err: %v, must contain: %v", err, want)
@@ -807,7 +808,7 @@ func TestBuildPlayerPlanExclude(t *testing.T) {
 	vr := &vreplicator{
 		workflowConfig: vttablet.DefaultVReplicationConfig,
 	}
-	plan, err := vr.buildReplicatorPlan(getSource(input), PrimaryKeyInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+	plan, err := vr.buildReplicatorPlan(getSource(input), PrimaryKeyInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
 	assert.NoError(t, err)
 
 	want := &TestReplicatorPlan{
@@ -964,3 +965,135 @@ func TestAppendFromRow(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateColumns(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			&ColumnInfo{Name: "id", IsPK: true},
+			&ColumnInfo{Name: "name"},
+			&ColumnInfo{Name: "email"},
+			&ColumnInfo{Name: "tier"},
+		},
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match: "t1",
+			Filter: "select id, concat(left(name, 1), '***') as name, sha2(email, 256) as email, " +
+				"case when score > 100 then 'gold' else 'silver' end as tier from src",
+		}},
+	}
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	// The expressions are evaluated by MySQL unless the workflow opts in.
+	plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
+	require.NoError(t, err)
+	require.Empty(t, plan.TablePlans["src"].EvalColumns)
+
+	config, err := vttablet.NewVReplicationConfig(map[string]string{"vreplication-evaluate-filter-expressions": "true"})
+	require.NoError(t, err)
+	vr.workflowConfig = config
+	plan, err = vr.buildReplicatorPlan(getSource(filter), colInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
+	require.NoError(t, err)
+	require.Equal(t, "select id, `name`, email, score from src", plan.VStreamFilter.Rules[0].Filter)
+
+	fields := sqltypes.MakeTestFields("id|name|email|score", "int64|varchar|varchar|int64")
+	tp, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "src", Fields: fields})
+	require.NoError(t, err)
+	require.Len(t, tp.EvalColumns, 3)
+
+	var queries []string
+	executor := func(query string) (*sqltypes.Result, error) {
+		queries = append(queries, query)
+		return &sqltypes.Result{}, nil
+	}
+	row := func(vals ...sqltypes.Value) *querypb.Row {
+		return sqltypes.RowToProto3(vals)
+	}
+	before := row(sqltypes.NewInt64(1), sqltypes.NewVarChar("alice"), sqltypes.NewVarChar("a@x.com"), sqltypes.NewInt64(50))
+	after := row(sqltypes.NewInt64(1), sqltypes.NewVarChar("alice"), sqltypes.NULL, sqltypes.NewInt64(150))
+
+	_, err = tp.applyChange(&binlogdatapb.RowChange{After: before}, executor)
+	require.NoError(t, err)
+	_, err = tp.applyChange(&binlogdatapb.RowChange{Before: before, After: after}, executor)
+	require.NoError(t, err)
+	_, err = tp.applyBulkInsert(&bytes2.Buffer{}, []*querypb.Row{before, after}, executor)
+	require.NoError(t, err)
+
+	hash := "'478abec7430569163161dfea8513b8ce89d05f559456a26e945c66e1fe55a29d'"
+	require.Equal(t, []string{
+		"insert into t1(id,`name`,email,tier) values (1,'a***'," + hash + ",'silver')",
+		"update t1 set `name`='a***', email=null, tier='gold' where id=1",
+		"insert into t1(id,`name`,email,tier) values (1,'a***'," + hash + ",'silver'), (1,'a***',null,'gold')",
+	}, queries)
+
+	// Partial row images do not have all the values that are needed.
+	_, err = tp.applyChange(&binlogdatapb.RowChange{
+		After:       after,
+		DataColumns: &binlogdatapb.RowChange_Bitmap{Count: 4, Cols: []byte{0x0b}},
+	}, executor)
+	require.ErrorContains(t, err, "binary log event for t1 has partial values")
+}
+
+func TestEvaluateColumnsCharset(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			&ColumnInfo{Name: "id", IsPK: true},
+			&ColumnInfo{Name: "name"},
+			&ColumnInfo{Name: "created"},
+		},
+	}
+	config, err := vttablet.NewVReplicationConfig(map[string]string{"vreplication-evaluate-filter-expressions": "true"})
+	require.NoError(t, err)
+	vr := &vreplicator{
+		workflowConfig: config,
+	}
+	latin1 := collations.MySQL8().LookupByName("latin1_swedish_ci")
+	utf8mb4 := collations.MySQL8().DefaultConnectionCharset()
+	row := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.MakeTrusted(sqltypes.VarChar, []byte("\xe9t\xe9"))})
+
+	testcases := []struct {
+		name           string
+		charset        collations.ID
+		convertCharset map[string]*binlogdatapb.CharsetConversion
+	}{{
+		name:    "source collation",
+		charset: latin1,
+	}, {
+		name:    "charset conversion",
+		charset: utf8mb4,
+		convertCharset: map[string]*binlogdatapb.CharsetConversion{
+			"name": {FromCharset: "latin1", ToCharset: "utf8mb4"},
+		},
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter := &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:          "t1",
+					Filter:         "select id, upper(name) as name, now() as created from src",
+					ConvertCharset: tc.convertCharset,
+				}},
+			}
+			plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
+			require.NoError(t, err)
+			fields := []*querypb.Field{
+				{Name: "id", Type: sqltypes.Int64, Charset: uint32(collations.CollationBinaryID)},
+				{Name: "name", Type: sqltypes.VarChar, Charset: uint32(tc.charset)},
+			}
+			tp, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "src", Fields: fields})
+			require.NoError(t, err)
+			// Non-deterministic expressions are still evaluated by MySQL.
+			require.Len(t, tp.EvalColumns, 1)
+
+			var queries []string
+			_, err = tp.applyChange(&binlogdatapb.RowChange{After: row}, func(query string) (*sqltypes.Result, error) {
+				queries = append(queries, query)
+				return &sqltypes.Result{}, nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, []string{"insert into t1(id,`name`,created) values (1,'ÉTÉ',now())"}, queries)
+		})
+	}
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Sink is a destination for the row changes of a workflow other than the
+// tables of the target database, e.g. a file or a message bus. It's set
+// using the vreplication-sink config of the workflow.
+//
+// Workflows that use a sink go through the same copy, catchup and
+// replication phases as any other workflow, and their state, such as the
+// position and the copy state, is still stored in the sidecar database.
+// The target tables must exist, as they define the columns of the rows
+// that are written to the sink, but their contents are never changed.
+//
+// A sink is flushed before the state of the workflow is saved, so every
+// change is delivered at least once: the changes that were flushed after
+// the last time the state was saved are written again when the workflow
+// is restarted. In the replication phase, the sink is flushed and the
+// position is saved at most about once a second.
+//
+// As the target tables are never changed, the on-ddl setting of the
+// workflow must be IGNORE or STOP.
+type Sink interface {
+	// Write adds the row changes to the sink. They don't have to be
+	// durable until Flush is called. Write can be called concurrently
+	// by the copy workers.
+	Write(ctx context.Context, changes []*SinkRowChange) error
+	// Flush makes all the row changes written so far durable.
+	Flush(ctx context.Context) error
+	// Close releases the resources of the sink. The changes that were
+	// not flushed are discarded.
+	Close() error
+}
+
+// SinkParams are the parameters used to create a Sink.
+type SinkParams struct {
+	// URL is the parsed value of the vreplication-sink config. Its scheme
+	// selects the sink.
+	URL *url.URL
+	// Workflow is the name of the workflow.
+	Workflow string
+	// StreamID is the id of the stream in the vreplication table. There
+	// is a sink per stream, e.g. per source shard of a workflow.
+	StreamID int32
+}
+
+// SinkFactory creates a Sink.
+type SinkFactory func(params *SinkParams) (Sink, error)
+
+var (
+	sinkFactoriesMu sync.Mutex
+	sinkFactories   = make(map[string]SinkFactory)
+)
+
+// RegisterSink registers the factory of the sinks for the URL scheme.
+// It's meant to be called from init functions.
+func RegisterSink(scheme string, factory SinkFactory) {
+	sinkFactoriesMu.Lock()
+	defer sinkFactoriesMu.Unlock()
+	if _, ok := sinkFactories[scheme]; ok {
+		panic(fmt.Sprintf("sink %s is already registered", scheme))
+	}
+	sinkFactories[scheme] = factory
+}
+
+// newSink creates the Sink for the vreplication-sink config of the workflow.
+func newSink(sinkURL, workflow string, streamID int32) (Sink, error) {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid vreplication-sink %s", sinkURL)
+	}
+	sinkFactoriesMu.Lock()
+	factory, ok := sinkFactories[u.Scheme]
+	sinkFactoriesMu.Unlock()
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown sink %q in vreplication-sink %s", u.Scheme, sinkURL)
+	}
+	return factory(&SinkParams{
+		URL:      u,
+		Workflow: workflow,
+		StreamID: streamID,
+	})
+}
+
+// SinkOp is the operation of a SinkRowChange.
+type SinkOp string
+
+const (
+	// SinkOpCopy is used for the rows that are copied in the copy phase.
+	SinkOpCopy   = SinkOp("copy")
+	SinkOpInsert = SinkOp("insert")
+	SinkOpUpdate = SinkOp("update")
+	SinkOpDelete = SinkOp("delete")
+)
+
+// SinkRowChange is a change to a row of a target table.
+type SinkRowChange struct {
+	Workflow string
+	Table    string
+	Op       SinkOp
+	// Columns are the columns of the target table.
+	Columns []string
+	// PKColumns contains the offsets of the primary key columns in Columns.
+	PKColumns []int
+	// Before is nil for copies and inserts, and After is nil for deletes.
+	Before []sqltypes.Value
+	After  []sqltypes.Value
+	// Position is the position of the source transaction that made the
+	// change. It's empty in the copy phase.
+	Position string
+	// Timestamp is the time, in seconds, at which the source transaction
+	// was committed. It's zero in the copy phase.
+	Timestamp int64
+}
+
+// MarshalJSON returns the change as a JSON object that has the workflow,
+// table, op, before, after, position and ts keys. The row images are
+// objects keyed by the column names.
+func (rc *SinkRowChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Workflow  string         `json:"workflow"`
+		Table     string         `json:"table"`
+		Op        SinkOp         `json:"op"`
+		Before    map[string]any `json:"before"`
+		After     map[string]any `json:"after"`
+		Position  string         `json:"position,omitempty"`
+		Timestamp int64          `json:"ts,omitempty"`
+	}{
+		Workflow:  rc.Workflow,
+		Table:     rc.Table,
+		Op:        rc.Op,
+		Before:    rc.rowJSON(rc.Before, nil),
+		After:     rc.rowJSON(rc.After, nil),
+		Position:  rc.Position,
+		Timestamp: rc.Timestamp,
+	})
+}
+
+// Key returns the primary key of the row as a JSON object. It can be used
+// to route all the changes to a row to the same partition of a message bus.
+func (rc *SinkRowChange) Key() ([]byte, error) {
+	row := rc.After
+	if row == nil {
+		row = rc.Before
+	}
+	return json.Marshal(rc.rowJSON(row, rc.PKColumns))
+}
+
+func (rc *SinkRowChange) rowJSON(row []sqltypes.Value, offsets []int) map[string]any {
+	if row == nil {
+		return nil
+	}
+	if offsets == nil {
+		offsets = make([]int, len(rc.Columns))
+		for i := range offsets {
+			offsets[i] = i
+		}
+	}
+	out := make(map[string]any, len(offsets))
+	for _, i := range offsets {
+		out[rc.Columns[i]] = sinkValueJSON(row[i])
+	}
+	return out
+}
+
+// sinkValueJSON returns the value so that it's marshalled as a JSON number
+// for numeric types, a JSON document for JSON columns, a base64 string for
+// binary types and a string for everything else.
+func sinkValueJSON(v sqltypes.Value) any {
+	switch {
+	case v.IsNull():
+		return nil
+	case v.IsIntegral(), v.IsFloat(), v.IsDecimal():
+		return json.RawMessage(v.Raw())
+	case v.Type() == querypb.Type_JSON:
+		if json.Valid(v.Raw()) {
+			return json.RawMessage(v.Raw())
+		}
+		return v.ToString()
+	case v.IsBinary():
+		return v.Raw()
+	default:
+		return v.ToString()
+	}
+}
+
+// sinkColumn maps a column of the target table to the value that is
+// written to a sink.
+type sinkColumn struct {
+	name string
+	isPK bool
+	// field is the offset of the value in the rows streamed from the
+	// source, when the column is not computed.
+	field int
+	eval  *evalColumn
+}
+
+// sinkColumns returns the columns of the target table that are written to
+// sinks. Aggregations, and expressions that can only be evaluated by the
+// target, are not supported.
+func (tp *TablePlan) sinkColumns() ([]*sinkColumn, error) {
+	tpb := tp.TablePlanBuilder
+	if tpb == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "plan for %s is not complete", tp.TargetName)
+	}
+	if tpb.onInsert != insertNormal {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "group by is not supported by sinks for table %s", tp.TargetName)
+	}
+	columns := make([]*sinkColumn, 0, len(tpb.colExprs))
+	for _, cexpr := range tpb.colExprs {
+		if cexpr.isGenerated {
+			continue
+		}
+		col := &sinkColumn{
+			name:  cexpr.colName.String(),
+			isPK:  cexpr.isPK,
+			field: -1,
+		}
+		if cexpr.evalColumn != nil {
+			// The expression of the column is translated using the types of
+			// the fields once they are received.
+			for _, ec := range tp.EvalColumns {
+				if ec.name == cexpr.evalColumn.name {
+					col.eval = ec
+					break
+				}
+			}
+			if col.eval == nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "expression for %s.%s not found in the plan", tp.TargetName, col.name)
+			}
+		} else {
+			// The column either matches a source column, or it's converted
+			// and then aliased in the select that is sent to the source.
+			fieldName := cexpr.colName.String()
+			switch expr := cexpr.expr.(type) {
+			case *sqlparser.ColName:
+				fieldName = expr.Name.String()
+			case *sqlparser.ConvertUsingExpr:
+			default:
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expression for %s.%s is not supported by sinks: %s",
+					tp.TargetName, col.name, sqlparser.String(cexpr.expr))
+			}
+			for i, field := range tp.Fields {
+				if strings.EqualFold(strings.Trim(field.Name, "`"), fieldName) {
+					col.field = i
+					break
+				}
+			}
+			if col.field < 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "column %s not found in the fields of %s", fieldName, tp.TargetName)
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// sinkRow returns the values of the target columns for a row image. The
+// values of the columns of ConvertCharset are converted to their target
+// charset, like they are when they are written to the target tables.
+func (tp *TablePlan) sinkRow(exprEnv *evalengine.ExpressionEnv, columns []*sinkColumn, vals []sqltypes.Value) ([]sqltypes.Value, error) {
+	vals, err := tp.convertCharsets(vals)
+	if err != nil {
+		return nil, err
+	}
+	row := make([]sqltypes.Value, len(columns))
+	for i, col := range columns {
+		if col.eval == nil {
+			row[i] = vals[col.field]
+			continue
+		}
+		val, err := tp.evaluateColumn(exprEnv, col.eval, vals)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = val
+	}
+	return row, nil
+}
+
+// sinkRowChanges converts row changes streamed from the source to the row
+// changes of the target table. Copied rows are passed as after images.
+func (tp *TablePlan) sinkRowChanges(workflow string, rowChanges []*binlogdatapb.RowChange, pos string, ts int64) ([]*SinkRowChange, error) {
+	columns, err := tp.sinkColumns()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(columns))
+	var pkColumns []int
+	for i, col := range columns {
+		names[i] = col.name
+		if col.isPK {
+			pkColumns = append(pkColumns, i)
+		}
+	}
+	exprEnv := evalengine.EmptyExpressionEnv(tp.TablePlanBuilder.env)
+	changes := make([]*SinkRowChange, 0, len(rowChanges))
+	for _, rowChange := range rowChanges {
+		if tp.isPartial(rowChange) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+				"binary log event for %s has partial values, which are not supported by sinks; you will need to re-run the workflow with binlog-row-image=FULL and binlog-row-value-options=''",
+				tp.TargetName)
+		}
+		before, after := rowChange.Before, rowChange.After
+		if tp.Lastpk != nil && pos != "" {
+			// Only the changes to the rows that were already copied are
+			// replicated while the table is being copied, like the target
+			// does with the "(pk) <= (lastpk)" condition of the queries.
+			if before, err = tp.sinkCopiedRow(before); err != nil {
+				return nil, err
+			}
+			if after, err = tp.sinkCopiedRow(after); err != nil {
+				return nil, err
+			}
+			if before == nil && after == nil {
+				continue
+			}
+		}
+		change := &SinkRowChange{
+			Workflow:  workflow,
+			Table:     tp.TargetName,
+			Columns:   names,
+			PKColumns: pkColumns,
+			Position:  pos,
+			Timestamp: ts,
+		}
+		if before != nil {
+			if change.Before, err = tp.sinkRow(exprEnv, columns, sqltypes.MakeRowTrusted(tp.Fields, before)); err != nil {
+				return nil, err
+			}
+		}
+		if after != nil {
+			if change.After, err = tp.sinkRow(exprEnv, columns, sqltypes.MakeRowTrusted(tp.Fields, after)); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case pos == "":
+			change.Op = SinkOpCopy
+		case change.Before == nil:
+			change.Op = SinkOpInsert
+		case change.After == nil:
+			change.Op = SinkOpDelete
+		default:
+			change.Op = SinkOpUpdate
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// sinkCopiedRow returns the row image if its primary key is not after the
+// last primary key that was copied, and nil otherwise.
+func (tp *TablePlan) sinkCopiedRow(row *querypb.Row) (*querypb.Row, error) {
+	if row == nil {
+		return nil, nil
+	}
+	vals := sqltypes.MakeRowTrusted(tp.Fields, row)
+	for i, pkField := range tp.Lastpk.Fields {
+		offset := -1
+		for j, field := range tp.Fields {
+			if strings.EqualFold(field.Name, pkField.Name) {
+				offset = j
+				break
+			}
+		}
+		if offset < 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "primary key column %s not found in the fields of %s", pkField.Name, tp.TargetName)
+		}
+		result, err := evalengine.NullsafeCompare(vals[offset], tp.Lastpk.Rows[0][i], tp.CollationEnv, collations.ID(tp.Fields[offset].Charset), nil)
+		if err != nil {
+			return nil, err
+		}
+		if result < 0 {
+			return row, nil
+		}
+		if result > 0 {
+			return nil, nil
+		}
+	}
+	return row, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func init() {
+	RegisterSink("file", newFileSink)
+}
+
+// fileSink appends the row changes as newline delimited JSON objects to
+// a file per stream, <dir>/<workflow>.<stream id>.ndjson, where dir is
+// the path of the file:///dir URL.
+type fileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	pending bytes.Buffer
+}
+
+func newFileSink(params *SinkParams) (Sink, error) {
+	dir := params.URL.Path
+	if dir == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "file sink needs a directory, e.g. file:///var/vt/cdc")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := filepath.Join(dir, fmt.Sprintf("%s.%d.ndjson", params.Workflow, params.StreamID))
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+// Write implements Sink.
+func (fs *fileSink) Write(ctx context.Context, changes []*SinkRowChange) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for _, change := range changes {
+		b, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+		fs.pending.Write(b)
+		fs.pending.WriteByte('\n')
+	}
+	return nil
+}
+
+// Flush implements Sink.
+func (fs *fileSink) Flush(ctx context.Context) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.pending.Len() == 0 {
+		return nil
+	}
+	if _, err := fs.file.Write(fs.pending.Bytes()); err != nil {
+		return err
+	}
+	fs.pending.Reset()
+	return fs.file.Sync()
+}
+
+// Close implements Sink.
+func (fs *fileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.pending.Reset()
+	return fs.file.Close()
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/vtenv"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// buildSinkTestPlan returns the plan of a table that has a copied column,
+// a converted column and a computed one.
+func buildSinkTestPlan(t *testing.T, lastpk *sqltypes.Result) *TablePlan {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			&ColumnInfo{Name: "id", IsPK: true},
+			&ColumnInfo{Name: "name"},
+			&ColumnInfo{Name: "doc"},
+			&ColumnInfo{Name: "masked"},
+			&ColumnInfo{Name: "gen", IsGenerated: true},
+		},
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select id, convert(val using utf8mb4) as name, doc, concat(left(secret, 2), '**') as masked from src",
+		}},
+	}
+	config, err := vttablet.NewVReplicationConfig(map[string]string{"vreplication-sink": "file:///tmp/cdc"})
+	require.NoError(t, err)
+	vr := &vreplicator{
+		workflowConfig: config,
+	}
+	var copyState map[string]*sqltypes.Result
+	if lastpk != nil {
+		copyState = map[string]*sqltypes.Result{"t1": lastpk}
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, copyState, binlogplayer.NewStats(), vtenv.NewTestEnv())
+	require.NoError(t, err)
+	fields := sqltypes.MakeTestFields("id|name|doc|secret", "int64|varchar|json|varbinary")
+	tp, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "src", Fields: fields})
+	require.NoError(t, err)
+	return tp
+}
+
+func sinkTestRow(id int64, name string, secret string) *querypb.Row {
+	return sqltypes.RowToProto3([]sqltypes.Value{
+		sqltypes.NewInt64(id),
+		sqltypes.NewVarChar(name),
+		sqltypes.MakeTrusted(querypb.Type_JSON, []byte(`{"a":[1,2]}`)),
+		sqltypes.NewVarBinary(secret),
+	})
+}
+
+func TestSinkRowChanges(t *testing.T) {
+	tp := buildSinkTestPlan(t, nil)
+
+	changes, err := tp.sinkRowChanges("wf", []*binlogdatapb.RowChange{
+		{After: sinkTestRow(1, "alice", "s3cret")},
+		{Before: sinkTestRow(1, "alice", "s3cret"), After: sinkTestRow(1, "bob", "s3cret")},
+		{Before: sinkTestRow(1, "bob", "s3cret")},
+	}, "MySQL56/00000000-0000-0000-0000-000000000001:1-10", 1700000000)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	var got []string
+	for _, change := range changes {
+		b, err := json.Marshal(change)
+		require.NoError(t, err)
+		got = append(got, string(b))
+	}
+	pos := `"position":"MySQL56/00000000-0000-0000-0000-000000000001:1-10","ts":1700000000`
+	require.Equal(t, []string{
+		`{"workflow":"wf","table":"t1","op":"insert","before":null,"after":{"doc":{"a":[1,2]},"id":1,"masked":"s3**","name":"alice"},` + pos + `}`,
+		`{"workflow":"wf","table":"t1","op":"update","before":{"doc":{"a":[1,2]},"id":1,"masked":"s3**","name":"alice"},"after":{"doc":{"a":[1,2]},"id":1,"masked":"s3**","name":"bob"},` + pos + `}`,
+		`{"workflow":"wf","table":"t1","op":"delete","before":{"doc":{"a":[1,2]},"id":1,"masked":"s3**","name":"bob"},"after":null,` + pos + `}`,
+	}, got)
+
+	key, err := changes[2].Key()
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1}`, string(key))
+
+	// Copied rows have no position.
+	changes, err = tp.sinkRowChanges("wf", []*binlogdatapb.RowChange{{After: sinkTestRow(2, "carol", "pw")}}, "", 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, SinkOpCopy, changes[0].Op)
+
+	// Partial row images can't be written.
+	_, err = tp.sinkRowChanges("wf", []*binlogdatapb.RowChange{{
+		After:       sinkTestRow(1, "alice", "s3cret"),
+		DataColumns: &binlogdatapb.RowChange_Bitmap{Count: 4, Cols: []byte{0x07}},
+	}}, "pos", 1)
+	require.ErrorContains(t, err, "partial values, which are not supported by sinks")
+}
+
+func TestSinkRowChangesConvertCharset(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			&ColumnInfo{Name: "id", IsPK: true},
+			&ColumnInfo{Name: "name"},
+			&ColumnInfo{Name: "shout"},
+		},
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select id, name, upper(name) as shout from src",
+			ConvertCharset: map[string]*binlogdatapb.CharsetConversion{
+				"name": {FromCharset: "latin1", ToCharset: "utf8mb4"},
+			},
+		}},
+	}
+	config, err := vttablet.NewVReplicationConfig(map[string]string{"vreplication-sink": "file:///tmp/cdc"})
+	require.NoError(t, err)
+	vr := &vreplicator{
+		workflowConfig: config,
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
+	require.NoError(t, err)
+	fields := sqltypes.MakeTestFields("id|name", "int64|varchar")
+	tp, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "src", Fields: fields})
+	require.NoError(t, err)
+
+	row := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.MakeTrusted(sqltypes.VarChar, []byte("\xe9t\xe9"))})
+	changes, err := tp.sinkRowChanges("wf", []*binlogdatapb.RowChange{{After: row}}, "pos", 1)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	b, err := json.Marshal(changes[0].After)
+	require.NoError(t, err)
+	require.Equal(t, `[1,"été","ÉTÉ"]`, string(b))
+}
+
+func TestSinkRowChangesCopiedRows(t *testing.T) {
+	tp := buildSinkTestPlan(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "5"))
+
+	changes, err := tp.sinkRowChanges("wf", []*binlogdatapb.RowChange{
+		// Only the rows up to the last primary key that was copied are replicated.
+		{After: sinkTestRow(5, "a", "")},
+		{After: sinkTestRow(6, "b", "")},
+		// Moving a row out of the copied range deletes it, and moving it
+		// into the copied range inserts it.
+		{Before: sinkTestRow(4, "c", ""), After: sinkTestRow(7, "c", "")},
+		{Before: sinkTestRow(8, "d", ""), After: sinkTestRow(3, "d", "")},
+		{Before: sinkTestRow(9, "e", "")},
+	}, "pos", 1)
+	require.NoError(t, err)
+	var got []string
+	for _, change := range changes {
+		key, err := change.Key()
+		require.NoError(t, err)
+		got = append(got, string(change.Op)+" "+string(key))
+	}
+	require.Equal(t, []string{`insert {"id":5}`, `delete {"id":4}`, `insert {"id":3}`}, got)
+}
+
+func TestSinkColumnsUnsupported(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			&ColumnInfo{Name: "id", IsPK: true},
+			&ColumnInfo{Name: "cnt"},
+		},
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select id, count(*) as cnt from src group by id",
+		}},
+	}
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(filter), colInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
+	require.NoError(t, err)
+	tp, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "src",
+		Fields:    sqltypes.MakeTestFields("id", "int64"),
+	})
+	require.NoError(t, err)
+	_, err = tp.sinkRowChanges("wf", []*binlogdatapb.RowChange{{After: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1)})}}, "pos", 1)
+	require.ErrorContains(t, err, "group by is not supported by sinks")
+}
+
+func TestNewSink(t *testing.T) {
+	_, err := newSink("nosuch://x", "wf", 1)
+	require.ErrorContains(t, err, `unknown sink "nosuch"`)
+}
+
+func TestFileSink(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "cdc")
+	sink, err := newSink("file://"+dir, "wf", 3)
+	require.NoError(t, err)
+
+	change := func(id int64) *SinkRowChange {
+		return &SinkRowChange{
+			Workflow:  "wf",
+			Table:     "t1",
+			Op:        SinkOpInsert,
+			Columns:   []string{"id", "val"},
+			PKColumns: []int{0},
+			After:     []sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NULL},
+			Position:  "pos",
+			Timestamp: 1,
+		}
+	}
+	name := filepath.Join(dir, "wf.3.ndjson")
+	require.NoError(t, sink.Write(ctx, []*SinkRowChange{change(1), change(2)}))
+	b, err := os.ReadFile(name)
+	require.NoError(t, err)
+	require.Empty(t, b, "changes must not be written before the flush")
+
+	require.NoError(t, sink.Flush(ctx))
+	// Changes that were not flushed are discarded.
+	require.NoError(t, sink.Write(ctx, []*SinkRowChange{change(3)}))
+	require.NoError(t, sink.Close())
+
+	// A restarted workflow appends to the same file.
+	sink, err = newSink("file://"+dir, "wf", 3)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(ctx, []*SinkRowChange{change(4)}))
+	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Close())
+
+	b, err = os.ReadFile(name)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Equal(t, []string{
+		`{"workflow":"wf","table":"t1","op":"insert","before":null,"after":{"id":1,"val":null},"position":"pos","ts":1}`,
+		`{"workflow":"wf","table":"t1","op":"insert","before":null,"after":{"id":2,"val":null},"position":"pos","ts":1}`,
+		`{"workflow":"wf","table":"t1","op":"insert","before":null,"after":{"id":4,"val":null},"position":"pos","ts":1}`,
+	}, lines)
+}
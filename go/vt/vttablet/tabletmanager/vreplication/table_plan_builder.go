@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -63,6 +64,7 @@ type tablePlanBuilder struct {
 	pkIndices         []bool
 
 	collationEnv   *collations.Environment
+	env            *vtenv.Environment
 	workflowConfig *vttablet.VReplicationConfig
 }
 
@@ -80,6 +82,9 @@ type colExpr struct {
 	expr sqlparser.Expr
 	// references contains all the column names referenced in the expression.
 	references map[string]bool
+	// evalColumn is set if the expression is evaluated by the evalengine
+	// for every row. If so, expr is a placeholder for the result.
+	evalColumn *evalColumn
 
 	isGrouped   bool
 	isPK        bool
@@ -134,7 +139,7 @@ const (
 // The TablePlan built is a partial plan. The full plan for a table is built
 // when we receive field information from events or rows sent by the source.
 // buildExecutionPlan is the function that builds the full plan.
-func (vr *vreplicator) buildReplicatorPlan(source *binlogdatapb.BinlogSource, colInfoMap map[string][]*ColumnInfo, copyState map[string]*sqltypes.Result, stats *binlogplayer.Stats, collationEnv *collations.Environment, parser *sqlparser.Parser) (*ReplicatorPlan, error) {
+func (vr *vreplicator) buildReplicatorPlan(source *binlogdatapb.BinlogSource, colInfoMap map[string][]*ColumnInfo, copyState map[string]*sqltypes.Result, stats *binlogplayer.Stats, env *vtenv.Environment) (*ReplicatorPlan, error) {
 	filter := source.Filter
 	plan := &ReplicatorPlan{
 		VStreamFilter:  &binlogdatapb.Filter{FieldEventMode: filter.FieldEventMode},
@@ -143,7 +148,8 @@ func (vr *vreplicator) buildReplicatorPlan(source *binlogdatapb.BinlogSource, co
 		ColInfoMap:     colInfoMap,
 		stats:          stats,
 		Source:         source,
-		collationEnv:   collationEnv,
+		collationEnv:   env.CollationEnv(),
+		env:            env,
 		workflowConfig: vr.workflowConfig,
 	}
 	for tableName := range colInfoMap {
@@ -163,7 +169,7 @@ func (vr *vreplicator) buildReplicatorPlan(source *binlogdatapb.BinlogSource, co
 		if !ok {
 			return nil, fmt.Errorf("table %s not found in schema", tableName)
 		}
-		tablePlan, err := buildTablePlan(tableName, rule, colInfos, lastpk, stats, source, collationEnv, parser, vr.workflowConfig)
+		tablePlan, err := buildTablePlan(tableName, rule, colInfos, lastpk, stats, source, env, vr.workflowConfig)
 		if err != nil {
 			return nil, vterrors.Wrapf(err, "failed to build table replication plan for %s table", tableName)
 		}
@@ -203,8 +209,8 @@ func MatchTable(tableName string, filter *binlogdatapb.Filter) (*binlogdatapb.Ru
 }
 
 func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*ColumnInfo, lastpk *sqltypes.Result,
-	stats *binlogplayer.Stats, source *binlogdatapb.BinlogSource, collationEnv *collations.Environment,
-	parser *sqlparser.Parser, workflowConfig *vttablet.VReplicationConfig,
+	stats *binlogplayer.Stats, source *binlogdatapb.BinlogSource, env *vtenv.Environment,
+	workflowConfig *vttablet.VReplicationConfig,
 ) (*TablePlan, error) {
 	planError := func(err error, query string) error {
 		// Use the error string here to ensure things are uniform across
@@ -227,7 +233,7 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 	case filter == ExcludeStr:
 		return nil, nil
 	}
-	sel, fromTable, err := analyzeSelectFrom(query, parser)
+	sel, fromTable, err := analyzeSelectFrom(query, env.Parser())
 	if err != nil {
 		return nil, planError(err, query)
 	}
@@ -252,7 +258,7 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 			Stats:            stats,
 			ConvertCharset:   rule.ConvertCharset,
 			ConvertIntToEnum: rule.ConvertIntToEnum,
-			CollationEnv:     collationEnv,
+			CollationEnv:     env.CollationEnv(),
 			WorkflowConfig:   workflowConfig,
 		}
 
@@ -269,7 +275,8 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 		colInfos:       colInfos,
 		stats:          stats,
 		source:         source,
-		collationEnv:   collationEnv,
+		collationEnv:   env.CollationEnv(),
+		env:            env,
 		workflowConfig: workflowConfig,
 	}
 
@@ -357,6 +364,13 @@ func (tpb *tablePlanBuilder) generate() *TablePlan {
 
 	bvf := &bindvarFormatter{}
 
+	var evalColumns []*evalColumn
+	for _, cexpr := range tpb.colExprs {
+		if cexpr.evalColumn != nil {
+			evalColumns = append(evalColumns, cexpr.evalColumn)
+		}
+	}
+
 	fieldsToSkip := make(map[string]bool)
 	for _, colInfo := range tpb.colInfos {
 		if colInfo.IsGenerated {
@@ -373,11 +387,13 @@ func (tpb *tablePlanBuilder) generate() *TablePlan {
 		Update:                  tpb.generateUpdateStatement(),
 		Delete:                  tpb.generateDeleteStatement(),
 		MultiDelete:             tpb.generateMultiDeleteStatement(),
+		BeforeImageCheck:        tpb.generateBeforeImageCheck(),
 		PKReferences:            pkrefs,
 		PKIndices:               tpb.pkIndices,
 		Stats:                   tpb.stats,
 		FieldsToSkip:            fieldsToSkip,
 		HasExtraSourcePkColumns: len(tpb.extraSourcePkCols) > 0,
+		EvalColumns:             evalColumns,
 		TablePlanBuilder:        tpb,
 		PartialInserts:          make(map[string]*sqlparser.ParsedQuery, 0),
 		PartialUpdates:          make(map[string]*sqlparser.ParsedQuery, 0),
@@ -532,6 +548,9 @@ func (tpb *tablePlanBuilder) analyzeExpr(selExpr sqlparser.SelectExpr) (*colExpr
 		return nil, err
 	}
 	cexpr.expr = aliased.Expr
+	if _, ok := aliased.Expr.(*sqlparser.ColName); !ok {
+		tpb.analyzeEvalColumn(cexpr)
+	}
 	return cexpr, nil
 }
 
@@ -849,6 +868,47 @@ func (tpb *tablePlanBuilder) generateUpdateStatement() *sqlparser.ParsedQuery {
 	return buf.ParsedQuery()
 }
 
+// generateBeforeImageCheck returns the query that compares the row found on the
+// target with the before image of a change, for the bidirectional workflows. It
+// returns a true value if they match, and no row if the target doesn't have the
+// row. Float columns are not compared, as their values are not exact.
+func (tpb *tablePlanBuilder) generateBeforeImageCheck() *sqlparser.ParsedQuery {
+	if tpb.workflowConfig == nil || !tpb.workflowConfig.Bidirectional || tpb.onInsert != insertNormal {
+		return nil
+	}
+	bvf := &bindvarFormatter{mode: bvBefore}
+	buf := sqlparser.NewTrackedBuffer(bvf.formatter)
+	buf.WriteString("select ")
+	separator := ""
+	for _, cexpr := range tpb.colExprs {
+		if cexpr.isPK || cexpr.isGenerated || cexpr.operation != opExpr || cexpr.colType == querypb.Type_FLOAT32 {
+			continue
+		}
+		buf.Myprintf("%s%v<=>", separator, cexpr.colName)
+		separator = " and "
+		switch cexpr.colType {
+		case querypb.Type_JSON:
+			buf.Myprintf("cast(%v as json)", cexpr.expr)
+		case querypb.Type_DATETIME:
+			sourceTZ := tpb.source.SourceTimeZone
+			targetTZ := tpb.source.TargetTimeZone
+			if sourceTZ != "" && targetTZ != "" {
+				buf.Myprintf("convert_tz(%v, '%s', '%s')", cexpr.expr, sourceTZ, targetTZ)
+			} else {
+				buf.Myprintf("%v", cexpr.expr)
+			}
+		default:
+			buf.Myprintf("%v", cexpr.expr)
+		}
+	}
+	if separator == "" {
+		buf.WriteString("1")
+	}
+	buf.Myprintf(" from %v", tpb.name)
+	tpb.generateWhere(buf, bvf)
+	return buf.ParsedQuery()
+}
+
 func (tpb *tablePlanBuilder) generateDeleteStatement() *sqlparser.ParsedQuery {
 	bvf := &bindvarFormatter{}
 	buf := sqlparser.NewTrackedBuffer(bvf.formatter)
@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"fmt"
+	"slices"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// evalColumnPrefix is the prefix of the placeholder column names that hold
+// the results of the computed columns in the generated queries. For example,
+// the value of "upper(a) as c1" is bound as :a_vt_expr_c1 and :b_vt_expr_c1.
+const evalColumnPrefix = "vt_expr_"
+
+// evalColumn is a computed column of the target table whose value is
+// evaluated by the evalengine for every row, rather than being sent to
+// the target as an expression in the generated queries. This lets the
+// filter use the same functions as the rest of Vitess: string and JSON
+// functions, CASE, hashing, type conversions, etc. Columns are only
+// evaluated if the workflow sets EvaluateFilterExpressions.
+type evalColumn struct {
+	// name is the name of the placeholder column that holds the result.
+	name string
+	// source is the expression of the column in the filter.
+	source sqlparser.Expr
+	// expr is translated from source using the offsets and the types of the
+	// referenced columns in the select that is sent to the source. It's only
+	// set in the execution plans, which know the fields of the source.
+	expr evalengine.Expr
+	// offsets contains the offsets of all the referenced columns.
+	offsets []int
+}
+
+// isDeterministic returns true if the expression, and all of its
+// subexpressions, are guaranteed to produce the same result for the
+// same row.
+func isDeterministic(expr sqlparser.Expr) bool {
+	deterministic := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.CurTimeFuncExpr, *sqlparser.Variable:
+			deterministic = false
+		case *sqlparser.FuncExpr:
+			if sqlparser.NonDeterministicFuncs[node.Name.Lowered()] {
+				deterministic = false
+			}
+		}
+		return deterministic, nil
+	}, expr)
+	return deterministic
+}
+
+// resolveSendColumn returns the offset of the column in the select that is
+// sent to the source.
+func (tpb *tablePlanBuilder) resolveSendColumn(col *sqlparser.ColName) (int, error) {
+	for i, selExpr := range tpb.sendSelect.SelectExprs.Exprs {
+		aliased, ok := selExpr.(*sqlparser.AliasedExpr)
+		if !ok || !aliased.As.IsEmpty() {
+			continue
+		}
+		if sendCol, ok := aliased.Expr.(*sqlparser.ColName); ok && sendCol.Name.Equal(col.Name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("column %v not found in the select sent to the source", sqlparser.String(col))
+}
+
+// analyzeEvalColumn makes the computed expression of cexpr an evalColumn if
+// the workflow evaluates the filter expressions, or writes its row changes to
+// a sink, which has no MySQL to evaluate them. All the columns it references
+// must already be in the select that is sent to the source. Non-deterministic
+// expressions, and expressions that the evalengine does not support, are left
+// as they are and are evaluated by the target.
+func (tpb *tablePlanBuilder) analyzeEvalColumn(cexpr *colExpr) {
+	if tpb.workflowConfig == nil || !isDeterministic(cexpr.expr) {
+		return
+	}
+	if !tpb.workflowConfig.EvaluateFilterExpressions && tpb.workflowConfig.Sink == "" {
+		return
+	}
+	ec := &evalColumn{
+		name:   evalColumnPrefix + cexpr.colName.String(),
+		source: cexpr.expr,
+	}
+	// The types of the columns are only known once the fields are received,
+	// so this only checks that the expression can be translated.
+	if _, err := evalengine.Translate(cexpr.expr, &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			offset, err := tpb.resolveSendColumn(col)
+			if err == nil {
+				ec.offsets = append(ec.offsets, offset)
+			}
+			return offset, err
+		},
+		Collation:   tpb.env.CollationEnv().DefaultConnectionCharset(),
+		Environment: tpb.env,
+	}); err != nil {
+		return
+	}
+	cexpr.evalColumn = ec
+	cexpr.expr = &sqlparser.ColName{Name: sqlparser.NewIdentifierCI(ec.name)}
+}
+
+// translateEvalColumns translates the computed columns of the execution plan
+// using the types and collations of its fields. Values that are converted to
+// another charset are evaluated after the conversion, as they are bound to
+// the generated queries.
+func (tp *TablePlan) translateEvalColumns() error {
+	if len(tp.EvalColumns) == 0 {
+		return nil
+	}
+	tpb := tp.TablePlanBuilder
+	evalColumns := make([]*evalColumn, 0, len(tp.EvalColumns))
+	for _, ec := range tp.EvalColumns {
+		expr, err := evalengine.Translate(ec.source, &evalengine.Config{
+			ResolveColumn: tpb.resolveSendColumn,
+			ResolveType: func(expr sqlparser.Expr) (evalengine.Type, bool) {
+				col, ok := expr.(*sqlparser.ColName)
+				if !ok {
+					return evalengine.Type{}, false
+				}
+				offset, err := tpb.resolveSendColumn(col)
+				if err != nil || offset >= len(tp.Fields) {
+					return evalengine.Type{}, false
+				}
+				field := tp.Fields[offset]
+				collation := collations.ID(field.Charset)
+				if conversion, ok := tp.ConvertCharset[field.Name]; ok {
+					collation = tp.CollationEnv.DefaultCollationForCharset(conversion.ToCharset)
+				}
+				return evalengine.NewType(field.Type, collation), true
+			},
+			Collation:   tpb.env.CollationEnv().DefaultConnectionCharset(),
+			Environment: tpb.env,
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to translate the expression for %s.%s", tp.TargetName, ec.name[len(evalColumnPrefix):])
+		}
+		evalColumns = append(evalColumns, &evalColumn{name: ec.name, source: ec.source, expr: expr, offsets: ec.offsets})
+	}
+	tp.EvalColumns = evalColumns
+	return nil
+}
+
+// evaluateColumns evaluates the computed columns of the plan against the
+// given row image, and adds the results to the bind variables using the
+// prefix of the image, i.e. "b_" for the before image and "a_" for the
+// after image.
+func (tp *TablePlan) evaluateColumns(bindvars map[string]*querypb.BindVariable, prefix string, vals []sqltypes.Value) error {
+	if len(tp.EvalColumns) == 0 {
+		return nil
+	}
+	vals, err := tp.convertCharsets(vals)
+	if err != nil {
+		return err
+	}
+	exprEnv := evalengine.EmptyExpressionEnv(tp.TablePlanBuilder.env)
+	for _, ec := range tp.EvalColumns {
+		val, err := tp.evaluateColumn(exprEnv, ec, vals)
+		if err != nil {
+			return err
+		}
+		bindvars[prefix+ec.name] = sqltypes.ValueBindVariable(val)
+	}
+	return nil
+}
+
+// convertCharsets returns the row image with the values of the columns of
+// ConvertCharset converted to their target charset, like they are when they
+// are bound to the generated queries.
+func (tp *TablePlan) convertCharsets(vals []sqltypes.Value) ([]sqltypes.Value, error) {
+	if len(tp.ConvertCharset) == 0 {
+		return vals, nil
+	}
+	vals = slices.Clone(vals)
+	for i, field := range tp.Fields {
+		conversion, ok := tp.ConvertCharset[field.Name]
+		if !ok || i >= len(vals) || vals[i].IsNull() {
+			continue
+		}
+		out, err := tp.convertStringCharset(vals[i].Raw(), conversion, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = sqltypes.MakeTrusted(vals[i].Type(), out)
+	}
+	return vals, nil
+}
+
+// evaluateColumn evaluates a single computed column against the given row image.
+func (tp *TablePlan) evaluateColumn(exprEnv *evalengine.ExpressionEnv, ec *evalColumn, vals []sqltypes.Value) (sqltypes.Value, error) {
+	if ec.expr == nil {
+		return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "the expression for %s.%s was not translated",
+			tp.TargetName, ec.name[len(evalColumnPrefix):])
+	}
+	for _, offset := range ec.offsets {
+		if offset >= len(vals) {
+			return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "wrong number of values for %s: got %d values, need a value at offset %d",
+				tp.TargetName, len(vals), offset)
+		}
+	}
+	exprEnv.Row = vals
+	result, err := exprEnv.Evaluate(ec.expr)
+	if err != nil {
+		return sqltypes.NULL, vterrors.Wrapf(err, "failed to evaluate the expression for %s.%s",
+			tp.TargetName, ec.name[len(evalColumnPrefix):])
+	}
+	return result.Value(tp.TablePlanBuilder.env.CollationEnv().DefaultConnectionCharset()), nil
+}
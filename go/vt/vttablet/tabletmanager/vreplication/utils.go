@@ -58,6 +58,10 @@ const (
 	LogCopyEnd = "Ended Copy Phase"
 	// LogStateChange is used when the state of the stream changes.
 	LogStateChange = "State Changed"
+	// LogConflict is used when a bidirectional stream skips a change which conflicts with the target row.
+	LogConflict = "Conflict"
+	// LogDDLTransform is used when a DDL is translated onto the target by the TRANSFORM OnDDL action.
+	LogDDLTransform = "DDL Transformed"
 
 	// TODO: LogError is not used atm. Currently irrecoverable errors, resumable errors and informational messages
 	//  are all treated the same: the message column is updated and state left as Running.
@@ -140,6 +140,11 @@ type vcopierCopyWorker struct {
 	pkfields        []*querypb.Field
 	sqlbuffer       bytes2.Buffer
 	tablePlan       *TablePlan
+	// sink receives the copied rows instead of the target table, if set.
+	sink     Sink
+	workflow string
+	// originComment tags the inserts of a bidirectional workflow. See bidirectional.go.
+	originComment string
 }
 
 func newVCopier(vr *vreplicator) *vcopier {
@@ -207,11 +212,18 @@ func newVCopierCopyWorkQueue(
 func newVCopierCopyWorker(
 	closeDbClient bool,
 	vdbClient *vdbClient,
+	vr *vreplicator,
 ) *vcopierCopyWorker {
-	return &vcopierCopyWorker{
+	vbc := &vcopierCopyWorker{
 		closeDbClient: closeDbClient,
 		vdbClient:     vdbClient,
+		sink:          vr.sink,
+		workflow:      vr.WorkflowName,
 	}
+	if vr.workflowConfig.Bidirectional {
+		vbc.originComment = originComment(vr.WorkflowName, vr.id)
+	}
+	return vbc
 }
 
 // initTablesForCopy (phase 1) identifies the list of tables to be copied and inserts
@@ -221,7 +233,7 @@ func newVCopierCopyWorker(
 func (vc *vcopier) initTablesForCopy(ctx context.Context) error {
 	defer vc.vr.dbClient.Rollback()
 
-	plan, err := vc.vr.buildReplicatorPlan(vc.vr.source, vc.vr.colInfoMap, nil, vc.vr.stats, vc.vr.vre.env.CollationEnv(), vc.vr.vre.env.Parser())
+	plan, err := vc.vr.buildReplicatorPlan(vc.vr.source, vc.vr.colInfoMap, nil, vc.vr.stats, vc.vr.vre.env)
 	if err != nil {
 		return err
 	}
@@ -382,7 +394,7 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 
 	log.Info(fmt.Sprintf("Copying table %s, lastpk: %v", tableName, copyState[tableName]))
 
-	plan, err := vc.vr.buildReplicatorPlan(vc.vr.source, vc.vr.colInfoMap, nil, vc.vr.stats, vc.vr.vre.env.CollationEnv(), vc.vr.vre.env.Parser())
+	plan, err := vc.vr.buildReplicatorPlan(vc.vr.source, vc.vr.colInfoMap, nil, vc.vr.stats, vc.vr.vre.env)
 	if err != nil {
 		return err
 	}
@@ -727,6 +739,7 @@ func (vc *vcopier) newCopyWorkerFactory(parallelism int) func(context.Context) (
 			return newVCopierCopyWorker(
 				true, /* close db client */
 				dbClient,
+				vc.vr,
 			), nil
 		}
 	}
@@ -734,6 +747,7 @@ func (vc *vcopier) newCopyWorkerFactory(parallelism int) func(context.Context) (
 		return newVCopierCopyWorker(
 			false, /* close db client */
 			vc.vr.dbClient,
+			vc.vr,
 		), nil
 	}
 }
@@ -1155,15 +1169,38 @@ func (vbc *vcopierCopyWorker) insertCopyState(ctx context.Context, lastpk *query
 }
 
 func (vbc *vcopierCopyWorker) insertRows(ctx context.Context, rows []*querypb.Row) (*sqltypes.Result, error) {
+	if vbc.sink != nil {
+		return vbc.writeRowsToSink(ctx, rows)
+	}
 	return vbc.tablePlan.applyBulkInsert(
 		&vbc.sqlbuffer,
 		rows,
 		func(sql string) (*sqltypes.Result, error) {
-			return vbc.ExecuteWithRetry(ctx, sql)
+			return vbc.ExecuteWithRetry(ctx, vbc.originComment+sql)
 		},
 	)
 }
 
+// writeRowsToSink writes the copied rows to the sink, and flushes it before
+// the copy state is updated.
+func (vbc *vcopierCopyWorker) writeRowsToSink(ctx context.Context, rows []*querypb.Row) (*sqltypes.Result, error) {
+	rowChanges := make([]*binlogdatapb.RowChange, 0, len(rows))
+	for _, row := range rows {
+		rowChanges = append(rowChanges, &binlogdatapb.RowChange{After: row})
+	}
+	changes, err := vbc.tablePlan.sinkRowChanges(vbc.workflow, rowChanges, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := vbc.sink.Write(ctx, changes); err != nil {
+		return nil, err
+	}
+	if err := vbc.sink.Flush(ctx); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to flush the sink")
+	}
+	return &sqltypes.Result{RowsAffected: uint64(len(changes))}, nil
+}
+
 // open the vcopierCopyWorker. The provided arguments are used to generate
 // statements for inserting rows and copy state.
 func (vbc *vcopierCopyWorker) open(
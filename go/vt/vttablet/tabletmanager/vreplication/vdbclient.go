@@ -168,6 +168,15 @@ func (vc *vdbClient) ExecuteTrxQueryBatch() ([]*sqltypes.Result, error) {
 	return qrs, nil
 }
 
+// forgetLastQuery removes the last query from the queries which are replayed when the
+// transaction is retried. It's used after a statement failed without aborting the
+// transaction, and the failure was handled.
+func (vc *vdbClient) forgetLastQuery() {
+	if len(vc.queries) > 0 {
+		vc.queries = vc.queries[:len(vc.queries)-1]
+	}
+}
+
 // Execute is ExecuteFetch without the maxrows.
 func (vc *vdbClient) Execute(query string) (*sqltypes.Result, error) {
 	// Number of rows should never exceed relayLogMaxItems.
@@ -99,6 +99,12 @@ type vplayer struct {
 	// foreignKeyChecksStateInitialized is set to true once we have initialized the foreignKeyChecksEnabled.
 	// The initialization is done on the first row event that this vplayer sees.
 	foreignKeyChecksStateInitialized bool
+
+	// originComment tags the statements applied by a bidirectional workflow. It's empty otherwise.
+	originComment string
+	// skipPeerRows is set while the row events of a statement applied by the peer of a
+	// bidirectional workflow are received. See bidirectional.go.
+	skipPeerRows bool
 }
 
 // NoForeignKeyCheckFlagBitmask is the bitmask for the 2nd bit (least significant) of the flags in a binlog row event.
@@ -133,8 +139,14 @@ func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map
 	commitFunc := func() error {
 		return vr.dbClient.Commit()
 	}
-	// We only do batching in the running/replicating phase.
-	batchMode := len(copyState) == 0 && vr.workflowConfig.ExperimentalFlags&vttablet.VReplicationExperimentalFlagVPlayerBatching != 0
+	// We only do batching in the running/replicating phase. Bidirectional workflows need the
+	// result of each statement to detect conflicts, so they don't batch.
+	batchMode := len(copyState) == 0 && vr.workflowConfig.ExperimentalFlags&vttablet.VReplicationExperimentalFlagVPlayerBatching != 0 &&
+		!vr.workflowConfig.Bidirectional
+	var origin string
+	if vr.workflowConfig.Bidirectional {
+		origin = originComment(vr.WorkflowName, vr.id)
+	}
 
 	if batchMode {
 		// relayLogMaxSize is effectively the limit used when not batching.
@@ -180,6 +192,7 @@ func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map
 		query:            queryFunc,
 		commit:           commitFunc,
 		batchMode:        batchMode,
+		originComment:    origin,
 	}
 }
 
@@ -193,7 +206,7 @@ func (vp *vplayer) play(ctx context.Context) error {
 		return nil
 	}
 
-	plan, err := vp.vr.buildReplicatorPlan(vp.vr.source, vp.vr.colInfoMap, vp.copyState, vp.vr.stats, vp.vr.vre.env.CollationEnv(), vp.vr.vre.env.Parser())
+	plan, err := vp.vr.buildReplicatorPlan(vp.vr.source, vp.vr.colInfoMap, vp.copyState, vp.vr.stats, vp.vr.vre.env)
 	if err != nil {
 		vp.vr.stats.ErrorCounts.Add([]string{"Plan"}, 1)
 		return err
@@ -347,7 +360,7 @@ func (vp *vplayer) applyRowEvent(ctx context.Context, rowEvent *binlogdatapb.Row
 	}
 	applyFunc := func(sql string) (*sqltypes.Result, error) {
 		start := time.Now()
-		qr, err := vp.query(ctx, sql)
+		qr, err := vp.query(ctx, vp.originComment+sql)
 		vp.vr.stats.QueryCount.Add(vp.phase, 1)
 		vp.vr.stats.QueryTimings.Record(vp.phase, start)
 		if vp.vr.workflowConfig.EnableHttpLog {
@@ -375,6 +388,12 @@ func (vp *vplayer) applyRowEvent(ctx context.Context, rowEvent *binlogdatapb.Row
 	}
 
 	for _, change := range rowEvent.RowChanges {
+		if vp.vr.workflowConfig.Bidirectional {
+			if err := vp.applyBidirectionalChange(tplan, change, applyFunc); err != nil {
+				return err
+			}
+			continue
+		}
 		if _, err := tplan.applyChange(change, applyFunc); err != nil {
 			return err
 		}
@@ -383,8 +402,33 @@ func (vp *vplayer) applyRowEvent(ctx context.Context, rowEvent *binlogdatapb.Row
 	return nil
 }
 
+// writeRowEventToSink writes the row changes of the event to the sink of the
+// workflow, instead of applying them to the target tables.
+func (vp *vplayer) writeRowEventToSink(ctx context.Context, event *binlogdatapb.VEvent) error {
+	tplan := vp.tablePlans[event.RowEvent.TableName]
+	if tplan == nil {
+		return fmt.Errorf("unexpected event on table %s", event.RowEvent.TableName)
+	}
+	changes, err := tplan.sinkRowChanges(vp.vr.WorkflowName, event.RowEvent.RowChanges, replication.EncodePosition(vp.pos), event.Timestamp)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return vp.vr.sink.Write(ctx, changes)
+}
+
 // updatePos should get called at a minimum of vreplicationMinimumHeartbeatUpdateInterval.
+// If the workflow has a sink, it's flushed first, so that the position is never
+// saved before the changes up to it are durable. The sink workflows call it at most
+// once every idleTimeout, rather than for every transaction.
 func (vp *vplayer) updatePos(ctx context.Context, ts int64) (posReached bool, err error) {
+	if vp.vr.sink != nil {
+		if err := vp.vr.sink.Flush(ctx); err != nil {
+			return false, vterrors.Wrapf(err, "failed to flush the sink")
+		}
+	}
 	update := binlogplayer.GenerateUpdatePos(vp.vr.id, vp.pos, time.Now().Unix(), ts, vp.vr.stats.CopyRowCount.Get(), vp.vr.workflowConfig.StoreCompressedGTID)
 	if _, err := vp.query(ctx, update); err != nil {
 		return false, fmt.Errorf("error %v updating position", err)
@@ -491,6 +535,26 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 	// can estimate this value more accurately.
 	defer vp.vr.stats.ReplicationLagSeconds.Store(math.MaxInt64)
 	defer vp.vr.stats.VReplicationLagGauges.Set(strconv.Itoa(int(vp.vr.id)), math.MaxInt64)
+	setLag := func(lag int64) {
+		if lag >= 0 {
+			lagSecs := lag / 1e9
+			vp.vr.stats.ReplicationLagSeconds.Store(lagSecs)
+			vp.vr.stats.VReplicationLagGauges.Set(strconv.Itoa(int(vp.vr.id)), lagSecs)
+		} else { // We couldn't determine the lag, so we need to estimate it
+			estimateLag()
+		}
+	}
+
+	// In the running phase, transactions can be applied by parallel workers.
+	var parallelApplier *parallelApplier
+	if vp.canApplyInParallel() {
+		var err error
+		parallelApplier, err = newParallelApplier(ctx, vp, vp.vr.workflowConfig.ParallelApplyWorkers)
+		if err != nil {
+			return err
+		}
+		defer parallelApplier.close()
+	}
 	var lag int64
 	for {
 		if ctx.Err() != nil {
@@ -525,6 +589,14 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 			}
 		}
 
+		if parallelApplier != nil {
+			if lag, err = parallelApplier.applyItems(ctx, items); err != nil {
+				return err
+			}
+			setLag(lag)
+			continue
+		}
+
 		lag = -1
 		for i, events := range items {
 			for j, event := range events {
@@ -548,7 +620,7 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 					}
 				}
 				if err := vp.applyEvent(ctx, event, mustSave); err != nil {
-					if err != io.EOF {
+					if err != io.EOF && err != errDDLTransformed {
 						vp.vr.stats.ErrorCounts.Add([]string{"Apply"}, 1)
 						var table, tableLogMsg, gtidLogMsg string
 						switch {
@@ -586,13 +658,7 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 			}
 		}
 
-		if lag >= 0 {
-			lagSecs := lag / 1e9
-			vp.vr.stats.ReplicationLagSeconds.Store(lagSecs)
-			vp.vr.stats.VReplicationLagGauges.Set(strconv.Itoa(int(vp.vr.id)), lagSecs)
-		} else { // We couldn't determine the lag, so we need to estimate it
-			estimateLag()
-		}
+		setLag(lag)
 	}
 }
 
@@ -656,12 +722,14 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 		vp.pos = pos
 		// A new position should not be saved until a saveable event occurs.
 		vp.unsavedEvent = nil
+		vp.skipPeerRows = false
 		if vp.stopPos.IsZero() {
 			return nil
 		}
 	case binlogdatapb.VEventType_BEGIN:
 		// No-op: begin is called as needed.
 	case binlogdatapb.VEventType_COMMIT:
+		vp.skipPeerRows = false
 		if mustSave {
 			if err := vp.vr.dbClient.Begin(); err != nil {
 				return err
@@ -673,6 +741,16 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			vp.unsavedEvent = event
 			return nil
 		}
+		if vp.vr.sink != nil && !mustSave && vp.stopPos.IsZero() && time.Since(vp.timeLastSaved) < idleTimeout {
+			// Flushing the sink can be expensive, so the changes written to it
+			// are flushed, and the position is saved, at most once every
+			// idleTimeout, like the position of empty transactions.
+			if err := vp.commit(); err != nil {
+				return err
+			}
+			vp.unsavedEvent = event
+			return nil
+		}
 		posReached, err := vp.updatePos(ctx, event.Timestamp)
 		if err != nil {
 			return err
@@ -717,10 +795,21 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			}
 		}
 	case binlogdatapb.VEventType_ROW:
+		if vp.skipPeerRows {
+			// The change was applied by the peer of this bidirectional workflow.
+			break
+		}
 		// This player is configured for row based replication
 		if err := vp.vr.dbClient.Begin(); err != nil {
 			return err
 		}
+		if vp.vr.sink != nil {
+			if err := vp.writeRowEventToSink(ctx, event); err != nil {
+				log.Info("Error writing row event to the sink: " + err.Error())
+				return err
+			}
+			break
+		}
 		if err := vp.applyRowEvent(ctx, event.RowEvent); err != nil {
 			log.Info("Error applying row event: " + err.Error())
 			return err
@@ -807,9 +896,18 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			if posReached {
 				return io.EOF
 			}
+		case binlogdatapb.OnDDLAction_TRANSFORM:
+			if stats != nil {
+				stats.Send(event.Statement)
+			}
+			return vp.applyTransformedDDL(ctx, event)
 		}
 	case binlogdatapb.VEventType_ROWS_QUERY:
 		// The original SQL query is informational only; VReplication applies row changes directly.
+		// Bidirectional workflows use it to recognize the changes applied by their peer.
+		if vp.vr.workflowConfig.Bidirectional {
+			vp.skipPeerRows = vp.isPeerRowsQuery(event)
+		}
 	case binlogdatapb.VEventType_JOURNAL:
 		if vp.vr.dbClient.InTransaction {
 			// Unreachable
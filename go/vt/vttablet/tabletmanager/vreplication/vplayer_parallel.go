@@ -0,0 +1,709 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// parallelApplier applies the transactions of the relay log concurrently on
+// several target connections, in the spirit of MySQL's writeset based
+// parallel replication:
+//   - The keys of the rows that a transaction changes make up its conflict
+//     set. A transaction only starts once all earlier transactions that share
+//     a key with it have committed.
+//   - Transactions update the position in _vt.vreplication and commit in the
+//     order of the source, so the recorded position never skips a transaction.
+//   - The keys cover the primary key, the unique keys and the foreign keys of
+//     the target tables. Conflicts which are not captured by the keys make a
+//     transaction fail or wait on a lock, which times out quickly while
+//     transactions run in parallel. Such a transaction is rolled back along
+//     with all later ones that are waiting to commit, and they are then
+//     applied one at a time with the regular lock wait timeout.
+//
+// Only transactions that consist of row events with full row images are
+// applied in parallel. All other events are applied by the vplayer as usual,
+// after all transactions that were handed to the parallelApplier committed.
+// The parallelApplier is drained at the end of every relay log batch.
+type parallelApplier struct {
+	vp *vplayer
+	// workers holds the idle workers.
+	workers    chan *parallelApplyWorker
+	allWorkers []*parallelApplyWorker
+	// conflictKeys caches the keys of the target table of a table plan, see
+	// loadConflictKeys.
+	conflictKeys map[*TablePlan][]conflictKey
+	wg           sync.WaitGroup
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// dispatched and committed are the sequence numbers of the last
+	// transaction that was dispatched and committed.
+	dispatched int64
+	committed  int64
+	// committedPos is the position of the last committed transaction.
+	committedPos replication.Position
+	// keys holds, per row key, the last dispatched transaction which changes
+	// the row.
+	keys map[string]int64
+	// serialAfter is set to the sequence number of a transaction which failed
+	// or ran into a lock while executing in parallel. All transactions after it
+	// are applied one at a time until the parallelApplier is drained.
+	serialAfter int64
+	// holding contains the transactions which executed their statements, but
+	// did not commit or roll back yet.
+	holding map[int64]bool
+	err     error
+	// canceled is set when the context of the parallelApplier is done.
+	canceled bool
+}
+
+// parallelApplyWorker applies one transaction at a time on its own connection.
+type parallelApplyWorker struct {
+	dbClient *vdbClient
+
+	// See vplayer.updateFKCheck.
+	foreignKeyChecksEnabled          bool
+	foreignKeyChecksStateInitialized bool
+	// See updateLockWaitTimeout.
+	lockWaitTimeoutShortened        bool
+	lockWaitTimeoutStateInitialized bool
+}
+
+// parallelApplyLockWaitTimeout is the innodb_lock_wait_timeout in seconds of
+// transactions which are applied in parallel. A transaction which waits on a
+// lock that the keys did not predict is retried serially soon after.
+const parallelApplyLockWaitTimeout = 1
+
+// parallelTxn is a transaction that is applied by the parallelApplier.
+type parallelTxn struct {
+	seq       int64
+	pos       replication.Position
+	timestamp int64
+	rowEvents []*binlogdatapb.RowEvent
+	// plans has the table plan of every row event. The plans are resolved
+	// when the transaction is dispatched because FIELD events of later
+	// transactions may replace them.
+	plans []*TablePlan
+	keys  []string
+	// dependsOn is the last earlier transaction which shares a key with this one.
+	dependsOn int64
+}
+
+// canApplyInParallel returns true if the vplayer should apply transactions
+// with a parallelApplier. This is only the case in the running phase and
+// without a stop position, which needs the exact handling of the commits,
+// nor a sink, which has to receive the changes in order. Bidirectional
+// workflows, which skip and log conflicting changes, are applied serially.
+func (vp *vplayer) canApplyInParallel() bool {
+	return vp.vr.workflowConfig.ParallelApplyWorkers > 1 && len(vp.copyState) == 0 && vp.stopPos.IsZero() && vp.vr.sink == nil &&
+		!vp.vr.workflowConfig.Bidirectional
+}
+
+func newParallelApplier(ctx context.Context, vp *vplayer, parallelism int) (*parallelApplier, error) {
+	pa := &parallelApplier{
+		vp:           vp,
+		workers:      make(chan *parallelApplyWorker, parallelism),
+		conflictKeys: make(map[*TablePlan][]conflictKey),
+		keys:         make(map[string]int64),
+		holding:      make(map[int64]bool),
+	}
+	pa.cond = sync.NewCond(&pa.mu)
+	for range parallelism {
+		dbClient, err := vp.vr.newClientConnection(ctx)
+		if err != nil {
+			pa.close()
+			return nil, fmt.Errorf("failed to create new db client: %s", err.Error())
+		}
+		w := &parallelApplyWorker{dbClient: dbClient}
+		pa.allWorkers = append(pa.allWorkers, w)
+		pa.workers <- w
+	}
+	context.AfterFunc(ctx, func() {
+		pa.mu.Lock()
+		defer pa.mu.Unlock()
+		pa.canceled = true
+		pa.cond.Broadcast()
+	})
+	log.Info(fmt.Sprintf("VReplication player id: %v applies transactions with %d parallel workers", vp.vr.id, parallelism))
+	return pa, nil
+}
+
+// close waits for all transactions to finish and closes the connections.
+func (pa *parallelApplier) close() {
+	pa.wg.Wait()
+	for _, w := range pa.allWorkers {
+		_ = w.dbClient.Rollback()
+		w.dbClient.Close()
+	}
+}
+
+// applyItems applies a batch of the relay log and returns the lag based on the
+// last event that had a timestamp, or -1 if there was no such event.
+func (pa *parallelApplier) applyItems(ctx context.Context, items [][]*binlogdatapb.VEvent) (int64, error) {
+	vp := pa.vp
+	lag := int64(-1)
+	// A transaction that was partially applied in the previous batch is
+	// completed by the vplayer.
+	serial := vp.vr.dbClient.InTransaction
+	// txn holds the events of the current transaction, starting with its GTID.
+	var txn []*binlogdatapb.VEvent
+	for _, events := range items {
+		for _, event := range events {
+			if event.Timestamp != 0 && (event.Type != binlogdatapb.VEventType_HEARTBEAT || !event.Throttled) {
+				vp.lastTimestampNs = event.Timestamp * 1e9
+				now := time.Now().UnixNano()
+				vp.timeOffsetNs = now - event.CurrentTime
+				lag = now - vp.lastTimestampNs - vp.timeOffsetNs
+			}
+			if serial {
+				if err := pa.applySerially(ctx, event); err != nil {
+					return lag, err
+				}
+				serial = vp.vr.dbClient.InTransaction
+				continue
+			}
+			switch event.Type {
+			case binlogdatapb.VEventType_GTID, binlogdatapb.VEventType_BEGIN, binlogdatapb.VEventType_FIELD,
+				binlogdatapb.VEventType_ROW, binlogdatapb.VEventType_ROWS_QUERY:
+				txn = append(txn, event)
+			case binlogdatapb.VEventType_COMMIT:
+				txn = append(txn, event)
+				if err := pa.applyTxn(ctx, txn); err != nil {
+					return lag, err
+				}
+				txn = nil
+			default:
+				// Statements, DDLs and all other events are applied by the vplayer.
+				if err := pa.applySerially(ctx, append(txn, event)...); err != nil {
+					return lag, err
+				}
+				txn = nil
+				serial = vp.vr.dbClient.InTransaction
+			}
+		}
+	}
+	// A partial transaction at the end of the batch is applied by the vplayer,
+	// which completes it with the next batch.
+	if err := pa.applySerially(ctx, txn...); err != nil {
+		return lag, err
+	}
+	return lag, pa.drain(ctx)
+}
+
+// applySerially drains the parallelApplier and applies the events with the vplayer.
+func (pa *parallelApplier) applySerially(ctx context.Context, events ...*binlogdatapb.VEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := pa.drain(ctx); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := pa.vp.applyEvent(ctx, event, false); err != nil {
+			if err != io.EOF && err != errDDLTransformed {
+				pa.vp.vr.stats.ErrorCounts.Add([]string{"Apply"}, 1)
+				err = vterrors.Wrapf(err, "error applying event")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTxn dispatches a complete transaction to a worker if it can be applied
+// in parallel, or applies it with the vplayer otherwise.
+func (pa *parallelApplier) applyTxn(ctx context.Context, events []*binlogdatapb.VEvent) error {
+	vp := pa.vp
+	ptxn := &parallelTxn{}
+	for _, event := range events {
+		switch event.Type {
+		case binlogdatapb.VEventType_FIELD:
+			// Table plans are only replaced in the order of the events, by the
+			// vplayer or here.
+			tplan, err := vp.replicatorPlan.buildExecutionPlan(event.FieldEvent)
+			if err != nil {
+				return err
+			}
+			vp.tablePlans[event.FieldEvent.TableName] = tplan
+		case binlogdatapb.VEventType_ROW:
+			tplan := vp.tablePlans[event.RowEvent.TableName]
+			if tplan == nil || slices.ContainsFunc(event.RowEvent.RowChanges, tplan.isPartial) {
+				// Partial row images are applied by the vplayer because their
+				// statements are generated and cached on demand by the table plan.
+				return pa.applySerially(ctx, events...)
+			}
+			ptxn.rowEvents = append(ptxn.rowEvents, event.RowEvent)
+			ptxn.plans = append(ptxn.plans, tplan)
+			keys, err := pa.appendKeys(ptxn.keys, tplan, event.RowEvent)
+			if err != nil {
+				return err
+			}
+			ptxn.keys = keys
+		case binlogdatapb.VEventType_COMMIT:
+			ptxn.timestamp = event.Timestamp
+		}
+	}
+	if len(ptxn.rowEvents) == 0 {
+		// Empty transactions are remembered by the vplayer as unsaved events.
+		return pa.applySerially(ctx, events...)
+	}
+	for _, event := range events {
+		if event.Type != binlogdatapb.VEventType_GTID {
+			continue
+		}
+		pos, err := binlogplayer.DecodePosition(event.Gtid)
+		if err != nil {
+			return err
+		}
+		vp.pos = pos
+		vp.unsavedEvent = nil
+	}
+	ptxn.pos = vp.pos
+	return pa.dispatch(ctx, ptxn)
+}
+
+// conflictKey is a key of a target table whose values identify a row.
+type conflictKey struct {
+	// name is made of the table and the columns of the key. A foreign key is
+	// named after the referenced table and columns, so that a child row
+	// conflicts with the parent row it references.
+	name string
+	// fields are the indexes of the fields of the table plan which the
+	// columns of the key are computed from.
+	fields []int
+}
+
+// keyFieldIndexes returns the indexes of the fields of the table plan which
+// the columns of the target table are computed from, or nil if a column
+// can't be mapped to fields.
+func keyFieldIndexes(tplan *TablePlan, columns []string) []int {
+	tpb := tplan.TablePlanBuilder
+	if tpb == nil || len(columns) == 0 {
+		return nil
+	}
+	var indexes []int
+	for _, column := range columns {
+		i := slices.IndexFunc(tpb.colExprs, func(cexpr *colExpr) bool {
+			return cexpr.colName.EqualString(column)
+		})
+		if i < 0 || len(tpb.colExprs[i].references) == 0 {
+			return nil
+		}
+		var refs []int
+		for ref := range tpb.colExprs[i].references {
+			j := slices.IndexFunc(tplan.Fields, func(field *querypb.Field) bool {
+				return strings.EqualFold(field.Name, ref)
+			})
+			if j < 0 {
+				return nil
+			}
+			refs = append(refs, j)
+		}
+		slices.Sort(refs)
+		indexes = append(indexes, refs...)
+	}
+	return indexes
+}
+
+// loadConflictKeys returns the primary key, the unique keys and the foreign
+// keys of the target table of the table plan. If any of them can't be mapped
+// to fields, it returns no keys, so all transactions on the table conflict.
+func (pa *parallelApplier) loadConflictKeys(tplan *TablePlan) ([]conflictKey, error) {
+	tpb := tplan.TablePlanBuilder
+	if tpb == nil {
+		return nil, nil
+	}
+	// keyName is the name of the key made of the columns of the table.
+	keyName := func(table string, columns []string) string {
+		return table + "(" + strings.ToLower(strings.Join(columns, ",")) + ")"
+	}
+	var pkColumns []string
+	for _, cexpr := range tpb.pkCols {
+		pkColumns = append(pkColumns, cexpr.colName.String())
+	}
+	keys := []conflictKey{{name: keyName(tplan.TargetName, pkColumns)}}
+
+	dbClient := pa.vp.vr.dbClient
+	dbName := encodeString(dbClient.DBName())
+	qr, err := dbClient.ExecuteFetch(fmt.Sprintf("select index_name, column_name from information_schema.statistics "+
+		"where table_schema=%s and table_name=%s and non_unique=0 and index_name != 'PRIMARY' order by index_name, seq_in_index",
+		dbName, encodeString(tplan.TargetName)), -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the unique keys of %s: %w", tplan.TargetName, err)
+	}
+	var columns []string
+	for i, row := range qr.Rows {
+		columns = append(columns, row[1].ToString())
+		if i == len(qr.Rows)-1 || qr.Rows[i+1][0].ToString() != row[0].ToString() {
+			keys = append(keys, conflictKey{name: keyName(tplan.TargetName, columns), fields: keyFieldIndexes(tplan, columns)})
+			columns = nil
+		}
+	}
+
+	qr, err = dbClient.ExecuteFetch(fmt.Sprintf("select constraint_name, column_name, referenced_table_name, referenced_column_name "+
+		"from information_schema.key_column_usage where table_schema=%s and table_name=%s and referenced_table_name is not null "+
+		"order by constraint_name, ordinal_position",
+		dbName, encodeString(tplan.TargetName)), -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the foreign keys of %s: %w", tplan.TargetName, err)
+	}
+	var referencedColumns []string
+	for i, row := range qr.Rows {
+		columns = append(columns, row[1].ToString())
+		referencedColumns = append(referencedColumns, row[3].ToString())
+		if i == len(qr.Rows)-1 || qr.Rows[i+1][0].ToString() != row[0].ToString() {
+			keys = append(keys, conflictKey{name: keyName(row[2].ToString(), referencedColumns), fields: keyFieldIndexes(tplan, columns)})
+			columns, referencedColumns = nil, nil
+		}
+	}
+
+	keys[0].fields = keyFieldIndexes(tplan, pkColumns)
+	for _, key := range keys {
+		if key.fields == nil {
+			return nil, nil
+		}
+	}
+	return keys, nil
+}
+
+// appendKeys appends the keys of the rows which the row event changes. For
+// the primary key, every unique key and every foreign key of the target table,
+// a key is made of its name and the values of its fields in the before and
+// after images. Keys with a NULL value are skipped, as they don't constrain
+// other rows. If a key can't be mapped to fields, the key is the target table,
+// so all transactions on the table conflict. Conflicts which the keys still
+// miss, e.g. through triggers or cascading foreign keys, make a transaction
+// fail or run into the short lock wait timeout, and are resolved by applying
+// the transactions serially.
+func (pa *parallelApplier) appendKeys(keys []string, tplan *TablePlan, rowEvent *binlogdatapb.RowEvent) ([]string, error) {
+	conflictKeys, ok := pa.conflictKeys[tplan]
+	if !ok {
+		var err error
+		if conflictKeys, err = pa.loadConflictKeys(tplan); err != nil {
+			return nil, err
+		}
+		pa.conflictKeys[tplan] = conflictKeys
+	}
+	if len(conflictKeys) == 0 {
+		return append(keys, tplan.TargetName), nil
+	}
+	appendKey := func(row *querypb.Row) {
+		if row == nil {
+			return
+		}
+		vals := sqltypes.MakeRowTrusted(tplan.Fields, row)
+	nextKey:
+		for _, conflictKey := range conflictKeys {
+			var key strings.Builder
+			key.WriteString(conflictKey.name)
+			for _, i := range conflictKey.fields {
+				if vals[i].IsNull() {
+					continue nextKey
+				}
+				raw := vals[i].Raw()
+				key.WriteByte(0)
+				key.WriteString(strconv.Itoa(len(raw)))
+				key.WriteByte(':')
+				key.Write(raw)
+			}
+			keys = append(keys, key.String())
+		}
+	}
+	for _, change := range rowEvent.RowChanges {
+		appendKey(change.Before)
+		appendKey(change.After)
+	}
+	return keys, nil
+}
+
+// dispatch hands the transaction to the next idle worker.
+func (pa *parallelApplier) dispatch(ctx context.Context, ptxn *parallelTxn) error {
+	pa.mu.Lock()
+	if pa.err != nil {
+		pa.mu.Unlock()
+		return pa.err
+	}
+	pa.dispatched++
+	ptxn.seq = pa.dispatched
+	for _, key := range ptxn.keys {
+		if seq := pa.keys[key]; seq > ptxn.dependsOn {
+			ptxn.dependsOn = seq
+		}
+		pa.keys[key] = ptxn.seq
+	}
+	pa.mu.Unlock()
+
+	var w *parallelApplyWorker
+	select {
+	case w = <-pa.workers:
+	case <-ctx.Done():
+		return io.EOF
+	}
+	pa.wg.Add(1)
+	go func() {
+		defer pa.wg.Done()
+		defer func() { pa.workers <- w }()
+		if err := pa.run(ctx, w, ptxn); err != nil {
+			_ = w.dbClient.Rollback()
+			pa.fail(vterrors.Wrapf(err, "error applying transaction at position %v", ptxn.pos))
+		}
+	}()
+	return nil
+}
+
+// run applies the transaction and commits it in order.
+func (pa *parallelApplier) run(ctx context.Context, w *parallelApplyWorker, ptxn *parallelTxn) error {
+	// Wait for the earlier transactions which change the same rows.
+	if err := pa.wait(func() bool { return pa.committed >= ptxn.dependsOn }); err != nil {
+		return err
+	}
+
+	pa.mu.Lock()
+	serial := pa.isSerialLocked(ptxn.seq)
+	if !serial {
+		pa.holding[ptxn.seq] = true
+	}
+	pa.mu.Unlock()
+
+	if !serial {
+		if err := pa.execute(ctx, w, ptxn, false); err != nil {
+			// The transaction may conflict with a later one which holds a lock
+			// it needs. Release the locks of all later transactions and retry
+			// once it's its turn.
+			log.Info(fmt.Sprintf("Retrying transaction at position %v serially after error: %v", ptxn.pos, err))
+			pa.vp.vr.stats.ErrorCounts.Add([]string{"ParallelApplyRetry"}, 1)
+			pa.rollback(w, ptxn, true)
+			serial = true
+		}
+	}
+	if !serial {
+		// Wait for the turn of the transaction, unless an earlier transaction
+		// needs this one to release its locks.
+		if err := pa.wait(func() bool { return pa.committed == ptxn.seq-1 || pa.isSerialLocked(ptxn.seq) }); err != nil {
+			return err
+		}
+		pa.mu.Lock()
+		serial = pa.committed != ptxn.seq-1
+		pa.mu.Unlock()
+		if serial {
+			pa.rollback(w, ptxn, false)
+		}
+	}
+	if serial {
+		// All earlier transactions committed and no later one holds any lock.
+		if err := pa.wait(func() bool { return pa.committed == ptxn.seq-1 && !pa.isHeldAfterLocked(ptxn.seq) }); err != nil {
+			return err
+		}
+		if err := pa.execute(ctx, w, ptxn, true); err != nil {
+			return err
+		}
+	}
+
+	update := binlogplayer.GenerateUpdatePos(pa.vp.vr.id, ptxn.pos, time.Now().Unix(), ptxn.timestamp, pa.vp.vr.stats.CopyRowCount.Get(), pa.vp.vr.workflowConfig.StoreCompressedGTID)
+	if _, err := w.dbClient.Execute(update); err != nil {
+		return fmt.Errorf("error %v updating position", err)
+	}
+	if err := w.dbClient.Commit(); err != nil {
+		return err
+	}
+	pa.vp.vr.stats.SetLastPosition(ptxn.pos)
+
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	pa.committed = ptxn.seq
+	pa.committedPos = ptxn.pos
+	delete(pa.holding, ptxn.seq)
+	for _, key := range ptxn.keys {
+		if pa.keys[key] == ptxn.seq {
+			delete(pa.keys, key)
+		}
+	}
+	pa.cond.Broadcast()
+	return nil
+}
+
+// execute begins a transaction on the worker's connection and applies the
+// row events of the transaction. Unless the transaction is applied serially,
+// it only waits briefly for locks.
+func (pa *parallelApplier) execute(ctx context.Context, w *parallelApplyWorker, ptxn *parallelTxn, serial bool) error {
+	vr := pa.vp.vr
+	if err := w.updateLockWaitTimeout(!serial); err != nil {
+		return err
+	}
+	if err := w.dbClient.Begin(); err != nil {
+		return err
+	}
+	applyFunc := func(sql string) (*sqltypes.Result, error) {
+		start := time.Now()
+		// Unlike the vplayer, lock wait timeouts and deadlocks are not retried
+		// here, as they are resolved by applying the transaction serially.
+		qr, err := w.dbClient.Execute(sql)
+		vr.stats.QueryCount.Add(pa.vp.phase, 1)
+		vr.stats.QueryTimings.Record(pa.vp.phase, start)
+		if vr.workflowConfig.EnableHttpLog {
+			stats := NewVrLogStats("ROWCHANGE", start)
+			stats.Send(sql)
+		}
+		return qr, err
+	}
+	for i, rowEvent := range ptxn.rowEvents {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.updateFKCheck(rowEvent.Flags); err != nil {
+			return err
+		}
+		for _, change := range rowEvent.RowChanges {
+			if _, err := ptxn.plans[i].applyChange(change, applyFunc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rollback rolls back the transaction on the worker's connection. If
+// serializeLater is set, all later transactions are applied serially.
+func (pa *parallelApplier) rollback(w *parallelApplyWorker, ptxn *parallelTxn, serializeLater bool) {
+	_ = w.dbClient.Rollback()
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	delete(pa.holding, ptxn.seq)
+	if serializeLater && (pa.serialAfter == 0 || ptxn.seq < pa.serialAfter) {
+		pa.serialAfter = ptxn.seq
+	}
+	pa.cond.Broadcast()
+}
+
+// isSerialLocked returns true if the transaction must be applied serially.
+func (pa *parallelApplier) isSerialLocked(seq int64) bool {
+	return pa.serialAfter != 0 && pa.serialAfter < seq
+}
+
+// isHeldAfterLocked returns true if any transaction after seq holds locks.
+func (pa *parallelApplier) isHeldAfterLocked(seq int64) bool {
+	for held := range pa.holding {
+		if held > seq {
+			return true
+		}
+	}
+	return false
+}
+
+// wait waits until the condition is met, or the parallelApplier failed or
+// was canceled.
+func (pa *parallelApplier) wait(condition func() bool) error {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	for {
+		switch {
+		case pa.err != nil:
+			return pa.err
+		case pa.canceled:
+			return io.EOF
+		case condition():
+			return nil
+		}
+		pa.cond.Wait()
+	}
+}
+
+// fail records the first error and wakes up all waiting transactions.
+func (pa *parallelApplier) fail(err error) {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	if pa.err == nil && err != io.EOF {
+		log.Error(err.Error())
+		pa.vp.vr.stats.ErrorCounts.Add([]string{"Apply"}, 1)
+		pa.err = err
+	}
+	pa.cond.Broadcast()
+}
+
+// drain waits until all dispatched transactions committed.
+func (pa *parallelApplier) drain(ctx context.Context) error {
+	err := pa.wait(func() bool { return pa.committed == pa.dispatched })
+	pa.wg.Wait()
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return io.EOF
+	}
+
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	pa.serialAfter = 0
+	if !pa.committedPos.IsZero() {
+		// The position was saved by the transactions.
+		pa.vp.numAccumulatedHeartbeats = 0
+		pa.vp.timeLastSaved = time.Now()
+		pa.committedPos = replication.Position{}
+	}
+	return nil
+}
+
+// updateFKCheck updates the @@session.foreign_key_checks variable of the
+// worker's connection based on the binlog row event flags.
+func (w *parallelApplyWorker) updateFKCheck(flags2 uint32) error {
+	enabled := flags2&NoForeignKeyCheckFlagBitmask != NoForeignKeyCheckFlagBitmask
+	if w.foreignKeyChecksStateInitialized && enabled == w.foreignKeyChecksEnabled {
+		return nil
+	}
+	if _, err := w.dbClient.Execute("set @@session.foreign_key_checks=" + strconv.FormatBool(enabled)); err != nil {
+		return fmt.Errorf("failed to set session foreign_key_checks: %w", err)
+	}
+	w.foreignKeyChecksEnabled = enabled
+	w.foreignKeyChecksStateInitialized = true
+	return nil
+}
+
+// updateLockWaitTimeout sets the @@session.innodb_lock_wait_timeout variable
+// of the worker's connection to parallelApplyLockWaitTimeout, or back to the
+// global value for transactions that are applied serially.
+func (w *parallelApplyWorker) updateLockWaitTimeout(shortened bool) error {
+	if w.lockWaitTimeoutStateInitialized && shortened == w.lockWaitTimeoutShortened {
+		return nil
+	}
+	value := "@@global.innodb_lock_wait_timeout"
+	if shortened {
+		value = strconv.Itoa(parallelApplyLockWaitTimeout)
+	}
+	if _, err := w.dbClient.Execute("set @@session.innodb_lock_wait_timeout=" + value); err != nil {
+		return fmt.Errorf("failed to set session innodb_lock_wait_timeout: %w", err)
+	}
+	w.lockWaitTimeoutShortened = shortened
+	w.lockWaitTimeoutStateInitialized = true
+	return nil
+}
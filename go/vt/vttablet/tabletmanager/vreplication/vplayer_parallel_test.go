@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/vtenv"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestParallelApplierKeys(t *testing.T) {
+	vttablet.InitVReplicationConfigDefaults()
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select val, id as pk from t1",
+		}, {
+			Match:  "t0",
+			Filter: "select name, extra from t0",
+		}},
+	}
+	primaryKeyInfos := map[string][]*ColumnInfo{
+		"t1": {&ColumnInfo{Name: "pk", IsPK: true}},
+		"t0": {&ColumnInfo{Name: "name", IsPK: true}},
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(filter), primaryKeyInfos, nil, binlogplayer.NewStats(), vtenv.NewTestEnv())
+	require.NoError(t, err)
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "t1",
+		Fields: []*querypb.Field{
+			{Name: "val", Type: querypb.Type_VARCHAR},
+			{Name: "id", Type: querypb.Type_INT64},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, keyFieldIndexes(tplan, []string{"pk"}))
+	require.Equal(t, []int{0, 1}, keyFieldIndexes(tplan, []string{"val", "pk"}))
+	require.Nil(t, keyFieldIndexes(tplan, []string{"other"}))
+
+	dbClient := binlogplayer.NewMockDBClient(t)
+	vr.dbClient = newVDBClient(dbClient, binlogplayer.NewStats(), 10)
+	pa := &parallelApplier{
+		vp:           &vplayer{vr: vr},
+		conflictKeys: make(map[*TablePlan][]conflictKey),
+	}
+	expectKeys := func(table string, uniqueKeys, foreignKeys *sqltypes.Result) {
+		dbClient.ExpectRequest(fmt.Sprintf("select index_name, column_name from information_schema.statistics "+
+			"where table_schema='db' and table_name='%s' and non_unique=0 and index_name != 'PRIMARY' order by index_name, seq_in_index", table),
+			uniqueKeys, nil)
+		dbClient.ExpectRequest(fmt.Sprintf("select constraint_name, column_name, referenced_table_name, referenced_column_name "+
+			"from information_schema.key_column_usage where table_schema='db' and table_name='%s' and referenced_table_name is not null "+
+			"order by constraint_name, ordinal_position", table),
+			foreignKeys, nil)
+	}
+	expectKeys("t1",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("index_name|column_name", "varchar|varchar"), "uk|val"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("constraint_name|column_name|referenced_table_name|referenced_column_name",
+			"varchar|varchar|varchar|varchar"), "fk|val|t0|name"),
+	)
+
+	row := func(val sqltypes.Value, id int64) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{val, sqltypes.NewInt64(id)})
+	}
+	keys, err := pa.appendKeys(nil, tplan, &binlogdatapb.RowEvent{
+		TableName: "t1",
+		RowChanges: []*binlogdatapb.RowChange{
+			{After: row(sqltypes.NewVarChar("a"), 1)},
+			{Before: row(sqltypes.NewVarChar("a"), 12), After: row(sqltypes.NULL, 3)},
+		},
+	})
+	require.NoError(t, err)
+	dbClient.Wait()
+	require.Equal(t, []string{
+		"t1(pk)\x001:1", "t1(val)\x001:a", "t0(name)\x001:a",
+		"t1(pk)\x002:12", "t1(val)\x001:a", "t0(name)\x001:a",
+		// NULL values don't conflict on the unique and foreign keys.
+		"t1(pk)\x001:3",
+	}, keys)
+
+	// The keys are cached per table plan, and the primary key is the same
+	// regardless of the values of the other columns.
+	otherKeys, err := pa.appendKeys(nil, tplan, &binlogdatapb.RowEvent{
+		TableName:  "t1",
+		RowChanges: []*binlogdatapb.RowChange{{Before: row(sqltypes.NewVarChar("c"), 1)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"t1(pk)\x001:1", "t1(val)\x001:c", "t0(name)\x001:c"}, otherKeys)
+
+	// A child row conflicts with the parent row it references.
+	parentPlan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "t0",
+		Fields: []*querypb.Field{
+			{Name: "name", Type: querypb.Type_VARCHAR},
+			{Name: "extra", Type: querypb.Type_INT64},
+		},
+	})
+	require.NoError(t, err)
+	expectKeys("t0", &sqltypes.Result{}, &sqltypes.Result{})
+	parentKeys, err := pa.appendKeys(nil, parentPlan, &binlogdatapb.RowEvent{
+		TableName:  "t0",
+		RowChanges: []*binlogdatapb.RowChange{{Before: row(sqltypes.NewVarChar("a"), 5)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"t0(name)\x001:a"}, parentKeys)
+
+	// Without a mapping of all keys to fields, all changes of the table conflict.
+	tplan, err = plan.buildExecutionPlan(&binlogdatapb.FieldEvent{
+		TableName: "t1",
+		Fields: []*querypb.Field{
+			{Name: "val", Type: querypb.Type_VARCHAR},
+			{Name: "id", Type: querypb.Type_INT64},
+		},
+	})
+	require.NoError(t, err)
+	expectKeys("t1",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("index_name|column_name", "varchar|varchar"), "uk|other"),
+		&sqltypes.Result{},
+	)
+	keys, err = pa.appendKeys(nil, tplan, &binlogdatapb.RowEvent{
+		TableName:  "t1",
+		RowChanges: []*binlogdatapb.RowChange{{After: row(sqltypes.NewVarChar("a"), 1)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"t1"}, keys)
+
+	keys, err = pa.appendKeys(nil, &TablePlan{TargetName: "t2"}, &binlogdatapb.RowEvent{
+		TableName:  "t2",
+		RowChanges: []*binlogdatapb.RowChange{{After: row(sqltypes.NewVarChar("a"), 1)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"t2"}, keys)
+}
+
+func TestPlayerParallelApply(t *testing.T) {
+	oldParallelApplyWorkers := vttablet.DefaultVReplicationConfig.ParallelApplyWorkers
+	vttablet.DefaultVReplicationConfig.ParallelApplyWorkers = 4
+	defer func() {
+		vttablet.DefaultVReplicationConfig.ParallelApplyWorkers = oldParallelApplyWorkers
+	}()
+
+	defer deleteTablet(addTablet(100))
+	execStatements(t, []string{
+		"create table t1(id int, val varchar(128), primary key(id))",
+		fmt.Sprintf("create table %s.t1(id int, val varchar(128), primary key(id))", vrepldb),
+		"create table t2(id int, val varchar(128), primary key(id), unique key(val))",
+		fmt.Sprintf("create table %s.t2(id int, val varchar(128), primary key(id), unique key(val))", vrepldb),
+	})
+	defer execStatements(t, []string{
+		"drop table t1",
+		fmt.Sprintf("drop table %s.t1", vrepldb),
+		"drop table t2",
+		fmt.Sprintf("drop table %s.t2", vrepldb),
+	})
+
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match: "/.*",
+		}},
+	}
+	bls := &binlogdatapb.BinlogSource{
+		Keyspace: env.KeyspaceName,
+		Shard:    env.ShardName,
+		Filter:   filter,
+		OnDdl:    binlogdatapb.OnDDLAction_IGNORE,
+	}
+	cancel, _ := startVReplication(t, bls, "")
+	defer cancel()
+
+	var statements []string
+	for i := 1; i <= 20; i++ {
+		statements = append(statements, fmt.Sprintf("insert into t1 values(%d, 'v%d')", i, i))
+	}
+	// Transactions which change the same rows are applied in order.
+	for i := 1; i <= 20; i += 2 {
+		statements = append(statements, fmt.Sprintf("update t1 set val = 'u%d' where id = %d", i, i))
+	}
+	statements = append(statements, "delete from t1 where id = 20")
+	// Transactions which conflict on a unique key are applied in order.
+	statements = append(statements,
+		"insert into t2 values(1, 'a')",
+		"update t2 set val = 'b' where id = 1",
+		"insert into t2 values(2, 'a')",
+	)
+	execStatements(t, statements)
+
+	var want [][]string
+	for i := 1; i < 20; i++ {
+		val := fmt.Sprintf("v%d", i)
+		if i%2 == 1 {
+			val = fmt.Sprintf("u%d", i)
+		}
+		want = append(want, []string{fmt.Sprint(i), val})
+	}
+	expectData(t, "t1", want)
+	expectData(t, "t2", [][]string{
+		{"1", "b"},
+		{"2", "a"},
+	})
+}
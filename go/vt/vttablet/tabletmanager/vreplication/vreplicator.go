@@ -117,6 +117,10 @@ type vreplicator struct {
 
 	throttleUpdatesRateLimiter *timer.RateLimiter
 	workflowConfig             *vttablet.VReplicationConfig
+
+	// sink is set when the workflow writes the row changes to a sink
+	// instead of the target tables. See the vreplication-sink config.
+	sink Sink
 }
 
 // newVReplicator creates a new vreplicator. The valid fields from the source are:
@@ -238,6 +242,30 @@ func (vr *vreplicator) validateBinlogRowImage() error {
 	return nil
 }
 
+// validateBinlogRowsQueryLogEvents checks that the statements applied by a bidirectional
+// workflow are recorded in the binary log, along with the comment which tags them.
+// The stream of the opposite direction reads this binary log, and would apply the
+// changes back otherwise.
+func (vr *vreplicator) validateBinlogRowsQueryLogEvents() error {
+	if !vr.workflowConfig.Bidirectional {
+		return nil
+	}
+	rs, err := vr.dbClient.Execute("select @@global.binlog_rows_query_log_events, @@session.binlog_rows_query_log_events")
+	if err != nil {
+		return err
+	}
+	if len(rs.Rows) != 1 || len(rs.Rows[0]) != 2 {
+		return vterrors.New(vtrpcpb.Code_INTERNAL, fmt.Sprintf("'select @@binlog_rows_query_log_events' returns an invalid result: %+v", rs.Rows))
+	}
+	for _, val := range rs.Rows[0] {
+		if v := strings.ToLower(val.ToString()); v != "1" && v != "on" {
+			return vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION,
+				"binlog_rows_query_log_events must be enabled, both globally and in the session, for bidirectional workflows")
+		}
+	}
+	return nil
+}
+
 func (vr *vreplicator) replicate(ctx context.Context) error {
 	// Manage SQL_MODE in the same way that mysqldump does.
 	// Save the original sql_mode, set it to a permissive mode,
@@ -266,6 +294,15 @@ func (vr *vreplicator) replicate(ctx context.Context) error {
 	vr.throttleUpdatesRateLimiter = timer.NewRateLimiter(time.Second)
 	defer vr.throttleUpdatesRateLimiter.Stop()
 
+	defer func() {
+		if vr.sink != nil {
+			if err := vr.sink.Close(); err != nil {
+				log.Warn(fmt.Sprintf("Unable to close the sink of workflow %s: %v", vr.WorkflowName, err))
+			}
+			vr.sink = nil
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -284,6 +321,22 @@ func (vr *vreplicator) replicate(ctx context.Context) error {
 		if err := vr.validateBinlogRowImage(); err != nil {
 			return err
 		}
+		if err := vr.validateBinlogRowsQueryLogEvents(); err != nil {
+			return err
+		}
+
+		if vr.workflowConfig.Sink != "" && vr.sink == nil {
+			// The target tables of a sink are never changed, so the DDLs
+			// can only be ignored or stop the workflow.
+			switch vr.source.OnDdl {
+			case binlogdatapb.OnDDLAction_EXEC, binlogdatapb.OnDDLAction_EXEC_IGNORE, binlogdatapb.OnDDLAction_TRANSFORM:
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "on-ddl %s is not supported by workflows that use vreplication-sink %s",
+					vr.source.OnDdl, vr.workflowConfig.Sink)
+			}
+			if vr.sink, err = newSink(vr.workflowConfig.Sink, vr.WorkflowName, vr.id); err != nil {
+				return err
+			}
+		}
 
 		// If any of the operations below changed state to Stopped or Error, we should return.
 		if settings.State == binlogdatapb.VReplicationWorkflowState_Stopped || settings.State == binlogdatapb.VReplicationWorkflowState_Error {
@@ -346,7 +399,14 @@ func (vr *vreplicator) replicate(ctx context.Context) error {
 				vr.stats.ErrorCounts.Add([]string{"Replicate"}, 1)
 				return err
 			}
-			return newVPlayer(vr, settings, nil, replication.Position{}, "replicate").play(ctx)
+			err := newVPlayer(vr, settings, nil, replication.Position{}, "replicate").play(ctx)
+			if !errors.Is(err, errDDLTransformed) {
+				return err
+			}
+			// The target schema or the filter changed, so we restart the player with a new plan.
+			if vr.colInfoMap, err = vr.buildColInfoMap(ctx); err != nil {
+				return err
+			}
 		}
 	}
 }
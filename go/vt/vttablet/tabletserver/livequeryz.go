@@ -53,9 +53,35 @@ var (
 			<td><a href='terminate?connID={{.ConnID}}'>Terminate</a></td>
 		</tr>
 	`))
+	livequeryzKillsHeader = []byte(`</table>
+	<h3>Killed Queries</h3>
+	<table class="gridtable">
+	<thead>
+		<tr>
+			<th>Time</th>
+			<th>Type</th>
+			<th>Query</th>
+			<th>ConnectionID</th>
+			<th>Resource</th>
+			<th>Value</th>
+			<th>Limit</th>
+		</tr>
+        </thead>
+	`)
+	livequeryzKillsTmpl = template.Must(template.New("kills").Parse(`
+		<tr>
+			<td>{{.Time}}</td>
+			<td>{{.Type}}</td>
+			<td>{{.Query}}</td>
+			<td>{{.ConnID}}</td>
+			<td>{{.Resource}}</td>
+			<td>{{.Value}}</td>
+			<td>{{.Limit}}</td>
+		</tr>
+	`))
 )
 
-func livequeryzHandler(queryLists []*QueryList, w http.ResponseWriter, r *http.Request) {
+func livequeryzHandler(queryLists []*QueryList, killer *queryKiller, w http.ResponseWriter, r *http.Request) {
 	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
 		acl.SendError(w, err)
 		return
@@ -87,9 +113,20 @@ func livequeryzHandler(queryLists []*QueryList, w http.ResponseWriter, r *http.R
 			log.Error(fmt.Sprintf("livequeryz: couldn't execute template: %v", err))
 		}
 	}
+	if !killer.IsEnabled() {
+		return
+	}
+	// Queries killed for exceeding resource limits are listed below the
+	// running queries.
+	w.Write(livequeryzKillsHeader)
+	for _, event := range killer.Events() {
+		if err := livequeryzKillsTmpl.Execute(w, event); err != nil {
+			log.Error(fmt.Sprintf("livequeryz: couldn't execute template: %v", err))
+		}
+	}
 }
 
-func livequeryzTerminateHandler(queryLists []*QueryList, w http.ResponseWriter, r *http.Request) {
+func livequeryzTerminateHandler(queryLists []*QueryList, killer *queryKiller, w http.ResponseWriter, r *http.Request) {
 	if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
 		acl.SendError(w, err)
 		return
@@ -109,5 +146,5 @@ func livequeryzTerminateHandler(queryLists []*QueryList, w http.ResponseWriter,
 			break
 		}
 	}
-	livequeryzHandler(queryLists, w, r)
+	livequeryzHandler(queryLists, killer, w, r)
 }
@@ -37,7 +37,7 @@ func TestLiveQueryzHandlerJSON(t *testing.T) {
 	err = queryList.Add(NewQueryDetail(context.Background(), &testConn{id: 2}))
 	require.NoError(t, err)
 
-	livequeryzHandler([]*QueryList{queryList}, resp, req)
+	livequeryzHandler([]*QueryList{queryList}, nil, resp, req)
 }
 
 func TestLiveQueryzHandlerHTTP(t *testing.T) {
@@ -50,14 +50,14 @@ func TestLiveQueryzHandlerHTTP(t *testing.T) {
 	err = queryList.Add(NewQueryDetail(context.Background(), &testConn{id: 2}))
 	require.NoError(t, err)
 
-	livequeryzHandler([]*QueryList{queryList}, resp, req)
+	livequeryzHandler([]*QueryList{queryList}, nil, resp, req)
 }
 
 func TestLiveQueryzHandlerHTTPFailedInvalidForm(t *testing.T) {
 	resp := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/livequeryz/", nil)
 
-	livequeryzHandler([]*QueryList{NewQueryList("test", sqlparser.NewTestParser())}, resp, req)
+	livequeryzHandler([]*QueryList{NewQueryList("test", sqlparser.NewTestParser())}, nil, resp, req)
 	if resp.Code != http.StatusInternalServerError {
 		t.Fatalf("http call should fail and return code: %d, but got: %d",
 			http.StatusInternalServerError, resp.Code)
@@ -75,7 +75,7 @@ func TestLiveQueryzHandlerTerminateConn(t *testing.T) {
 	if testConn.IsKilled() {
 		t.Fatalf("conn should still be alive")
 	}
-	livequeryzTerminateHandler([]*QueryList{queryList}, resp, req)
+	livequeryzTerminateHandler([]*QueryList{queryList}, nil, resp, req)
 	if !testConn.IsKilled() {
 		t.Fatalf("conn should be killed")
 	}
@@ -85,7 +85,7 @@ func TestLiveQueryzHandlerTerminateFailedInvalidConnID(t *testing.T) {
 	resp := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/livequeryz//terminate?connID=invalid", nil)
 
-	livequeryzTerminateHandler([]*QueryList{NewQueryList("test", sqlparser.NewTestParser())}, resp, req)
+	livequeryzTerminateHandler([]*QueryList{NewQueryList("test", sqlparser.NewTestParser())}, nil, resp, req)
 	if resp.Code != http.StatusInternalServerError {
 		t.Fatalf("http call should fail and return code: %d, but got: %d",
 			http.StatusInternalServerError, resp.Code)
@@ -96,7 +96,7 @@ func TestLiveQueryzHandlerTerminateFailedInvalidForm(t *testing.T) {
 	resp := httptest.NewRecorder()
 	req, _ := http.NewRequest("POST", "/livequeryz//terminate?inva+lid=2", nil)
 
-	livequeryzTerminateHandler([]*QueryList{NewQueryList("test", sqlparser.NewTestParser())}, resp, req)
+	livequeryzTerminateHandler([]*QueryList{NewQueryList("test", sqlparser.NewTestParser())}, nil, resp, req)
 	if resp.Code != http.StatusInternalServerError {
 		t.Fatalf("http call should fail and return code: %d, but got: %d",
 			http.StatusInternalServerError, resp.Code)
@@ -17,6 +17,7 @@ limitations under the License.
 package planbuilder
 
 import (
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -30,9 +31,9 @@ import (
 
 func analyzeUnion(stmt *sqlparser.Union, noRowslimit bool) *Plan {
 	if noRowslimit {
-		return &Plan{PlanID: PlanSelect, FullQuery: GenerateFullQuery(stmt)}
+		return &Plan{PlanID: PlanSelect, FullQuery: GenerateFullQuery(stmt), LocksRows: hasLockClause(stmt)}
 	}
-	return &Plan{PlanID: PlanSelect, FullQuery: GenerateLimitQuery(stmt)}
+	return &Plan{PlanID: PlanSelect, FullQuery: GenerateLimitQuery(stmt), LocksRows: hasLockClause(stmt)}
 }
 
 func analyzeSelect(env *vtenv.Environment, sel *sqlparser.Select, tables map[string]*schema.Table, noRowsLimit bool) (plan *Plan, err error) {
@@ -47,6 +48,7 @@ func analyzeSelect(env *vtenv.Environment, sel *sqlparser.Select, tables map[str
 	}
 
 	plan.Table = lookupTables(sel.From, tables)
+	plan.LocksRows = hasLockClause(sel)
 
 	if sel.Where != nil {
 		comp, ok := sel.Where.Expr.(*sqlparser.ComparisonExpr)
@@ -77,9 +79,73 @@ func analyzeSelect(env *vtenv.Environment, sel *sqlparser.Select, tables map[str
 		plan.PlanID = PlanSelectLockFunc
 		plan.NeedsReservedConn = true
 	}
+	if plan.PlanID == PlanSelect {
+		plan.ResultCacheable = isResultCacheable(sel, tables)
+	}
 	return plan, nil
 }
 
+// hasLockClause returns true if any SELECT in the statement has a locking
+// clause like FOR UPDATE or LOCK IN SHARE MODE.
+func hasLockClause(stmt sqlparser.Statement) bool {
+	locks := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			locks = locks || node.Lock != sqlparser.NoLock
+		case *sqlparser.Union:
+			locks = locks || node.Lock != sqlparser.NoLock
+		}
+		return !locks, nil
+	}, stmt)
+	return locks
+}
+
+// isResultCacheable returns true if the results of the select may be served
+// from the result cache. The RESULT_CACHE directive takes precedence over the
+// tables' setting. Without the directive, all tables must be flagged with the
+// vitess_result_cache comment. In either case, every table must be a known
+// base table, because the cache is invalidated per table by row events, and
+// the result must not depend on when or by whom the query is executed.
+func isResultCacheable(sel *sqlparser.Select, tables map[string]*schema.Table) bool {
+	if sel.Lock != sqlparser.NoLock || sel.SQLCalcFoundRows || !isDeterministic(sel) {
+		return false
+	}
+	tableNames := sqlparser.ExtractAllTables(sel)
+	if len(tableNames) == 0 {
+		return false
+	}
+	allFlagged := true
+	for _, name := range tableNames {
+		t := tables[name]
+		if t == nil || t.Type == schema.View {
+			return false
+		}
+		allFlagged = allFlagged && t.ResultCache
+	}
+	if val, ok := sel.GetParsedComments().Directives().GetString(sqlparser.DirectiveResultCache, ""); ok {
+		enabled, err := strconv.ParseBool(val)
+		return err == nil && enabled
+	}
+	return allFlagged
+}
+
+// isDeterministic returns false if the select uses non-deterministic
+// functions like NOW() or RAND(), or user or system variables.
+func isDeterministic(sel *sqlparser.Select) bool {
+	deterministic := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.CurTimeFuncExpr, *sqlparser.Variable:
+			deterministic = false
+		case *sqlparser.FuncExpr:
+			deterministic = !sqlparser.NonDeterministicFuncs[node.Name.Lowered()]
+		}
+		return deterministic, nil
+	}, sel)
+	return deterministic
+}
+
 // analyzeUpdate code is almost identical to analyzeDelete.
 func analyzeUpdate(upd *sqlparser.Update, tables map[string]*schema.Table) (plan *Plan, err error) {
 	plan = &Plan{
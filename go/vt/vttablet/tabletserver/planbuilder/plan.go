@@ -178,6 +178,14 @@ type Plan struct {
 
 	// NeedsReservedConn indicates at a reserved connection is needed to execute this plan
 	NeedsReservedConn bool
+
+	// LocksRows is set for SELECTs with a locking clause like FOR UPDATE.
+	// Such SELECTs must run on the connection of their transaction.
+	LocksRows bool
+
+	// ResultCacheable indicates that the results of this plan may be served
+	// from the tablet result cache.
+	ResultCacheable bool
 }
 
 // TableName returns the table name for the plan.
@@ -291,6 +291,75 @@ func TestLockPlan(t *testing.T) {
 	}
 }
 
+func TestResultCacheablePlan(t *testing.T) {
+	testSchema := loadSchema("schema_test.json")
+	testSchema["a"].ResultCache = true
+	testSchema["b"].ResultCache = true
+	parser := sqlparser.NewTestParser()
+
+	testcases := []struct {
+		query     string
+		cacheable bool
+	}{
+		{"select * from a", true},
+		{"select * from a join b on a.eid = b.eid", true},
+		{"select * from a where eid in (select eid from b)", true},
+		{"select * from a join c on a.eid = c.eid", false},
+		{"select * from c", false},
+		{"select /*vt+ RESULT_CACHE=true */ * from c", true},
+		{"select /*vt+ RESULT_CACHE=false */ * from a", false},
+		{"select * from a for update", false},
+		{"select * from a lock in share mode", false},
+		{"select sql_calc_found_rows * from a", false},
+		{"select * from unknown_table", false},
+		{"select /*vt+ RESULT_CACHE=true */ * from unknown_table", false},
+		{"select 1 from dual", false},
+		{"select get_lock('foo', 10) from a", false},
+		{"select now(), eid from a", false},
+		{"select * from a where eid > rand()", false},
+		{"select uuid() from a", false},
+		{"select * from a where eid = @eid", false},
+		{"select @@session.sql_mode, eid from a", false},
+		{"select upper(foo) from a", true},
+		{"select * from a union select * from b", false},
+	}
+	for _, tcase := range testcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			statement, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			plan, err := Build(vtenv.NewTestEnv(), statement, testSchema, "dbName", false)
+			require.NoError(t, err)
+			require.Equal(t, tcase.cacheable, plan.ResultCacheable)
+		})
+	}
+}
+
+func TestLocksRowsPlan(t *testing.T) {
+	testSchema := loadSchema("schema_test.json")
+	parser := sqlparser.NewTestParser()
+
+	testcases := []struct {
+		query     string
+		locksRows bool
+	}{
+		{"select * from a", false},
+		{"select * from a for update", true},
+		{"select * from a lock in share mode", true},
+		{"select * from a where eid in (select eid from b for share)", true},
+		{"select * from a union select * from b", false},
+		{"select * from a union select * from b for update", true},
+	}
+	for _, tcase := range testcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			statement, err := parser.Parse(tcase.query)
+			require.NoError(t, err)
+			plan, err := Build(vtenv.NewTestEnv(), statement, testSchema, "dbName", false)
+			require.NoError(t, err)
+			require.Equal(t, tcase.locksRows, plan.LocksRows)
+		})
+	}
+}
+
 func loadSchema(name string) map[string]*schema.Table {
 	b, err := os.ReadFile(locateFile(name))
 	if err != nil {
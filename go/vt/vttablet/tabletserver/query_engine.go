@@ -47,6 +47,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/resultcache"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -172,6 +173,15 @@ type QueryEngine struct {
 	// that we start more than one transaction per hot row (range).
 	// For implementation details, please see BeginExecute() in tabletserver.go.
 	txSerializer *txserializer.TxSerializer
+	// resultCache serves the results of SELECTs on tables which opted into
+	// caching. It's nil unless set up by the TabletServer.
+	resultCache *resultcache.Cache
+	// queryKiller kills running queries which exceed resource limits.
+	// It's nil unless set up by the TabletServer.
+	queryKiller *queryKiller
+	// rowsExamined reads the rows examined by the queries which are subject
+	// to a rows quota. It's nil unless set up by the TabletServer.
+	rowsExamined *rowsExaminedReader
 
 	// Vars
 	maxResultSize    atomic.Int64
@@ -350,6 +360,10 @@ func (qe *QueryEngine) Open() error {
 	}
 
 	qe.streamConns.Open(config.DB.AppWithDB(), config.DB.DbaWithDB(), config.DB.AppDebugWithDB())
+	qe.txSerializer.Open()
+	qe.resultCache.Open()
+	qe.queryKiller.Open()
+	qe.rowsExamined.Open()
 	qe.se.RegisterNotifier("qe", qe.schemaChanged, true)
 	qe.plans.EnsureOpen()
 	qe.settings.EnsureOpen()
@@ -370,6 +384,10 @@ func (qe *QueryEngine) Close() {
 	qe.plans.Close()
 	qe.settings.Close()
 
+	qe.rowsExamined.Close()
+	qe.queryKiller.Close()
+	qe.resultCache.Close()
+	qe.txSerializer.Close()
 	qe.streamConns.Close()
 	qe.conns.Close()
 	log.Info("Query Engine: closed")
@@ -67,6 +67,12 @@ type QueryExecutor struct {
 	// The target type we requested might be different from tsv's tablet type, if we had a change to the tablet type recently.
 	targetTabletType topodatapb.TabletType
 	setting          *smartconnpool.Setting
+	// rowsExamined counts the rows which MySQL examined for the query. It's
+	// only fetched if a rows quota applies to the query, in which case
+	// fetchRowsExamined is set.
+	fetchRowsExamined bool
+	rowsExamined      int64
+	rowsExaminedKnown bool
 }
 
 const (
@@ -121,6 +127,53 @@ func (qre *QueryExecutor) shouldConsolidate() bool {
 	}
 }
 
+// consolidationKey returns the key under which the query can be consolidated
+// with identical queries. It returns false if the query must not be
+// consolidated, either because the consolidator is disabled for it, or
+// because a write to one of its tables is in flight.
+func (qre *QueryExecutor) consolidationKey(sqlWithoutComments string) (string, bool) {
+	// Check tablet type.
+	if !qre.shouldConsolidate() {
+		return "", false
+	}
+	return qre.tsv.te.txPool.writes.consolidationKey(sqlWithoutComments, qre.plan.TableNames())
+}
+
+// shouldConsolidateInTransaction returns true if a read inside the transaction
+// may be consolidated with identical reads of other sessions, which requires
+// --enable-consolidator-read-your-writes. Only the first statement of a
+// transaction may be consolidated: once the transaction read or wrote, or if
+// it was started WITH CONSISTENT SNAPSHOT, its reads must see its own
+// snapshot. Locking reads and reads on reserved connections always run on
+// the transaction's connection.
+func (qre *QueryExecutor) shouldConsolidateInTransaction(conn *StatefulConnection) bool {
+	if qre.tsv.te.txPool.writes == nil || qre.plan.PlanID != p.PlanSelect || qre.plan.LocksRows {
+		return false
+	}
+	if conn.IsTainted() || !conn.TxProperties().InTransaction() || qre.setting != nil || !qre.shouldConsolidate() {
+		return false
+	}
+	return !conn.TxProperties().Snapshot
+}
+
+// execConsolidatedInTransaction runs a read of a transaction outside of it,
+// so that it can be consolidated with identical reads of other sessions.
+// If it can't be consolidated, it runs on the transaction's connection.
+func (qre *QueryExecutor) execConsolidatedInTransaction(conn *StatefulConnection) (*sqltypes.Result, error) {
+	// Later reads must not see an older snapshot than this one.
+	conn.TxProperties().Snapshot = true
+	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := qre.consolidationKey(sqlWithoutComments); ok {
+		if res, consolidated, err := qre.execConsolidated(key, sql); consolidated {
+			return res, err
+		}
+	}
+	return qre.execTxQuery(conn, sql, false)
+}
+
 // Execute performs a non-streaming query execution.
 func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 	planName := qre.plan.PlanID.String()
@@ -141,6 +194,14 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		vtErrorCode := vterrors.Code(err)
 		errCode = vtErrorCode.String()
 
+		if qre.tsv.quotas.IsEnabled() {
+			var rows int64
+			if reply != nil {
+				rows = int64(len(reply.Rows)) + int64(reply.RowsAffected)
+			}
+			qre.tsv.quotas.Record(qre.callerPrincipal(), qre.plan.TableNames(), qre.quotaRows(rows), mysqlTime)
+		}
+
 		if reply == nil {
 			qre.tsv.qe.AddStats(qre.plan, tableName, qre.options.GetWorkloadName(), qre.targetTabletType, 1, duration, mysqlTime, 0, 0, 1, errCode)
 			qre.plan.AddStats(1, duration, mysqlTime, 0, 0, 1)
@@ -162,6 +223,13 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		return nil, reqThrottledErr
 	}
 
+	if qre.tsv.quotas.IsEnabled() {
+		if err = qre.tsv.quotas.Check(qre.callerPrincipal(), qre.plan.TableNames()); err != nil {
+			return nil, err
+		}
+		qre.fetchRowsExamined = qre.tsv.quotas.LimitsRows(qre.callerPrincipal(), qre.plan.TableNames())
+	}
+
 	if qre.plan.PlanID == p.PlanNextval {
 		return qre.execNextval()
 	}
@@ -319,7 +387,13 @@ func (qre *QueryExecutor) txConnExec(conn *StatefulConnection) (*sqltypes.Result
 		if qre.bindVars[sqltypes.BvReplaceSchemaName] != nil {
 			qre.bindVars[sqltypes.BvSchemaName] = sqltypes.StringBindVariable(qre.tsv.config.DB.DBName)
 		}
-		qr, err := qre.txFetch(conn, false)
+		var qr *sqltypes.Result
+		var err error
+		if qre.shouldConsolidateInTransaction(conn) {
+			qr, err = qre.execConsolidatedInTransaction(conn)
+		} else {
+			qr, err = qre.txFetch(conn, false)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -357,6 +431,26 @@ func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 		return reqThrottledErr
 	}
 
+	if qre.tsv.quotas.IsEnabled() {
+		principal, tables := qre.callerPrincipal(), qre.plan.TableNames()
+		release, err := qre.tsv.quotas.StartStream(principal, tables)
+		if err != nil {
+			return err
+		}
+		defer release()
+		qre.fetchRowsExamined = qre.tsv.quotas.LimitsRows(principal, tables)
+
+		var rows int64
+		defer func() {
+			qre.tsv.quotas.Record(principal, tables, qre.quotaRows(rows), qre.logStats.MysqlResponseTime)
+		}()
+		streamCallback := callback
+		callback = func(result *sqltypes.Result) error {
+			rows += int64(len(result.Rows))
+			return streamCallback(result)
+		}
+	}
+
 	switch qre.plan.PlanID {
 	case p.PlanSelectStream:
 		if qre.bindVars[sqltypes.BvReplaceSchemaName] != nil {
@@ -733,49 +827,45 @@ func (qre *QueryExecutor) execNextval() (*sqltypes.Result, error) {
 	}, nil
 }
 
-// execSelect sends a query to mysql only if another identical query is not running. Otherwise, it waits and
-// reuses the result. If the plan is missing field info, it sends the query to mysql requesting full info.
+// execSelect serves the query from the result cache if possible. Otherwise, it sends the query to mysql
+// only if another identical query is not running. Otherwise, it waits and reuses the result.
 func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
 	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
-	// Check tablet type.
-	if qre.shouldConsolidate() {
-		q, original := qre.tsv.qe.consolidator.Create(sqlWithoutComments)
-		waiterCapExceeded := false
-		if original {
-			defer q.Broadcast()
-			conn, err := qre.getConn()
+	if !qre.shouldUseResultCache() {
+		return qre.execSelectSQL(sql, sqlWithoutComments)
+	}
+	// The bind variables are already substituted, so the query
+	// without comments is a complete key for the result.
+	rc := qre.tsv.qe.resultCache
+	res, token, ok := rc.Get(sqlWithoutComments, qre.plan.TableNames())
+	if ok {
+		qre.logStats.QuerySources |= tabletenv.QuerySourceResultCache
+		return res, nil
+	}
+	res, err = qre.execSelectSQL(sql, sqlWithoutComments)
+	if err != nil {
+		return nil, err
+	}
+	rc.Set(sqlWithoutComments, token, res)
+	return res, nil
+}
 
-			if err != nil {
-				q.SetErr(err)
-			} else {
-				defer conn.Recycle()
-				res, err := qre.execDBConn(conn.Conn, sql, true)
-				q.SetResult(res)
-				q.SetErr(err)
-			}
-		} else {
-			waiterCap := qre.tsv.config.ConsolidatorQueryWaiterCap
-			if waiterCap == 0 || *q.AddWaiterCounter(0) <= waiterCap {
-				qre.logStats.QuerySources |= tabletenv.QuerySourceConsolidator
-				startTime := time.Now()
-				q.Wait()
-				qre.tsv.stats.WaitTimings.Record("Consolidations", startTime)
-			} else {
-				// Waiter cap exceeded, fall back to independent query execution
-				waiterCapExceeded = true
-			}
-			q.AddWaiterCounter(-1)
-		}
+// shouldUseResultCache returns true if the result of the query may be served
+// from, and stored in, the result cache. Queries with connection settings
+// are excluded because the settings can change the result.
+func (qre *QueryExecutor) shouldUseResultCache() bool {
+	return qre.plan.ResultCacheable && qre.setting == nil && qre.tsv.qe.resultCache.IsEnabled()
+}
 
-		// Return consolidation results unless waiter cap was exceeded
-		if !waiterCapExceeded {
-			if q.Err() != nil {
-				return nil, q.Err()
-			}
-			return q.Result(), nil
+// execSelectSQL sends the final query to mysql, consolidating it with
+// an identical query which is already running if enabled.
+func (qre *QueryExecutor) execSelectSQL(sql, sqlWithoutComments string) (*sqltypes.Result, error) {
+	if key, ok := qre.consolidationKey(sqlWithoutComments); ok {
+		if res, consolidated, err := qre.execConsolidated(key, sql); consolidated {
+			return res, err
 		}
 		// If waiter cap exceeded, fall through to independent execution
 	}
@@ -791,6 +881,42 @@ func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
 	return res, nil
 }
 
+// execConsolidated runs the query on a pool connection unless an identical
+// query is already running, in which case it waits for its result instead.
+// It returns false if the query was not executed, because the waiter cap was
+// exceeded.
+func (qre *QueryExecutor) execConsolidated(key, sql string) (*sqltypes.Result, bool, error) {
+	q, original := qre.tsv.qe.consolidator.Create(key)
+	if original {
+		defer q.Broadcast()
+		conn, err := qre.getConn()
+
+		if err != nil {
+			q.SetErr(err)
+		} else {
+			defer conn.Recycle()
+			res, err := qre.execDBConn(conn.Conn, sql, true)
+			q.SetResult(res)
+			q.SetErr(err)
+		}
+	} else {
+		defer q.AddWaiterCounter(-1)
+		waiterCap := qre.tsv.config.ConsolidatorQueryWaiterCap
+		if waiterCap != 0 && *q.AddWaiterCounter(0) > waiterCap {
+			// Waiter cap exceeded, fall back to independent query execution
+			return nil, false, nil
+		}
+		qre.logStats.QuerySources |= tabletenv.QuerySourceConsolidator
+		startTime := time.Now()
+		q.Wait()
+		qre.tsv.stats.WaitTimings.Record("Consolidations", startTime)
+	}
+	if q.Err() != nil {
+		return nil, true, q.Err()
+	}
+	return q.Result(), true, nil
+}
+
 func (qre *QueryExecutor) execDMLLimit(conn *StatefulConnection) (*sqltypes.Result, error) {
 	maxrows := qre.tsv.qe.maxResultSize.Load()
 	qre.bindVars["#maxLimit"] = sqltypes.Int64BindVariable(maxrows + 1)
@@ -860,6 +986,10 @@ func (qre *QueryExecutor) txFetch(conn *StatefulConnection, record bool) (*sqlty
 
 // execTxQuery executes the query provided and record in Tx Property if record is true.
 func (qre *QueryExecutor) execTxQuery(conn *StatefulConnection, sql string, record bool) (*sqltypes.Result, error) {
+	if record && conn.TxProperties().Autocommit {
+		// The write becomes visible as soon as it's executed.
+		defer qre.tsv.te.txPool.writes.start(qre.plan.TableNames())()
+	}
 	qr, err := qre.execStatefulConn(conn, sql, true)
 	if err != nil {
 		return nil, err
@@ -1186,9 +1316,11 @@ func (qre *QueryExecutor) execDBConn(conn *connpool.Conn, sql string, wantfields
 		return nil, err
 	}
 
+	skip := qre.lastInsertIDQueries(exec)
 	if err := qre.fetchLastInsertID(ctx, conn, exec); err != nil {
 		return nil, err
 	}
+	qre.addRowsExamined(ctx, conn, skip)
 
 	return exec, nil
 }
@@ -1205,6 +1337,10 @@ func (qre *QueryExecutor) execStatefulConn(conn *StatefulConnection, sql string,
 	}
 	defer qre.tsv.statefulql.Remove(qd)
 
+	if conn.TxProperties().InTransaction() {
+		conn.TxProperties().Snapshot = true
+	}
+
 	if err := qre.resetLastInsertIDIfNeeded(ctx, conn.UnderlyingDBConn().Conn); err != nil {
 		return nil, err
 	}
@@ -1214,9 +1350,11 @@ func (qre *QueryExecutor) execStatefulConn(conn *StatefulConnection, sql string,
 		return nil, err
 	}
 
+	skip := qre.lastInsertIDQueries(exec)
 	if err := qre.fetchLastInsertID(ctx, conn.UnderlyingDBConn().Conn, exec); err != nil {
 		return nil, err
 	}
+	qre.addRowsExamined(ctx, conn.UnderlyingDBConn().Conn, skip)
 
 	return exec, nil
 }
@@ -1240,8 +1378,17 @@ func (qre *QueryExecutor) resetLastInsertIDIfNeeded(ctx context.Context, conn *c
 	return nil
 }
 
-func (qre *QueryExecutor) fetchLastInsertID(ctx context.Context, conn *connpool.Conn, exec *sqltypes.Result) error {
+// lastInsertIDQueries returns the number of queries which fetchLastInsertID
+// runs for the result.
+func (qre *QueryExecutor) lastInsertIDQueries(exec *sqltypes.Result) int {
 	if exec.InsertIDUpdated() || !qre.options.GetFetchLastInsertId() {
+		return 0
+	}
+	return 1
+}
+
+func (qre *QueryExecutor) fetchLastInsertID(ctx context.Context, conn *connpool.Conn, exec *sqltypes.Result) error {
+	if qre.lastInsertIDQueries(exec) == 0 {
 		return nil
 	}
 
@@ -1262,6 +1409,34 @@ func (qre *QueryExecutor) fetchLastInsertID(ctx context.Context, conn *connpool.
 	return nil
 }
 
+// addRowsExamined adds the rows which MySQL examined for the query to
+// qre.rowsExamined, if a rows quota applies to the query. skip is the number
+// of statements which ran on the connection after the query. The rows are
+// read on another connection, so this costs a round trip to MySQL but does
+// not change the session of the connection. They stay unknown if
+// performance_schema does not report them.
+func (qre *QueryExecutor) addRowsExamined(ctx context.Context, conn *connpool.Conn, skip int) {
+	if !qre.fetchRowsExamined {
+		return
+	}
+	rows, ok := qre.tsv.qe.rowsExamined.Read(ctx, conn.ID(), skip)
+	if !ok {
+		return
+	}
+	qre.rowsExamined += rows
+	qre.rowsExaminedKnown = true
+}
+
+// quotaRows returns the rows to charge to the rows quotas of the query: the
+// rows which MySQL examined for it if they are known, or the given rows
+// otherwise, e.g. if the result was shared by the consolidator.
+func (qre *QueryExecutor) quotaRows(rows int64) int64 {
+	if qre.rowsExaminedKnown {
+		return qre.rowsExamined
+	}
+	return rows
+}
+
 func (qre *QueryExecutor) execStreamSQL(conn *connpool.PooledConn, isTransaction bool, sql string, callback func(*sqltypes.Result) error) error {
 	span, ctx := trace.NewSpan(qre.ctx, "QueryExecutor.execStreamSQL")
 	defer span.Finish()
@@ -1306,28 +1481,40 @@ func (qre *QueryExecutor) execStreamSQL(conn *connpool.PooledConn, isTransaction
 		err = conn.Conn.Stream(ctx, sql, cb, allocStreamResult, int(qre.tsv.qe.streamBufferSize.Load()), sqltypes.IncludeFieldsOrDefault(qre.options))
 	}
 
-	if err != nil || lastInsertIDSet || !qre.options.GetFetchLastInsertId() {
+	if err != nil {
 		return err
 	}
+	if lastInsertIDSet || !qre.options.GetFetchLastInsertId() {
+		qre.addRowsExamined(ctx, conn.Conn, 0)
+		return nil
+	}
 	res := &sqltypes.Result{}
 	if err = qre.fetchLastInsertID(ctx, conn.Conn, res); err != nil {
 		return err
 	}
+	qre.addRowsExamined(ctx, conn.Conn, 1)
 	if res.InsertIDUpdated() {
 		return callback(res)
 	}
 	return nil
 }
 
+// callerPrincipal returns the principal of the effective caller, or the
+// username of the immediate caller if there's no principal.
+func (qre *QueryExecutor) callerPrincipal() string {
+	principal := callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(qre.ctx))
+	if principal == "" {
+		principal = callerid.GetUsername(callerid.ImmediateCallerIDFromContext(qre.ctx))
+	}
+	return principal
+}
+
 func (qre *QueryExecutor) recordUserQuery(queryType string, duration int64) {
 	var username string
 	if qre.tsv.config.SkipUserMetrics {
 		username = userLabelDisabled
 	} else {
-		username = callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(qre.ctx))
-		if username == "" {
-			username = callerid.GetUsername(callerid.ImmediateCallerIDFromContext(qre.ctx))
-		}
+		username = qre.callerPrincipal()
 	}
 	tableName := qre.plan.TableName().String()
 	qre.tsv.Stats().UserTableQueryCount.Add([]string{tableName, username, queryType}, 1)
@@ -41,17 +41,21 @@ import (
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/callinfo"
 	"vitess.io/vitess/go/vt/callinfo/fakecallinfo"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/sidecardb"
 	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/tableacl/simpleacl"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/quota"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/resultcache"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tx"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/txthrottler"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -1539,6 +1543,219 @@ func TestQueryExecutorConsolidatorWaiterCapFallback(t *testing.T) {
 	db.VerifyAllExecutedOrFail()
 }
 
+func TestQueryExecutorConsolidatorReadYourWrites(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, enableConsolidator, db)
+	defer tsv.StopService()
+	tsv.te.txPool.writes = newWriteTracker()
+
+	fakeConsolidator := sync2.NewFakeConsolidator()
+	tsv.qe.consolidator = fakeConsolidator
+	fakePendingResult := &sync2.FakePendingResult{}
+	fakeConsolidator.CreateReturn = &sync2.FakeConsolidatorCreateReturn{
+		Created:       true,
+		PendingResult: fakePendingResult,
+	}
+
+	input := "select * from test_table limit 10001"
+	result := &sqltypes.Result{Fields: getTestTableFields()}
+	db.AddQuery(input, result)
+	db.AddQuery("update test_table set a = 1 limit 10001", &sqltypes.Result{RowsAffected: 1})
+
+	execute := func(txID int64) {
+		qre := newTestQueryExecutor(ctx, tsv, input, txID)
+		fakePendingResult.SetResult(result)
+		_, err := qre.Execute()
+		require.NoError(t, err)
+	}
+
+	execute(0)
+	assert.Equal(t, []string{input + "/*0*/"}, fakeConsolidator.CreateCalls)
+
+	// Reads are not consolidated while a write to their table is in flight.
+	done := tsv.te.txPool.writes.start([]string{"test_table"})
+	execute(0)
+	assert.Len(t, fakeConsolidator.CreateCalls, 1)
+	done()
+	execute(0)
+	assert.Equal(t, input+"/*1*/", fakeConsolidator.CreateCalls[1])
+
+	// Only the first read of a transaction is consolidated. Later reads must
+	// see the transaction's own snapshot.
+	txID := newTransaction(tsv, nil)
+	execute(txID)
+	assert.Equal(t, input+"/*1*/", fakeConsolidator.CreateCalls[2])
+	execute(txID)
+	assert.Len(t, fakeConsolidator.CreateCalls, 3)
+	qre := newTestQueryExecutor(ctx, tsv, "update test_table set a = 1", txID)
+	_, err := qre.Execute()
+	require.NoError(t, err)
+	execute(txID)
+	assert.Len(t, fakeConsolidator.CreateCalls, 3)
+
+	// Reads which can't be consolidated run inside the transaction.
+	otherTxID := newTransaction(tsv, nil)
+	done = tsv.te.txPool.writes.start([]string{"test_table"})
+	called := db.GetQueryCalledNum(input)
+	execute(otherTxID)
+	done()
+	assert.Len(t, fakeConsolidator.CreateCalls, 3)
+	assert.Equal(t, called+1, db.GetQueryCalledNum(input))
+	otherConn, err := tsv.te.txPool.GetAndLock(otherTxID, "for test")
+	require.NoError(t, err)
+	assert.True(t, otherConn.TxProperties().Snapshot)
+	otherConn.Unlock()
+	_, err = tsv.Rollback(ctx, tsv.sm.Target(), otherTxID)
+	require.NoError(t, err)
+
+	// Transactions started with a consistent snapshot are never consolidated.
+	db.AddQuery("start transaction with consistent snapshot", &sqltypes.Result{})
+	snapshotTxID := newTransaction(tsv, &querypb.ExecuteOptions{
+		TransactionAccessMode: []querypb.ExecuteOptions_TransactionAccessMode{querypb.ExecuteOptions_CONSISTENT_SNAPSHOT},
+	})
+	execute(snapshotTxID)
+	assert.Len(t, fakeConsolidator.CreateCalls, 3)
+	_, err = tsv.Rollback(ctx, tsv.sm.Target(), snapshotTxID)
+	require.NoError(t, err)
+
+	// Locking reads always run inside the transaction.
+	db.AddQuery("select * from test_table limit 10001 for update", result)
+	qre = newTestQueryExecutor(ctx, tsv, "select * from test_table for update", txID)
+	_, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Len(t, fakeConsolidator.CreateCalls, 3)
+
+	// The commit completes the write.
+	_, err = tsv.Commit(ctx, tsv.sm.Target(), txID)
+	require.NoError(t, err)
+	execute(0)
+	assert.Equal(t, input+"/*3*/", fakeConsolidator.CreateCalls[3])
+}
+
+func TestQueryExecutorQuotas(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.QueryQuotas = &tabletenv.QueryQuotasFlag{Quotas: []tabletenv.QueryQuota{
+		{Principal: "tenant1", Table: "test_table", RowsPerMinute: 5, MaxConcurrentStreams: 1},
+	}}
+	tsv.quotas = quota.New(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name()))
+
+	input := "select * from test_table limit 10001"
+	db.AddQuery(input, &sqltypes.Result{
+		Fields: getTestTableFields(),
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewInt32(1), sqltypes.NewInt32(2), sqltypes.NewInt32(3)},
+			{sqltypes.NewInt32(4), sqltypes.NewInt32(5), sqltypes.NewInt32(6)},
+		},
+	})
+
+	tenant1 := callerid.NewContext(ctx, callerid.NewEffectiveCallerID("tenant1", "", ""), callerid.NewImmediateCallerID("app"))
+	tenant2 := callerid.NewContext(ctx, callerid.NewEffectiveCallerID("tenant2", "", ""), callerid.NewImmediateCallerID("app"))
+
+	// The query examines more rows than it returns. The rows examined are
+	// read on another connection.
+	var rowsExaminedQueries []string
+	tsv.qe.rowsExamined = &rowsExaminedReader{
+		errorLog: logutil.NewThrottledLogger("RowsExamined", time.Minute),
+		exec: func(ctx context.Context, query string) (*sqltypes.Result, error) {
+			rowsExaminedQueries = append(rowsExaminedQueries, query)
+			return sqltypes.MakeTestResult(sqltypes.MakeTestFields("ROWS_EXAMINED", "int64"), "3"), nil
+		},
+	}
+
+	for range 2 {
+		_, err := newTestQueryExecutor(tenant1, tsv, input, 0).Execute()
+		require.NoError(t, err)
+	}
+	// The rows examined by the first two queries used up the quota of tenant1.
+	_, err := newTestQueryExecutor(tenant1, tsv, input, 0).Execute()
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "query quota exceeded for principal tenant1 on table test_table: rows")
+	// Other principals are not affected, and their rows examined are not
+	// fetched since no rows quota applies to them.
+	_, err = newTestQueryExecutor(tenant2, tsv, input, 0).Execute()
+	require.NoError(t, err)
+	require.Len(t, rowsExaminedQueries, 2)
+	assert.Contains(t, rowsExaminedQueries[0], "where threads.PROCESSLIST_ID = ")
+	assert.Contains(t, rowsExaminedQueries[0], "limit 0, 1")
+}
+
+func TestQueryExecutorResultCache(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.ResultCacheSize = 10
+	vs := resultcache.NewFakeVStreamer()
+	rc := resultcache.NewCache(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "ResultCacheTest"), vs)
+	tsv.qe.resultCache = rc
+	rc.Open()
+	defer rc.Close()
+
+	input := "select /*vt+ RESULT_CACHE=true */ * from test_table limit 10001"
+	result := &sqltypes.Result{
+		Fields: getTestTableFields(),
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt32(1),
+			sqltypes.NewInt32(100),
+			sqltypes.NewInt32(200),
+		}},
+	}
+	db.AddQuery(input, result)
+
+	execute := func() *QueryExecutor {
+		qre := newTestQueryExecutor(ctx, tsv, input, 0)
+		got, err := qre.Execute()
+		require.NoError(t, err)
+		require.Equal(t, result.Rows, got.Rows)
+		return qre
+	}
+
+	// The first query starts the event stream for its table, and is only
+	// cached once the stream is up.
+	qre := execute()
+	assert.Equal(t, "mysql", qre.logStats.FmtQuerySources())
+	vs.Send(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+	qre = execute()
+	assert.Equal(t, "mysql", qre.logStats.FmtQuerySources())
+	qre = execute()
+	assert.Equal(t, "result_cache", qre.logStats.FmtQuerySources())
+	assert.Equal(t, 2, db.GetQueryCalledNum(input))
+
+	// A row event for the table invalidates the result.
+	vs.Send(&binlogdatapb.VEvent{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "test_table"},
+	})
+	qre = execute()
+	assert.Equal(t, "mysql", qre.logStats.FmtQuerySources())
+	assert.Equal(t, 3, db.GetQueryCalledNum(input))
+
+	// Queries without the directive on tables which are not flagged
+	// are never cached.
+	uncached := "select * from test_table limit 10001"
+	db.AddQuery(uncached, result)
+	for range 2 {
+		qre = newTestQueryExecutor(ctx, tsv, uncached, 0)
+		_, err := qre.Execute()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, db.GetQueryCalledNum(uncached))
+}
+
 func TestGetConnectionLogStats(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
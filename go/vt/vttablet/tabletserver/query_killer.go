@@ -0,0 +1,312 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+// maxQueryKillEvents is the number of recent kill events shown on /livequeryz.
+const maxQueryKillEvents = 100
+
+// The resources for which a query can be killed.
+const (
+	queryKillRowsExamined = "rows_examined"
+	queryKillTmpTableSize = "tmp_table_size"
+	queryKillMemory       = "memory"
+)
+
+// QueryKillEvent describes a query which was killed by the queryKiller.
+type QueryKillEvent struct {
+	Time     time.Time
+	Type     string
+	ConnID   int64
+	Query    string
+	Resource string
+	Value    int64
+	Limit    int64
+}
+
+// queryKiller periodically samples the resource usage of the statements
+// which run on the connections of the query lists and kills those which
+// exceed the configured limits.
+type queryKiller struct {
+	env             tabletenv.Env
+	queryLists      []*QueryList
+	interval        time.Duration
+	maxRowsExamined int64
+	maxTmpTableSize int64
+	maxMemory       int64
+	now             func() time.Time
+	errorLog        *logutil.ThrottledLogger
+	kills           *stats.CountersWithSingleLabel
+
+	// exec runs the sampling query. It's a field to allow tests to
+	// inject results without a MySQL server.
+	exec func(ctx context.Context, query string) (*sqltypes.Result, error)
+
+	runMu  sync.Mutex
+	isOpen bool
+	pool   *connpool.Pool
+	ticks  *timer.Timer
+
+	mu sync.Mutex
+	// events holds the most recent kill events, oldest first.
+	events []QueryKillEvent
+}
+
+func newQueryKiller(env tabletenv.Env, queryLists []*QueryList) *queryKiller {
+	config := env.Config()
+	qk := &queryKiller{
+		env:             env,
+		queryLists:      queryLists,
+		interval:        config.QueryKillInterval,
+		maxRowsExamined: config.QueryKillMaxRowsExamined,
+		maxTmpTableSize: config.QueryKillMaxTmpTableSize,
+		maxMemory:       config.QueryKillMaxMemory,
+		now:             time.Now,
+		errorLog:        logutil.NewThrottledLogger("QueryKiller", 60*time.Second),
+		kills:           env.Exporter().NewCountersWithSingleLabel("QueryKills", "Number of queries killed for exceeding a resource limit", "Resource"),
+	}
+	if !config.QueryKillEnabled() {
+		return qk
+	}
+	qk.ticks = timer.NewTimer(config.QueryKillInterval)
+	qk.pool = connpool.NewPool(env, "QueryKillerPool", tabletenv.ConnPoolConfig{
+		Size:        1,
+		IdleTimeout: config.OltpReadPool.IdleTimeout,
+	})
+	qk.exec = qk.execPool
+	return qk
+}
+
+// IsEnabled returns true if the queryKiller enforces any limit.
+func (qk *queryKiller) IsEnabled() bool {
+	return qk != nil && qk.exec != nil
+}
+
+// Open starts sampling running queries.
+func (qk *queryKiller) Open() {
+	if !qk.IsEnabled() {
+		return
+	}
+	qk.runMu.Lock()
+	defer qk.runMu.Unlock()
+	if qk.isOpen {
+		return
+	}
+	log.Info("Query Killer: opening")
+
+	if qk.pool != nil {
+		dbConfig := qk.env.Config().DB.DbaWithDB()
+		qk.pool.Open(dbConfig, dbConfig, dbConfig)
+	}
+	if qk.ticks != nil {
+		qk.ticks.Start(func() { qk.sample() })
+	}
+	qk.isOpen = true
+}
+
+// Close stops sampling running queries.
+func (qk *queryKiller) Close() {
+	if !qk.IsEnabled() {
+		return
+	}
+	qk.runMu.Lock()
+	defer qk.runMu.Unlock()
+	if !qk.isOpen {
+		return
+	}
+	if qk.ticks != nil {
+		qk.ticks.Stop()
+	}
+	if qk.pool != nil {
+		qk.pool.Close()
+	}
+	qk.isOpen = false
+	log.Info("Query Killer: closed")
+}
+
+func (qk *queryKiller) execPool(ctx context.Context, query string) (*sqltypes.Result, error) {
+	conn, err := qk.pool.Get(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+	return conn.Conn.Exec(ctx, query, -1, false)
+}
+
+// sampleQuery returns the query which reads the thread and event ids, the
+// rows examined, the memory and the temporary tablespace size of the
+// statements currently running on the given connections. Memory and
+// temporary tablespaces are only read when they are limited because their
+// tables are more expensive to aggregate.
+func (qk *queryKiller) sampleQuery(connIDs []int64) string {
+	ids := make([]string, 0, len(connIDs))
+	for _, connID := range connIDs {
+		ids = append(ids, strconv.FormatInt(connID, 10))
+	}
+
+	columns := []string{"threads.PROCESSLIST_ID", "statements.THREAD_ID", "statements.EVENT_ID", "statements.ROWS_EXAMINED"}
+	joins := []string{"join performance_schema.events_statements_current as statements on statements.THREAD_ID = threads.THREAD_ID and statements.END_EVENT_ID is null"}
+	if qk.maxMemory > 0 {
+		columns = append(columns, "coalesce(memory.bytes, 0)")
+		joins = append(joins, "left join (select THREAD_ID, cast(sum(CURRENT_NUMBER_OF_BYTES_USED) as signed) as bytes from performance_schema.memory_summary_by_thread_by_event_name group by THREAD_ID) as memory on memory.THREAD_ID = threads.THREAD_ID")
+	} else {
+		columns = append(columns, "0")
+	}
+	if qk.maxTmpTableSize > 0 {
+		columns = append(columns, "coalesce(tmp.bytes, 0)")
+		joins = append(joins, "left join (select ID, cast(sum(SIZE) as signed) as bytes from information_schema.INNODB_SESSION_TEMP_TABLESPACES group by ID) as tmp on tmp.ID = threads.PROCESSLIST_ID")
+	} else {
+		columns = append(columns, "0")
+	}
+	return fmt.Sprintf("select %s from performance_schema.threads as threads %s where threads.PROCESSLIST_ID in (%s)",
+		strings.Join(columns, ", "), strings.Join(joins, " "), strings.Join(ids, ", "))
+}
+
+// sampledStatement identifies a statement which was running when the
+// queries were sampled.
+type sampledStatement struct {
+	connID   int64
+	threadID int64
+	eventID  int64
+}
+
+// sample reads the resource usage of all running queries exactly once and
+// kills the queries which exceed a limit.
+func (qk *queryKiller) sample() {
+	defer qk.env.LogError()
+
+	var connIDs []int64
+	for _, ql := range qk.queryLists {
+		connIDs = append(connIDs, ql.ConnIDs()...)
+	}
+	if len(connIDs) == 0 {
+		return
+	}
+	slices.Sort(connIDs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), qk.interval)
+	defer cancel()
+
+	qr, err := qk.exec(ctx, qk.sampleQuery(connIDs))
+	if err != nil {
+		qk.errorLog.Errorf("failed to sample running queries: %v", err)
+		return
+	}
+	for _, row := range qr.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		var values [6]int64
+		for i := range values {
+			// NULLs and unparsable values count as 0.
+			values[i], _ = row[i].ToCastInt64()
+		}
+		stmt := sampledStatement{connID: values[0], threadID: values[1], eventID: values[2]}
+		switch {
+		case qk.maxRowsExamined > 0 && values[3] > qk.maxRowsExamined:
+			qk.kill(ctx, stmt, queryKillRowsExamined, values[3], qk.maxRowsExamined)
+		case qk.maxMemory > 0 && values[4] > qk.maxMemory:
+			qk.kill(ctx, stmt, queryKillMemory, values[4], qk.maxMemory)
+		case qk.maxTmpTableSize > 0 && values[5] > qk.maxTmpTableSize:
+			qk.kill(ctx, stmt, queryKillTmpTableSize, values[5], qk.maxTmpTableSize)
+		}
+	}
+}
+
+// isRunning returns true if the sampled statement is still running. The
+// connection may have moved on to another statement since the sample, which
+// must not be killed.
+func (qk *queryKiller) isRunning(ctx context.Context, stmt sampledStatement) bool {
+	qr, err := qk.exec(ctx, fmt.Sprintf("select 1 from performance_schema.events_statements_current where THREAD_ID = %d and EVENT_ID = %d and END_EVENT_ID is null", stmt.threadID, stmt.eventID))
+	if err != nil {
+		qk.errorLog.Errorf("failed to check the query running on connection %d: %v", stmt.connID, err)
+		return false
+	}
+	return len(qr.Rows) > 0
+}
+
+// kill kills the query running on the connection, if it's still running the
+// sampled statement, and records a kill event. The statement is checked
+// again right before the kill: a statement which started after the sample is
+// not killed, unless it started in the instant between the check and the kill.
+func (qk *queryKiller) kill(ctx context.Context, stmt sampledStatement, resource string, value, limit int64) {
+	if !qk.isRunning(ctx, stmt) {
+		return
+	}
+	reason := fmt.Sprintf("QueryKiller: %s of %d exceeded the limit of %d", resource, value, limit)
+	for _, ql := range qk.queryLists {
+		query, ok := ql.Kill(stmt.connID, reason)
+		if !ok {
+			continue
+		}
+		event := QueryKillEvent{
+			Time:     qk.now(),
+			Type:     ql.name,
+			ConnID:   stmt.connID,
+			Query:    query,
+			Resource: resource,
+			Value:    value,
+			Limit:    limit,
+		}
+		log.Warn("QueryKiller: killed query",
+			slog.String("type", event.Type),
+			slog.Int64("conn_id", event.ConnID),
+			slog.String("query", event.Query),
+			slog.String("resource", event.Resource),
+			slog.Int64("value", event.Value),
+			slog.Int64("limit", event.Limit))
+		qk.kills.Add(resource, 1)
+
+		qk.mu.Lock()
+		qk.events = append(qk.events, event)
+		if len(qk.events) > maxQueryKillEvents {
+			qk.events = slices.Delete(qk.events, 0, len(qk.events)-maxQueryKillEvents)
+		}
+		qk.mu.Unlock()
+		return
+	}
+}
+
+// Events returns the most recent kill events, newest first.
+func (qk *queryKiller) Events() []QueryKillEvent {
+	if qk == nil {
+		return nil
+	}
+	qk.mu.Lock()
+	defer qk.mu.Unlock()
+	events := slices.Clone(qk.events)
+	slices.Reverse(events)
+	return events
+}
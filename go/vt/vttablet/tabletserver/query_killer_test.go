@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+func TestQueryKillerDisabled(t *testing.T) {
+	qk := newQueryKiller(tabletenv.NewEnv(vtenv.NewTestEnv(), tabletenv.NewDefaultConfig(), t.Name()), nil)
+	assert.False(t, qk.IsEnabled())
+	qk.Open()
+	qk.Close()
+	assert.Empty(t, qk.Events())
+
+	var nilKiller *queryKiller
+	assert.False(t, nilKiller.IsEnabled())
+	assert.Empty(t, nilKiller.Events())
+}
+
+func TestQueryKiller(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.QueryKillMaxRowsExamined = 1000
+	cfg.QueryKillMaxMemory = 1 << 20
+
+	oltp := NewQueryList("oltp", sqlparser.NewTestParser())
+	olap := NewQueryList("olap", sqlparser.NewTestParser())
+	conns := []*testConn{
+		{id: 3, query: "select * from t1"},
+		{id: 1, query: "select * from t2"},
+		{id: 2, query: "select * from t3"},
+		{id: 4, query: "select * from t4"},
+	}
+	require.NoError(t, oltp.Add(NewQueryDetail(context.Background(), conns[0])))
+	require.NoError(t, oltp.Add(NewQueryDetail(context.Background(), conns[1])))
+	require.NoError(t, olap.Add(NewQueryDetail(context.Background(), conns[2])))
+	require.NoError(t, oltp.Add(NewQueryDetail(context.Background(), conns[3])))
+
+	qk := newQueryKiller(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name()), []*QueryList{oltp, olap})
+	require.True(t, qk.IsEnabled())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	qk.now = func() time.Time { return now }
+	var queries []string
+	qk.exec = func(ctx context.Context, query string) (*sqltypes.Result, error) {
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "select 1 ") {
+			// The statement of connection 4 finished after the sample.
+			if strings.Contains(query, "THREAD_ID = 44 ") {
+				return &sqltypes.Result{}, nil
+			}
+			return sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1"), nil
+		}
+		return sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("id|thread_id|event_id|rows_examined|memory|tmp", "uint64|uint64|uint64|uint64|int64|int64"),
+			"1|11|100|1001|0|0",
+			"2|22|200|10|2097152|0",
+			"3|33|300|1000|1048576|0",
+			"4|44|400|5000|0|0",
+		), nil
+	}
+
+	qk.sample()
+	require.Len(t, queries, 4)
+	assert.Contains(t, queries[0], "where threads.PROCESSLIST_ID in (1, 2, 3, 4)")
+	assert.Contains(t, queries[0], "performance_schema.memory_summary_by_thread_by_event_name")
+	assert.NotContains(t, queries[0], "INNODB_SESSION_TEMP_TABLESPACES")
+	assert.Equal(t, "select 1 from performance_schema.events_statements_current where THREAD_ID = 11 and EVENT_ID = 100 and END_EVENT_ID is null", queries[1])
+
+	assert.True(t, conns[1].IsKilled())
+	assert.True(t, conns[2].IsKilled())
+	assert.False(t, conns[0].IsKilled())
+	assert.False(t, conns[3].IsKilled())
+	assert.Equal(t, []QueryKillEvent{
+		{Time: now, Type: "olap", ConnID: 2, Query: "select * from t3", Resource: queryKillMemory, Value: 2097152, Limit: 1 << 20},
+		{Time: now, Type: "oltp", ConnID: 1, Query: "select * from t2", Resource: queryKillRowsExamined, Value: 1001, Limit: 1000},
+	}, qk.Events())
+	assert.Equal(t, map[string]int64{queryKillMemory: 1, queryKillRowsExamined: 1}, qk.kills.Counts())
+
+	// Nothing is sampled without running queries.
+	qk = newQueryKiller(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name()+"Empty"), []*QueryList{NewQueryList("oltp", sqlparser.NewTestParser())})
+	qk.exec = func(ctx context.Context, query string) (*sqltypes.Result, error) {
+		t.Fatalf("unexpected query: %s", query)
+		return nil, nil
+	}
+	qk.sample()
+}
+
+func TestQueryKillerMaxEvents(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.QueryKillMaxTmpTableSize = 1
+	ql := NewQueryList("oltp", sqlparser.NewTestParser())
+	qk := newQueryKiller(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name()), []*QueryList{ql})
+	qk.exec = func(ctx context.Context, query string) (*sqltypes.Result, error) {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1"), nil
+	}
+
+	ctx := context.Background()
+	for i := range maxQueryKillEvents + 10 {
+		qd := NewQueryDetail(ctx, &testConn{id: int64(i)})
+		require.NoError(t, ql.Add(qd))
+		qk.kill(ctx, sampledStatement{connID: int64(i)}, queryKillTmpTableSize, 2, 1)
+		ql.Remove(qd)
+	}
+	events := qk.Events()
+	require.Len(t, events, maxQueryKillEvents)
+	assert.EqualValues(t, maxQueryKillEvents+9, events[0].ConnID)
+	assert.EqualValues(t, 10, events[maxQueryKillEvents-1].ConnID)
+
+	// Connections which are no longer running a query are not recorded.
+	qk.kill(ctx, sampledStatement{connID: 1000}, queryKillTmpTableSize, 2, 1)
+	assert.Len(t, qk.Events(), maxQueryKillEvents)
+}
+
+func TestLiveQueryzHandlerKills(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.QueryKillMaxRowsExamined = 1
+	ql := NewQueryList("oltp", sqlparser.NewTestParser())
+	require.NoError(t, ql.Add(NewQueryDetail(context.Background(), &testConn{id: 1, query: "select * from killed_table"})))
+	qk := newQueryKiller(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name()), []*QueryList{ql})
+	qk.exec = func(ctx context.Context, query string) (*sqltypes.Result, error) {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1"), nil
+	}
+	qk.kill(context.Background(), sampledStatement{connID: 1}, queryKillRowsExamined, 2, 1)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/livequeryz/", nil)
+	livequeryzHandler([]*QueryList{ql}, qk, resp, req)
+	assert.Contains(t, resp.Body.String(), "Killed Queries")
+	assert.Contains(t, resp.Body.String(), "select * from killed_table")
+}
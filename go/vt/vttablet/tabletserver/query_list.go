@@ -137,19 +137,42 @@ func (ql *QueryList) Remove(qd *QueryDetail) {
 
 // Terminate updates the query status and kills the connection
 func (ql *QueryList) Terminate(connID int64) bool {
+	_, ok := ql.Kill(connID, "QueryList.Terminate()")
+	return ok
+}
+
+// Kill kills the connection for the given reason and returns the query which
+// was running on it. The query is redacted if the debug UI redacts queries.
+func (ql *QueryList) Kill(connID int64, reason string) (string, bool) {
 	ql.mu.Lock()
 	defer ql.mu.Unlock()
 	qds, exists := ql.queryDetails[connID]
 	if !exists {
-		return false
+		return "", false
 	}
+	var query string
 	for _, qd := range qds {
-		err := qd.conn.Kill("QueryList.Terminate()", time.Since(qd.start))
+		query = qd.conn.Current()
+		err := qd.conn.Kill(reason, time.Since(qd.start))
 		if err != nil {
 			log.Warn(fmt.Sprintf("Error terminating query on connection id: %d, error: %v", qd.conn.ID(), err))
 		}
 	}
-	return true
+	if ql.redactUIQuery {
+		query, _ = ql.parser.RedactSQLQuery(query)
+	}
+	return query, true
+}
+
+// ConnIDs returns the ids of all connections which are running a query.
+func (ql *QueryList) ConnIDs() []int64 {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+	connIDs := make([]int64, 0, len(ql.queryDetails))
+	for connID := range ql.queryDetails {
+		connIDs = append(connIDs, connID)
+	}
+	return connIDs
 }
 
 // TerminateAll terminates all queries and kills the MySQL connections
@@ -0,0 +1,257 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota enforces per principal and table resource quotas, so that
+// a single tenant of a keyspace cannot starve the others.
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	resourceRows      = "rows"
+	resourceQueryTime = "query_time"
+	resourceStreams   = "streams"
+)
+
+// logRejected logs the rejected queries, which can be rejected at a high rate
+// once a quota is used up.
+var logRejected = logutil.NewThrottledLogger("QueryQuota", 1*time.Minute)
+
+// Quotas tracks the resources which every principal used on every table
+// during the current minute, and rejects queries once a quota is used up.
+// Queries which are running when a quota is used up are not interrupted.
+type Quotas struct {
+	quotas []tabletenv.QueryQuota
+	dryRun bool
+	now    func() time.Time
+
+	mu    sync.Mutex
+	usage map[usageKey]*usage
+	// swept is the start of the minute in which idle usages were last evicted.
+	swept time.Time
+
+	rejections, rejectionsDryRun *stats.CountersWithMultiLabels
+}
+
+type usageKey struct {
+	principal, table string
+}
+
+type usage struct {
+	quota *tabletenv.QueryQuota
+	// window is the start of the minute which rows and queryTime count.
+	window    time.Time
+	rows      int64
+	queryTime time.Duration
+	streams   int
+}
+
+// New creates the Quotas configured by --query-quota-config-file.
+// If there are none, all methods are no-ops.
+func New(env tabletenv.Env) *Quotas {
+	config := env.Config()
+	if config.QueryQuotas == nil || len(config.QueryQuotas.Quotas) == 0 {
+		return &Quotas{}
+	}
+	labels := []string{"Principal", "Table", "Resource"}
+	return &Quotas{
+		quotas:           config.QueryQuotas.Quotas,
+		dryRun:           config.QueryQuotasDryRun,
+		now:              time.Now,
+		usage:            make(map[usageKey]*usage),
+		rejections:       env.Exporter().NewCountersWithMultiLabels("QueryQuotaRejections", "Queries rejected because a query quota was used up", labels),
+		rejectionsDryRun: env.Exporter().NewCountersWithMultiLabels("QueryQuotaRejectionsDryRun", "Queries which would have been rejected because a query quota was used up", labels),
+	}
+}
+
+// IsEnabled returns true if any quotas are configured.
+func (q *Quotas) IsEnabled() bool {
+	return q != nil && len(q.quotas) > 0
+}
+
+// Check returns a RESOURCE_EXHAUSTED error if the principal used up its
+// rows or query time quota on any of the tables during the current minute.
+func (q *Quotas) Check(principal string, tables []string) error {
+	if !q.IsEnabled() {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	for _, table := range tables {
+		u := q.usageLocked(principal, table, now)
+		if u == nil {
+			continue
+		}
+		if limit := u.quota.RowsPerMinute; limit > 0 && u.rows >= limit {
+			if err := q.rejectLocked(principal, table, resourceRows); err != nil {
+				return err
+			}
+		}
+		if limit := u.quota.QueryTimePerMinute; limit > 0 && u.queryTime >= limit {
+			if err := q.rejectLocked(principal, table, resourceQueryTime); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Record charges the rows and the query time of a finished query to the
+// quotas of the principal on all the tables of the query.
+func (q *Quotas) Record(principal string, tables []string, rows int64, queryTime time.Duration) {
+	if !q.IsEnabled() {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	for _, table := range tables {
+		if u := q.usageLocked(principal, table, now); u != nil {
+			u.rows += rows
+			u.queryTime += queryTime
+		}
+	}
+}
+
+// LimitsRows returns true if a rows quota applies to the principal on any of
+// the tables.
+func (q *Quotas) LimitsRows(principal string, tables []string) bool {
+	if !q.IsEnabled() {
+		return false
+	}
+	for _, table := range tables {
+		if quota := q.match(principal, table); table != "" && quota != nil && quota.RowsPerMinute > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// StartStream checks the quotas like Check and takes a slot of the
+// concurrent streams quota on all the tables. If there's no error,
+// the returned function must be called once the stream ended.
+func (q *Quotas) StartStream(principal string, tables []string) (func(), error) {
+	if !q.IsEnabled() {
+		return func() {}, nil
+	}
+	if err := q.Check(principal, tables); err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var taken []*usage
+	for _, table := range tables {
+		u := q.usageLocked(principal, table, now)
+		if u == nil {
+			continue
+		}
+		if limit := u.quota.MaxConcurrentStreams; limit > 0 && u.streams >= limit {
+			if err := q.rejectLocked(principal, table, resourceStreams); err != nil {
+				for _, t := range taken {
+					t.streams--
+				}
+				return nil, err
+			}
+		}
+		u.streams++
+		taken = append(taken, u)
+	}
+	return func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		for _, u := range taken {
+			u.streams--
+		}
+	}, nil
+}
+
+// usageLocked returns the usage of the principal on the table, or nil if
+// no quota applies to them. It resets the counters at every minute.
+func (q *Quotas) usageLocked(principal, table string, now time.Time) *usage {
+	if table == "" {
+		return nil
+	}
+	q.sweepLocked(now)
+	key := usageKey{principal: principal, table: table}
+	u, ok := q.usage[key]
+	if !ok {
+		quota := q.match(principal, table)
+		if quota == nil {
+			return nil
+		}
+		u = &usage{quota: quota}
+		q.usage[key] = u
+	}
+	if window := now.Truncate(time.Minute); !window.Equal(u.window) {
+		u.window = window
+		u.rows = 0
+		u.queryTime = 0
+	}
+	return u
+}
+
+// sweepLocked evicts the usages which were not used during the current
+// minute and have no running streams, once per minute, so that the usages of
+// principals which went away don't pile up.
+func (q *Quotas) sweepLocked(now time.Time) {
+	window := now.Truncate(time.Minute)
+	if window.Equal(q.swept) {
+		return
+	}
+	q.swept = window
+	for key, u := range q.usage {
+		if u.streams == 0 && u.window.Before(window) {
+			delete(q.usage, key)
+		}
+	}
+}
+
+// match returns the first quota which applies to the principal and table.
+func (q *Quotas) match(principal, table string) *tabletenv.QueryQuota {
+	for i := range q.quotas {
+		quota := &q.quotas[i]
+		if (quota.Principal == "*" || quota.Principal == principal) && (quota.Table == "*" || quota.Table == table) {
+			return quota
+		}
+	}
+	return nil
+}
+
+func (q *Quotas) rejectLocked(principal, table, resource string) error {
+	labels := []string{principal, table, resource}
+	if q.dryRun {
+		q.rejectionsDryRun.Add(labels, 1)
+		return nil
+	}
+	logRejected.Infof("rejecting query of principal %s on table %s, %s quota used up", principal, table, resource)
+	q.rejections.Add(labels, 1)
+	return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "query quota exceeded for principal %s on table %s: %s", principal, table, resource)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func newTestQuotas(t *testing.T, dryRun bool, quotas ...tabletenv.QueryQuota) *Quotas {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.QueryQuotas = &tabletenv.QueryQuotasFlag{Quotas: quotas}
+	cfg.QueryQuotasDryRun = dryRun
+	q := New(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name()))
+	require.True(t, q.IsEnabled())
+	return q
+}
+
+func TestQuotasDisabled(t *testing.T) {
+	q := New(tabletenv.NewEnv(vtenv.NewTestEnv(), tabletenv.NewDefaultConfig(), t.Name()))
+	assert.False(t, q.IsEnabled())
+	assert.NoError(t, q.Check("user", []string{"t1"}))
+	q.Record("user", []string{"t1"}, 1000, time.Hour)
+	release, err := q.StartStream("user", []string{"t1"})
+	require.NoError(t, err)
+	release()
+}
+
+func TestQuotasRowsAndQueryTime(t *testing.T) {
+	q := newTestQuotas(t, false,
+		tabletenv.QueryQuota{Principal: "tenant1", Table: "t1", RowsPerMinute: 10},
+		tabletenv.QueryQuota{Principal: "*", Table: "*", QueryTimePerMinute: time.Second},
+	)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	q.Record("tenant1", []string{"t1"}, 9, time.Hour)
+	assert.NoError(t, q.Check("tenant1", []string{"t1"}))
+	q.Record("tenant1", []string{"t1"}, 1, 0)
+	err := q.Check("tenant1", []string{"t2", "t1"})
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "query quota exceeded for principal tenant1 on table t1: rows")
+
+	// The first matching quota applies, so t1 has no query time limit for
+	// tenant1, but t2 does.
+	q.Record("tenant1", []string{"t2"}, 0, time.Second)
+	assert.ErrorContains(t, q.Check("tenant1", []string{"t2"}), "on table t2: query_time")
+	// Every principal has its own budget.
+	assert.NoError(t, q.Check("tenant2", []string{"t1", "t2"}))
+	assert.Equal(t, map[string]int64{"tenant1.t1.rows": 1, "tenant1.t2.query_time": 1}, q.rejections.Counts())
+
+	// The budgets are reset every minute.
+	now = now.Add(time.Minute)
+	assert.NoError(t, q.Check("tenant1", []string{"t1", "t2"}))
+}
+
+func TestQuotasLimitsRows(t *testing.T) {
+	q := newTestQuotas(t, false,
+		tabletenv.QueryQuota{Principal: "tenant1", Table: "t1", RowsPerMinute: 10},
+		tabletenv.QueryQuota{Principal: "*", Table: "*", MaxConcurrentStreams: 1},
+	)
+	assert.True(t, q.LimitsRows("tenant1", []string{"t2", "t1"}))
+	assert.False(t, q.LimitsRows("tenant1", []string{"t2"}))
+	assert.False(t, q.LimitsRows("tenant2", []string{"t1"}))
+}
+
+func TestQuotasEviction(t *testing.T) {
+	q := newTestQuotas(t, false, tabletenv.QueryQuota{Principal: "*", Table: "*", RowsPerMinute: 10, MaxConcurrentStreams: 1})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	q.Record("tenant1", []string{"t1"}, 1, 0)
+	release, err := q.StartStream("tenant2", []string{"t1"})
+	require.NoError(t, err)
+	assert.Len(t, q.usage, 2)
+
+	// Usages which are idle for a minute are evicted, unless a stream is running.
+	now = now.Add(time.Minute)
+	q.Record("tenant3", []string{"t1"}, 1, 0)
+	assert.Len(t, q.usage, 2)
+	assert.Contains(t, q.usage, usageKey{principal: "tenant2", table: "t1"})
+
+	release()
+	now = now.Add(time.Minute)
+	q.Record("tenant3", []string{"t1"}, 1, 0)
+	assert.Len(t, q.usage, 1)
+}
+
+func TestQuotasStreams(t *testing.T) {
+	q := newTestQuotas(t, false,
+		tabletenv.QueryQuota{Principal: "*", Table: "t1", MaxConcurrentStreams: 1},
+		tabletenv.QueryQuota{Principal: "*", Table: "t2", MaxConcurrentStreams: 2},
+	)
+
+	release1, err := q.StartStream("user", []string{"t2", "t1"})
+	require.NoError(t, err)
+	_, err = q.StartStream("user", []string{"t2", "t1"})
+	assert.ErrorContains(t, err, "on table t1: streams")
+	// The failed stream did not keep its slot on t2.
+	release2, err := q.StartStream("user", []string{"t2"})
+	require.NoError(t, err)
+	_, err = q.StartStream("user", []string{"t2"})
+	assert.ErrorContains(t, err, "on table t2: streams")
+
+	release1()
+	release2()
+	release3, err := q.StartStream("user", []string{"t1", "t2"})
+	require.NoError(t, err)
+	release3()
+}
+
+func TestQuotasDryRun(t *testing.T) {
+	q := newTestQuotas(t, true, tabletenv.QueryQuota{Principal: "*", Table: "*", RowsPerMinute: 1, MaxConcurrentStreams: 1})
+
+	q.Record("user", []string{"t1"}, 1, 0)
+	assert.NoError(t, q.Check("user", []string{"t1"}))
+	release1, err := q.StartStream("user", []string{"t1"})
+	require.NoError(t, err)
+	defer release1()
+	release2, err := q.StartStream("user", []string{"t1"})
+	require.NoError(t, err)
+	defer release2()
+
+	assert.Empty(t, q.rejections.Counts())
+	assert.Equal(t, map[string]int64{"user.t1.rows": 3, "user.t1.streams": 1}, q.rejectionsDryRun.Counts())
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+var _ VStreamer = (*FakeVStreamer)(nil)
+
+// FakeVStreamer is a VStreamer for tests, which streams the events passed
+// to Send.
+type FakeVStreamer struct {
+	events chan []*binlogdatapb.VEvent
+	sent   chan bool
+
+	mu     sync.Mutex
+	filter *binlogdatapb.Filter
+}
+
+// NewFakeVStreamer returns a new FakeVStreamer.
+func NewFakeVStreamer() *FakeVStreamer {
+	return &FakeVStreamer{
+		events: make(chan []*binlogdatapb.VEvent),
+		sent:   make(chan bool),
+	}
+}
+
+// Stream is part of the VStreamer interface.
+func (f *FakeVStreamer) Stream(ctx context.Context, startPos string, tablePKs []*binlogdatapb.TableLastPK,
+	filter *binlogdatapb.Filter, throttlerApp throttlerapp.Name, send func([]*binlogdatapb.VEvent) error, options *binlogdatapb.VStreamOptions,
+) error {
+	f.mu.Lock()
+	f.filter = filter
+	f.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case events := <-f.events:
+			// The events are sent again to the next stream if this one
+			// was canceled in the meantime.
+			if ctx.Err() != nil {
+				f.sent <- false
+				return nil
+			}
+			err := send(events)
+			f.sent <- true
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Send blocks until the events were processed by a running stream.
+func (f *FakeVStreamer) Send(events ...*binlogdatapb.VEvent) {
+	for {
+		f.events <- events
+		if <-f.sent {
+			return
+		}
+	}
+}
+
+// Filter returns the filter of the last stream.
+func (f *FakeVStreamer) Filter() *binlogdatapb.Filter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filter
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resultcache implements a cache for the results of read-only
+// queries. Entries are invalidated by the row events which the tablet's own
+// vstreamer reports for the tables that a query reads from.
+package resultcache
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// VStreamer defines the functions of VStreamer
+// that the result cache needs.
+type VStreamer interface {
+	Stream(ctx context.Context, startPos string, tablePKs []*binlogdatapb.TableLastPK, filter *binlogdatapb.Filter,
+		throttlerApp throttlerapp.Name, send func([]*binlogdatapb.VEvent) error, options *binlogdatapb.VStreamOptions) error
+}
+
+// Cache caches query results until a row event or a DDL is seen for one of
+// the tables the query reads from. The event stream only covers the tables
+// which cacheable queries read from, and is restarted when a query reads
+// from a table it doesn't cover yet.
+//
+// Invalidation is lazy: every table has a generation which is incremented for
+// each row event on it, and every entry remembers the generations of its
+// tables at the time the query was sent to MySQL. An entry whose generations
+// are outdated is treated as a miss. DDLs and restarts of the event stream
+// increment a global epoch instead, which outdates all entries at once.
+//
+// Because the event stream trails the commits in MySQL, a cached result can
+// be stale for as long as it takes the vstreamer to report a change.
+type Cache struct {
+	env     tabletenv.Env
+	vs      VStreamer
+	maxRows int
+
+	entries *cache.LRUCache[*entry]
+
+	mu sync.Mutex
+	// watched are the tables which cacheable queries read from.
+	watched map[string]bool
+	// watchedChanged is signaled when a table is added to watched.
+	watchedChanged chan struct{}
+	// streaming are the tables covered by the running event stream. Nothing
+	// is cached or served for other tables, because changes could be missed.
+	streaming map[string]bool
+	// restartStream restarts the event stream, to cover all watched tables.
+	restartStream context.CancelFunc
+	epoch         int64
+	generations   map[string]int64
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	hits          *stats.Counter
+	misses        *stats.Counter
+	invalidations *stats.Counter
+}
+
+type entry struct {
+	token  Token
+	result *sqltypes.Result
+}
+
+// Token records the state of the cache before a query is executed.
+// It must be passed back to Set along with the query's result.
+type Token struct {
+	valid       bool
+	epoch       int64
+	tables      []string
+	generations []int64
+}
+
+// NewCache creates a new Cache. The cache is disabled if
+// --queryserver-result-cache-size is 0.
+func NewCache(env tabletenv.Env, vs VStreamer) *Cache {
+	config := env.Config()
+	if config.ResultCacheSize <= 0 {
+		return &Cache{env: env}
+	}
+	rc := &Cache{
+		env:            env,
+		vs:             vs,
+		maxRows:        config.ResultCacheMaxRows,
+		entries:        cache.NewLRUCache[*entry](int64(config.ResultCacheSize)),
+		watched:        make(map[string]bool),
+		watchedChanged: make(chan struct{}, 1),
+		generations:    make(map[string]int64),
+		hits:           env.Exporter().NewCounter("ResultCacheHits", "Number of queries served from the result cache"),
+		misses:         env.Exporter().NewCounter("ResultCacheMisses", "Number of cacheable queries not found in the result cache"),
+		invalidations:  env.Exporter().NewCounter("ResultCacheInvalidations", "Number of row events and DDLs which invalidated result cache entries"),
+	}
+	env.Exporter().NewGaugeFunc("ResultCacheLength", "Number of entries in the result cache", func() int64 {
+		return int64(rc.entries.Len())
+	})
+	return rc
+}
+
+// IsEnabled returns true if the cache was configured with a non-zero size.
+func (rc *Cache) IsEnabled() bool {
+	return rc != nil && rc.entries != nil
+}
+
+// Open starts watching the event stream.
+func (rc *Cache) Open() {
+	if !rc.IsEnabled() {
+		return
+	}
+	rc.runMu.Lock()
+	defer rc.runMu.Unlock()
+	if rc.cancel != nil {
+		return
+	}
+	log.Info("Result Cache: opening")
+
+	ctx, cancel := context.WithCancel(tabletenv.LocalContext())
+	rc.cancel = cancel
+	rc.wg.Add(1)
+	go rc.process(ctx)
+}
+
+// Close stops watching the event stream and drops all entries.
+func (rc *Cache) Close() {
+	if !rc.IsEnabled() {
+		return
+	}
+	rc.runMu.Lock()
+	defer rc.runMu.Unlock()
+	if rc.cancel == nil {
+		return
+	}
+	rc.cancel()
+	rc.cancel = nil
+	rc.wg.Wait()
+
+	// Shrinking the cache to zero drops all entries.
+	rc.entries.SetCapacity(0)
+	rc.entries.SetCapacity(int64(rc.env.Config().ResultCacheSize))
+	log.Info("Result Cache: closed")
+}
+
+// Get returns the cached result for the key. If there's none, it returns
+// a Token which must be captured before the query is sent to MySQL.
+func (rc *Cache) Get(key string, tables []string) (*sqltypes.Result, Token, bool) {
+	rc.mu.Lock()
+	rc.watchLocked(tables)
+	token := rc.tokenLocked(tables)
+	rc.mu.Unlock()
+	if !token.valid {
+		return nil, token, false
+	}
+	if e, ok := rc.entries.Get(key); ok && e.token.equal(token) {
+		rc.hits.Add(1)
+		return e.result, token, true
+	}
+	rc.misses.Add(1)
+	return nil, token, false
+}
+
+// Set caches the result of the query for the key. The result is dropped if
+// any of the tables changed since the token was obtained from Get, or if
+// the result is larger than --queryserver-result-cache-max-rows.
+func (rc *Cache) Set(key string, token Token, result *sqltypes.Result) {
+	if !token.valid || result == nil || len(result.Rows) > rc.maxRows {
+		return
+	}
+	rc.mu.Lock()
+	current := rc.tokenLocked(token.tables)
+	rc.mu.Unlock()
+	if !current.equal(token) {
+		return
+	}
+	rc.entries.Set(key, &entry{token: token, result: result})
+}
+
+func (rc *Cache) tokenLocked(tables []string) Token {
+	token := Token{
+		valid:       rc.streaming != nil,
+		epoch:       rc.epoch,
+		tables:      tables,
+		generations: make([]int64, len(tables)),
+	}
+	for i, table := range tables {
+		token.valid = token.valid && rc.streaming[table]
+		token.generations[i] = rc.generations[table]
+	}
+	return token
+}
+
+// watchLocked adds the tables to the ones covered by the event stream, and
+// restarts the stream if any of them wasn't covered yet.
+func (rc *Cache) watchLocked(tables []string) {
+	added := false
+	for _, table := range tables {
+		if !rc.watched[table] {
+			rc.watched[table] = true
+			added = true
+		}
+	}
+	if !added {
+		return
+	}
+	if rc.restartStream != nil {
+		rc.restartStream()
+	}
+	select {
+	case rc.watchedChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (t Token) equal(other Token) bool {
+	if !t.valid || !other.valid || t.epoch != other.epoch || len(t.generations) != len(other.generations) {
+		return false
+	}
+	for i := range t.generations {
+		if t.tables[i] != other.tables[i] || t.generations[i] != other.generations[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// setStreaming records the tables covered by the event stream, or nil if
+// the stream is down.
+func (rc *Cache) setStreaming(tables []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.streaming = nil
+	if tables != nil {
+		rc.streaming = make(map[string]bool, len(tables))
+		for _, table := range tables {
+			rc.streaming[table] = true
+		}
+	}
+	// Changes may have been missed while the stream was down.
+	rc.epoch++
+}
+
+func (rc *Cache) invalidateTable(table string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.generations[table]++
+	rc.invalidations.Add(1)
+}
+
+func (rc *Cache) invalidateAll() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.epoch++
+	rc.invalidations.Add(1)
+}
+
+func (rc *Cache) process(ctx context.Context) {
+	defer rc.env.LogError()
+	defer rc.wg.Done()
+
+	options := &binlogdatapb.VStreamOptions{
+		// Heartbeats tell us that the stream is up even if there are no writes.
+		EventTypes: []binlogdatapb.VEventType{
+			binlogdatapb.VEventType_ROW,
+			binlogdatapb.VEventType_DDL,
+			binlogdatapb.VEventType_HEARTBEAT,
+		},
+	}
+	for {
+		rc.mu.Lock()
+		tables := slices.Sorted(maps.Keys(rc.watched))
+		streamCtx, cancel := context.WithCancel(ctx)
+		rc.restartStream = cancel
+		rc.mu.Unlock()
+
+		if len(tables) == 0 {
+			// There is nothing to stream until a cacheable query is executed.
+			select {
+			case <-ctx.Done():
+				cancel()
+				return
+			case <-rc.watchedChanged:
+				cancel()
+				continue
+			}
+		}
+
+		started := false
+		err := rc.vs.Stream(streamCtx, "current", nil, tablesFilter(tables), throttlerapp.ResultCacheName, func(events []*binlogdatapb.VEvent) error {
+			if !started {
+				started = true
+				rc.setStreaming(tables)
+			}
+			for _, event := range events {
+				switch event.Type {
+				case binlogdatapb.VEventType_ROW:
+					rc.invalidateTable(event.RowEvent.GetTableName())
+				case binlogdatapb.VEventType_DDL:
+					rc.invalidateAll()
+				}
+			}
+			return nil
+		}, options)
+		cancel()
+		rc.setStreaming(nil)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		rc.mu.Lock()
+		restarted := len(rc.watched) != len(tables)
+		rc.mu.Unlock()
+		if restarted {
+			continue
+		}
+		log.Warn(fmt.Sprintf("Result Cache's vstream ended (error: %v), retrying in 5 seconds...", err))
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// tablesFilter returns a filter which matches the given tables only.
+func tablesFilter(tables []string) *binlogdatapb.Filter {
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = regexp.QuoteMeta(table)
+	}
+	return &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match: "/^(" + strings.Join(quoted, "|") + ")$",
+		}},
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func rowEvent(table string) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: table},
+	}
+}
+
+func newTestCache(t *testing.T, size int) (*Cache, *FakeVStreamer) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.ResultCacheSize = size
+	cfg.ResultCacheMaxRows = 2
+	vs := NewFakeVStreamer()
+	rc := NewCache(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "ResultCacheTest"), vs)
+	return rc, vs
+}
+
+func TestResultCacheDisabled(t *testing.T) {
+	rc, _ := newTestCache(t, 0)
+	assert.False(t, rc.IsEnabled())
+	rc.Open()
+	rc.Close()
+
+	var nilCache *Cache
+	assert.False(t, nilCache.IsEnabled())
+}
+
+func TestResultCache(t *testing.T) {
+	rc, vs := newTestCache(t, 10)
+	require.True(t, rc.IsEnabled())
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")
+	tables := []string{"t1", "t2"}
+
+	// Nothing is cached until the event stream is up.
+	_, token, ok := rc.Get("q1", tables)
+	assert.False(t, ok)
+	rc.Set("q1", token, result)
+	_, _, ok = rc.Get("q1", tables)
+	assert.False(t, ok)
+
+	// The stream only covers the tables of cacheable queries.
+	rc.Open()
+	defer rc.Close()
+	vs.Send(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+	assert.Equal(t, "/^(t1|t2)$", vs.Filter().Rules[0].Match)
+
+	_, token, ok = rc.Get("q1", tables)
+	assert.False(t, ok)
+	rc.Set("q1", token, result)
+	got, _, ok := rc.Get("q1", tables)
+	assert.True(t, ok)
+	assert.Equal(t, result, got)
+
+	// Row events on other tables don't invalidate the entry.
+	vs.Send(rowEvent("t3"))
+	_, _, ok = rc.Get("q1", tables)
+	assert.True(t, ok)
+
+	// Row events on any of the query's tables do.
+	vs.Send(rowEvent("t2"))
+	_, token, ok = rc.Get("q1", tables)
+	assert.False(t, ok)
+
+	// A row event between Get and Set prevents caching a result which
+	// might have been read before the change.
+	vs.Send(rowEvent("t1"))
+	rc.Set("q1", token, result)
+	_, token, ok = rc.Get("q1", tables)
+	assert.False(t, ok)
+	rc.Set("q1", token, result)
+	_, _, ok = rc.Get("q1", tables)
+	assert.True(t, ok)
+
+	// DDLs invalidate everything.
+	_, token, _ = rc.Get("q2", []string{"t1"})
+	rc.Set("q2", token, result)
+	vs.Send(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_DDL})
+	_, _, ok = rc.Get("q1", tables)
+	assert.False(t, ok)
+	_, _, ok = rc.Get("q2", []string{"t1"})
+	assert.False(t, ok)
+
+	// Results with too many rows are not cached.
+	big := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2", "3")
+	_, token, _ = rc.Get("q3", tables)
+	rc.Set("q3", token, big)
+	_, _, ok = rc.Get("q3", tables)
+	assert.False(t, ok)
+
+	// A query on a table which isn't covered yet restarts the stream.
+	_, token, _ = rc.Get("q4", []string{"t3"})
+	assert.False(t, token.valid)
+	vs.Send(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+	assert.Equal(t, "/^(t1|t2|t3)$", vs.Filter().Rules[0].Match)
+	_, token, _ = rc.Get("q4", []string{"t3"})
+	assert.True(t, token.valid)
+	rc.Set("q4", token, result)
+	_, _, ok = rc.Get("q4", []string{"t3"})
+	assert.True(t, ok)
+
+	assert.EqualValues(t, 4, rc.hits.Get())
+	assert.EqualValues(t, 4, rc.invalidations.Get())
+}
+
+func TestResultCacheClose(t *testing.T) {
+	rc, vs := newTestCache(t, 10)
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")
+
+	rc.Get("q1", []string{"t1"})
+	rc.Open()
+	vs.Send(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+	_, token, _ := rc.Get("q1", []string{"t1"})
+	rc.Set("q1", token, result)
+	assert.Equal(t, 1, rc.entries.Len())
+
+	rc.Close()
+	assert.Equal(t, 0, rc.entries.Len())
+	_, token, ok := rc.Get("q1", []string{"t1"})
+	assert.False(t, ok)
+	assert.False(t, token.valid)
+
+	// Entries cached before a restart of the stream are not served.
+	rc.Open()
+	defer rc.Close()
+	vs.Send(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_HEARTBEAT})
+	_, _, ok = rc.Get("q1", []string{"t1"})
+	assert.False(t, ok)
+}
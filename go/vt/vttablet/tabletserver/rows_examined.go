@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+// rowsExaminedPoolSize is the number of connections used to read the rows
+// examined by the queries which are subject to a rows quota.
+const rowsExaminedPoolSize = 4
+
+// rowsExaminedReader reads the rows which MySQL examined for the last
+// statements of a connection from performance_schema. It uses its own
+// connections, so that the session of the connection, e.g. ROW_COUNT()
+// and FOUND_ROWS(), is left untouched. It needs the
+// events_statements_history consumer, which is enabled by default.
+type rowsExaminedReader struct {
+	env      tabletenv.Env
+	errorLog *logutil.ThrottledLogger
+
+	// exec runs the query which reads the rows examined. It's a field to
+	// allow tests to inject results without a MySQL server.
+	exec func(ctx context.Context, query string) (*sqltypes.Result, error)
+
+	mu     sync.Mutex
+	isOpen bool
+	pool   *connpool.Pool
+}
+
+func newRowsExaminedReader(env tabletenv.Env) *rowsExaminedReader {
+	rr := &rowsExaminedReader{
+		env:      env,
+		errorLog: logutil.NewThrottledLogger("RowsExamined", 60*time.Second),
+	}
+	config := env.Config()
+	if !config.QueryQuotas.LimitsRows() {
+		return rr
+	}
+	rr.pool = connpool.NewPool(env, "RowsExaminedPool", tabletenv.ConnPoolConfig{
+		Size:        rowsExaminedPoolSize,
+		IdleTimeout: config.OltpReadPool.IdleTimeout,
+	})
+	rr.exec = rr.execPool
+	return rr
+}
+
+// IsEnabled returns true if any quota limits the rows examined.
+func (rr *rowsExaminedReader) IsEnabled() bool {
+	return rr != nil && rr.exec != nil
+}
+
+// Open opens the connection pool.
+func (rr *rowsExaminedReader) Open() {
+	if !rr.IsEnabled() {
+		return
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.isOpen {
+		return
+	}
+	log.Info("Rows Examined Reader: opening")
+	if rr.pool != nil {
+		dbConfig := rr.env.Config().DB.DbaWithDB()
+		rr.pool.Open(dbConfig, dbConfig, dbConfig)
+	}
+	rr.isOpen = true
+}
+
+// Close closes the connection pool.
+func (rr *rowsExaminedReader) Close() {
+	if !rr.IsEnabled() {
+		return
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if !rr.isOpen {
+		return
+	}
+	if rr.pool != nil {
+		rr.pool.Close()
+	}
+	rr.isOpen = false
+	log.Info("Rows Examined Reader: closed")
+}
+
+func (rr *rowsExaminedReader) execPool(ctx context.Context, query string) (*sqltypes.Result, error) {
+	conn, err := rr.pool.Get(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+	return conn.Conn.Exec(ctx, query, 1, false)
+}
+
+// Read returns the rows examined by a statement which ran on the connection.
+// skip is the number of statements which ran on the connection after it.
+// It returns false if performance_schema does not report them.
+func (rr *rowsExaminedReader) Read(ctx context.Context, connID int64, skip int) (int64, bool) {
+	if !rr.IsEnabled() {
+		return 0, false
+	}
+	query := fmt.Sprintf("select statements.ROWS_EXAMINED from performance_schema.threads as threads "+
+		"join performance_schema.events_statements_history as statements on statements.THREAD_ID = threads.THREAD_ID "+
+		"where threads.PROCESSLIST_ID = %d order by statements.EVENT_ID desc limit %d, 1", connID, skip)
+	result, err := rr.exec(ctx, query)
+	if err != nil {
+		rr.errorLog.Errorf("failed to read the rows examined on connection %d: %v", connID, err)
+		return 0, false
+	}
+	if len(result.Rows) == 0 {
+		rr.errorLog.Warningf("performance_schema.events_statements_history has no statement for connection %d, the events_statements_history consumer may be disabled", connID)
+		return 0, false
+	}
+	rows, err := result.Rows[0][0].ToCastInt64()
+	if err != nil {
+		rr.errorLog.Errorf("invalid rows examined on connection %d: %v", connID, err)
+		return 0, false
+	}
+	return rows, true
+}
@@ -54,6 +54,9 @@ func LoadTable(conn *connpool.PooledConn, databaseName, tableName, tableType str
 		}
 		ta.Type = Message
 	}
+	if strings.Contains(comment, "vitess_result_cache") {
+		ta.ResultCache = true
+	}
 	return ta, nil
 }
 
@@ -75,6 +75,16 @@ func TestLoadView(t *testing.T) {
 	assert.Equal(t, want, table)
 }
 
+func TestLoadTableResultCache(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	mockLoadTableQueries(db)
+	table, err := newTestLoadTable("USER_TABLE", "lookup data, vitess_result_cache", db)
+	require.NoError(t, err)
+	assert.True(t, table.ResultCache)
+	assert.Equal(t, NoType, table.Type)
+}
+
 // TestLoadTableSequence tests that sequence tables are loaded correctly.
 // It also confirms that a reset of a sequence table works.
 func TestLoadTableSequence(t *testing.T) {
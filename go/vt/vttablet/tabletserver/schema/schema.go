@@ -57,6 +57,10 @@ type Table struct {
 	// MessageInfo contains info for message tables.
 	MessageInfo *MessageInfo
 
+	// ResultCache is true if the results of SELECTs on this table may be
+	// cached by the tablet result cache.
+	ResultCache bool
+
 	CreateTime    int64
 	FileSize      uint64
 	AllocatedSize uint64
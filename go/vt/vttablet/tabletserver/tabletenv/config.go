@@ -114,6 +114,86 @@ func (t *TxThrottlerConfigFlag) Set(arg string) error {
 
 func (t *TxThrottlerConfigFlag) Type() string { return "string" }
 
+// QueryQuota limits the resources which a principal may use on a table
+// within a minute. A limit of zero means unlimited.
+type QueryQuota struct {
+	// Principal is the callerid principal the quota applies to, or "*" for
+	// all principals. Every principal has its own budget.
+	Principal string `json:"principal"`
+	// Table is the table the quota applies to, or "*" for all tables.
+	// Every table has its own budget.
+	Table string `json:"table"`
+	// RowsPerMinute limits the rows examined by MySQL per minute, as reported
+	// by performance_schema. The rows returned and affected are counted
+	// instead if MySQL does not report them.
+	RowsPerMinute int64 `json:"rowsPerMinute,omitempty"`
+	// QueryTimePerMinute limits the time spent in MySQL per minute.
+	QueryTimePerMinute time.Duration `json:"-"`
+	// MaxConcurrentStreams limits the number of streaming queries.
+	MaxConcurrentStreams int `json:"maxConcurrentStreams,omitempty"`
+}
+
+// QueryQuotasFlag is a flag which loads the query quotas from a JSON file
+// like {"quotas": [{"principal": "tenant1", "table": "*", "rowsPerMinute": 100000,
+// "queryTimePerMinute": "30s", "maxConcurrentStreams": 2}]}.
+// The first quota which matches a principal and table applies.
+type QueryQuotasFlag struct {
+	path   string
+	Quotas []QueryQuota
+}
+
+func (f *QueryQuotasFlag) String() string { return f.path }
+
+func (f *QueryQuotasFlag) Set(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var config struct {
+		Quotas []struct {
+			QueryQuota
+			QueryTimePerMinute string `json:"queryTimePerMinute,omitempty"`
+		} `json:"quotas"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("cannot parse query quota config file %s: %w", path, err)
+	}
+	quotas := make([]QueryQuota, 0, len(config.Quotas))
+	for _, q := range config.Quotas {
+		quota := q.QueryQuota
+		if quota.Principal == "" || quota.Table == "" {
+			return fmt.Errorf("query quota in %s must specify a principal and a table, use \"*\" to match all", path)
+		}
+		if q.QueryTimePerMinute != "" {
+			if quota.QueryTimePerMinute, err = time.ParseDuration(q.QueryTimePerMinute); err != nil {
+				return fmt.Errorf("invalid queryTimePerMinute for principal %s and table %s: %w", quota.Principal, quota.Table, err)
+			}
+		}
+		if quota.RowsPerMinute < 0 || quota.QueryTimePerMinute < 0 || quota.MaxConcurrentStreams < 0 {
+			return fmt.Errorf("query quota for principal %s and table %s must not be negative", quota.Principal, quota.Table)
+		}
+		quotas = append(quotas, quota)
+	}
+	f.path = path
+	f.Quotas = quotas
+	return nil
+}
+
+func (f *QueryQuotasFlag) Type() string { return "string" }
+
+// LimitsRows returns true if any of the quotas limits the rows examined.
+func (f *QueryQuotasFlag) LimitsRows() bool {
+	if f == nil {
+		return false
+	}
+	for _, quota := range f.Quotas {
+		if quota.RowsPerMinute > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // RegisterTabletEnvFlags is a public API to register tabletenv flags for use by test cases that expect
 // some flags to be set with default values
 func RegisterTabletEnvFlags(fs *pflag.FlagSet) {
@@ -138,6 +218,9 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 
 	fs.Int64Var(&currentConfig.QueryCacheMemory, "queryserver-config-query-cache-memory", defaultConfig.QueryCacheMemory, "query server query cache size in bytes, maximum amount of memory to be used for caching. vttablet analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
 
+	fs.IntVar(&currentConfig.ResultCacheSize, "queryserver-result-cache-size", defaultConfig.ResultCacheSize, "query server result cache size, the maximum number of SELECT results cached by vttablet. Only SELECTs on tables whose comment contains vitess_result_cache, or which carry the /*vt+ RESULT_CACHE=true */ directive, are cached. Entries are invalidated by row events from the tablet's own vstreamer, so cached results can be stale for the replication delay of the binlog stream. Setting to 0 disables the result cache.")
+	fs.IntVar(&currentConfig.ResultCacheMaxRows, "queryserver-result-cache-max-rows", defaultConfig.ResultCacheMaxRows, "query server result cache max rows, results with more rows than this are not cached.")
+
 	fs.DurationVar(&currentConfig.SchemaReloadInterval, "queryserver-config-schema-reload-time", defaultConfig.SchemaReloadInterval, "query server schema reload time, how often vttablet reloads schemas from underlying MySQL instance. vttablet keeps table schemas in its own memory and periodically refreshes it from MySQL. This config controls the reload time.")
 	fs.DurationVar(&currentConfig.SchemaChangeReloadTimeout, "schema-change-reload-timeout", defaultConfig.SchemaChangeReloadTimeout, "query server schema change reload timeout, this is how long to wait for the signaled schema reload operation to complete before giving up")
 	fs.BoolVar(&currentConfig.SignalWhenSchemaChange, "queryserver-config-schema-change-signal", defaultConfig.SignalWhenSchemaChange, "query server schema signal, will signal connected vtgates that schema has changed whenever this is detected. VTGates will need to have -schema-change-signal enabled for this to work")
@@ -178,11 +261,25 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&currentConfig.TxThrottlerDryRun, "tx-throttler-dry-run", defaultConfig.TxThrottlerDryRun, "If present, the transaction throttler only records metrics about requests received and throttled, but does not actually throttle any requests.")
 	fs.DurationVar(&currentConfig.TxThrottlerTopoRefreshInterval, "tx-throttler-topo-refresh-interval", time.Minute*5, "The rate that the transaction throttler will refresh the topology to find cells.")
 
+	// Query quotas config
+	utils.SetFlagVar(fs, currentConfig.QueryQuotas, "query-quota-config-file", "JSON file with per principal and table quotas for rows examined per minute, query time per minute and concurrent streaming queries, enforced by vttablet for each query.")
+	utils.SetFlagBoolVar(fs, &currentConfig.QueryQuotasDryRun, "query-quota-dry-run", defaultConfig.QueryQuotasDryRun, "If true, queries over the limits of --query-quota-config-file are only counted in stats but not rejected.")
+
+	// Query killer config
+	utils.SetFlagDurationVar(fs, &currentConfig.QueryKillInterval, "query-kill-interval", defaultConfig.QueryKillInterval, "How often the resource usage of running queries is sampled from performance_schema to enforce the --query-kill-max-* limits.")
+	utils.SetFlagInt64Var(fs, &currentConfig.QueryKillMaxRowsExamined, "query-kill-max-rows-examined", defaultConfig.QueryKillMaxRowsExamined, "Running queries which examined more rows than this are killed. 0 means no limit.")
+	utils.SetFlagInt64Var(fs, &currentConfig.QueryKillMaxTmpTableSize, "query-kill-max-tmp-table-size", defaultConfig.QueryKillMaxTmpTableSize, "Running queries whose session uses more bytes of InnoDB temporary tablespace than this are killed. 0 means no limit.")
+	utils.SetFlagInt64Var(fs, &currentConfig.QueryKillMaxMemory, "query-kill-max-memory", defaultConfig.QueryKillMaxMemory, "Running queries whose MySQL thread uses more bytes of memory than this are killed. 0 means no limit.")
+
 	utils.SetFlagBoolVar(fs, &enableHotRowProtection, "enable-hot-row-protection", false, "If true, incoming transactions for the same row (range) will be queued and cannot consume all txpool slots.")
 	utils.SetFlagBoolVar(fs, &enableHotRowProtectionDryRun, "enable-hot-row-protection-dry-run", false, "If true, hot row protection is not enforced but logs if transactions would have been queued.")
 	utils.SetFlagIntVar(fs, &currentConfig.HotRowProtection.MaxQueueSize, "hot-row-protection-max-queue-size", defaultConfig.HotRowProtection.MaxQueueSize, "Maximum number of BeginExecute RPCs which will be queued for the same row (range).")
 	utils.SetFlagIntVar(fs, &currentConfig.HotRowProtection.MaxGlobalQueueSize, "hot-row-protection-max-global-queue-size", defaultConfig.HotRowProtection.MaxGlobalQueueSize, "Global queue limit across all row (ranges). Useful to prevent that the queue can grow unbounded.")
 	utils.SetFlagIntVar(fs, &currentConfig.HotRowProtection.MaxConcurrency, "hot-row-protection-concurrent-transactions", defaultConfig.HotRowProtection.MaxConcurrency, "Number of concurrent transactions let through to the txpool/MySQL for the same hot row. Should be > 1 to have enough 'ready' transactions in MySQL and benefit from a pipelining effect.")
+	utils.SetFlagBoolVar(fs, &currentConfig.HotRowProtection.AutoDetect, "hot-row-protection-auto-detect", defaultConfig.HotRowProtection.AutoDetect, "If true, hot row protection only queues transactions for rows which MySQL recently reported as lock wait hotspots in performance_schema.data_lock_waits. Requires --enable-hot-row-protection.")
+	utils.SetFlagDurationVar(fs, &currentConfig.HotRowProtection.AutoDetectInterval, "hot-row-protection-auto-detect-interval", defaultConfig.HotRowProtection.AutoDetectInterval, "How often lock waits are sampled to detect hot rows.")
+	utils.SetFlagIntVar(fs, &currentConfig.HotRowProtection.AutoDetectMinWaits, "hot-row-protection-auto-detect-min-waits", defaultConfig.HotRowProtection.AutoDetectMinWaits, "Number of sampled lock waits on the same row after which the row is considered hot.")
+	utils.SetFlagDurationVar(fs, &currentConfig.HotRowProtection.AutoDetectTTL, "hot-row-protection-auto-detect-ttl", defaultConfig.HotRowProtection.AutoDetectTTL, "How long a detected hot row stays protected after the last lock wait was observed on it.")
 
 	utils.SetFlagBoolVar(fs, &currentConfig.EnableTransactionLimit, "enable-transaction-limit", defaultConfig.EnableTransactionLimit, "If true, limit on number of transactions open at the same time will be enforced for all users. User trying to open a new transaction after exhausting their limit will receive an error immediately, regardless of whether there are available slots or not.")
 	utils.SetFlagBoolVar(fs, &currentConfig.EnableTransactionLimitDryRun, "enable-transaction-limit-dry-run", defaultConfig.EnableTransactionLimitDryRun, "If true, limit on number of transactions open at the same time will be tracked for all users, but not enforced.")
@@ -202,6 +299,7 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.Int64Var(&currentConfig.ConsolidatorStreamQuerySize, "consolidator-stream-query-size", defaultConfig.ConsolidatorStreamQuerySize, "Configure the stream consolidator query size in bytes. Setting to 0 disables the stream consolidator.")
 	fs.Int64Var(&currentConfig.ConsolidatorStreamTotalSize, "consolidator-stream-total-size", defaultConfig.ConsolidatorStreamTotalSize, "Configure the stream consolidator total size in bytes. Setting to 0 disables the stream consolidator.")
 
+	fs.BoolVar(&currentConfig.ConsolidatorReadYourWrites, "enable-consolidator-read-your-writes", defaultConfig.ConsolidatorReadYourWrites, "If true, the first read of a transaction is consolidated too, unless the transaction was started with a consistent snapshot, and reads are never consolidated with an identical query which may have missed a completed write to one of their tables. Requires the query consolidator to be enabled.")
 	fs.Int64Var(&currentConfig.ConsolidatorQueryWaiterCap, "consolidator-query-waiter-cap", 0, "Configure the maximum number of clients allowed to wait on the consolidator.")
 	utils.SetFlagDurationVar(fs, &healthCheckInterval, "health-check-interval", defaultConfig.Healthcheck.Interval, "Interval between health checks")
 	utils.SetFlagDurationVar(fs, &degradedThreshold, "degraded-threshold", defaultConfig.Healthcheck.DegradedThreshold, "replication lag after which a replica is considered degraded")
@@ -371,6 +469,16 @@ type TabletConfig struct {
 
 	TransactionLimitConfig `json:"-"`
 
+	QueryQuotas       *QueryQuotasFlag `json:"-"`
+	QueryQuotasDryRun bool             `json:"-"`
+
+	// QueryKillInterval is how often the resource usage of running queries
+	// is sampled. The QueryKillMax* limits are disabled when they are 0.
+	QueryKillInterval        time.Duration `json:"-"`
+	QueryKillMaxRowsExamined int64         `json:"-"`
+	QueryKillMaxTmpTableSize int64         `json:"-"`
+	QueryKillMaxMemory       int64         `json:"-"`
+
 	EnforceStrictTransTables bool `json:"-"`
 	EnableOnlineDDL          bool `json:"-"`
 
@@ -378,6 +486,11 @@ type TabletConfig struct {
 
 	EnableViews bool `json:"-"`
 
+	ConsolidatorReadYourWrites bool `json:"-"`
+
+	ResultCacheSize    int `json:"-"`
+	ResultCacheMaxRows int `json:"-"`
+
 	EnablePerWorkloadTableMetrics       bool          `json:"-"`
 	SkipUserMetrics                     bool          `json:"-"`
 	QueryThrottlerConfigRefreshInterval time.Duration `json:"-"`
@@ -625,6 +738,73 @@ type HotRowProtectionConfig struct {
 	MaxQueueSize       int    `json:"maxQueueSize,omitempty"`
 	MaxGlobalQueueSize int    `json:"maxGlobalQueueSize,omitempty"`
 	MaxConcurrency     int    `json:"maxConcurrency,omitempty"`
+
+	// AutoDetect restricts the protection to rows which were recently seen
+	// as lock wait hotspots by sampling performance_schema.data_lock_waits.
+	AutoDetect bool `json:"autoDetect,omitempty"`
+	// AutoDetectInterval is the sampling interval for lock waits.
+	AutoDetectInterval time.Duration `json:"autoDetectIntervalSeconds,omitempty"`
+	// AutoDetectMinWaits is the number of sampled lock waits after which a
+	// row is considered hot.
+	AutoDetectMinWaits int `json:"autoDetectMinWaits,omitempty"`
+	// AutoDetectTTL is how long a row stays hot after its last lock wait.
+	AutoDetectTTL time.Duration `json:"autoDetectTTLSeconds,omitempty"`
+}
+
+func (cfg *HotRowProtectionConfig) MarshalJSON() ([]byte, error) {
+	type HRPProxy HotRowProtectionConfig
+
+	tmp := struct {
+		HRPProxy
+		AutoDetectInterval string `json:"autoDetectIntervalSeconds,omitempty"`
+		AutoDetectTTL      string `json:"autoDetectTTLSeconds,omitempty"`
+	}{
+		HRPProxy: HRPProxy(*cfg),
+	}
+
+	if d := cfg.AutoDetectInterval; d != 0 {
+		tmp.AutoDetectInterval = d.String()
+	}
+
+	if d := cfg.AutoDetectTTL; d != 0 {
+		tmp.AutoDetectTTL = d.String()
+	}
+
+	return json.Marshal(&tmp)
+}
+
+func (cfg *HotRowProtectionConfig) UnmarshalJSON(data []byte) (err error) {
+	type HRPProxy HotRowProtectionConfig
+
+	var tmp struct {
+		HRPProxy
+		AutoDetectInterval string `json:"autoDetectIntervalSeconds,omitempty"`
+		AutoDetectTTL      string `json:"autoDetectTTLSeconds,omitempty"`
+	}
+
+	tmp.HRPProxy = HRPProxy(*cfg)
+
+	if err = json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+
+	*cfg = HotRowProtectionConfig(tmp.HRPProxy)
+
+	if tmp.AutoDetectInterval != "" {
+		cfg.AutoDetectInterval, err = time.ParseDuration(tmp.AutoDetectInterval)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tmp.AutoDetectTTL != "" {
+		cfg.AutoDetectTTL, err = time.ParseDuration(tmp.AutoDetectTTL)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // SemiSyncMonitorConfig contains the config for the semi-sync monitor.
@@ -922,9 +1102,44 @@ func (c *TabletConfig) Verify() error {
 	if v := c.HotRowProtection.MaxConcurrency; v <= 0 {
 		return fmt.Errorf("--hot-row-protection-concurrent-transactions must be > 0 (specified value: %v)", v)
 	}
+	if v := c.ResultCacheSize; v < 0 {
+		return fmt.Errorf("--queryserver-result-cache-size must be >= 0 (specified value: %v)", v)
+	}
+	if v := c.ResultCacheMaxRows; c.ResultCacheSize > 0 && v <= 0 {
+		return fmt.Errorf("--queryserver-result-cache-max-rows must be > 0 (specified value: %v)", v)
+	}
+	if v := c.QueryKillMaxRowsExamined; v < 0 {
+		return fmt.Errorf("--query-kill-max-rows-examined must be >= 0 (specified value: %v)", v)
+	}
+	if v := c.QueryKillMaxTmpTableSize; v < 0 {
+		return fmt.Errorf("--query-kill-max-tmp-table-size must be >= 0 (specified value: %v)", v)
+	}
+	if v := c.QueryKillMaxMemory; v < 0 {
+		return fmt.Errorf("--query-kill-max-memory must be >= 0 (specified value: %v)", v)
+	}
+	if v := c.QueryKillInterval; c.QueryKillEnabled() && v <= 0 {
+		return fmt.Errorf("--query-kill-interval must be > 0 (specified value: %v)", v)
+	}
+	if c.HotRowProtection.AutoDetect {
+		if v := c.HotRowProtection.AutoDetectInterval; v <= 0 {
+			return fmt.Errorf("--hot-row-protection-auto-detect-interval must be > 0 (specified value: %v)", v)
+		}
+		if v := c.HotRowProtection.AutoDetectMinWaits; v <= 0 {
+			return fmt.Errorf("--hot-row-protection-auto-detect-min-waits must be > 0 (specified value: %v)", v)
+		}
+		if v := c.HotRowProtection.AutoDetectTTL; v <= 0 {
+			return fmt.Errorf("--hot-row-protection-auto-detect-ttl must be > 0 (specified value: %v)", v)
+		}
+	}
 	return nil
 }
 
+// QueryKillEnabled returns true if running queries are killed when they
+// exceed any of the QueryKillMax* limits.
+func (c *TabletConfig) QueryKillEnabled() bool {
+	return c.QueryKillMaxRowsExamined > 0 || c.QueryKillMaxTmpTableSize > 0 || c.QueryKillMaxMemory > 0
+}
+
 // verifyUnmanagedTabletConfig checks unmanaged tablet related config for sanity
 func (c *TabletConfig) verifyUnmanagedTabletConfig() error {
 	// Skip checks if tablet is not unmanaged
@@ -1086,6 +1301,11 @@ var defaultConfig = TabletConfig{
 		// Allow more than 1 transaction for the same hot row through to have enough
 		// of them ready in MySQL and profit from a pipelining effect.
 		MaxConcurrency: 5,
+		// Auto detection is opt-in. A row needs at least two observed lock
+		// waits before it gets protected.
+		AutoDetectInterval: time.Second,
+		AutoDetectMinWaits: 2,
+		AutoDetectTTL:      time.Minute,
 	},
 	Consolidator:                Enable,
 	ConsolidatorStreamTotalSize: 128 * 1024 * 1024,
@@ -1096,8 +1316,9 @@ var defaultConfig = TabletConfig{
 	// memory copies.  so with the encoding overhead, this seems to work
 	// great (the overhead makes the final packets on the wire about twice
 	// bigger than this).
-	StreamBufferSize: 32 * 1024,
-	QueryCacheMemory: 32 * 1024 * 1024, // 32 mb for our query cache
+	StreamBufferSize:   32 * 1024,
+	QueryCacheMemory:   32 * 1024 * 1024, // 32 mb for our query cache
+	ResultCacheMaxRows: 1000,
 	// The doorkeeper for the plan cache is disabled by default in endtoend tests to ensure
 	// results are consistent between runs.
 	QueryCacheDoorkeeper: !servenv.TestingEndtoend,
@@ -1112,6 +1333,8 @@ var defaultConfig = TabletConfig{
 
 	EnableTxThrottler:              false,
 	TxThrottlerConfig:              defaultTxThrottlerConfig(),
+	QueryQuotas:                    &QueryQuotasFlag{},
+	QueryKillInterval:              time.Second,
 	TxThrottlerHealthCheckCells:    []string{},
 	TxThrottlerDefaultPriority:     sqlparser.MaxPriorityValue, // This leads to all queries being candidates to throttle
 	TxThrottlerTabletTypes:         &topoproto.TabletTypeListFlag{topodatapb.TabletType_REPLICA},
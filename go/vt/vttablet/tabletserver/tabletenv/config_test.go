@@ -18,6 +18,7 @@ package tabletenv
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -141,6 +142,9 @@ healthcheck:
   intervalSeconds: 20s
   unhealthyThresholdSeconds: 2h0m0s
 hotRowProtection:
+  autoDetectIntervalSeconds: 1s
+  autoDetectMinWaits: 2
+  autoDetectTTLSeconds: 1m0s
   maxConcurrency: 5
   maxGlobalQueueSize: 1000
   maxQueueSize: 20
@@ -361,6 +365,35 @@ func TestTxThrottlerConfigFlag(t *testing.T) {
 	}
 }
 
+func TestQueryQuotasFlag(t *testing.T) {
+	writeConfig := func(content string) string {
+		path := filepath.Join(t.TempDir(), "quotas.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	f := &QueryQuotasFlag{}
+	assert.Equal(t, "string", f.Type())
+	path := writeConfig(`{"quotas": [
+		{"principal": "tenant1", "table": "orders", "rowsPerMinute": 1000, "queryTimePerMinute": "30s"},
+		{"principal": "*", "table": "*", "maxConcurrentStreams": 2}
+	]}`)
+	require.NoError(t, f.Set(path))
+	assert.Equal(t, path, f.String())
+	assert.Equal(t, []QueryQuota{
+		{Principal: "tenant1", Table: "orders", RowsPerMinute: 1000, QueryTimePerMinute: 30 * time.Second},
+		{Principal: "*", Table: "*", MaxConcurrentStreams: 2},
+	}, f.Quotas)
+
+	assert.ErrorContains(t, f.Set(writeConfig(`{"quotas": [{"table": "orders"}]}`)), "must specify a principal and a table")
+	assert.ErrorContains(t, f.Set(writeConfig(`{"quotas": [{"principal": "*", "table": "*", "queryTimePerMinute": "soon"}]}`)), "invalid queryTimePerMinute")
+	assert.ErrorContains(t, f.Set(writeConfig(`{"quotas": [{"principal": "*", "table": "*", "rowsPerMinute": -1}]}`)), "must not be negative")
+	assert.ErrorContains(t, f.Set(writeConfig(`not json`)), "cannot parse query quota config file")
+	assert.Error(t, f.Set(filepath.Join(t.TempDir(), "missing.json")))
+	// Failed loads keep the previous quotas.
+	assert.Equal(t, path, f.String())
+}
+
 func TestVerifyTxThrottlerConfig(t *testing.T) {
 	defaultMaxReplicationLagModuleConfig := throttler.DefaultMaxReplicationLagModuleConfig().Configuration
 	invalidMaxReplicationLagModuleConfig := throttler.DefaultMaxReplicationLagModuleConfig().Configuration
@@ -176,6 +176,11 @@ func TestLogStatsFormatQuerySources(t *testing.T) {
 	if !strings.Contains(logStats.FmtQuerySources(), "consolidator") {
 		t.Fatalf("'consolidator' should be in formatted query sources")
 	}
+
+	logStats.QuerySources |= QuerySourceResultCache
+	if got := logStats.FmtQuerySources(); got != "mysql,consolidator,result_cache" {
+		t.Fatalf("formatted query sources: got %q, want %q", got, "mysql,consolidator,result_cache")
+	}
 }
 
 func TestLogStatsContextHTML(t *testing.T) {
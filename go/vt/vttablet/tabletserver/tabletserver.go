@@ -66,7 +66,9 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/gc"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/messager"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/quota"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/repltracker"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/resultcache"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -142,6 +144,8 @@ type TabletServer struct {
 	env *vtenv.Environment
 
 	queryThrottler *querythrottler.QueryThrottler
+
+	quotas *quota.Quotas
 }
 
 var _ queryservice.QueryService = (*TabletServer)(nil)
@@ -191,10 +195,14 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 	tsv.lagThrottler = throttle.NewThrottler(tsv, srvTopoServer, topoServer, alias, tsv.rt.HeartbeatWriter(), tabletTypeFunc, throttlerPoolName)
 	tsv.qThrottler = throttle.NewThrottler(tsv, srvTopoServer, topoServer, alias, tsv.rt.HeartbeatWriter(), tabletTypeFunc, queryThrottlerPoolName)
 	tsv.queryThrottler = querythrottler.NewQueryThrottler(ctx, tsv.qThrottler, tsv, alias, srvTopoServer)
+	tsv.quotas = quota.New(tsv)
 
 	tsv.vstreamer = vstreamer.NewEngine(tsv, srvTopoServer, tsv.se, tsv.lagThrottler, alias.Cell)
 	tsv.tracker = schema.NewTracker(tsv, tsv.vstreamer, tsv.se)
 	tsv.qe = NewQueryEngine(tsv, tsv.se)
+	tsv.qe.resultCache = resultcache.NewCache(tsv, tsv.vstreamer)
+	tsv.qe.queryKiller = newQueryKiller(tsv, []*QueryList{tsv.statelessql, tsv.statefulql, tsv.olapql})
+	tsv.qe.rowsExamined = newRowsExaminedReader(tsv)
 	tsv.txThrottler = txthrottler.NewTxThrottler(tsv, topoServer)
 	tsv.te = NewTxEngine(tsv, tsv.hs.sendUnresolvedTransactionSignal)
 	tsv.messager = messager.NewEngine(tsv, tsv.se, tsv.vstreamer)
@@ -251,7 +259,7 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 	tsv.registerQueryzHandler()
 	tsv.registerQuerylogzHandler()
 	tsv.registerTxlogzHandler()
-	tsv.registerQueryListHandlers([]*QueryList{tsv.statelessql, tsv.statefulql, tsv.olapql})
+	tsv.registerQueryListHandlers([]*QueryList{tsv.statelessql, tsv.statefulql, tsv.olapql}, tsv.qe.queryKiller)
 	tsv.registerTwopczHandler()
 	tsv.registerThrottlerHandlers()
 	tsv.registerDebugEnvHandler()
@@ -1161,7 +1169,8 @@ func (tsv *TabletServer) beginWaitForSameRangeTransactions(ctx context.Context,
 // whether two queries would update the same row (range).
 // Additionally, it returns the table name (needed for updating stats vars).
 // It returns an empty string as key if the row (range) cannot be parsed from
-// the query and bind variables or the table name is empty.
+// the query and bind variables or the table name is empty. With automatic hot
+// row detection enabled, the key is also empty for rows which are not hot.
 func (tsv *TabletServer) computeTxSerializerKey(ctx context.Context, logStats *tabletenv.LogStats, sql string, bindVariables map[string]*querypb.BindVariable) (string, string) {
 	// Strip trailing comments so we don't pollute the query cache.
 	sql, _ = sqlparser.SplitMarginComments(sql)
@@ -1191,6 +1200,11 @@ func (tsv *TabletServer) computeTxSerializerKey(ctx context.Context, logStats *t
 		return "", ""
 	}
 
+	if !tsv.qe.txSerializer.IsHotRow(plan.Table, where) {
+		// Hot rows are detected automatically and this row is not one of them.
+		return "", ""
+	}
+
 	// Example: table1 where id = 1 and sub_id = 2
 	key := fmt.Sprintf("%s%s", tableName, where)
 	return key, tableName.String()
@@ -1854,12 +1868,12 @@ func (tsv *TabletServer) registerTxlogzHandler() {
 	})
 }
 
-func (tsv *TabletServer) registerQueryListHandlers(queryLists []*QueryList) {
+func (tsv *TabletServer) registerQueryListHandlers(queryLists []*QueryList, killer *queryKiller) {
 	tsv.exporter.HandleFunc("/livequeryz/", func(w http.ResponseWriter, r *http.Request) {
-		livequeryzHandler(queryLists, w, r)
+		livequeryzHandler(queryLists, killer, w, r)
 	})
 	tsv.exporter.HandleFunc("/livequeryz/terminate", func(w http.ResponseWriter, r *http.Request) {
-		livequeryzTerminateHandler(queryLists, w, r)
+		livequeryzTerminateHandler(queryLists, killer, w, r)
 	})
 }
 
@@ -63,8 +63,9 @@ const (
 	// ThrottlerStimulatorName is used by a replica tablet to stimulate the throttler on the Primary tablet
 	ThrottlerStimulatorName Name = "throttler-stimulator"
 
-	TableGCName   Name = "tablegc"
-	OnlineDDLName Name = "online-ddl"
+	TableGCName             Name = "tablegc"
+	OnlineDDLName           Name = "online-ddl"
+	PartitionManagementName Name = "partition-management"
 
 	VReplicationName      Name = "vreplication"
 	VStreamerName         Name = "vstreamer"
@@ -74,10 +75,12 @@ const (
 	RowStreamerName       Name = "rowstreamer"
 	ExternalConnectorName Name = "external-connector"
 	ReplicaConnectorName  Name = "replica-connector"
+	VDiffRepairName       Name = "vdiff-repair"
 
 	BinlogWatcherName Name = "binlog-watcher"
 	MessagerName      Name = "messager"
 	SchemaTrackerName Name = "schema-tracker"
+	ResultCacheName   Name = "result-cache"
 
 	TestingName                Name = "test"
 	TestingAlwaysThrottledName Name = "always-throttled-app"
@@ -88,6 +91,7 @@ var exemptFromChecks = map[string]bool{
 	BinlogWatcherName.String(): true,
 	MessagerName.String():      true,
 	SchemaTrackerName.String(): true,
+	ResultCacheName.String():   true,
 }
 
 // ExemptFromChecks returns 'true' for apps that should skip the throttler checks. The throttler should
@@ -32,6 +32,7 @@ func TestExemptFromChecks(t *testing.T) {
 		BinlogWatcherName.String(): true,
 		MessagerName.String():      true,
 		SchemaTrackerName.String(): true,
+		ResultCacheName.String():   true,
 	}
 	for app, expectExempt := range tcases {
 		t.Run(app, func(t *testing.T) {
@@ -54,7 +54,11 @@ type (
 		Queries         []Query
 		Autocommit      bool
 		Conclusion      string
-		LogToFile       bool
+		// Snapshot is true once the reads of the transaction must come from
+		// its own consistent snapshot: it was started WITH CONSISTENT
+		// SNAPSHOT, or it already executed a statement.
+		Snapshot  bool
+		LogToFile bool
 
 		Stats *servenv.TimingsWrapper
 	}
@@ -209,6 +213,25 @@ func (p *Properties) String(sanitize bool, parser *sqlparser.Parser) string {
 	)
 }
 
+// Tables returns the tables involved in the queries recorded against
+// this transaction, in the order they were first seen.
+func (p *Properties) Tables() []string {
+	if p == nil {
+		return nil
+	}
+	var tables []string
+	seen := make(map[string]bool)
+	for _, query := range p.Queries {
+		for _, table := range query.Tables {
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables
+}
+
 func (p *Properties) GetQueries() []Query {
 	if p == nil {
 		return nil
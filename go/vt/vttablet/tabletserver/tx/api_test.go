@@ -60,3 +60,18 @@ func TestRollbackToSavePointQueryDetails(t *testing.T) {
 		{Sql: "select 3"},
 	})
 }
+
+func TestPropertiesTables(t *testing.T) {
+	var nilProperties *Properties
+	require.Nil(t, nilProperties.Tables())
+
+	p := &Properties{}
+	p.RecordQueryDetail("set @@sql_mode = ''", nil)
+	p.RecordQueryDetail("insert into t1 values (1)", []string{"t1"})
+	p.RecordSavePointDetail("s1")
+	p.RecordQueryDetail("update t2 join t1 on t1.id = t2.id set t2.c = 1", []string{"t2", "t1"})
+	require.Equal(t, []string{"t1", "t2"}, p.Tables())
+
+	require.NoError(t, p.RollbackToSavepoint("s1"))
+	require.Equal(t, []string{"t1"}, p.Tables())
+}
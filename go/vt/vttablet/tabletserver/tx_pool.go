@@ -19,6 +19,7 @@ package tabletserver
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -73,6 +74,9 @@ type (
 		logMu   sync.Mutex
 		lastLog time.Time
 		txStats *servenv.TimingsWrapper
+
+		// writes is only set with --enable-consolidator-read-your-writes.
+		writes *writeTracker
 	}
 )
 
@@ -86,6 +90,9 @@ func NewTxPool(env tabletenv.Env, limiter txlimiter.TxLimiter) *TxPool {
 		limiter: limiter,
 		txStats: env.Exporter().NewTimings("Transactions", "Transaction stats", "operation"),
 	}
+	if config.ConsolidatorReadYourWrites {
+		axp.writes = newWriteTracker()
+	}
 	// Careful: conns also exports name+"xxx" vars,
 	// but we know it doesn't export Timeout.
 	env.Exporter().NewGaugeDurationFunc("OlapTransactionTimeout", "OLAP transaction timeout", func() time.Duration {
@@ -191,6 +198,7 @@ func (tp *TxPool) Commit(ctx context.Context, txConn *StatefulConnection) (strin
 		return "", nil
 	}
 
+	defer tp.writes.start(txConn.TxProperties().Tables())()
 	if _, err := txConn.Exec(ctx, "commit", 1, false); err != nil {
 		txConn.Close()
 		return "", err
@@ -297,6 +305,8 @@ func (tp *TxPool) begin(ctx context.Context, options *querypb.ExecuteOptions, re
 		return "", "", err
 	}
 	conn.txProps = tp.NewTxProps(immediateCaller, effectiveCaller, autocommit)
+	conn.txProps.Snapshot = options.GetTransactionIsolation() == querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY ||
+		slices.Contains(options.GetTransactionAccessMode(), querypb.ExecuteOptions_CONSISTENT_SNAPSHOT)
 	return beginQueries, sessionStateChanges, nil
 }
 
@@ -0,0 +1,313 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package txserializer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+// sqlSelectLockWaits returns, per primary key record of the current database,
+// how many transactions are waiting for the lock held on it.
+const sqlSelectLockWaits = `select blocking.OBJECT_NAME, blocking.LOCK_DATA, count(*)
+from performance_schema.data_lock_waits as waits
+	join performance_schema.data_locks as blocking on blocking.ENGINE_LOCK_ID = waits.BLOCKING_ENGINE_LOCK_ID
+where blocking.OBJECT_SCHEMA = database() and blocking.INDEX_NAME = 'PRIMARY' and blocking.LOCK_TYPE = 'RECORD'
+group by blocking.OBJECT_NAME, blocking.LOCK_DATA`
+
+// hotRowDetector periodically samples InnoDB lock waits and keeps track of
+// the rows which other transactions are queuing up on.
+// When auto detection is enabled, the TxSerializer only serializes
+// transactions for rows which this detector considers hot.
+type hotRowDetector struct {
+	env      tabletenv.Env
+	parser   *sqlparser.Parser
+	minWaits int
+	ttl      time.Duration
+	now      func() time.Time
+	errorLog *logutil.ThrottledLogger
+
+	// exec runs the lock wait query. It's a field to allow tests to
+	// inject results without a MySQL server.
+	exec func(ctx context.Context, query string) (*sqltypes.Result, error)
+
+	runMu  sync.Mutex
+	isOpen bool
+	pool   *connpool.Pool
+	ticks  *timer.Timer
+
+	mu sync.Mutex
+	// rows is keyed by table name and then by the InnoDB LOCK_DATA of the
+	// primary key record e.g. "1" or "1, 'abc'".
+	rows map[string]map[string]*hotRow
+}
+
+// hotRow holds the lock wait statistics of a single row.
+type hotRow struct {
+	waits    int64
+	lastSeen time.Time
+}
+
+// HotRow describes a row which was detected as hot.
+type HotRow struct {
+	Table    string
+	LockData string
+	Waits    int64
+	LastSeen time.Time
+}
+
+func newHotRowDetector(env tabletenv.Env) *hotRowDetector {
+	config := env.Config()
+	d := &hotRowDetector{
+		env:      env,
+		parser:   env.Environment().Parser(),
+		minWaits: config.HotRowProtection.AutoDetectMinWaits,
+		ttl:      config.HotRowProtection.AutoDetectTTL,
+		now:      time.Now,
+		errorLog: logutil.NewThrottledLogger("HotRowDetector", 60*time.Second),
+		ticks:    timer.NewTimer(config.HotRowProtection.AutoDetectInterval),
+		pool: connpool.NewPool(env, "HotRowDetectorPool", tabletenv.ConnPoolConfig{
+			Size:        1,
+			IdleTimeout: config.OltpReadPool.IdleTimeout,
+		}),
+		rows: make(map[string]map[string]*hotRow),
+	}
+	d.exec = d.execPool
+	env.Exporter().NewGaugeFunc("TxSerializerHotRows", "Number of rows currently detected as hot by hot row protection", func() int64 {
+		return int64(len(d.HotRows()))
+	})
+	return d
+}
+
+// Open starts sampling lock waits.
+func (d *hotRowDetector) Open() {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	if d.isOpen {
+		return
+	}
+	log.Info("Hot Row Detector: opening")
+
+	dbConfig := d.env.Config().DB.DbaWithDB()
+	d.pool.Open(dbConfig, dbConfig, dbConfig)
+	d.ticks.Start(func() { d.sample() })
+	d.isOpen = true
+}
+
+// Close stops sampling lock waits and forgets all hot rows.
+func (d *hotRowDetector) Close() {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	if !d.isOpen {
+		return
+	}
+	d.ticks.Stop()
+	d.pool.Close()
+
+	d.mu.Lock()
+	d.rows = make(map[string]map[string]*hotRow)
+	d.mu.Unlock()
+
+	d.isOpen = false
+	log.Info("Hot Row Detector: closed")
+}
+
+func (d *hotRowDetector) execPool(ctx context.Context, query string) (*sqltypes.Result, error) {
+	conn, err := d.pool.Get(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+	return conn.Conn.Exec(ctx, query, -1, false)
+}
+
+// sample reads the current lock waits exactly once and updates the hot set.
+func (d *hotRowDetector) sample() {
+	defer d.env.LogError()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.env.Config().HotRowProtection.AutoDetectInterval)
+	defer cancel()
+
+	qr, err := d.exec(ctx, sqlSelectLockWaits)
+	if err != nil {
+		d.errorLog.Errorf("failed to sample lock waits: %v", err)
+		return
+	}
+	d.record(qr, d.now())
+}
+
+// record adds the lock waits of a sample to the statistics and expires rows
+// which have not been seen for longer than the TTL.
+func (d *hotRowDetector) record(qr *sqltypes.Result, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, row := range qr.Rows {
+		if len(row) < 3 || row[0].IsNull() || row[1].IsNull() {
+			continue
+		}
+		waits, err := row[2].ToInt64()
+		if err != nil {
+			continue
+		}
+		table, lockData := row[0].ToString(), row[1].ToString()
+		rows, ok := d.rows[table]
+		if !ok {
+			rows = make(map[string]*hotRow)
+			d.rows[table] = rows
+		}
+		r, ok := rows[lockData]
+		if !ok {
+			r = &hotRow{}
+			rows[lockData] = r
+		}
+		r.waits += waits
+		r.lastSeen = now
+	}
+
+	for table, rows := range d.rows {
+		for lockData, r := range rows {
+			if now.Sub(r.lastSeen) > d.ttl {
+				delete(rows, lockData)
+			}
+		}
+		if len(rows) == 0 {
+			delete(d.rows, table)
+		}
+	}
+}
+
+// isHot returns true if the row which is selected by the WHERE clause was
+// detected as hot. The WHERE clause must already have its bind variables
+// substituted. Only WHERE clauses which select a row by all of its primary
+// key columns can be matched. For all others, isHot returns false.
+func (d *hotRowDetector) isHot(table *schema.Table, where string) bool {
+	if table == nil || !table.HasPrimary() {
+		return false
+	}
+
+	d.mu.Lock()
+	rows := d.rows[table.Name.String()]
+	hasCandidates := len(rows) > 0
+	d.mu.Unlock()
+	if !hasCandidates {
+		// Avoid parsing the WHERE clause for tables without any lock waits.
+		return false
+	}
+
+	lockData, ok := d.lockData(table, where)
+	if !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r, ok := d.rows[table.Name.String()][lockData]
+	return ok && r.waits >= int64(d.minWaits)
+}
+
+// lockData formats the primary key values of the row selected by the WHERE
+// clause the same way InnoDB reports them in performance_schema.data_locks.
+func (d *hotRowDetector) lockData(table *schema.Table, where string) (string, bool) {
+	stmt, err := d.parser.Parse("select 1 from dual" + where)
+	if err != nil {
+		return "", false
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Where == nil {
+		return "", false
+	}
+
+	values := make(map[string]string)
+	for _, expr := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+		cmp, ok := expr.(*sqlparser.ComparisonExpr)
+		if !ok || cmp.Operator != sqlparser.EqualOp {
+			continue
+		}
+		col, lit := cmp.Left, cmp.Right
+		if _, isCol := col.(*sqlparser.ColName); !isCol {
+			col, lit = lit, col
+		}
+		colName, ok := col.(*sqlparser.ColName)
+		if !ok {
+			continue
+		}
+		literal, ok := lit.(*sqlparser.Literal)
+		if !ok {
+			continue
+		}
+		values[colName.Name.Lowered()] = literal.Val
+	}
+
+	parts := make([]string, 0, len(table.PKColumns))
+	for _, idx := range table.PKColumns {
+		field := table.Fields[idx]
+		val, ok := values[strings.ToLower(field.Name)]
+		if !ok {
+			return "", false
+		}
+		if sqltypes.IsQuoted(field.Type) {
+			val = "'" + val + "'"
+		}
+		parts = append(parts, val)
+	}
+	return strings.Join(parts, ", "), true
+}
+
+// HotRows returns the rows which are currently considered hot, sorted by
+// table and number of waits.
+func (d *hotRowDetector) HotRows() []HotRow {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var hot []HotRow
+	for table, rows := range d.rows {
+		for lockData, r := range rows {
+			if r.waits < int64(d.minWaits) {
+				continue
+			}
+			hot = append(hot, HotRow{
+				Table:    table,
+				LockData: lockData,
+				Waits:    r.waits,
+				LastSeen: r.lastSeen,
+			})
+		}
+	}
+	sort.Slice(hot, func(i, j int) bool {
+		if hot[i].Table != hot[j].Table {
+			return hot[i].Table < hot[j].Table
+		}
+		if hot[i].Waits != hot[j].Waits {
+			return hot[i].Waits > hot[j].Waits
+		}
+		return hot[i].LockData < hot[j].LockData
+	})
+	return hot
+}
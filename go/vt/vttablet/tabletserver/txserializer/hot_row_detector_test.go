@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package txserializer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func newAutoDetectTxSerializer(t *testing.T) *TxSerializer {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.HotRowProtection.Mode = tabletenv.Enable
+	cfg.HotRowProtection.AutoDetect = true
+	cfg.HotRowProtection.AutoDetectMinWaits = 2
+	cfg.HotRowProtection.AutoDetectTTL = time.Minute
+	txs := New(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "HotRowDetectorTest"))
+	require.NotNil(t, txs.detector)
+	return txs
+}
+
+func lockWaitsResult(rows ...string) *sqltypes.Result {
+	return sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("OBJECT_NAME|LOCK_DATA|count(*)", "varchar|varchar|int64"),
+		rows...,
+	)
+}
+
+func TestHotRowDetectorDisabled(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.HotRowProtection.Mode = tabletenv.Enable
+	txs := New(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "HotRowDetectorTest"))
+	assert.Nil(t, txs.detector)
+	assert.True(t, txs.IsHotRow(nil, " where id = 1"))
+	assert.Nil(t, txs.HotRows())
+}
+
+func TestHotRowDetectorIsHot(t *testing.T) {
+	txs := newAutoDetectTxSerializer(t)
+	d := txs.detector
+
+	t1 := &schema.Table{
+		Name: sqlparser.NewIdentifierCS("t1"),
+		Fields: []*querypb.Field{
+			{Name: "id", Type: sqltypes.Int64},
+			{Name: "val", Type: sqltypes.VarChar},
+		},
+		PKColumns: []int{0},
+	}
+	t2 := &schema.Table{
+		Name: sqlparser.NewIdentifierCS("t2"),
+		Fields: []*querypb.Field{
+			{Name: "a", Type: sqltypes.Int64},
+			{Name: "b", Type: sqltypes.VarChar},
+		},
+		PKColumns: []int{0, 1},
+	}
+
+	now := time.Now()
+	d.record(lockWaitsResult("t1|1|1", "t2|1, 'x'|3"), now)
+
+	// A single wait is below the threshold.
+	assert.False(t, txs.IsHotRow(t1, " where id = 1"))
+	assert.True(t, txs.IsHotRow(t2, " where a = 1 and b = 'x'"))
+	assert.True(t, txs.IsHotRow(t2, " where b = 'x' and 1 = a"))
+	assert.False(t, txs.IsHotRow(t2, " where a = 1 and b = 'y'"))
+	// Not all primary key columns are restricted.
+	assert.False(t, txs.IsHotRow(t2, " where a = 1"))
+
+	// The second wait turns the row hot.
+	d.record(lockWaitsResult("t1|1|1"), now.Add(time.Second))
+	assert.True(t, txs.IsHotRow(t1, " where id = 1"))
+	assert.True(t, txs.IsHotRow(t1, " where id = 1 and val = 'foo'"))
+	assert.False(t, txs.IsHotRow(t1, " where id = 2"))
+	assert.False(t, txs.IsHotRow(t1, " where id > 1"))
+
+	assert.Equal(t, []HotRow{
+		{Table: "t1", LockData: "1", Waits: 2, LastSeen: now.Add(time.Second)},
+		{Table: "t2", LockData: "1, 'x'", Waits: 3, LastSeen: now},
+	}, txs.HotRows())
+
+	// Rows expire once they haven't been seen for longer than the TTL.
+	d.record(lockWaitsResult(), now.Add(time.Minute+time.Millisecond))
+	assert.False(t, txs.IsHotRow(t2, " where a = 1 and b = 'x'"))
+	assert.True(t, txs.IsHotRow(t1, " where id = 1"))
+	d.record(lockWaitsResult(), now.Add(2*time.Minute))
+	assert.False(t, txs.IsHotRow(t1, " where id = 1"))
+	assert.Empty(t, txs.HotRows())
+}
+
+func TestHotRowDetectorSample(t *testing.T) {
+	txs := newAutoDetectTxSerializer(t)
+	d := txs.detector
+
+	var queries []string
+	d.exec = func(ctx context.Context, query string) (*sqltypes.Result, error) {
+		queries = append(queries, query)
+		return lockWaitsResult("t1|5|2"), nil
+	}
+	d.sample()
+	assert.Equal(t, []string{sqlSelectLockWaits}, queries)
+	require.Len(t, txs.HotRows(), 1)
+	assert.Equal(t, "5", txs.HotRows()[0].LockData)
+
+	req, err := http.NewRequest("GET", "/path-is-ignored-in-test", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	txs.ServeHTTP(rr, req)
+	assert.Contains(t, rr.Body.String(), "Detected hot rows: 1\n2: t1 (5) last seen ")
+}
@@ -32,6 +32,7 @@ import (
 	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -86,6 +87,10 @@ type TxSerializer struct {
 	logQueueExceededDryRun       *logutil.ThrottledLogger
 	logGlobalQueueExceededDryRun *logutil.ThrottledLogger
 
+	// detector is only set if hot rows are automatically detected. In that
+	// case, only transactions for rows which it considers hot are serialized.
+	detector *hotRowDetector
+
 	mu            sync.Mutex
 	queues        map[string]*queue
 	globalSize    int
@@ -95,7 +100,7 @@ type TxSerializer struct {
 // New returns a TxSerializer object.
 func New(env tabletenv.Env) *TxSerializer {
 	config := env.Config()
-	return &TxSerializer{
+	txs := &TxSerializer{
 		env:                    env,
 		ConsolidatorCache:      sync2.NewConsolidatorCache(1000),
 		dryRun:                 config.HotRowProtection.Mode == tabletenv.Dryrun,
@@ -132,6 +137,44 @@ func New(env tabletenv.Env) *TxSerializer {
 		queues:                       make(map[string]*queue),
 		redactUIQuery:                streamlog.NewQueryLogConfigForTest().RedactDebugUIQueries,
 	}
+	if config.HotRowProtection.Mode != tabletenv.Disable && config.HotRowProtection.AutoDetect {
+		txs.detector = newHotRowDetector(env)
+	}
+	return txs
+}
+
+// Open starts the automatic hot row detection, if enabled.
+func (txs *TxSerializer) Open() {
+	if txs.detector != nil {
+		txs.detector.Open()
+	}
+}
+
+// Close stops the automatic hot row detection, if enabled.
+func (txs *TxSerializer) Close() {
+	if txs.detector != nil {
+		txs.detector.Close()
+	}
+}
+
+// IsHotRow returns true if transactions for the row (range) selected by the
+// WHERE clause should be serialized. Without automatic detection all rows
+// qualify. Otherwise, only rows which are currently detected as hot do.
+// The WHERE clause must have its bind variables already substituted.
+func (txs *TxSerializer) IsHotRow(table *schema.Table, where string) bool {
+	if txs.detector == nil {
+		return true
+	}
+	return txs.detector.isHot(table, where)
+}
+
+// HotRows returns the rows which are currently detected as hot. It returns
+// nil if automatic detection is disabled.
+func (txs *TxSerializer) HotRows() []HotRow {
+	if txs.detector == nil {
+		return nil
+	}
+	return txs.detector.HotRows()
 }
 
 // DoneFunc is returned by Wait() and must be called by the caller.
@@ -347,6 +390,13 @@ func (txs *TxSerializer) ServeHTTP(response http.ResponseWriter, request *http.R
 	}
 	items := txs.Items()
 	response.Header().Set("Content-Type", "text/plain")
+	if txs.detector != nil {
+		hotRows := txs.detector.HotRows()
+		fmt.Fprintf(response, "Detected hot rows: %d\n", len(hotRows))
+		for _, r := range hotRows {
+			fmt.Fprintf(response, "%v: %s (%s) last seen %s\n", r.Waits, r.Table, r.LockData, r.LastSeen.Format(time.RFC3339))
+		}
+	}
 	if items == nil {
 		response.Write([]byte("empty\n"))
 		return
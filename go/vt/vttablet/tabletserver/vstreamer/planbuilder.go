@@ -108,6 +108,9 @@ const (
 	// in the plan we rewrite `x BETWEEN a AND b` to `x >= a AND x <= b`
 	// NotBetween is used to filter a comparable column if it doesn't lie within a specific range
 	NotBetween
+	// Expression is used to filter on any other predicate, which is evaluated
+	// by the evalengine against the row.
+	Expression
 )
 
 // Filter contains opcodes for filtering.
@@ -119,6 +122,9 @@ type Filter struct {
 	// Values will be used to store tuple/list values.
 	Values []sqltypes.Value
 
+	// Expr is the predicate of an Expression filter.
+	Expr evalengine.Expr
+
 	// Parameters for VindexMatch.
 	// Vindex, VindexColumns and KeyRange, if set, will be used
 	// to filter the row.
@@ -306,6 +312,16 @@ func (plan *Plan) shouldFilter(values []sqltypes.Value, charsets []collations.ID
 			if err != nil || !isValueGreaterThanRightFilter {
 				return false, false, err
 			}
+		case Expression:
+			exprEnv := evalengine.EmptyExpressionEnv(plan.env)
+			exprEnv.Row = values
+			result, err := exprEnv.Evaluate(filter.Expr)
+			if err != nil {
+				return false, false, err
+			}
+			if !result.ToBoolean() {
+				return false, false, nil
+			}
 		default:
 			match, err := compare(filter.Opcode, values[filter.ColNum], filter.Value, plan.env.CollationEnv(), charsets[filter.ColNum])
 			if err != nil {
@@ -630,9 +646,17 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 	if where == nil {
 		return nil
 	}
-	// Only a series of AND expressions are supported.
+	// The conditions of the top level AND expressions which have one of the
+	// simple forms below are compared directly. All the others are evaluated
+	// by the evalengine.
 	exprs := splitAndExpression(nil, where.Expr)
 	for _, expr := range exprs {
+		if !isSimpleFilter(expr) {
+			if err := plan.appendExpressionFilter(expr); err != nil {
+				return err
+			}
+			continue
+		}
 		switch expr := expr.(type) {
 		case *sqlparser.ComparisonExpr:
 			opcode, err := getOpcode(expr)
@@ -678,11 +702,8 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 			// Add it to the expressions that get pushed down to mysqld.
 			plan.whereExprsToPushDown = append(plan.whereExprsToPushDown, expr)
 		case *sqlparser.FuncExpr:
-			// We cannot filter binlog events in VStreamer using MySQL functions so
-			// we only allow the in_keyrange() function, which is VStreamer specific.
-			if !expr.Name.EqualString("in_keyrange") {
-				return fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
-			}
+			// The in_keyrange() function is VStreamer specific, so it's
+			// never pushed down to MySQL.
 			if err := plan.analyzeInKeyRange(vschema, expr.Exprs); err != nil {
 				return err
 			}
@@ -771,6 +792,103 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 	return nil
 }
 
+// isSimpleFilter returns true if the condition has one of the forms that
+// analyzeWhere compares without the evalengine: a column compared to a
+// literal, or to a tuple of literals with IN, a column BETWEEN two literals,
+// IS [NOT] NULL and in_keyrange().
+func isSimpleFilter(expr sqlparser.Expr) bool {
+	isColumn := func(expr sqlparser.Expr) bool {
+		_, ok := expr.(*sqlparser.ColName)
+		return ok
+	}
+	isLiteral := func(expr sqlparser.Expr) bool {
+		_, ok := expr.(*sqlparser.Literal)
+		return ok
+	}
+	switch expr := expr.(type) {
+	case *sqlparser.ComparisonExpr:
+		if _, err := getOpcode(expr); err != nil || !isColumn(expr.Left) {
+			return false
+		}
+		if expr.Operator != sqlparser.InOp {
+			return isLiteral(expr.Right)
+		}
+		tuple, ok := expr.Right.(sqlparser.ValTuple)
+		if !ok {
+			return false
+		}
+		for _, val := range tuple {
+			if !isLiteral(val) {
+				return false
+			}
+		}
+		return true
+	case *sqlparser.FuncExpr:
+		return expr.Name.EqualString("in_keyrange")
+	case *sqlparser.IsExpr:
+		return isColumn(expr.Left) && (expr.Right == sqlparser.IsNullOp || expr.Right == sqlparser.IsNotNullOp)
+	case *sqlparser.BetweenExpr:
+		return isColumn(expr.Left) && isLiteral(expr.From) && isLiteral(expr.To)
+	}
+	return false
+}
+
+// appendExpressionFilter adds a filter that evaluates the condition with the
+// evalengine. The condition can only reference the columns of the table, and
+// is also pushed down to MySQL in the copy phase.
+func (plan *Plan) appendExpressionFilter(expr sqlparser.Expr) error {
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			if !node.Qualifier.IsEmpty() {
+				return false, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(node))
+			}
+		case *sqlparser.Subquery, *sqlparser.ExistsExpr, *sqlparser.Variable, *sqlparser.CurTimeFuncExpr, sqlparser.AggrFunc:
+			return false, fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
+		case *sqlparser.FuncExpr:
+			if sqlparser.NonDeterministicFuncs[node.Name.Lowered()] {
+				return false, fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
+			}
+		}
+		return true, nil
+	}, expr)
+	if err != nil {
+		return err
+	}
+	evalExpr, err := evalengine.Translate(expr, &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			return findColumn(plan.Table, col.Name)
+		},
+		ResolveType: func(expr sqlparser.Expr) (evalengine.Type, bool) {
+			col, ok := expr.(*sqlparser.ColName)
+			if !ok {
+				return evalengine.Type{}, false
+			}
+			colnum := plan.Table.FindColumn(col.Name)
+			if colnum < 0 {
+				return evalengine.Type{}, false
+			}
+			return evalengine.NewTypeFromField(plan.Table.Fields[colnum]), true
+		},
+		Collation:   plan.env.CollationEnv().DefaultConnectionCharset(),
+		Environment: plan.env,
+		// Any column can be NULL in a binlog row image, regardless of its
+		// current definition, so the expression is always interpreted.
+		NoCompilation: true,
+	})
+	if err != nil {
+		return fmt.Errorf("unsupported constraint: %v: %v", sqlparser.String(expr), err)
+	}
+	plan.Filters = append(plan.Filters, Filter{
+		Opcode: Expression,
+		ColNum: -1,
+		Expr:   evalExpr,
+	})
+	// Add it to the expressions that get pushed down to mysqld.
+	plan.whereExprsToPushDown = append(plan.whereExprsToPushDown, expr)
+	return nil
+}
+
 // splitAndExpression breaks up the Expr into AND-separated conditions
 // and appends them to filters, which can be shuffled and recombined
 // as needed.
@@ -896,6 +896,141 @@ func TestPlanBuilderFilterComparison(t *testing.T) {
 	}
 }
 
+func TestPlanBuilderFilterExpression(t *testing.T) {
+	t1 := &Table{
+		Name: "t1",
+		Fields: []*querypb.Field{{
+			Name:    "id",
+			Type:    sqltypes.Int64,
+			Charset: collations.CollationBinaryID,
+			Flags:   uint32(querypb.MySqlFlag_BINARY_FLAG | querypb.MySqlFlag_NUM_FLAG),
+		}, {
+			Name:    "status",
+			Type:    sqltypes.VarChar,
+			Charset: uint32(collations.MySQL8().DefaultConnectionCharset()),
+		}, {
+			Name:    "amount",
+			Type:    sqltypes.Decimal,
+			Charset: collations.CollationBinaryID,
+			Flags:   uint32(querypb.MySqlFlag_BINARY_FLAG | querypb.MySqlFlag_NUM_FLAG),
+		}},
+	}
+	row := func(id int64, status string, amount string) []sqltypes.Value {
+		return []sqltypes.Value{
+			sqltypes.NewInt64(id),
+			sqltypes.NewVarChar(status),
+			sqltypes.MakeTrusted(sqltypes.Decimal, []byte(amount)),
+		}
+	}
+	rows := [][]sqltypes.Value{
+		row(1, "paid", "150.00"),
+		row(2, "paid", "50.00"),
+		row(3, "refunded", "500.00"),
+		row(4, "pending", "101.00"),
+		{sqltypes.NewInt64(5), sqltypes.NULL, sqltypes.NULL},
+	}
+	testcases := []struct {
+		name        string
+		inFilter    string
+		outFilters  int
+		outPushDown []string
+		outIDs      []int64
+		outErr      string
+	}{{
+		name:        "simple-comparisons",
+		inFilter:    "select * from t1 where status = 'paid' and amount > 100",
+		outFilters:  2,
+		outPushDown: []string{"`status` = 'paid'", "amount > 100"},
+		outIDs:      []int64{1},
+	}, {
+		name:        "or",
+		inFilter:    "select * from t1 where (status = 'paid' and amount > 100) or status = 'pending'",
+		outFilters:  1,
+		outPushDown: []string{"`status` = 'paid' and amount > 100 or `status` = 'pending'"},
+		outIDs:      []int64{1, 4},
+	}, {
+		name:        "arithmetic",
+		inFilter:    "select * from t1 where amount * 2 > 250 and id < 4",
+		outFilters:  2,
+		outPushDown: []string{"amount * 2 > 250", "id < 4"},
+		outIDs:      []int64{1, 3},
+	}, {
+		name:        "not-and-like",
+		inFilter:    "select * from t1 where not (status like 'p%')",
+		outFilters:  1,
+		outPushDown: []string{"not `status` like 'p%'"},
+		outIDs:      []int64{3},
+	}, {
+		name:        "column-comparison",
+		inFilter:    "select * from t1 where amount > id * 100",
+		outFilters:  1,
+		outPushDown: []string{"amount > id * 100"},
+		outIDs:      []int64{1, 3},
+	}, {
+		name:        "functions",
+		inFilter:    "select * from t1 where length(status) = 4 and coalesce(amount, 0) < 100",
+		outFilters:  2,
+		outPushDown: []string{"length(`status`) = 4", "coalesce(amount, 0) < 100"},
+		outIDs:      []int64{2},
+	}, {
+		name:     "unknown-column",
+		inFilter: "select * from t1 where id + 1 = nosuch",
+		outErr:   "column nosuch not found in table t1",
+	}, {
+		name:     "qualified-column",
+		inFilter: "select * from t1 where t1.id + 1 = 2",
+		outErr:   "unsupported qualifier for column: t1.id",
+	}, {
+		name:     "non-deterministic-function",
+		inFilter: "select * from t1 where rand() < 0.5",
+		outErr:   "unsupported constraint: rand() < 0.5",
+	}, {
+		name:     "current-time",
+		inFilter: "select * from t1 where id > unix_timestamp(now())",
+		outErr:   "unsupported constraint: id > unix_timestamp(now())",
+	}, {
+		name:     "subquery",
+		inFilter: "select * from t1 where id in (select id from t2)",
+		outErr:   "unsupported constraint: id in (select id from t2)",
+	}}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			plan, err := buildPlan(vtenv.NewTestEnv(), t1, testLocalVSchema, &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{Match: "t1", Filter: tcase.inFilter}},
+			})
+			if tcase.outErr != "" {
+				assert.Nil(t, plan)
+				assert.ErrorContains(t, err, tcase.outErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, plan.Filters, tcase.outFilters)
+			var pushDown []string
+			for _, expr := range plan.whereExprsToPushDown {
+				pushDown = append(pushDown, sqlparser.String(expr))
+			}
+			require.Equal(t, tcase.outPushDown, pushDown)
+
+			charsets := make([]collations.ID, len(t1.Fields))
+			for i, field := range t1.Fields {
+				charsets[i] = collations.ID(field.Charset)
+			}
+			var ids []int64
+			for _, values := range rows {
+				ok, _, err := plan.shouldFilter(values, charsets)
+				require.NoError(t, err)
+				if ok {
+					id, err := values[0].ToInt64()
+					require.NoError(t, err)
+					ids = append(ids, id)
+				}
+			}
+			require.Equal(t, tcase.outIDs, ids)
+		})
+	}
+}
+
 func TestCompare(t *testing.T) {
 	type testcase struct {
 		opcode                   Opcode
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// writeTracker keeps track of the writes which are in flight for each table,
+// and counts the writes which completed. The consolidator uses it to make
+// sure that a read never joins an identical query which started before a
+// write to one of its tables became visible. Otherwise, a session could miss
+// its own write, which it just committed.
+//
+// A nil writeTracker tracks nothing.
+type writeTracker struct {
+	mu sync.Mutex
+	// inFlight counts the writes per table which are executing
+	// and may become visible at any time.
+	inFlight map[string]int
+	// generations counts the completed writes per table.
+	generations map[string]int64
+}
+
+func newWriteTracker() *writeTracker {
+	return &writeTracker{
+		inFlight:    make(map[string]int),
+		generations: make(map[string]int64),
+	}
+}
+
+// start registers a write to the tables. The returned function must be
+// called once the write is visible to other sessions, or failed.
+func (wt *writeTracker) start(tables []string) func() {
+	if wt == nil || len(tables) == 0 {
+		return func() {}
+	}
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	for _, table := range tables {
+		wt.inFlight[table]++
+	}
+	return func() {
+		wt.mu.Lock()
+		defer wt.mu.Unlock()
+		for _, table := range tables {
+			if wt.inFlight[table]--; wt.inFlight[table] <= 0 {
+				delete(wt.inFlight, table)
+			}
+			wt.generations[table]++
+		}
+	}
+}
+
+// consolidationKey returns the key under which a read of the tables can be
+// consolidated with identical reads. The key changes whenever a write to one
+// of the tables completes, so that later reads don't join a query which may
+// have missed the write. It returns false if a write to one of the tables is
+// in flight, in which case the read must not be consolidated at all.
+func (wt *writeTracker) consolidationKey(sql string, tables []string) (string, bool) {
+	if wt == nil {
+		return sql, true
+	}
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	var sb strings.Builder
+	sb.WriteString(sql)
+	for _, table := range tables {
+		if wt.inFlight[table] > 0 {
+			return "", false
+		}
+		sb.WriteString("/*")
+		sb.WriteString(strconv.FormatInt(wt.generations[table], 10))
+		sb.WriteString("*/")
+	}
+	return sb.String(), true
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTracker(t *testing.T) {
+	var nilTracker *writeTracker
+	nilTracker.start([]string{"t1"})()
+	key, ok := nilTracker.consolidationKey("select 1", []string{"t1"})
+	assert.True(t, ok)
+	assert.Equal(t, "select 1", key)
+
+	wt := newWriteTracker()
+	key1, ok := wt.consolidationKey("select 1", []string{"t1", "t2"})
+	assert.True(t, ok)
+
+	// Reads are not consolidated while a write to one of their tables is in flight.
+	done1 := wt.start([]string{"t2"})
+	done2 := wt.start([]string{"t2", "t3"})
+	_, ok = wt.consolidationKey("select 1", []string{"t1", "t2"})
+	assert.False(t, ok)
+	key, ok = wt.consolidationKey("select 1", []string{"t1"})
+	assert.True(t, ok)
+	assert.Equal(t, "select 1/*0*/", key)
+
+	done1()
+	_, ok = wt.consolidationKey("select 1", []string{"t1", "t2"})
+	assert.False(t, ok)
+	done2()
+
+	// Once the writes completed, reads get a new key, so they don't join
+	// queries which started before.
+	key2, ok := wt.consolidationKey("select 1", []string{"t1", "t2"})
+	assert.True(t, ok)
+	assert.NotEqual(t, key1, key2)
+	key, ok = wt.consolidationKey("select 1", []string{"t1", "t2"})
+	assert.True(t, ok)
+	assert.Equal(t, key2, key)
+	assert.Empty(t, wt.inFlight)
+}
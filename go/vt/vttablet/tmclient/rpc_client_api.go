@@ -293,6 +293,12 @@ type TabletManagerClient interface {
 	// RestoreFromBackup deletes local data and restores database from backup
 	RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error)
 
+	// VerifyBackup restores a backup into a scratch mysqld on the tablet and checks its tables
+	VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error)
+
+	// CloneFrom deletes local data and replaces it with a MySQL CLONE of the donor tablet
+	CloneFrom(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CloneFromRequest) (logutil.EventStream, error)
+
 	// Throttler
 	CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error)
 	GetThrottlerStatus(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetThrottlerStatusRequest) (*tabletmanagerdatapb.GetThrottlerStatusResponse, error)
@@ -40,6 +40,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
+	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -1507,6 +1508,76 @@ func (fra *fakeRPCTM) RestoreFromBackup(ctx context.Context, logger logutil.Logg
 	return nil
 }
 
+var testVerifyBackupRequest = &tabletmanagerdatapb.VerifyBackupRequest{
+	BackupName:  "2026-10-18.000000.test-0000000100",
+	Concurrency: 4,
+}
+
+var testVerifyBackupResponse = &tabletmanagerdatapb.VerifyBackupResponse{
+	Result: &mysqlctlpb.VerifyBackupResult{
+		BackupName: "2026-10-18.000000.test-0000000100",
+		Directory:  "test_keyspace/-80",
+		Tables:     []*mysqlctlpb.VerifyBackupTableResult{{Schema: "vt_test_keyspace", Name: "t1", RowCount: 10, Ok: true}},
+		Ok:         true,
+	},
+}
+
+func (fra *fakeRPCTM) VerifyBackup(ctx context.Context, request *tabletmanagerdatapb.VerifyBackupRequest) (*tabletmanagerdatapb.VerifyBackupResponse, error) {
+	if fra.panics {
+		panic(errors.New("test-triggered panic"))
+	}
+	compare(fra.t, "VerifyBackup request", request, testVerifyBackupRequest)
+	return testVerifyBackupResponse, nil
+}
+
+func tmRPCTestVerifyBackup(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	resp, err := client.VerifyBackup(ctx, tablet, testVerifyBackupRequest)
+	compareError(t, "VerifyBackup", err, resp, testVerifyBackupResponse)
+}
+
+func tmRPCTestVerifyBackupPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.VerifyBackup(ctx, tablet, testVerifyBackupRequest)
+	expectHandleRPCPanic(t, "VerifyBackup", true /*verbose*/, err)
+}
+
+var (
+	testCloneFromRequest = &tabletmanagerdatapb.CloneFromRequest{
+		DonorAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+	}
+	testCloneFromCalled = false
+)
+
+func (fra *fakeRPCTM) CloneFrom(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.CloneFromRequest) error {
+	if fra.panics {
+		panic(errors.New("test-triggered panic"))
+	}
+	compare(fra.t, "CloneFrom request", request, testCloneFromRequest)
+	logStuff(logger, 10)
+	testCloneFromCalled = true
+	return nil
+}
+
+func tmRPCTestCloneFrom(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	stream, err := client.CloneFrom(ctx, tablet, testCloneFromRequest)
+	if err != nil {
+		t.Fatalf("CloneFrom failed: %v", err)
+	}
+	err = compareLoggedStuff(t, "CloneFrom", stream, 10)
+	compareError(t, "CloneFrom", err, true, testCloneFromCalled)
+}
+
+func tmRPCTestCloneFromPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	stream, err := client.CloneFrom(ctx, tablet, testCloneFromRequest)
+	if err != nil {
+		t.Fatalf("CloneFrom failed: %v", err)
+	}
+	e, err := stream.Recv()
+	if err == nil {
+		t.Fatalf("Unexpected CloneFrom logs: %v", e)
+	}
+	expectHandleRPCPanic(t, "CloneFrom", true /*verbose*/, err)
+}
+
 func (fra *fakeRPCTM) CheckThrottler(ctx context.Context, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
 	if fra.panics {
 		panic(errors.New("test-triggered panic"))
@@ -1640,6 +1711,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackup(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackup(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestVerifyBackup(ctx, t, client, tablet)
+	tmRPCTestCloneFrom(ctx, t, client, tablet)
 
 	// Throttler related methods
 	tmRPCTestCheckThrottler(ctx, t, client, tablet, checkThrottlerRequest)
@@ -1702,6 +1775,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackupPanic(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackupPanic(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestVerifyBackupPanic(ctx, t, client, tablet)
+	tmRPCTestCloneFromPanic(ctx, t, client, tablet)
 
 	client.Close()
 }